@@ -79,6 +79,9 @@ func (a *arrayObject) init() {
 }
 
 func (a *arrayObject) _setLengthInt(l uint32, throw bool) bool {
+	if l > a.length {
+		a.val.runtime.checkArrayLength(l)
+	}
 	ret := true
 	if l <= a.length {
 		if a.propValueCount > 0 {
@@ -369,7 +372,9 @@ func (a *arrayObject) expand(idx uint32) bool {
 					}
 				}
 				tl := int(targetLen)
-				newValues := make([]Value, tl, growCap(tl, len(a.values), cap(a.values)))
+				newCap := growCap(tl, len(a.values), cap(a.values))
+				a.val.runtime.trackAlloc(memSizeN(newCap-cap(a.values), memSizeValue))
+				newValues := make([]Value, tl, newCap)
 				copy(newValues, a.values)
 				a.values = newValues
 			}
@@ -550,6 +555,7 @@ func (a *arrayObject) exportToArrayOrSlice(dst reflect.Value, typ reflect.Type,
 }
 
 func (a *arrayObject) setValuesFromSparse(items []sparseArrayItem, newMaxIdx int) {
+	a.val.runtime.trackAlloc(memSizeN(newMaxIdx+1, memSizeValue))
 	a.values = make([]Value, newMaxIdx+1)
 	for _, item := range items {
 		a.values[item.idx] = item.value
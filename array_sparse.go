@@ -31,6 +31,9 @@ func (a *sparseArrayObject) findIdx(idx uint32) int {
 }
 
 func (a *sparseArrayObject) _setLengthInt(l uint32, throw bool) bool {
+	if l > a.length {
+		a.val.runtime.checkArrayLength(l)
+	}
 	ret := true
 	if l <= a.length {
 		if a.propValueCount > 0 {
@@ -141,6 +144,7 @@ func (a *sparseArrayObject) getOwnPropIdx(idx valueInt) Value {
 
 func (a *sparseArrayObject) add(idx uint32, val Value) {
 	i := a.findIdx(idx)
+	a.val.runtime.trackAlloc(memSizeSparseItem)
 	a.items = append(a.items, sparseArrayItem{})
 	copy(a.items[i+1:], a.items[i:])
 	a.items[i] = sparseArrayItem{
@@ -177,6 +181,7 @@ func (a *sparseArrayObject) _setOwnIdx(idx uint32, val Value, throw bool) bool {
 		}
 
 		if a.expand(idx) {
+			a.val.runtime.trackAlloc(memSizeSparseItem)
 			a.items = append(a.items, sparseArrayItem{})
 			copy(a.items[i+1:], a.items[i:])
 			a.items[i] = sparseArrayItem{
@@ -351,6 +356,7 @@ func (a *sparseArrayObject) _defineIdxProperty(idx uint32, desc PropertyDescript
 		}
 		if i >= len(a.items) || a.items[i].idx != idx {
 			if a.expand(idx) {
+				a.val.runtime.trackAlloc(memSizeSparseItem)
 				a.items = append(a.items, sparseArrayItem{})
 				copy(a.items[i+1:], a.items[i:])
 				a.items[i] = sparseArrayItem{
@@ -779,7 +779,15 @@ func (self *BranchStatement) Idx1() file.Idx {
 	}
 	return self.Label.Idx1()
 }
-func (self *CaseStatement) Idx1() file.Idx       { return self.Consequent[len(self.Consequent)-1].Idx1() }
+func (self *CaseStatement) Idx1() file.Idx {
+	if len(self.Consequent) > 0 {
+		return self.Consequent[len(self.Consequent)-1].Idx1()
+	}
+	if self.Test != nil {
+		return self.Test.Idx1()
+	}
+	return self.Case + 7 // "default"
+}
 func (self *CatchStatement) Idx1() file.Idx      { return self.Body.Idx1() }
 func (self *DebuggerStatement) Idx1() file.Idx   { return self.Debugger + 8 }
 func (self *DoWhileStatement) Idx1() file.Idx    { return self.RightParenthesis + 1 }
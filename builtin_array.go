@@ -24,6 +24,8 @@ func (r *Runtime) newArrayObject() *arrayObject {
 }
 
 func setArrayValues(a *arrayObject, values []Value) *arrayObject {
+	a.val.runtime.checkArrayLength(uint32(len(values)))
+	a.val.runtime.trackAlloc(memSizeN(len(values), memSizeValue))
 	a.values = values
 	a.length = uint32(len(values))
 	a.objCount = len(values)
@@ -204,7 +206,10 @@ func (r *Runtime) arrayproto_join(call FunctionCall) Value {
 		}
 	}
 
-	return buf.String()
+	res := buf.String()
+	r.checkStringLength(res.Length())
+	r.trackAlloc(memSizeString(res))
+	return res
 }
 
 func (r *Runtime) arrayproto_toString(call FunctionCall) Value {
@@ -232,12 +237,13 @@ func (r *Runtime) arrayproto_toString(call FunctionCall) Value {
 	})
 }
 
-func (r *Runtime) writeItemLocaleString(item Value, buf *StringBuilder) {
+func (r *Runtime) writeItemLocaleString(item, locales, options Value, buf *StringBuilder) {
 	if item != nil && item != _undefined && item != _null {
 		if f, ok := r.getVStr(item, "toLocaleString").(*Object); ok {
 			if c, ok := f.self.assertCallable(); ok {
 				strVal := c(FunctionCall{
-					This: item,
+					This:      item,
+					Arguments: []Value{locales, options},
 				})
 				buf.WriteString(strVal.toString())
 				return
@@ -249,13 +255,14 @@ func (r *Runtime) writeItemLocaleString(item Value, buf *StringBuilder) {
 
 func (r *Runtime) arrayproto_toLocaleString(call FunctionCall) Value {
 	array := call.This.ToObject(r)
+	locales, options := call.Argument(0), call.Argument(1)
 	var buf StringBuilder
 	if a := r.checkStdArrayObj(array); a != nil {
 		for i, item := range a.values {
 			if i > 0 {
 				buf.WriteRune(',')
 			}
-			r.writeItemLocaleString(item, &buf)
+			r.writeItemLocaleString(item, locales, options, &buf)
 		}
 	} else {
 		length := toLength(array.self.getStr("length", nil))
@@ -264,7 +271,7 @@ func (r *Runtime) arrayproto_toLocaleString(call FunctionCall) Value {
 				buf.WriteRune(',')
 			}
 			item := array.self.getIdx(valueInt(i), nil)
-			r.writeItemLocaleString(item, &buf)
+			r.writeItemLocaleString(item, locales, options, &buf)
 		}
 	}
 
@@ -970,6 +977,141 @@ func (r *Runtime) arrayproto_reverse(call FunctionCall) Value {
 	return o
 }
 
+// arrayFastValues returns a copy of the elements if o is a standard array without holes or a Go slice, so that
+// the elements can be read without side effects.
+func (r *Runtime) arrayFastValues(o *Object) ([]Value, bool) {
+	if a := r.checkStdArrayObj(o); a != nil {
+		values := make([]Value, len(a.values))
+		copy(values, a.values)
+		return values, true
+	}
+	if s, ok := o.self.(*objectGoSlice); ok {
+		values := make([]Value, len(*s.data))
+		for i := range values {
+			values[i] = s._getIdx(i)
+		}
+		return values, true
+	}
+	return nil, false
+}
+
+// arrayLikeValues reads the first length elements of o, the holes are read as undefined.
+func (r *Runtime) arrayLikeValues(o *Object, length int64) []Value {
+	values := make([]Value, length)
+	for i := range values {
+		values[i] = nilSafe(o.self.getIdx(valueInt(i), nil))
+	}
+	return values
+}
+
+func (r *Runtime) checkArrayCreateLength(length int64) {
+	if length > math.MaxUint32 {
+		panic(r.newError(r.getRangeError(), "Invalid array length"))
+	}
+}
+
+func (r *Runtime) arrayproto_toReversed(call FunctionCall) Value {
+	o := call.This.ToObject(r)
+	values, ok := r.arrayFastValues(o)
+	if !ok {
+		length := toLength(o.self.getStr("length", nil))
+		r.checkArrayCreateLength(length)
+		values = make([]Value, length)
+		for i := range values {
+			values[i] = nilSafe(o.self.getIdx(valueInt(length-int64(i)-1), nil))
+		}
+		return r.newArrayValues(values)
+	}
+	for lower, upper := 0, len(values)-1; lower < upper; lower, upper = lower+1, upper-1 {
+		values[lower], values[upper] = values[upper], values[lower]
+	}
+	return r.newArrayValues(values)
+}
+
+func (r *Runtime) arrayproto_toSorted(call FunctionCall) Value {
+	var compareFn func(FunctionCall) Value
+	if arg := call.Argument(0); arg != _undefined {
+		if arg, ok := arg.(*Object); ok {
+			compareFn, _ = arg.self.assertCallable()
+		}
+		if compareFn == nil {
+			panic(r.NewTypeError("The comparison function must be either a function or undefined"))
+		}
+	}
+	o := call.This.ToObject(r)
+	values, ok := r.arrayFastValues(o)
+	if !ok {
+		length := toLength(o.self.getStr("length", nil))
+		r.checkArrayCreateLength(length)
+		values = r.arrayLikeValues(o, length)
+	}
+	a := r.newArrayValues(values)
+	ctx := arraySortCtx{
+		obj:     a.self,
+		compare: compareFn,
+	}
+	sort.Stable(&ctx)
+	return a
+}
+
+func (r *Runtime) arrayproto_toSpliced(call FunctionCall) Value {
+	o := call.This.ToObject(r)
+	length := toLength(o.self.getStr("length", nil))
+	actualStart := relToIdx(call.Argument(0).ToInteger(), length)
+	var actualSkipCount int64
+	switch len(call.Arguments) {
+	case 0:
+	case 1:
+		actualSkipCount = length - actualStart
+	default:
+		actualSkipCount = min(max(call.Argument(1).ToInteger(), 0), length-actualStart)
+	}
+	var items []Value
+	if len(call.Arguments) > 2 {
+		items = call.Arguments[2:]
+	}
+	newLength := length + int64(len(items)) - actualSkipCount
+	if newLength >= maxInt {
+		panic(r.NewTypeError("Invalid array length"))
+	}
+	r.checkArrayCreateLength(newLength)
+	values := make([]Value, 0, newLength)
+	if src, ok := r.arrayFastValues(o); ok && int64(len(src)) == length {
+		values = append(values, src[:actualStart]...)
+		values = append(values, items...)
+		values = append(values, src[actualStart+actualSkipCount:]...)
+	} else {
+		for i := int64(0); i < actualStart; i++ {
+			values = append(values, nilSafe(o.self.getIdx(valueInt(i), nil)))
+		}
+		values = append(values, items...)
+		for i := actualStart + actualSkipCount; i < length; i++ {
+			values = append(values, nilSafe(o.self.getIdx(valueInt(i), nil)))
+		}
+	}
+	return r.newArrayValues(values)
+}
+
+func (r *Runtime) arrayproto_with(call FunctionCall) Value {
+	o := call.This.ToObject(r)
+	length := toLength(o.self.getStr("length", nil))
+	relativeIndex := call.Argument(0).ToInteger()
+	actualIndex := relativeIndex
+	if actualIndex < 0 {
+		actualIndex = length + relativeIndex
+	}
+	if actualIndex >= length || actualIndex < 0 {
+		panic(r.newError(r.getRangeError(), "Invalid index %s", call.Argument(0).String()))
+	}
+	r.checkArrayCreateLength(length)
+	values, ok := r.arrayFastValues(o)
+	if !ok || int64(len(values)) != length {
+		values = r.arrayLikeValues(o, length)
+	}
+	values[actualIndex] = call.Argument(1)
+	return r.newArrayValues(values)
+}
+
 func (r *Runtime) arrayproto_shift(call FunctionCall) Value {
 	o := call.This.ToObject(r)
 	if a := r.checkStdArrayObjWithProto(o); a != nil {
@@ -1359,6 +1501,188 @@ func (r *Runtime) array_from(call FunctionCall) Value {
 	return arr
 }
 
+// arrayFromAsync holds the state of an Array.fromAsync() call
+// (https://tc39.es/proposal-array-from-async/#sec-array.fromAsync).
+type arrayFromAsync struct {
+	r       *Runtime
+	pcap    *promiseCapability
+	arr     *Object
+	mapFn   func(FunctionCall) Value
+	thisArg Value
+	k       int64
+
+	// Set when consuming an iterator. If sync is true it's a synchronous iterator whose values are awaited.
+	iter *iteratorRecord
+	sync bool
+
+	// Set when consuming an array-like.
+	arrayLike *Object
+	length    int64
+}
+
+func (f *arrayFromAsync) start(call FunctionCall) {
+	r := f.r
+	if mapFnArg := call.Argument(1); mapFnArg != _undefined {
+		if mapFnObj, ok := mapFnArg.(*Object); ok {
+			f.mapFn, _ = mapFnObj.self.assertCallable()
+		}
+		if f.mapFn == nil {
+			panic(r.NewTypeError("%s is not a function", mapFnArg))
+		}
+	}
+	f.thisArg = call.Argument(2)
+	items := call.Argument(0)
+
+	var ctor func(args []Value, newTarget *Object) *Object
+	if o, ok := call.This.(*Object); ok {
+		ctor = o.self.assertConstructor()
+	}
+	usingIterator := toMethod(r.getV(items, SymAsyncIterator))
+	if usingIterator == nil {
+		usingIterator = toMethod(r.getV(items, SymIterator))
+		f.sync = usingIterator != nil
+	}
+	if usingIterator != nil {
+		if ctor != nil {
+			f.arr = ctor([]Value{}, nil)
+		} else {
+			f.arr = r.newArrayValues(nil)
+		}
+		f.iter = r.getIterator(items, usingIterator)
+	} else {
+		f.arrayLike = items.ToObject(r)
+		f.length = toLength(f.arrayLike.self.getStr("length", nil))
+		if ctor != nil {
+			f.arr = ctor([]Value{intToValue(f.length)}, nil)
+		} else {
+			f.arr = r.newArrayValues(nil)
+		}
+	}
+	f.next()
+}
+
+// try runs fn, rejecting the promise if it throws. If closeIter is true the iterator is closed first.
+func (f *arrayFromAsync) try(closeIter bool, fn func()) bool {
+	if ex := f.r.vm.try(fn); ex != nil {
+		if closeIter {
+			f.abort(ex.val)
+		} else {
+			f.pcap.reject(ex.val)
+		}
+		return false
+	}
+	return true
+}
+
+// abort closes the iterator (if any) and rejects the promise with reason.
+func (f *arrayFromAsync) abort(reason Value) {
+	iter := f.iter
+	if iter == nil {
+		f.pcap.reject(reason)
+		return
+	}
+	f.iter = nil
+	var res Value
+	if f.r.vm.try(func() {
+		if retMethod := toMethod(iter.iterator.self.getStr("return", nil)); retMethod != nil {
+			res = retMethod(FunctionCall{This: iter.iterator})
+		}
+	}) != nil || res == nil || f.sync {
+		f.pcap.reject(reason)
+		return
+	}
+	reject := func(Value) {
+		f.pcap.reject(reason)
+	}
+	f.try(false, func() {
+		f.r.await(res, reject, reject)
+	})
+}
+
+func (f *arrayFromAsync) next() {
+	r := f.r
+	if f.iter == nil {
+		if f.k >= f.length {
+			f.try(false, func() {
+				f.arr.self.setOwnStr("length", intToValue(f.length), true)
+				f.pcap.resolve(f.arr)
+			})
+			return
+		}
+		f.try(false, func() {
+			r.await(nilSafe(f.arrayLike.self.getIdx(valueInt(f.k), nil)), f.onValue, f.pcap.reject)
+		})
+		return
+	}
+	f.try(false, func() {
+		if f.iter.next == nil {
+			panic(r.NewTypeError("iterator.next is missing or not a function"))
+		}
+		res := f.iter.next(FunctionCall{This: f.iter.iterator})
+		if f.sync {
+			f.onResult(res)
+		} else {
+			r.await(res, func(res Value) {
+				f.try(false, func() {
+					f.onResult(res)
+				})
+			}, f.pcap.reject)
+		}
+	})
+}
+
+func (f *arrayFromAsync) onResult(res Value) {
+	r := f.r
+	resObj, ok := res.(*Object)
+	if !ok {
+		panic(r.NewTypeError("Iterator result %s is not an object", res.String()))
+	}
+	if iteratorComplete(resObj) {
+		f.iter = nil
+		f.arr.self.setOwnStr("length", intToValue(f.k), true)
+		f.pcap.resolve(f.arr)
+		return
+	}
+	value := iteratorValue(resObj)
+	if f.sync {
+		r.await(value, f.onValue, f.abort)
+	} else {
+		f.onValue(value)
+	}
+}
+
+func (f *arrayFromAsync) onValue(value Value) {
+	if f.mapFn == nil {
+		f.onMapped(value)
+		return
+	}
+	f.try(true, func() {
+		mapped := f.mapFn(FunctionCall{This: f.thisArg, Arguments: []Value{value, intToValue(f.k)}})
+		f.r.await(mapped, f.onMapped, f.abort)
+	})
+}
+
+func (f *arrayFromAsync) onMapped(value Value) {
+	if !f.try(true, func() {
+		createDataPropertyOrThrow(f.arr, intToValue(f.k), value)
+	}) {
+		return
+	}
+	f.k++
+	f.next()
+}
+
+func (r *Runtime) array_fromAsync(call FunctionCall) Value {
+	f := &arrayFromAsync{
+		r:    r,
+		pcap: r.newPromiseCapability(r.getPromise()),
+	}
+	f.try(true, func() {
+		f.start(call)
+	})
+	return f.pcap.promise
+}
+
 func (r *Runtime) array_isArray(call FunctionCall) Value {
 	if o, ok := call.Argument(0).(*Object); ok {
 		if isArray(o) {
@@ -1440,9 +1764,13 @@ func createArrayProtoTemplate() *objectTemplate {
 	t.putStr("sort", func(r *Runtime) Value { return r.methodProp(r.arrayproto_sort, "sort", 1) })
 	t.putStr("splice", func(r *Runtime) Value { return r.methodProp(r.arrayproto_splice, "splice", 2) })
 	t.putStr("toLocaleString", func(r *Runtime) Value { return r.methodProp(r.arrayproto_toLocaleString, "toLocaleString", 0) })
+	t.putStr("toReversed", func(r *Runtime) Value { return r.methodProp(r.arrayproto_toReversed, "toReversed", 0) })
+	t.putStr("toSorted", func(r *Runtime) Value { return r.methodProp(r.arrayproto_toSorted, "toSorted", 1) })
+	t.putStr("toSpliced", func(r *Runtime) Value { return r.methodProp(r.arrayproto_toSpliced, "toSpliced", 2) })
 	t.putStr("toString", func(r *Runtime) Value { return valueProp(r.getArrayToString(), true, false, true) })
 	t.putStr("unshift", func(r *Runtime) Value { return r.methodProp(r.arrayproto_unshift, "unshift", 1) })
 	t.putStr("values", func(r *Runtime) Value { return valueProp(r.getArrayValues(), true, false, true) })
+	t.putStr("with", func(r *Runtime) Value { return r.methodProp(r.arrayproto_with, "with", 2) })
 
 	t.putSym(SymIterator, func(r *Runtime) Value { return valueProp(r.getArrayValues(), true, false, true) })
 	t.putSym(SymUnscopables, func(r *Runtime) Value {
@@ -1458,9 +1786,10 @@ func createArrayProtoTemplate() *objectTemplate {
 		bl.setOwnStr("flatMap", valueTrue, true)
 		bl.setOwnStr("includes", valueTrue, true)
 		bl.setOwnStr("keys", valueTrue, true)
+		bl.setOwnStr("toReversed", valueTrue, true)
+		bl.setOwnStr("toSorted", valueTrue, true)
+		bl.setOwnStr("toSpliced", valueTrue, true)
 		bl.setOwnStr("values", valueTrue, true)
-		bl.setOwnStr("groupBy", valueTrue, true)
-		bl.setOwnStr("groupByToMap", valueTrue, true)
 
 		return valueProp(bl.val, false, false, true)
 	})
@@ -1501,6 +1830,7 @@ func (r *Runtime) getArray() *Object {
 func (r *Runtime) createArray(val *Object) objectImpl {
 	o := r.newNativeFuncConstructObj(val, r.builtin_newArray, "Array", r.getArrayPrototype(), 1)
 	o._putProp("from", r.newNativeFunc(r.array_from, "from", 1), true, false, true)
+	o._putProp("fromAsync", r.newNativeFunc(r.array_fromAsync, "fromAsync", 1), true, false, true)
 	o._putProp("isArray", r.newNativeFunc(r.array_isArray, "isArray", 1), true, false, true)
 	o._putProp("of", r.newNativeFunc(r.array_of, "of", 0), true, false, true)
 	r.putSpeciesReturnThis(o)
@@ -168,6 +168,119 @@ func TestArrayFrom(t *testing.T) {
 	testScriptWithTestLib(SCRIPT, _undefined, t)
 }
 
+func TestArrayFromAsync(t *testing.T) {
+	const SCRIPT = `
+	function asyncIterable(values) {
+		var o = {};
+		o[Symbol.asyncIterator] = function() {
+			var i = 0;
+			return {
+				next: function() {
+					return Promise.resolve(i < values.length ? {value: values[i++], done: false} : {done: true});
+				},
+				return: function() {
+					o.closed = true;
+					return Promise.resolve({done: true});
+				}
+			};
+		};
+		return o;
+	}
+
+	var p = Array.fromAsync([1, 2]);
+	assert(p instanceof Promise, "returns a Promise");
+
+	var res = await Array.fromAsync(asyncIterable([1, 2, 3]));
+	assert(Array.isArray(res), "result is an array");
+	assert(compareArray(res, [1, 2, 3]), "async iterable");
+
+	res = await Array.fromAsync([Promise.resolve(1), 2, Promise.resolve(3)]);
+	assert(compareArray(res, [1, 2, 3]), "sync iterable of promises");
+
+	res = await Array.fromAsync({length: 2, 0: Promise.resolve("a"), 1: "b"});
+	assert(compareArray(res, ["a", "b"]), "array-like");
+
+	res = await Array.fromAsync(asyncIterable([1, 2]), async function(x, i) { return x * 10 + i; });
+	assert(compareArray(res, [10, 21]), "mapFn");
+
+	class MyArray {}
+	res = await Array.fromAsync.call(MyArray, [1]);
+	assert(res instanceof MyArray, "constructor");
+	assert.sameValue(res.length, 1);
+
+	var it = asyncIterable([1, 2]);
+	try {
+		await Array.fromAsync(it, function() { throw new Error("boom"); });
+		throw new Error("should have been rejected");
+	} catch (e) {
+		assert.sameValue(e.message, "boom");
+	}
+	assert(it.closed, "iterator is closed when mapFn throws");
+
+	try {
+		await Array.fromAsync([Promise.reject(new Error("rejected"))]);
+		throw new Error("should have been rejected");
+	} catch (e) {
+		assert.sameValue(e.message, "rejected");
+	}
+
+	try {
+		await Array.fromAsync([], 1);
+		throw new Error("should have been rejected");
+	} catch (e) {
+		assert(e instanceof TypeError, "invalid mapFn");
+	}
+	`
+	testAsyncFuncWithTestLib(SCRIPT, _undefined, t)
+}
+
+func TestArrayFromAsyncGo(t *testing.T) {
+	r := New()
+	var resolvers []func(interface{})
+	r.Set("fetchPage", func(n int) *Promise {
+		p, resolve, _ := r.NewPromise()
+		resolvers = append(resolvers, func(interface{}) {
+			if n < 3 {
+				resolve(map[string]interface{}{"value": n, "done": false})
+			} else {
+				resolve(map[string]interface{}{"done": true})
+			}
+		})
+		return p
+	})
+	v, err := r.RunString(`
+	var n = 0;
+	var pages = {};
+	pages[Symbol.asyncIterator] = function() {
+		return {
+			next: function() {
+				return fetchPage(n++);
+			}
+		};
+	};
+	Array.fromAsync(pages);
+	`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := v.Export().(*Promise)
+	for len(resolvers) > 0 {
+		resolve := resolvers[0]
+		resolvers = resolvers[1:]
+		resolve(nil)
+		// run the queued jobs
+		if _, err := r.RunString(""); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if p.State() != PromiseStateFulfilled {
+		t.Fatalf("Unexpected state: %v", p.State())
+	}
+	if res := p.Result().Export(); len(res.([]interface{})) != 3 {
+		t.Fatalf("Unexpected result: %v", res)
+	}
+}
+
 func TestArrayOf(t *testing.T) {
 	const SCRIPT = `
 	function T1() {
@@ -339,3 +452,64 @@ func TestArrayProto(t *testing.T) {
 	`
 	testScriptWithTestLib(SCRIPT, _undefined, t)
 }
+
+func TestArrayChangeByCopy(t *testing.T) {
+	const SCRIPT = `
+	var a = [3, 1, 2];
+	assert(compareArray(a.toReversed(), [2, 1, 3]), "toReversed");
+	assert(compareArray(a.toSorted(), [1, 2, 3]), "toSorted");
+	assert(compareArray(a.toSorted(function(x, y) { return y - x; }), [3, 2, 1]), "toSorted with comparator");
+	assert(compareArray(a.toSpliced(1, 1, "a", "b"), [3, "a", "b", 2]), "toSpliced");
+	assert(compareArray(a.toSpliced(1), [3]), "toSpliced without skipCount");
+	assert(compareArray(a.toSpliced(), [3, 1, 2]), "toSpliced without arguments");
+	assert(compareArray(a.with(-1, 5), [3, 1, 5]), "with");
+	assert(compareArray(a, [3, 1, 2]), "not modified");
+
+	var sparse = [1, , 3];
+	var res = sparse.toReversed();
+	assert(res.hasOwnProperty(1), "holes are filled");
+	assert.sameValue(res[1], undefined);
+	assert(compareArray([, "b", undefined, "a"].toSorted(), ["a", "b", undefined, undefined]), "toSorted with holes");
+
+	var arrayLike = {length: 3, 0: "c", 1: "a", 2: "b"};
+	assert(compareArray(Array.prototype.toSorted.call(arrayLike), ["a", "b", "c"]), "array-like toSorted");
+	assert(compareArray(Array.prototype.toReversed.call(arrayLike), ["b", "a", "c"]), "array-like toReversed");
+	assert(compareArray(Array.prototype.toSpliced.call(arrayLike, 0, 2), ["b"]), "array-like toSpliced");
+	assert(compareArray(Array.prototype.with.call(arrayLike, 0, "x"), ["x", "a", "b"]), "array-like with");
+
+	class MyArray extends Array {}
+	assert.sameValue(Object.getPrototypeOf(new MyArray(1, 2).toReversed()), Array.prototype, "no species");
+
+	assert.throws(RangeError, function() { a.with(3, 0); });
+	assert.throws(RangeError, function() { a.with(-4, 0); });
+	assert.throws(TypeError, function() { a.toSorted(null); });
+	assert.throws(TypeError, function() { Array.prototype.toSpliced.call({length: 2 ** 53 - 1}, 0, 0, 1); });
+	assert.throws(RangeError, function() { Array.prototype.toReversed.call({length: 2 ** 32}); });
+	assert.sameValue(Array.prototype[Symbol.unscopables].toSorted, true);
+	`
+	testScriptWithTestLib(SCRIPT, _undefined, t)
+}
+
+func TestArrayChangeByCopyGoSlice(t *testing.T) {
+	vm := New()
+	s := []interface{}{3, 1, 2}
+	vm.Set("s", s)
+	_, err := vm.RunString(`
+	function check(res, expected, msg) {
+		if (!Array.isArray(res) || res.join() !== expected) {
+			throw new Error(msg + ": " + res);
+		}
+		res.push(0);
+	}
+	check(s.toReversed(), "2,1,3", "toReversed");
+	check(s.toSorted(), "1,2,3", "toSorted");
+	check(s.toSpliced(0, 1), "1,2", "toSpliced");
+	check(s.with(0, 4), "4,1,2", "with");
+	`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s[0] != 3 || s[1] != 1 || s[2] != 2 {
+		t.Fatalf("The Go slice was modified: %v", s)
+	}
+}
@@ -202,7 +202,8 @@ func (r *Runtime) dateproto_toLocaleString(call FunctionCall) Value {
 	obj := r.toObject(call.This)
 	if d, ok := obj.self.(*dateObject); ok {
 		if d.isSet() {
-			return asciiString(d.time().Format(datetimeLayout_en_GB))
+			f := r.newIntlDateTimeFormat(call.Argument(0), call.Argument(1), "any", "all")
+			return newStringValue(f.format(d.msec))
 		} else {
 			return stringInvalidDate
 		}
@@ -214,7 +215,8 @@ func (r *Runtime) dateproto_toLocaleDateString(call FunctionCall) Value {
 	obj := r.toObject(call.This)
 	if d, ok := obj.self.(*dateObject); ok {
 		if d.isSet() {
-			return asciiString(d.time().Format(dateLayout_en_GB))
+			f := r.newIntlDateTimeFormat(call.Argument(0), call.Argument(1), "date", "date")
+			return newStringValue(f.format(d.msec))
 		} else {
 			return stringInvalidDate
 		}
@@ -226,7 +228,8 @@ func (r *Runtime) dateproto_toLocaleTimeString(call FunctionCall) Value {
 	obj := r.toObject(call.This)
 	if d, ok := obj.self.(*dateObject); ok {
 		if d.isSet() {
-			return asciiString(d.time().Format(timeLayout_en_GB))
+			f := r.newIntlDateTimeFormat(call.Argument(0), call.Argument(1), "time", "time")
+			return newStringValue(f.format(d.msec))
 		} else {
 			return stringInvalidDate
 		}
@@ -358,6 +358,7 @@ func createGlobalObjectTemplate() *objectTemplate {
 
 	t.putStr("Math", func(r *Runtime) Value { return valueProp(r.getMath(), true, false, true) })
 	t.putStr("JSON", func(r *Runtime) Value { return valueProp(r.getJSON(), true, false, true) })
+	t.putStr("Intl", func(r *Runtime) Value { return valueProp(r.getIntl(), true, false, true) })
 	addTypedArrays(t)
 	t.putStr("Symbol", func(r *Runtime) Value { return valueProp(r.getSymbol(), true, false, true) })
 	t.putStr("WeakSet", func(r *Runtime) Value { return valueProp(r.getWeakSet(), true, false, true) })
@@ -365,6 +366,7 @@ func createGlobalObjectTemplate() *objectTemplate {
 	t.putStr("Map", func(r *Runtime) Value { return valueProp(r.getMap(), true, false, true) })
 	t.putStr("Set", func(r *Runtime) Value { return valueProp(r.getSet(), true, false, true) })
 	t.putStr("Promise", func(r *Runtime) Value { return valueProp(r.getPromise(), true, false, true) })
+	t.putStr("Iterator", func(r *Runtime) Value { return valueProp(r.getIteratorConstructor(), true, false, true) })
 
 	t.putStr("globalThis", func(r *Runtime) Value { return valueProp(r.globalObject, true, false, true) })
 	t.putStr("NaN", func(r *Runtime) Value { return valueProp(_NaN, false, false, false) })
@@ -381,6 +383,7 @@ func createGlobalObjectTemplate() *objectTemplate {
 	t.putStr("encodeURIComponent", func(r *Runtime) Value { return r.methodProp(r.builtin_encodeURIComponent, "encodeURIComponent", 1) })
 	t.putStr("escape", func(r *Runtime) Value { return r.methodProp(r.builtin_escape, "escape", 1) })
 	t.putStr("unescape", func(r *Runtime) Value { return r.methodProp(r.builtin_unescape, "unescape", 1) })
+	t.putStr("structuredClone", func(r *Runtime) Value { return r.methodProp(r.builtin_structuredClone, "structuredClone", 1) })
 
 	// TODO: Annex B
 
@@ -0,0 +1,267 @@
+package goja
+
+import (
+	"sync"
+
+	"github.com/dop251/goja/unistring"
+	"golang.org/x/text/language"
+)
+
+// intlPart is an element of the array returned by the formatToParts() methods.
+type intlPart struct {
+	typ   string
+	value string
+	// Only set by Intl.RelativeTimeFormat.prototype.formatToParts().
+	unit string
+}
+
+var (
+	intlLocaleMatcher     language.Matcher
+	intlLocaleMatcherOnce sync.Once
+)
+
+func getIntlLocaleMatcher() language.Matcher {
+	intlLocaleMatcherOnce.Do(func() {
+		tags := make([]language.Tag, len(intlLocales))
+		for i, data := range intlLocales {
+			tags[i] = data.tag
+		}
+		intlLocaleMatcher = language.NewMatcher(tags)
+	})
+	return intlLocaleMatcher
+}
+
+func (r *Runtime) intlDefaultLocale() language.Tag {
+	if r.locale != nil {
+		return *r.locale
+	}
+	return language.AmericanEnglish
+}
+
+// intlCanonicalizeLocaleList implements CanonicalizeLocaleList (https://tc39.es/ecma402/#sec-canonicalizelocalelist).
+func (r *Runtime) intlCanonicalizeLocaleList(locales Value) []language.Tag {
+	if locales == nil || locales == _undefined {
+		return nil
+	}
+	var tags []language.Tag
+	seen := make(map[string]struct{})
+	add := func(v Value) {
+		var s string
+		switch v := v.(type) {
+		case String:
+			s = v.String()
+		case *Object:
+			s = v.toString().String()
+		default:
+			panic(r.NewTypeError("Language ID should be string or object."))
+		}
+		tag, err := language.Parse(s)
+		if _, unknown := err.(language.ValueError); unknown {
+			// well-formed, but not known to the language package
+			err = nil
+		}
+		if err != nil || s == "" {
+			panic(r.newError(r.getRangeError(), "Incorrect locale information provided"))
+		}
+		canonical := tag.String()
+		if _, exists := seen[canonical]; !exists {
+			seen[canonical] = struct{}{}
+			tags = append(tags, tag)
+		}
+	}
+	if s, ok := locales.(String); ok {
+		add(s)
+		return tags
+	}
+	o := r.toObject(locales)
+	l := toLength(o.self.getStr("length", nil))
+	for k := int64(0); k < l; k++ {
+		idx := valueInt(k)
+		if o.self.hasPropertyIdx(idx) {
+			add(nilSafe(o.self.getIdx(idx, nil)))
+		}
+	}
+	return tags
+}
+
+// intlMatchLocale returns the index of the best matching available locale for tag or -1 if there is no
+// reasonable match.
+func intlMatchLocale(tag language.Tag) int {
+	_, idx, conf := getIntlLocaleMatcher().Match(tag)
+	if conf == language.No {
+		return -1
+	}
+	return idx
+}
+
+// intlResolveLocale picks the first requested locale that has a match among the available ones, falling back to
+// the default locale. It returns the resolved tag (without extensions) and the requested tag which may carry
+// Unicode extension keywords.
+func (r *Runtime) intlResolveLocale(locales Value) (resolved, requested language.Tag, data *intlLocaleData) {
+	resolved, requested, idx := r.intlLookupLocale(locales, intlMatchLocale)
+	if idx < 0 {
+		data = intlLocales[0]
+		return data.tag, data.tag, data
+	}
+	return resolved, requested, intlLocales[idx]
+}
+
+// intlLookupLocale is like intlResolveLocale, but uses the specified matcher. The returned index is -1 if
+// neither the requested locales nor the default one have a match.
+func (r *Runtime) intlLookupLocale(locales Value, match func(language.Tag) int) (resolved, requested language.Tag, idx int) {
+	for _, tag := range r.intlCanonicalizeLocaleList(locales) {
+		if idx := match(tag); idx >= 0 {
+			return intlStripExtensions(tag), tag, idx
+		}
+	}
+	def := r.intlDefaultLocale()
+	return intlStripExtensions(def), def, match(def)
+}
+
+func intlStripExtensions(tag language.Tag) language.Tag {
+	base, script, region := tag.Raw()
+	t, err := language.Compose(base, script, region)
+	if err != nil {
+		return tag
+	}
+	return t
+}
+
+func (r *Runtime) intlSupportedLocales(locales Value, options Value, match func(language.Tag) int) Value {
+	requested := r.intlCanonicalizeLocaleList(locales)
+	if opts := r.intlCoerceOptions(options); opts != nil {
+		r.intlGetOption(opts, "localeMatcher", []string{"lookup", "best fit"}, "best fit")
+	}
+	values := make([]Value, 0, len(requested))
+	for _, tag := range requested {
+		if match(tag) >= 0 {
+			values = append(values, newStringValue(tag.String()))
+		}
+	}
+	return r.newArrayValues(values)
+}
+
+// intlCoerceOptions returns nil if options is undefined, otherwise converts it to an Object.
+func (r *Runtime) intlCoerceOptions(options Value) *Object {
+	if options == nil || options == _undefined {
+		return nil
+	}
+	return r.toObject(options)
+}
+
+// intlGetOption implements GetOption (https://tc39.es/ecma402/#sec-getoption) for string options. If values is
+// not nil the option must be one of them.
+func (r *Runtime) intlGetOption(options *Object, property unistring.String, values []string, fallback string) string {
+	if options == nil {
+		return fallback
+	}
+	v := options.self.getStr(property, nil)
+	if v == nil || v == _undefined {
+		return fallback
+	}
+	s := v.toString().String()
+	if values != nil {
+		for _, allowed := range values {
+			if s == allowed {
+				return s
+			}
+		}
+		panic(r.newError(r.getRangeError(), "Value %s out of range for options property %s", s, property))
+	}
+	return s
+}
+
+// intlGetBoolOption is like intlGetOption for boolean options. The second return value is false if the option
+// was not set.
+func (r *Runtime) intlGetBoolOption(options *Object, property unistring.String) (bool, bool) {
+	if options == nil {
+		return false, false
+	}
+	v := options.self.getStr(property, nil)
+	if v == nil || v == _undefined {
+		return false, false
+	}
+	return v.ToBoolean(), true
+}
+
+// intlGetNumberOption implements GetNumberOption (https://tc39.es/ecma402/#sec-getnumberoption).
+func (r *Runtime) intlGetNumberOption(options *Object, property unistring.String, minimum, maximum, fallback int) int {
+	if options == nil {
+		return fallback
+	}
+	return r.intlDefaultNumberOption(options.self.getStr(property, nil), property, minimum, maximum, fallback)
+}
+
+func (r *Runtime) intlDefaultNumberOption(v Value, property unistring.String, minimum, maximum, fallback int) int {
+	if v == nil || v == _undefined {
+		return fallback
+	}
+	f := v.ToFloat()
+	if f != f || f < float64(minimum) || f > float64(maximum) {
+		panic(r.newError(r.getRangeError(), "%s value is out of range.", property))
+	}
+	return int(f)
+}
+
+func (r *Runtime) intlPartsToArray(parts []intlPart) Value {
+	values := make([]Value, len(parts))
+	for i, part := range parts {
+		o := r.NewObject()
+		o.self._putProp("type", asciiString(part.typ), true, true, true)
+		o.self._putProp("value", newStringValue(part.value), true, true, true)
+		if part.unit != "" {
+			o.self._putProp("unit", asciiString(part.unit), true, true, true)
+		}
+		values[i] = o
+	}
+	return r.newArrayValues(values)
+}
+
+func (r *Runtime) intl_getCanonicalLocales(call FunctionCall) Value {
+	tags := r.intlCanonicalizeLocaleList(call.Argument(0))
+	values := make([]Value, len(tags))
+	for i, tag := range tags {
+		values[i] = newStringValue(tag.String())
+	}
+	return r.newArrayValues(values)
+}
+
+func createIntlTemplate() *objectTemplate {
+	t := newObjectTemplate()
+	t.protoFactory = func(r *Runtime) *Object {
+		return r.global.ObjectPrototype
+	}
+
+	t.putStr("Collator", func(r *Runtime) Value { return valueProp(r.getCollator(), true, false, true) })
+	t.putStr("DateTimeFormat", func(r *Runtime) Value { return valueProp(r.getDateTimeFormat(), true, false, true) })
+	t.putStr("ListFormat", func(r *Runtime) Value { return valueProp(r.getListFormat(), true, false, true) })
+	t.putStr("NumberFormat", func(r *Runtime) Value { return valueProp(r.getNumberFormat(), true, false, true) })
+	t.putStr("PluralRules", func(r *Runtime) Value { return valueProp(r.getPluralRules(), true, false, true) })
+	t.putStr("RelativeTimeFormat", func(r *Runtime) Value { return valueProp(r.getRelativeTimeFormat(), true, false, true) })
+	t.putStr("Segmenter", func(r *Runtime) Value { return valueProp(r.getSegmenter(), true, false, true) })
+	t.putStr("getCanonicalLocales", func(r *Runtime) Value { return r.methodProp(r.intl_getCanonicalLocales, "getCanonicalLocales", 1) })
+
+	t.putSym(SymToStringTag, func(r *Runtime) Value { return valueProp(asciiString(classIntl), false, false, true) })
+
+	return t
+}
+
+var intlTemplate *objectTemplate
+var intlTemplateOnce sync.Once
+
+func getIntlTemplate() *objectTemplate {
+	intlTemplateOnce.Do(func() {
+		intlTemplate = createIntlTemplate()
+	})
+	return intlTemplate
+}
+
+func (r *Runtime) getIntl() *Object {
+	ret := r.global.Intl
+	if ret == nil {
+		ret = &Object{runtime: r}
+		r.global.Intl = ret
+		r.newTemplatedObject(getIntlTemplate(), ret)
+	}
+	return ret
+}
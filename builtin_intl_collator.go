@@ -0,0 +1,310 @@
+package goja
+
+import (
+	"strings"
+	"sync"
+	"unicode"
+
+	"github.com/dop251/goja/unistring"
+	"golang.org/x/text/collate"
+	"golang.org/x/text/language"
+	"golang.org/x/text/unicode/norm"
+)
+
+// intlCollator contains the resolved state of an Intl.Collator. It is also used directly by
+// String.prototype.localeCompare().
+type intlCollator struct {
+	locale language.Tag
+
+	usage, sensitivity, caseFirst string
+	numeric, ignorePunctuation    bool
+
+	collator *collate.Collator
+	// Ignores the case differences, only set when upper case letters have to be sorted first.
+	caselessCollator *collate.Collator
+	// Used to compare the strings with the diacritics removed when sensitivity is "case".
+	caseCollator *collate.Collator
+}
+
+type collatorObject struct {
+	baseObject
+	c            *intlCollator
+	boundCompare *Object
+}
+
+var (
+	intlCollationTags        []language.Tag
+	intlCollationMatcher     language.Matcher
+	intlCollationMatcherOnce sync.Once
+)
+
+func intlMatchCollationLocale(tag language.Tag) int {
+	intlCollationMatcherOnce.Do(func() {
+		// Only the standard collations are considered, the root collation is used when nothing else matches.
+		for _, tag := range collate.Supported() {
+			if tag != language.Und && tag.TypeForKey("co") == "" {
+				intlCollationTags = append(intlCollationTags, tag)
+			}
+		}
+		intlCollationMatcher = language.NewMatcher(intlCollationTags)
+	})
+	_, idx, conf := intlCollationMatcher.Match(tag)
+	if conf == language.No {
+		return -1
+	}
+	return idx
+}
+
+func newCollateCollator(tag language.Tag, sensitivity string, numeric, ignorePunctuation bool) *collate.Collator {
+	var keys []string
+	if ignorePunctuation {
+		keys = append(keys, "ka-shifted")
+	}
+	switch sensitivity {
+	case "base":
+		keys = append(keys, "ks-level1")
+	case "accent":
+		keys = append(keys, "ks-level2")
+	case "case":
+		// Case differences are compared separately by intlCollator, see caseCollator
+		keys = append(keys, "ks-level1")
+	}
+	if numeric {
+		keys = append(keys, "kn-true")
+	}
+	if len(keys) == 0 {
+		return collate.New(tag)
+	}
+	return collate.New(tag, collate.OptionsFromTag(language.Make("und-u-"+strings.Join(keys, "-"))))
+}
+
+// newIntlCollator implements the InitializeCollator abstract operation
+// (https://tc39.es/ecma402/#sec-initializecollator).
+func (r *Runtime) newIntlCollator(locales, options Value) *intlCollator {
+	opts := r.intlCoerceOptions(options)
+	c := &intlCollator{}
+	c.usage = r.intlGetOption(opts, "usage", []string{"sort", "search"}, "sort")
+	r.intlGetOption(opts, "localeMatcher", []string{"lookup", "best fit"}, "best fit")
+	r.intlGetOption(opts, "collation", nil, "")
+	numeric, numericSet := r.intlGetBoolOption(opts, "numeric")
+	caseFirst := r.intlGetOption(opts, "caseFirst", []string{"upper", "lower", "false"}, "")
+
+	resolved, requested, idx := r.intlLookupLocale(locales, intlMatchCollationLocale)
+	c.locale = resolved
+	if !numericSet {
+		numeric = requested.TypeForKey("kn") == "true"
+	}
+	if caseFirst == "" {
+		switch kf := requested.TypeForKey("kf"); kf {
+		case "upper", "lower", "false":
+			caseFirst = kf
+		default:
+			caseFirst = "false"
+		}
+	}
+	c.numeric, c.caseFirst = numeric, caseFirst
+	c.sensitivity = r.intlGetOption(opts, "sensitivity", []string{"base", "accent", "case", "variant"}, "variant")
+	c.ignorePunctuation, _ = r.intlGetBoolOption(opts, "ignorePunctuation")
+
+	tag := language.Und
+	if idx >= 0 {
+		tag = intlCollationTags[idx]
+	}
+	c.collator = newCollateCollator(tag, c.sensitivity, c.numeric, c.ignorePunctuation)
+	if c.sensitivity == "case" {
+		c.caseCollator = newCollateCollator(tag, "variant", c.numeric, c.ignorePunctuation)
+	}
+	if c.caseFirst == "upper" {
+		switch c.sensitivity {
+		case "case":
+			c.caselessCollator = newCollateCollator(tag, "base", c.numeric, c.ignorePunctuation)
+		case "variant":
+			c.caselessCollator = newCollateCollator(tag, "accent", c.numeric, c.ignorePunctuation)
+		}
+	}
+	return c
+}
+
+func (c *intlCollator) compare(x, y String) int {
+	a := norm.NFD.String(x.String())
+	b := norm.NFD.String(y.String())
+	res := c.collator.CompareString(a, b)
+	if res == 0 && c.caseCollator != nil {
+		res = c.caseCollator.CompareString(intlStripMarks(a), intlStripMarks(b))
+	}
+	if res != 0 && c.caselessCollator != nil && c.caselessCollator.CompareString(a, b) == 0 {
+		// The strings only differ in case, the collator puts the lower case first.
+		res = -res
+	}
+	return res
+}
+
+// intlStripMarks removes the combining marks from an NFD-normalised string.
+func intlStripMarks(s string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.Is(unicode.Mn, r) {
+			return -1
+		}
+		return r
+	}, s)
+}
+
+// collator returns the Collator for the default locale with the default options.
+func (r *Runtime) collator() *intlCollator {
+	collator := r._collator
+	if collator == nil {
+		collator = r.newIntlCollator(_undefined, _undefined)
+		r._collator = collator
+	}
+	return collator
+}
+
+func (r *Runtime) toCollator(v Value, method string) *collatorObject {
+	if o, ok := v.(*Object); ok {
+		if c, ok := o.self.(*collatorObject); ok {
+			return c
+		}
+	}
+	panic(r.NewTypeError("Method Intl.Collator.prototype.%s called on incompatible receiver %s", method, r.objectproto_toString(FunctionCall{This: v})))
+}
+
+func (r *Runtime) builtin_newCollator(args []Value, newTarget *Object) *Object {
+	var locales, options Value = _undefined, _undefined
+	if len(args) > 0 {
+		locales = args[0]
+	}
+	if len(args) > 1 {
+		options = args[1]
+	}
+	proto := r.getCollatorPrototype()
+	if newTarget != nil {
+		proto = r.getPrototypeFromCtor(newTarget, r.getCollator(), proto)
+	}
+	o := &Object{runtime: r}
+	c := &collatorObject{
+		c: r.newIntlCollator(locales, options),
+	}
+	c.class = classObject
+	c.val = o
+	c.extensible = true
+	o.self = c
+	c.prototype = proto
+	c.init()
+	return o
+}
+
+func (r *Runtime) collatorProto_getCompare(call FunctionCall) Value {
+	c := r.toCollator(call.This, "compare")
+	if c.boundCompare == nil {
+		collator := c.c
+		c.boundCompare = r.newNativeFunc(func(call FunctionCall) Value {
+			return intToValue(int64(collator.compare(call.Argument(0).toString(), call.Argument(1).toString())))
+		}, "", 2)
+	}
+	return c.boundCompare
+}
+
+func (r *Runtime) collatorProto_resolvedOptions(call FunctionCall) Value {
+	c := r.toCollator(call.This, "resolvedOptions").c
+	res := r.NewObject()
+	put := func(name unistring.String, value Value) {
+		res.self._putProp(name, value, true, true, true)
+	}
+	put("locale", newStringValue(c.locale.String()))
+	put("usage", asciiString(c.usage))
+	put("sensitivity", asciiString(c.sensitivity))
+	put("ignorePunctuation", r.toBoolean(c.ignorePunctuation))
+	put("collation", asciiString("default"))
+	put("numeric", r.toBoolean(c.numeric))
+	put("caseFirst", asciiString(c.caseFirst))
+	return res
+}
+
+func (r *Runtime) collator_supportedLocalesOf(call FunctionCall) Value {
+	return r.intlSupportedLocales(call.Argument(0), call.Argument(1), intlMatchCollationLocale)
+}
+
+func createCollatorProtoTemplate() *objectTemplate {
+	t := newObjectTemplate()
+	t.protoFactory = func(r *Runtime) *Object {
+		return r.global.ObjectPrototype
+	}
+
+	t.putStr("constructor", func(r *Runtime) Value { return valueProp(r.getCollator(), true, false, true) })
+	t.putStr("compare", func(r *Runtime) Value {
+		return &valueProperty{
+			getterFunc:   r.newNativeFunc(r.collatorProto_getCompare, "get compare", 0),
+			accessor:     true,
+			configurable: true,
+		}
+	})
+	t.putStr("resolvedOptions", func(r *Runtime) Value {
+		return r.methodProp(r.collatorProto_resolvedOptions, "resolvedOptions", 0)
+	})
+
+	t.putSym(SymToStringTag, func(r *Runtime) Value {
+		return valueProp(asciiString("Intl.Collator"), false, false, true)
+	})
+
+	return t
+}
+
+var collatorProtoTemplate *objectTemplate
+var collatorProtoTemplateOnce sync.Once
+
+func getCollatorProtoTemplate() *objectTemplate {
+	collatorProtoTemplateOnce.Do(func() {
+		collatorProtoTemplate = createCollatorProtoTemplate()
+	})
+	return collatorProtoTemplate
+}
+
+func (r *Runtime) getCollatorPrototype() *Object {
+	ret := r.global.CollatorPrototype
+	if ret == nil {
+		ret = &Object{runtime: r}
+		r.global.CollatorPrototype = ret
+		r.newTemplatedObject(getCollatorProtoTemplate(), ret)
+	}
+	return ret
+}
+
+func createCollatorTemplate() *objectTemplate {
+	t := newObjectTemplate()
+	t.protoFactory = func(r *Runtime) *Object {
+		return r.getFunctionPrototype()
+	}
+
+	t.putStr("name", func(r *Runtime) Value { return valueProp(asciiString("Collator"), false, false, true) })
+	t.putStr("length", func(r *Runtime) Value { return valueProp(intToValue(0), false, false, true) })
+
+	t.putStr("prototype", func(r *Runtime) Value { return valueProp(r.getCollatorPrototype(), false, false, false) })
+
+	t.putStr("supportedLocalesOf", func(r *Runtime) Value {
+		return r.methodProp(r.collator_supportedLocalesOf, "supportedLocalesOf", 1)
+	})
+
+	return t
+}
+
+var collatorTemplate *objectTemplate
+var collatorTemplateOnce sync.Once
+
+func getCollatorTemplate() *objectTemplate {
+	collatorTemplateOnce.Do(func() {
+		collatorTemplate = createCollatorTemplate()
+	})
+	return collatorTemplate
+}
+
+func (r *Runtime) getCollator() *Object {
+	ret := r.global.Collator
+	if ret == nil {
+		ret = &Object{runtime: r}
+		r.global.Collator = ret
+		r.newTemplatedFuncObject(getCollatorTemplate(), ret, func(call FunctionCall) Value {
+			return r.builtin_newCollator(call.Arguments, nil)
+		}, r.builtin_newCollator)
+	}
+	return ret
+}
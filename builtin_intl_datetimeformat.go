@@ -0,0 +1,1106 @@
+package goja
+
+import (
+	"io/fs"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dop251/goja/unistring"
+	"golang.org/x/text/language"
+)
+
+// intlPatternToken is either a date field (e.g. 'y' repeated count times) or a literal.
+type intlPatternToken struct {
+	field   byte
+	count   int
+	literal string
+}
+
+// intlDateTimeFormat contains the resolved state of an Intl.DateTimeFormat. It is also used directly by
+// Date.prototype.toLocale*String().
+type intlDateTimeFormat struct {
+	locale language.Tag
+	data   *intlLocaleData
+
+	timeZone string
+	loc      *time.Location
+
+	hourCycle            string
+	dateStyle, timeStyle string
+	dayPeriod            string
+	timeZoneName         string
+
+	pattern []intlPatternToken
+}
+
+type dateTimeFormatObject struct {
+	baseObject
+	f           *intlDateTimeFormat
+	boundFormat *Object
+}
+
+var (
+	intlDateStyles    = []string{"full", "long", "medium", "short"}
+	intlTextWidths    = []string{"narrow", "short", "long"}
+	intlNumericWidths = []string{"2-digit", "numeric"}
+	intlMonthWidths   = []string{"2-digit", "numeric", "narrow", "short", "long"}
+	intlHourCycles    = []string{"h11", "h12", "h23", "h24"}
+	intlTzNameWidths  = []string{"short", "long", "shortOffset", "longOffset", "shortGeneric", "longGeneric"}
+)
+
+var (
+	intlLocalTimeZone     string
+	intlLocalTimeZoneOnce sync.Once
+
+	intlTimeZoneNames     map[string]string
+	intlTimeZoneNamesOnce sync.Once
+)
+
+func parseIntlPattern(pattern string) []intlPatternToken {
+	var tokens []intlPatternToken
+	var lit strings.Builder
+	flushLiteral := func() {
+		if lit.Len() > 0 {
+			tokens = append(tokens, intlPatternToken{literal: lit.String()})
+			lit.Reset()
+		}
+	}
+	for i := 0; i < len(pattern); {
+		c := pattern[i]
+		switch {
+		case c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z':
+			j := i + 1
+			for j < len(pattern) && pattern[j] == c {
+				j++
+			}
+			flushLiteral()
+			tokens = append(tokens, intlPatternToken{field: c, count: j - i})
+			i = j
+		case c == '\'':
+			if i+1 < len(pattern) && pattern[i+1] == '\'' {
+				lit.WriteByte('\'')
+				i += 2
+				continue
+			}
+			i++
+			for i < len(pattern) {
+				if pattern[i] == '\'' {
+					if i+1 < len(pattern) && pattern[i+1] == '\'' {
+						lit.WriteByte('\'')
+						i += 2
+						continue
+					}
+					i++
+					break
+				}
+				lit.WriteByte(pattern[i])
+				i++
+			}
+		default:
+			lit.WriteByte(c)
+			i++
+		}
+	}
+	flushLiteral()
+	return tokens
+}
+
+// intlRemoveFields removes the fields for which keep returns false together with the separating literal
+// (the preceding one if there is any, otherwise the following one).
+func intlRemoveFields(tokens []intlPatternToken, keep func(field byte) bool) []intlPatternToken {
+	removed := make([]bool, len(tokens))
+	for i, tok := range tokens {
+		if tok.field == 0 || keep(tok.field) {
+			continue
+		}
+		removed[i] = true
+		if i > 0 && tokens[i-1].field == 0 && !removed[i-1] {
+			removed[i-1] = true
+		} else if i+1 < len(tokens) && tokens[i+1].field == 0 {
+			removed[i+1] = true
+		}
+	}
+	res := make([]intlPatternToken, 0, len(tokens))
+	for i, tok := range tokens {
+		if !removed[i] {
+			res = append(res, tok)
+		}
+	}
+	return res
+}
+
+func intlHasField(tokens []intlPatternToken, fields string) bool {
+	for _, tok := range tokens {
+		if tok.field != 0 && strings.IndexByte(fields, tok.field) >= 0 {
+			return true
+		}
+	}
+	return false
+}
+
+func intlSetFieldCount(tokens []intlPatternToken, fields string, f func(count int) int) {
+	for i := range tokens {
+		if tok := &tokens[i]; tok.field != 0 && strings.IndexByte(fields, tok.field) >= 0 {
+			tok.count = f(tok.count)
+		}
+	}
+}
+
+func intlTextWidthCount(width string) int {
+	switch width {
+	case "long":
+		return 4
+	case "narrow":
+		return 5
+	}
+	return 3
+}
+
+func intlHourField(hourCycle string) byte {
+	switch hourCycle {
+	case "h11":
+		return 'K'
+	case "h12":
+		return 'h'
+	case "h24":
+		return 'k'
+	}
+	return 'H'
+}
+
+func intlIs12HourCycle(hourCycle string) bool {
+	return hourCycle == "h11" || hourCycle == "h12"
+}
+
+// intlJoinDateTime combines the date and time patterns using a glue pattern such as "{1}, {0}".
+func intlJoinDateTime(glue string, date, tm []intlPatternToken) []intlPatternToken {
+	var res []intlPatternToken
+	for len(glue) > 0 {
+		idx := strings.IndexByte(glue, '{')
+		if idx < 0 || idx+2 >= len(glue) {
+			res = append(res, parseIntlPattern(glue)...)
+			break
+		}
+		res = append(res, parseIntlPattern(glue[:idx])...)
+		switch glue[idx+1] {
+		case '0':
+			res = append(res, tm...)
+		case '1':
+			res = append(res, date...)
+		}
+		glue = glue[idx+3:]
+	}
+	return res
+}
+
+func getIntlLocalTimeZone() string {
+	intlLocalTimeZoneOnce.Do(func() {
+		name := time.Local.String()
+		if name == "Local" {
+			name = ""
+			if target, err := os.Readlink("/etc/localtime"); err == nil {
+				if idx := strings.Index(target, "zoneinfo/"); idx >= 0 {
+					name = target[idx+len("zoneinfo/"):]
+				}
+			}
+		}
+		if name == "" || strings.EqualFold(name, "UTC") || strings.EqualFold(name, "Etc/UTC") {
+			name = "UTC"
+		}
+		intlLocalTimeZone = name
+	})
+	return intlLocalTimeZone
+}
+
+func (r *Runtime) intlTimeZone(tz string) (string, *time.Location) {
+	switch strings.ToUpper(tz) {
+	case "UTC", "ETC/UTC", "ETC/GMT", "GMT":
+		return "UTC", time.UTC
+	}
+	if len(tz) == 6 && (tz[0] == '+' || tz[0] == '-') && tz[3] == ':' {
+		h, err1 := strconv.Atoi(tz[1:3])
+		m, err2 := strconv.Atoi(tz[4:])
+		if err1 == nil && err2 == nil && h < 24 && m < 60 {
+			offset := h*3600 + m*60
+			if tz[0] == '-' {
+				offset = -offset
+			}
+			return tz, time.FixedZone(tz, offset)
+		}
+	}
+	if tz == "" || strings.EqualFold(tz, "Local") {
+		panic(r.newError(r.getRangeError(), "Invalid time zone specified: %s", tz))
+	}
+	// IANA time zone names are case-insensitive, but the database lookup is not (except on case-insensitive
+	// file systems), so the name is converted to its canonical spelling first.
+	name := tz
+	if n, exists := getIntlTimeZoneNames()[strings.ToUpper(tz)]; exists {
+		name = n
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		// the system database is not available, try the usual capitalisation (e.g. America/New_York)
+		name = intlTitleTimeZone(tz)
+		loc, err = time.LoadLocation(name)
+		if err != nil {
+			panic(r.newError(r.getRangeError(), "Invalid time zone specified: %s", tz))
+		}
+	}
+	return name, loc
+}
+
+// getIntlTimeZoneNames returns the names found in the system time zone database (in the same locations
+// time.LoadLocation() uses) indexed by their upper case form.
+func getIntlTimeZoneNames() map[string]string {
+	intlTimeZoneNamesOnce.Do(func() {
+		names := make(map[string]string)
+		dirs := []string{"/usr/share/zoneinfo", "/usr/share/lib/zoneinfo", "/usr/lib/locale/TZ"}
+		if dir := os.Getenv("ZONEINFO"); dir != "" {
+			dirs = append([]string{dir}, dirs...)
+		}
+		for _, dir := range dirs {
+			_ = filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+				if err != nil {
+					return nil
+				}
+				rel, err := filepath.Rel(dir, path)
+				if err != nil {
+					return nil
+				}
+				rel = filepath.ToSlash(rel)
+				if d.IsDir() {
+					if rel == "posix" || rel == "right" {
+						return filepath.SkipDir
+					}
+					return nil
+				}
+				key := strings.ToUpper(rel)
+				if _, exists := names[key]; !exists {
+					names[key] = rel
+				}
+				return nil
+			})
+		}
+		intlTimeZoneNames = names
+	})
+	return intlTimeZoneNames
+}
+
+// intlTitleTimeZone capitalises each word of a time zone name.
+func intlTitleTimeZone(tz string) string {
+	b := []byte(strings.ToLower(tz))
+	start := true
+	for i, c := range b {
+		if start && c >= 'a' && c <= 'z' {
+			b[i] = c - 'a' + 'A'
+		}
+		start = c == '/' || c == '_' || c == '-'
+	}
+	return string(b)
+}
+
+// newIntlDateTimeFormat implements the CreateDateTimeFormat abstract operation
+// (https://tc39.es/ecma402/#sec-createdatetimeformat). required is one of "date", "time" or "any",
+// defaults is one of "date", "time" or "all".
+func (r *Runtime) newIntlDateTimeFormat(locales, options Value, required, defaults string) *intlDateTimeFormat {
+	locale, requested, data := r.intlResolveLocale(locales)
+	opts := r.intlCoerceOptions(options)
+	f := &intlDateTimeFormat{
+		locale: locale,
+		data:   data,
+	}
+
+	r.intlGetOption(opts, "localeMatcher", []string{"lookup", "best fit"}, "best fit")
+	r.intlGetOption(opts, "calendar", nil, "")
+	r.intlGetOption(opts, "numberingSystem", nil, "")
+	hour12, hour12Set := r.intlGetBoolOption(opts, "hour12")
+	hourCycle := r.intlGetOption(opts, "hourCycle", intlHourCycles, "")
+	if hour12Set {
+		hourCycle = ""
+	} else if hourCycle == "" {
+		if hc := requested.TypeForKey("hc"); hc != "" {
+			for _, allowed := range intlHourCycles {
+				if hc == allowed {
+					hourCycle = hc
+				}
+			}
+		}
+	}
+
+	if tz := r.intlGetOption(opts, "timeZone", nil, ""); tz != "" {
+		f.timeZone, f.loc = r.intlTimeZone(tz)
+	} else {
+		f.timeZone, f.loc = getIntlLocalTimeZone(), time.Local
+	}
+
+	weekday := r.intlGetOption(opts, "weekday", intlTextWidths, "")
+	era := r.intlGetOption(opts, "era", intlTextWidths, "")
+	year := r.intlGetOption(opts, "year", intlNumericWidths, "")
+	month := r.intlGetOption(opts, "month", intlMonthWidths, "")
+	day := r.intlGetOption(opts, "day", intlNumericWidths, "")
+	f.dayPeriod = r.intlGetOption(opts, "dayPeriod", intlTextWidths, "")
+	hour := r.intlGetOption(opts, "hour", intlNumericWidths, "")
+	minute := r.intlGetOption(opts, "minute", intlNumericWidths, "")
+	second := r.intlGetOption(opts, "second", intlNumericWidths, "")
+	fractionalSecondDigits := r.intlGetNumberOption(opts, "fractionalSecondDigits", 1, 3, 0)
+	f.timeZoneName = r.intlGetOption(opts, "timeZoneName", intlTzNameWidths, "")
+	r.intlGetOption(opts, "formatMatcher", []string{"basic", "best fit"}, "best fit")
+	f.dateStyle = r.intlGetOption(opts, "dateStyle", intlDateStyles, "")
+	f.timeStyle = r.intlGetOption(opts, "timeStyle", intlDateStyles, "")
+
+	hasDate := weekday != "" || year != "" || month != "" || day != ""
+	hasTime := f.dayPeriod != "" || hour != "" || minute != "" || second != "" || fractionalSecondDigits != 0
+
+	if f.dateStyle != "" || f.timeStyle != "" {
+		if hasDate || hasTime || era != "" || f.timeZoneName != "" {
+			panic(r.NewTypeError("Can't set option %s when dateStyle or timeStyle is used", intlFirstSetOption(weekday, era, year, month, day, f.dayPeriod, hour, minute, second, fractionalSecondDigits, f.timeZoneName)))
+		}
+		if required == "date" && f.dateStyle == "" {
+			panic(r.NewTypeError("Invalid option : timeStyle"))
+		}
+		if required == "time" && f.timeStyle == "" {
+			panic(r.NewTypeError("Invalid option : dateStyle"))
+		}
+	} else {
+		needDefaults := true
+		if (required == "date" || required == "any") && hasDate {
+			needDefaults = false
+		}
+		if (required == "time" || required == "any") && hasTime {
+			needDefaults = false
+		}
+		if needDefaults && (defaults == "date" || defaults == "all") {
+			year, month, day = "numeric", "numeric", "numeric"
+		}
+		if needDefaults && (defaults == "time" || defaults == "all") {
+			hour, minute, second = "numeric", "numeric", "numeric"
+		}
+	}
+
+	if hour12Set {
+		if hour12 {
+			if strings.IndexByte(data.time12, 'K') >= 0 {
+				hourCycle = "h11"
+			} else {
+				hourCycle = "h12"
+			}
+		} else {
+			hourCycle = "h23"
+		}
+	} else if hourCycle == "" {
+		hourCycle = data.hourCycle
+	}
+
+	if f.dateStyle != "" || f.timeStyle != "" {
+		f.pattern = f.stylePattern(hourCycle)
+	} else {
+		f.pattern = f.componentsPattern(weekday, era, year, month, day, hour, minute, second, fractionalSecondDigits, hourCycle)
+	}
+	if intlHasField(f.pattern, "hHKk") {
+		for _, tok := range f.pattern {
+			switch tok.field {
+			case 'K':
+				f.hourCycle = "h11"
+			case 'h':
+				f.hourCycle = "h12"
+			case 'H':
+				f.hourCycle = "h23"
+			case 'k':
+				f.hourCycle = "h24"
+			}
+		}
+	}
+	return f
+}
+
+func intlFirstSetOption(weekday, era, year, month, day, dayPeriod, hour, minute, second string, fractionalSecondDigits int, timeZoneName string) string {
+	switch {
+	case weekday != "":
+		return "weekday"
+	case era != "":
+		return "era"
+	case year != "":
+		return "year"
+	case month != "":
+		return "month"
+	case day != "":
+		return "day"
+	case dayPeriod != "":
+		return "dayPeriod"
+	case hour != "":
+		return "hour"
+	case minute != "":
+		return "minute"
+	case second != "":
+		return "second"
+	case fractionalSecondDigits != 0:
+		return "fractionalSecondDigits"
+	}
+	return "timeZoneName"
+}
+
+func intlStyleIndex(style string) int {
+	for i, s := range intlDateStyles {
+		if s == style {
+			return i
+		}
+	}
+	return intlStyleMedium
+}
+
+func (f *intlDateTimeFormat) timeBasePattern(hourCycle string) []intlPatternToken {
+	var tokens []intlPatternToken
+	if intlIs12HourCycle(hourCycle) {
+		tokens = parseIntlPattern(f.data.time12)
+	} else {
+		tokens = parseIntlPattern(f.data.time24)
+	}
+	hourField := intlHourField(hourCycle)
+	for i := range tokens {
+		switch tokens[i].field {
+		case 'h', 'H', 'K', 'k':
+			tokens[i].field = hourField
+		}
+	}
+	return tokens
+}
+
+func (f *intlDateTimeFormat) stylePattern(hourCycle string) []intlPatternToken {
+	var date, tm []intlPatternToken
+	if f.dateStyle != "" {
+		date = parseIntlPattern(f.data.dateFormats[intlStyleIndex(f.dateStyle)])
+	}
+	if f.timeStyle != "" {
+		style := intlStyleIndex(f.timeStyle)
+		tm = parseIntlPattern(f.data.timeFormats[style])
+		if intlIs12HourCycle(hourCycle) != intlIs12HourCycle(f.data.hourCycle) {
+			tm = f.timeBasePattern(hourCycle)
+			switch style {
+			case intlStyleFull:
+				tm = append(tm, intlPatternToken{literal: " "}, intlPatternToken{field: 'z', count: 4})
+			case intlStyleLong:
+				tm = append(tm, intlPatternToken{literal: " "}, intlPatternToken{field: 'z', count: 1})
+			case intlStyleShort:
+				tm = intlRemoveFields(tm, func(field byte) bool {
+					return field != 's'
+				})
+			}
+		} else {
+			hourField := intlHourField(hourCycle)
+			for i := range tm {
+				switch tm[i].field {
+				case 'h', 'H', 'K', 'k':
+					tm[i].field = hourField
+				}
+			}
+		}
+	}
+	if date == nil {
+		return tm
+	}
+	if tm == nil {
+		return date
+	}
+	return intlJoinDateTime(f.data.dateTimeFormats[intlStyleIndex(f.dateStyle)], date, tm)
+}
+
+func (f *intlDateTimeFormat) datePattern(weekday, era, year, month, day string) []intlPatternToken {
+	textMonth := month == "narrow" || month == "short" || month == "long"
+	var key strings.Builder
+	if year != "" {
+		key.WriteByte('y')
+	}
+	monthKey := ""
+	if textMonth {
+		monthKey = "MMM"
+	} else if month != "" {
+		monthKey = "M"
+	}
+	key.WriteString(monthKey)
+	if weekday != "" {
+		key.WriteByte('E')
+	}
+	if day != "" {
+		key.WriteByte('d')
+	}
+	skeleton := key.String()
+	var tokens []intlPatternToken
+	if skeleton == "" {
+		if era == "" {
+			return nil
+		}
+		return []intlPatternToken{{field: 'G', count: 1}}
+	}
+	pattern, ok := "", false
+	if month == "long" {
+		pattern, ok = f.data.dateSkeletons[strings.Replace(skeleton, "MMM", "MMMM", 1)]
+	}
+	if !ok {
+		pattern, ok = f.data.dateSkeletons[skeleton]
+	}
+	if ok {
+		tokens = parseIntlPattern(pattern)
+	} else {
+		full := "yMEd"
+		if textMonth {
+			full = "yMMMEd"
+		}
+		tokens = intlRemoveFields(parseIntlPattern(f.data.dateSkeletons[full]), func(field byte) bool {
+			switch field {
+			case 'y':
+				return year != ""
+			case 'M', 'L':
+				return month != ""
+			case 'E', 'c':
+				return weekday != ""
+			case 'd':
+				return day != ""
+			}
+			return true
+		})
+	}
+	if era != "" && !intlHasField(tokens, "G") {
+		tokens = append(tokens, intlPatternToken{literal: " "}, intlPatternToken{field: 'G'})
+	}
+
+	intlSetFieldCount(tokens, "y", func(int) int {
+		if year == "2-digit" {
+			return 2
+		}
+		return 1
+	})
+	intlSetFieldCount(tokens, "ML", func(count int) int {
+		if count >= 3 {
+			if textMonth {
+				return intlTextWidthCount(month)
+			}
+			return count
+		}
+		if month == "2-digit" {
+			return 2
+		}
+		return count
+	})
+	intlSetFieldCount(tokens, "d", func(count int) int {
+		if day == "2-digit" {
+			return 2
+		}
+		return count
+	})
+	intlSetFieldCount(tokens, "Ec", func(int) int {
+		return intlTextWidthCount(weekday)
+	})
+	intlSetFieldCount(tokens, "G", func(int) int {
+		switch era {
+		case "long":
+			return 4
+		case "narrow":
+			return 5
+		}
+		return 1
+	})
+	return tokens
+}
+
+func (f *intlDateTimeFormat) timePattern(hour, minute, second string, fractionalSecondDigits int, hourCycle string) []intlPatternToken {
+	if hour == "" && minute == "" && second == "" && fractionalSecondDigits == 0 {
+		if f.dayPeriod != "" {
+			return []intlPatternToken{{field: 'B', count: intlTextWidthCount(f.dayPeriod)}}
+		}
+		return nil
+	}
+	if fractionalSecondDigits != 0 && second == "" && minute == "" && hour == "" {
+		second = "numeric"
+	}
+	tokens := intlRemoveFields(f.timeBasePattern(hourCycle), func(field byte) bool {
+		switch field {
+		case 'h', 'H', 'K', 'k', 'a':
+			return hour != ""
+		case 'm':
+			return minute != ""
+		case 's':
+			return second != "" || fractionalSecondDigits != 0
+		}
+		return true
+	})
+	intlSetFieldCount(tokens, "hHKk", func(count int) int {
+		if hour == "2-digit" {
+			return 2
+		}
+		return count
+	})
+	if !intlHasField(tokens, "hHKk") {
+		intlSetFieldCount(tokens, "ms", func(count int) int {
+			return 2
+		})
+	}
+	if f.dayPeriod != "" {
+		for i := range tokens {
+			if tokens[i].field == 'a' {
+				tokens[i].field = 'B'
+				tokens[i].count = intlTextWidthCount(f.dayPeriod)
+			}
+		}
+	}
+	if fractionalSecondDigits != 0 {
+		for i, tok := range tokens {
+			if tok.field == 's' {
+				decimal := f.data.decimal
+				if decimal == "" {
+					decimal = "."
+				}
+				tail := append([]intlPatternToken{{literal: decimal}, {field: 'S', count: fractionalSecondDigits}}, tokens[i+1:]...)
+				tokens = append(tokens[:i+1], tail...)
+				break
+			}
+		}
+	}
+	return tokens
+}
+
+func (f *intlDateTimeFormat) componentsPattern(weekday, era, year, month, day, hour, minute, second string, fractionalSecondDigits int, hourCycle string) []intlPatternToken {
+	date := f.datePattern(weekday, era, year, month, day)
+	tm := f.timePattern(hour, minute, second, fractionalSecondDigits, hourCycle)
+	if f.timeZoneName != "" {
+		var tzToken intlPatternToken
+		switch f.timeZoneName {
+		case "long":
+			tzToken = intlPatternToken{field: 'z', count: 4}
+		case "shortOffset":
+			tzToken = intlPatternToken{field: 'O', count: 1}
+		case "longOffset":
+			tzToken = intlPatternToken{field: 'O', count: 4}
+		case "shortGeneric":
+			tzToken = intlPatternToken{field: 'v', count: 1}
+		case "longGeneric":
+			tzToken = intlPatternToken{field: 'v', count: 4}
+		default:
+			tzToken = intlPatternToken{field: 'z', count: 1}
+		}
+		if tm == nil && date == nil {
+			tm = []intlPatternToken{tzToken}
+		} else if tm == nil {
+			date = append(date, intlPatternToken{literal: ", "}, tzToken)
+		} else {
+			tm = append(tm, intlPatternToken{literal: " "}, tzToken)
+		}
+	}
+	if date == nil {
+		return tm
+	}
+	if tm == nil {
+		return date
+	}
+	style := intlStyleMedium
+	if month == "long" {
+		style = intlStyleLong
+		if weekday == "long" {
+			style = intlStyleFull
+		}
+	}
+	return intlJoinDateTime(f.data.dateTimeFormats[style], date, tm)
+}
+
+func intlPad(n int64, count int) string {
+	s := strconv.FormatInt(n, 10)
+	for len(s) < count {
+		s = "0" + s
+	}
+	return s
+}
+
+func intlFormatOffset(offset int, long bool) string {
+	if offset == 0 && !long {
+		return "GMT"
+	}
+	sign := byte('+')
+	if offset < 0 {
+		sign = '-'
+		offset = -offset
+	}
+	h, m := offset/3600, offset/60%60
+	var b strings.Builder
+	b.WriteString("GMT")
+	b.WriteByte(sign)
+	if long {
+		b.WriteString(intlPad(int64(h), 2))
+		b.WriteByte(':')
+		b.WriteString(intlPad(int64(m), 2))
+	} else {
+		b.WriteString(strconv.Itoa(h))
+		if m != 0 {
+			b.WriteByte(':')
+			b.WriteString(intlPad(int64(m), 2))
+		}
+	}
+	return b.String()
+}
+
+func (f *intlDateTimeFormat) formatTimeZone(t time.Time, tok intlPatternToken) string {
+	_, offset := t.Zone()
+	utc := f.timeZone == "UTC"
+	switch tok.field {
+	case 'O':
+		if offset == 0 {
+			return "GMT"
+		}
+		return intlFormatOffset(offset, tok.count == 4)
+	case 'v':
+		if tok.count == 4 && !utc {
+			return f.timeZone
+		}
+	}
+	if utc {
+		if tok.count == 4 && f.data.tag == intlLocaleEn.tag {
+			return "Coordinated Universal Time"
+		}
+		return "UTC"
+	}
+	return intlFormatOffset(offset, tok.count == 4)
+}
+
+func (f *intlDateTimeFormat) dayPeriodName(t time.Time, tok intlPatternToken) string {
+	h := t.Hour()
+	if tok.field == 'B' && f.data == intlLocaleEn {
+		switch {
+		case h == 12 && t.Minute() == 0:
+			return "noon"
+		case h >= 6 && h < 12:
+			return "in the morning"
+		case h >= 12 && h < 18:
+			return "in the afternoon"
+		case h >= 18 && h < 21:
+			return "in the evening"
+		}
+		return "at night"
+	}
+	if h < 12 {
+		return f.data.dayPeriods[0]
+	}
+	return f.data.dayPeriods[1]
+}
+
+func (f *intlDateTimeFormat) formatToParts(msec int64) []intlPart {
+	t := timeFromMsec(msec).In(f.loc)
+	year := int64(t.Year())
+	era := 1
+	if year <= 0 {
+		era = 0
+		year = 1 - year
+	}
+	parts := make([]intlPart, 0, len(f.pattern))
+	for _, tok := range f.pattern {
+		var typ, value string
+		switch tok.field {
+		case 0:
+			typ, value = "literal", tok.literal
+		case 'G':
+			typ, value = "era", f.data.eraName(era, intlCountWidth(tok.count))
+		case 'y':
+			typ = "year"
+			if tok.count == 2 {
+				value = intlPad(year%100, 2)
+			} else {
+				value = intlPad(year, tok.count)
+			}
+		case 'M', 'L':
+			typ = "month"
+			if tok.count >= 3 {
+				value = f.data.monthName(int(t.Month())-1, intlCountWidth(tok.count), tok.field == 'L')
+			} else {
+				value = intlPad(int64(t.Month()), tok.count)
+			}
+		case 'd':
+			typ, value = "day", intlPad(int64(t.Day()), tok.count)
+		case 'E', 'c':
+			typ, value = "weekday", f.data.weekdayName(int(t.Weekday()), intlCountWidth(tok.count))
+		case 'a', 'B':
+			typ, value = "dayPeriod", f.dayPeriodName(t, tok)
+		case 'h':
+			h := t.Hour() % 12
+			if h == 0 {
+				h = 12
+			}
+			typ, value = "hour", intlPad(int64(h), tok.count)
+		case 'H':
+			typ, value = "hour", intlPad(int64(t.Hour()), tok.count)
+		case 'K':
+			typ, value = "hour", intlPad(int64(t.Hour()%12), tok.count)
+		case 'k':
+			h := t.Hour()
+			if h == 0 {
+				h = 24
+			}
+			typ, value = "hour", intlPad(int64(h), tok.count)
+		case 'm':
+			typ, value = "minute", intlPad(int64(t.Minute()), tok.count)
+		case 's':
+			typ, value = "second", intlPad(int64(t.Second()), tok.count)
+		case 'S':
+			ms := int64(t.Nanosecond() / 1e6)
+			typ, value = "fractionalSecond", intlPad(ms, 3)[:tok.count]
+		case 'z', 'O', 'v':
+			typ, value = "timeZoneName", f.formatTimeZone(t, tok)
+		default:
+			continue
+		}
+		if typ == "literal" && len(parts) > 0 && parts[len(parts)-1].typ == "literal" {
+			parts[len(parts)-1].value += value
+			continue
+		}
+		parts = append(parts, intlPart{typ: typ, value: value})
+	}
+	return parts
+}
+
+func intlCountWidth(count int) int {
+	switch count {
+	case 4:
+		return intlWidthLong
+	case 5:
+		return intlWidthNarrow
+	}
+	return intlWidthShort
+}
+
+func (f *intlDateTimeFormat) format(msec int64) string {
+	var b strings.Builder
+	for _, part := range f.formatToParts(msec) {
+		b.WriteString(part.value)
+	}
+	return b.String()
+}
+
+func (r *Runtime) intlDateValue(v Value) int64 {
+	if v == nil || v == _undefined {
+		return timeToMsec(r.now())
+	}
+	if o, ok := v.(*Object); ok {
+		if d, ok := o.self.(*dateObject); ok {
+			if !d.isSet() {
+				panic(r.newError(r.getRangeError(), "Invalid time value"))
+			}
+			return d.msec
+		}
+	}
+	f := v.ToFloat()
+	if math.IsNaN(f) || math.IsInf(f, 0) || math.Abs(f) > maxTime {
+		panic(r.newError(r.getRangeError(), "Invalid time value"))
+	}
+	return int64(f)
+}
+
+func (r *Runtime) toDateTimeFormat(v Value, method string) *dateTimeFormatObject {
+	if o, ok := v.(*Object); ok {
+		if dtf, ok := o.self.(*dateTimeFormatObject); ok {
+			return dtf
+		}
+	}
+	panic(r.NewTypeError("Method Intl.DateTimeFormat.prototype.%s called on incompatible receiver %s", method, r.objectproto_toString(FunctionCall{This: v})))
+}
+
+func (r *Runtime) builtin_newDateTimeFormat(args []Value, newTarget *Object) *Object {
+	var locales, options Value = _undefined, _undefined
+	if len(args) > 0 {
+		locales = args[0]
+	}
+	if len(args) > 1 {
+		options = args[1]
+	}
+	proto := r.getDateTimeFormatPrototype()
+	if newTarget != nil {
+		proto = r.getPrototypeFromCtor(newTarget, r.getDateTimeFormat(), proto)
+	}
+	o := &Object{runtime: r}
+	dtf := &dateTimeFormatObject{
+		f: r.newIntlDateTimeFormat(locales, options, "any", "date"),
+	}
+	dtf.class = classObject
+	dtf.val = o
+	dtf.extensible = true
+	o.self = dtf
+	dtf.prototype = proto
+	dtf.init()
+	return o
+}
+
+func (r *Runtime) dateTimeFormatProto_getFormat(call FunctionCall) Value {
+	dtf := r.toDateTimeFormat(call.This, "format")
+	if dtf.boundFormat == nil {
+		f := dtf.f
+		dtf.boundFormat = r.newNativeFunc(func(call FunctionCall) Value {
+			return newStringValue(f.format(r.intlDateValue(call.Argument(0))))
+		}, "", 1)
+	}
+	return dtf.boundFormat
+}
+
+func (r *Runtime) dateTimeFormatProto_formatToParts(call FunctionCall) Value {
+	dtf := r.toDateTimeFormat(call.This, "formatToParts")
+	return r.intlPartsToArray(dtf.f.formatToParts(r.intlDateValue(call.Argument(0))))
+}
+
+func (r *Runtime) dateTimeFormatProto_resolvedOptions(call FunctionCall) Value {
+	f := r.toDateTimeFormat(call.This, "resolvedOptions").f
+	res := r.NewObject()
+	put := func(name unistring.String, value string) {
+		if value != "" {
+			res.self._putProp(name, newStringValue(value), true, true, true)
+		}
+	}
+	put("locale", f.locale.String())
+	put("calendar", "gregory")
+	put("numberingSystem", "latn")
+	put("timeZone", f.timeZone)
+	if f.hourCycle != "" {
+		put("hourCycle", f.hourCycle)
+		res.self._putProp("hour12", r.toBoolean(intlIs12HourCycle(f.hourCycle)), true, true, true)
+	}
+	if f.dateStyle == "" && f.timeStyle == "" {
+		widths := map[byte]string{}
+		fsd := 0
+		for _, tok := range f.pattern {
+			switch tok.field {
+			case 0:
+			case 'E', 'c', 'G':
+				widths[tok.field] = intlTextWidths[2-intlCountWidth(tok.count)]
+			case 'M', 'L':
+				if tok.count >= 3 {
+					widths['M'] = intlTextWidths[2-intlCountWidth(tok.count)]
+					break
+				}
+				fallthrough
+			default:
+				if tok.count == 2 {
+					widths[tok.field] = "2-digit"
+				} else {
+					widths[tok.field] = "numeric"
+				}
+			}
+			if tok.field == 'S' {
+				fsd = tok.count
+			}
+			if tok.field == 'h' || tok.field == 'H' || tok.field == 'K' || tok.field == 'k' {
+				widths['h'] = widths[tok.field]
+			}
+			if tok.field == 'L' {
+				widths['M'] = widths['L']
+			}
+		}
+		put("weekday", widths['E']+widths['c'])
+		put("era", widths['G'])
+		put("year", widths['y'])
+		put("month", widths['M'])
+		put("day", widths['d'])
+		if intlHasField(f.pattern, "B") {
+			put("dayPeriod", f.dayPeriod)
+		}
+		put("hour", widths['h'])
+		put("minute", widths['m'])
+		put("second", widths['s'])
+		if fsd != 0 {
+			res.self._putProp("fractionalSecondDigits", intToValue(int64(fsd)), true, true, true)
+		}
+		put("timeZoneName", f.timeZoneName)
+	}
+	put("dateStyle", f.dateStyle)
+	put("timeStyle", f.timeStyle)
+	return res
+}
+
+func (r *Runtime) dateTimeFormat_supportedLocalesOf(call FunctionCall) Value {
+	return r.intlSupportedLocales(call.Argument(0), call.Argument(1), intlMatchLocale)
+}
+
+func createDateTimeFormatProtoTemplate() *objectTemplate {
+	t := newObjectTemplate()
+	t.protoFactory = func(r *Runtime) *Object {
+		return r.global.ObjectPrototype
+	}
+
+	t.putStr("constructor", func(r *Runtime) Value { return valueProp(r.getDateTimeFormat(), true, false, true) })
+	t.putStr("format", func(r *Runtime) Value {
+		return &valueProperty{
+			getterFunc:   r.newNativeFunc(r.dateTimeFormatProto_getFormat, "get format", 0),
+			accessor:     true,
+			configurable: true,
+		}
+	})
+	t.putStr("formatToParts", func(r *Runtime) Value {
+		return r.methodProp(r.dateTimeFormatProto_formatToParts, "formatToParts", 1)
+	})
+	t.putStr("resolvedOptions", func(r *Runtime) Value {
+		return r.methodProp(r.dateTimeFormatProto_resolvedOptions, "resolvedOptions", 0)
+	})
+
+	t.putSym(SymToStringTag, func(r *Runtime) Value {
+		return valueProp(asciiString("Intl.DateTimeFormat"), false, false, true)
+	})
+
+	return t
+}
+
+var dateTimeFormatProtoTemplate *objectTemplate
+var dateTimeFormatProtoTemplateOnce sync.Once
+
+func getDateTimeFormatProtoTemplate() *objectTemplate {
+	dateTimeFormatProtoTemplateOnce.Do(func() {
+		dateTimeFormatProtoTemplate = createDateTimeFormatProtoTemplate()
+	})
+	return dateTimeFormatProtoTemplate
+}
+
+func (r *Runtime) getDateTimeFormatPrototype() *Object {
+	ret := r.global.DateTimeFormatPrototype
+	if ret == nil {
+		ret = &Object{runtime: r}
+		r.global.DateTimeFormatPrototype = ret
+		r.newTemplatedObject(getDateTimeFormatProtoTemplate(), ret)
+	}
+	return ret
+}
+
+func createDateTimeFormatTemplate() *objectTemplate {
+	t := newObjectTemplate()
+	t.protoFactory = func(r *Runtime) *Object {
+		return r.getFunctionPrototype()
+	}
+
+	t.putStr("name", func(r *Runtime) Value { return valueProp(asciiString("DateTimeFormat"), false, false, true) })
+	t.putStr("length", func(r *Runtime) Value { return valueProp(intToValue(0), false, false, true) })
+
+	t.putStr("prototype", func(r *Runtime) Value { return valueProp(r.getDateTimeFormatPrototype(), false, false, false) })
+
+	t.putStr("supportedLocalesOf", func(r *Runtime) Value {
+		return r.methodProp(r.dateTimeFormat_supportedLocalesOf, "supportedLocalesOf", 1)
+	})
+
+	return t
+}
+
+var dateTimeFormatTemplate *objectTemplate
+var dateTimeFormatTemplateOnce sync.Once
+
+func getDateTimeFormatTemplate() *objectTemplate {
+	dateTimeFormatTemplateOnce.Do(func() {
+		dateTimeFormatTemplate = createDateTimeFormatTemplate()
+	})
+	return dateTimeFormatTemplate
+}
+
+func (r *Runtime) getDateTimeFormat() *Object {
+	ret := r.global.DateTimeFormat
+	if ret == nil {
+		ret = &Object{runtime: r}
+		r.global.DateTimeFormat = ret
+		r.newTemplatedFuncObject(getDateTimeFormatTemplate(), ret, func(call FunctionCall) Value {
+			return r.builtin_newDateTimeFormat(call.Arguments, nil)
+		}, r.builtin_newDateTimeFormat)
+	}
+	return ret
+}
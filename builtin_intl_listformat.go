@@ -0,0 +1,273 @@
+package goja
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/dop251/goja/unistring"
+	"golang.org/x/text/language"
+)
+
+var intlListTypes = map[string]int{
+	"conjunction": intlListConjunction,
+	"disjunction": intlListDisjunction,
+	"unit":        intlListUnit,
+}
+
+var intlListStyles = map[string]int{
+	"long":   intlWidthLong,
+	"short":  intlWidthShort,
+	"narrow": intlWidthNarrow,
+}
+
+// intlListFormat contains the resolved state of an Intl.ListFormat.
+type intlListFormat struct {
+	locale      language.Tag
+	typ, style  string
+	listPattern *intlListPattern
+	contextual  func(literal, next string) string
+}
+
+type listFormatObject struct {
+	baseObject
+	f *intlListFormat
+}
+
+// newIntlListFormat implements the Intl.ListFormat constructor steps
+// (https://tc39.es/ecma402/#sec-Intl.ListFormat).
+func (r *Runtime) newIntlListFormat(locales, options Value) *intlListFormat {
+	locale, _, data := r.intlResolveLocale(locales)
+	opts := r.intlCoerceOptions(options)
+	r.intlGetOption(opts, "localeMatcher", []string{"lookup", "best fit"}, "best fit")
+	f := &intlListFormat{
+		locale: locale,
+	}
+	f.typ = r.intlGetOption(opts, "type", []string{"conjunction", "disjunction", "unit"}, "conjunction")
+	f.style = r.intlGetOption(opts, "style", []string{"long", "short", "narrow"}, "long")
+	f.listPattern = &data.listPatterns[intlListTypes[f.typ]][intlListStyles[f.style]]
+	if f.typ != "unit" {
+		f.contextual = data.listContextual
+	}
+	return f
+}
+
+// intlApplyListPattern substitutes the "{0}" and "{1}" placeholders in pattern with first and second.
+func intlApplyListPattern(pattern string, first, second []intlPart) []intlPart {
+	parts := make([]intlPart, 0, len(first)+len(second)+3)
+	for pattern != "" {
+		idx := strings.IndexByte(pattern, '{')
+		if idx < 0 || idx+3 > len(pattern) {
+			parts = append(parts, intlPart{typ: "literal", value: pattern})
+			break
+		}
+		if idx > 0 {
+			parts = append(parts, intlPart{typ: "literal", value: pattern[:idx]})
+		}
+		if pattern[idx+1] == '0' {
+			parts = append(parts, first...)
+		} else {
+			parts = append(parts, second...)
+		}
+		pattern = pattern[idx+3:]
+	}
+	return parts
+}
+
+// formatToParts implements the CreatePartsFromList abstract operation
+// (https://tc39.es/ecma402/#sec-createpartsfromlist).
+func (f *intlListFormat) formatToParts(list []string) []intlPart {
+	n := len(list)
+	if n == 0 {
+		return nil
+	}
+	element := func(i int) []intlPart {
+		return []intlPart{{typ: "element", value: list[i]}}
+	}
+	if n == 1 {
+		return element(0)
+	}
+	p := f.listPattern
+	last := func(pattern string) []intlPart {
+		parts := intlApplyListPattern(pattern, element(n-2), element(n-1))
+		if f.contextual != nil {
+			for i := range parts {
+				if parts[i].typ == "literal" {
+					parts[i].value = f.contextual(parts[i].value, list[n-1])
+				}
+			}
+		}
+		return parts
+	}
+	if n == 2 {
+		return last(p.pair)
+	}
+	parts := last(p.end)
+	for i := n - 3; i > 0; i-- {
+		parts = intlApplyListPattern(p.middle, element(i), parts)
+	}
+	return intlApplyListPattern(p.start, element(0), parts)
+}
+
+// intlStringListFromIterable implements the StringListFromIterable abstract operation
+// (https://tc39.es/ecma402/#sec-createstringlistfromiterable).
+func (r *Runtime) intlStringListFromIterable(iterable Value) []string {
+	if iterable == _undefined {
+		return nil
+	}
+	var list []string
+	r.getIterator(iterable, nil).iterate(func(item Value) {
+		s, ok := item.(String)
+		if !ok {
+			panic(r.NewTypeError("Iterable yielded %s which is not a string", item.String()))
+		}
+		list = append(list, s.String())
+	})
+	return list
+}
+
+func (r *Runtime) toListFormat(v Value, method string) *listFormatObject {
+	if o, ok := v.(*Object); ok {
+		if f, ok := o.self.(*listFormatObject); ok {
+			return f
+		}
+	}
+	panic(r.NewTypeError("Method Intl.ListFormat.prototype.%s called on incompatible receiver %s", method, r.objectproto_toString(FunctionCall{This: v})))
+}
+
+func (r *Runtime) builtin_newListFormat(args []Value, newTarget *Object) *Object {
+	if newTarget == nil {
+		panic(r.needNew("Intl.ListFormat"))
+	}
+	var locales, options Value = _undefined, _undefined
+	if len(args) > 0 {
+		locales = args[0]
+	}
+	if len(args) > 1 {
+		options = args[1]
+	}
+	proto := r.getPrototypeFromCtor(newTarget, r.getListFormat(), r.getListFormatPrototype())
+	o := &Object{runtime: r}
+	f := &listFormatObject{
+		f: r.newIntlListFormat(locales, options),
+	}
+	f.class = classObject
+	f.val = o
+	f.extensible = true
+	o.self = f
+	f.prototype = proto
+	f.init()
+	return o
+}
+
+func (r *Runtime) listFormatProto_format(call FunctionCall) Value {
+	f := r.toListFormat(call.This, "format").f
+	return newStringValue(intlPartsString(f.formatToParts(r.intlStringListFromIterable(call.Argument(0)))))
+}
+
+func (r *Runtime) listFormatProto_formatToParts(call FunctionCall) Value {
+	f := r.toListFormat(call.This, "formatToParts").f
+	return r.intlPartsToArray(f.formatToParts(r.intlStringListFromIterable(call.Argument(0))))
+}
+
+func (r *Runtime) listFormatProto_resolvedOptions(call FunctionCall) Value {
+	f := r.toListFormat(call.This, "resolvedOptions").f
+	res := r.NewObject()
+	put := func(name unistring.String, value Value) {
+		res.self._putProp(name, value, true, true, true)
+	}
+	put("locale", newStringValue(f.locale.String()))
+	put("type", asciiString(f.typ))
+	put("style", asciiString(f.style))
+	return res
+}
+
+func (r *Runtime) listFormat_supportedLocalesOf(call FunctionCall) Value {
+	return r.intlSupportedLocales(call.Argument(0), call.Argument(1), intlMatchLocale)
+}
+
+func createListFormatProtoTemplate() *objectTemplate {
+	t := newObjectTemplate()
+	t.protoFactory = func(r *Runtime) *Object {
+		return r.global.ObjectPrototype
+	}
+
+	t.putStr("constructor", func(r *Runtime) Value { return valueProp(r.getListFormat(), true, false, true) })
+	t.putStr("format", func(r *Runtime) Value { return r.methodProp(r.listFormatProto_format, "format", 1) })
+	t.putStr("formatToParts", func(r *Runtime) Value {
+		return r.methodProp(r.listFormatProto_formatToParts, "formatToParts", 1)
+	})
+	t.putStr("resolvedOptions", func(r *Runtime) Value {
+		return r.methodProp(r.listFormatProto_resolvedOptions, "resolvedOptions", 0)
+	})
+
+	t.putSym(SymToStringTag, func(r *Runtime) Value {
+		return valueProp(asciiString("Intl.ListFormat"), false, false, true)
+	})
+
+	return t
+}
+
+var listFormatProtoTemplate *objectTemplate
+var listFormatProtoTemplateOnce sync.Once
+
+func getListFormatProtoTemplate() *objectTemplate {
+	listFormatProtoTemplateOnce.Do(func() {
+		listFormatProtoTemplate = createListFormatProtoTemplate()
+	})
+	return listFormatProtoTemplate
+}
+
+func (r *Runtime) getListFormatPrototype() *Object {
+	ret := r.global.ListFormatPrototype
+	if ret == nil {
+		ret = &Object{runtime: r}
+		r.global.ListFormatPrototype = ret
+		r.newTemplatedObject(getListFormatProtoTemplate(), ret)
+	}
+	return ret
+}
+
+func createListFormatTemplate() *objectTemplate {
+	t := newObjectTemplate()
+	t.protoFactory = func(r *Runtime) *Object {
+		return r.getFunctionPrototype()
+	}
+
+	t.putStr("name", func(r *Runtime) Value { return valueProp(asciiString("ListFormat"), false, false, true) })
+	t.putStr("length", func(r *Runtime) Value { return valueProp(intToValue(0), false, false, true) })
+
+	t.putStr("prototype", func(r *Runtime) Value { return valueProp(r.getListFormatPrototype(), false, false, false) })
+
+	t.putStr("supportedLocalesOf", func(r *Runtime) Value {
+		return r.methodProp(r.listFormat_supportedLocalesOf, "supportedLocalesOf", 1)
+	})
+
+	return t
+}
+
+var listFormatTemplate *objectTemplate
+var listFormatTemplateOnce sync.Once
+
+func getListFormatTemplate() *objectTemplate {
+	listFormatTemplateOnce.Do(func() {
+		listFormatTemplate = createListFormatTemplate()
+	})
+	return listFormatTemplate
+}
+
+func (r *Runtime) getListFormat() *Object {
+	ret := r.global.ListFormat
+	if ret == nil {
+		ret = &Object{runtime: r}
+		r.global.ListFormat = ret
+		r.newTemplatedFuncObject(getListFormatTemplate(), ret, func(call FunctionCall) Value {
+			return r.builtin_newListFormat(call.Arguments, nil)
+		}, func(args []Value, newTarget *Object) *Object {
+			if newTarget == nil {
+				newTarget = ret
+			}
+			return r.builtin_newListFormat(args, newTarget)
+		})
+	}
+	return ret
+}
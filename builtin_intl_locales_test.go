@@ -0,0 +1,123 @@
+//go:build !goja_intl_minimal
+// +build !goja_intl_minimal
+
+package goja
+
+import (
+	"testing"
+)
+
+// The tests for the locales that are left out when building with the goja_intl_minimal tag.
+
+func TestIntlDateTimeFormatLocales(t *testing.T) {
+	const SCRIPT = `
+	var d = new Date(Date.UTC(2006, 0, 2, 15, 4, 5, 123));
+
+	assert.sameValue(new Intl.DateTimeFormat("de", {timeZone: "UTC", dateStyle: "full", timeStyle: "short"}).format(d), "Montag, 2. Januar 2006 um 15:04");
+	assert.sameValue(new Intl.DateTimeFormat("fr", {timeZone: "UTC", weekday: "long", year: "numeric", month: "long", day: "numeric"}).format(d), "lundi 2 janvier 2006");
+	assert.sameValue(new Intl.DateTimeFormat("es", {timeZone: "UTC", year: "numeric", month: "long", day: "numeric"}).format(d), "2 de enero de 2006");
+	assert.sameValue(new Intl.DateTimeFormat("ru", {timeZone: "UTC", month: "long"}).format(d), "январь");
+	var opts = new Intl.DateTimeFormat("de-AT", {dateStyle: "short"}).resolvedOptions();
+	assert.sameValue(opts.locale, "de-AT");
+	assert.sameValue(opts.dateStyle, "short");
+	assert.sameValue(opts.month, undefined);
+
+	assert.sameValue(Intl.DateTimeFormat.supportedLocalesOf(["de-AT", "xx", "zh-Hans-CN"]).join(), "de-AT,zh-Hans-CN");
+	`
+	testScriptWithTestLib(SCRIPT, _undefined, t)
+}
+
+func TestDateToLocaleStringLocales(t *testing.T) {
+	const SCRIPT = `
+	var d = new Date(Date.UTC(2006, 0, 2, 15, 4, 5));
+	var utc = {timeZone: "UTC"};
+
+	assert.sameValue(d.toLocaleString("de", utc), "2.1.2006, 15:04:05");
+	assert.sameValue(d.toLocaleString("ja", utc), "2006/1/2 15:04:05");
+	`
+	testScriptWithTestLib(SCRIPT, _undefined, t)
+}
+
+func TestIntlNumberFormatLocales(t *testing.T) {
+	const SCRIPT = `
+	function fmt(n, locale, opts) {
+		return new Intl.NumberFormat(locale, opts).format(n);
+	}
+
+	assert.sameValue(fmt(1234567.891, "de"), "1.234.567,891");
+	assert.sameValue(fmt(1234, "es"), "1234", "minimum grouping digits");
+	assert.sameValue(fmt(12345, "es"), "12.345");
+	assert.sameValue(fmt(1234.5, "de-DE", {style: "currency", currency: "EUR"}), "1.234,50\u00a0€");
+	assert.sameValue(fmt(1234.5, "ja", {style: "currency", currency: "JPY"}), "￥1,235");
+	assert.sameValue(fmt(0.256, "de", {style: "percent", minimumFractionDigits: 1}), "25,6\u00a0%");
+	assert.sameValue(fmt(123456789, "ja", {notation: "compact"}), "1.2億");
+	var parts = new Intl.NumberFormat("de", {style: "currency", currency: "EUR"}).formatToParts(-1234.5);
+	assert.sameValue(parts.map(function(p) { return p.type; }).join(), "minusSign,integer,group,integer,decimal,fraction,literal,currency");
+
+	assert.sameValue((1234.5).toLocaleString("de-DE", {style: "currency", currency: "EUR"}), "1.234,50\u00a0€");
+	assert.sameValue([1234.5, 2].toLocaleString("de", {style: "currency", currency: "EUR"}), "1.234,50\u00a0€,2,00\u00a0€");
+	assert.sameValue(new Float64Array([1234.5]).toLocaleString("de"), "1.234,5");
+	`
+	testScriptWithTestLib(SCRIPT, _undefined, t)
+}
+
+func TestIntlPluralRulesLocales(t *testing.T) {
+	const SCRIPT = `
+	var ru = new Intl.PluralRules("ru");
+	assert.sameValue([1, 2, 5, 21, 1.5].map(function(n) { return ru.select(n); }).join(), "one,few,many,one,other");
+
+	var opts = ru.resolvedOptions();
+	assert.sameValue(opts.locale, "ru");
+	assert.sameValue(opts.type, "cardinal");
+	assert.sameValue(opts.pluralCategories.join(), "one,few,many,other");
+	assert.sameValue(opts.maximumFractionDigits, 3);
+	assert.sameValue(new Intl.PluralRules("ja").resolvedOptions().pluralCategories.join(), "other");
+
+	var fr = new Intl.PluralRules("fr");
+	assert.sameValue([0, 1, 1.5, 2, 1000000, 2000000, 1000001].map(function(n) { return fr.select(n); }).join(), "one,one,one,other,many,many,other");
+	assert.sameValue(fr.resolvedOptions().pluralCategories.join(), "one,many,other");
+	assert.sameValue(new Intl.PluralRules("es").select(1000000), "many");
+	assert.sameValue(new Intl.PluralRules("en").select(1000000), "other");
+	`
+	testScriptWithTestLib(SCRIPT, _undefined, t)
+}
+
+func TestIntlRelativeTimeFormatLocales(t *testing.T) {
+	const SCRIPT = `
+	assert.sameValue(new Intl.RelativeTimeFormat("de").format(-3, "day"), "vor 3 Tagen");
+	assert.sameValue(new Intl.RelativeTimeFormat("de", {numeric: "auto"}).format(1, "day"), "morgen");
+	assert.sameValue(new Intl.RelativeTimeFormat("ru").format(5, "day"), "через 5 дней");
+	assert.sameValue(new Intl.RelativeTimeFormat("ru").format(-2, "year"), "2 года назад");
+	assert.sameValue(new Intl.RelativeTimeFormat("ja").format(3, "day"), "3 日後");
+	assert.sameValue(new Intl.RelativeTimeFormat("fr").format(1000000, "day"), "dans 1\u00a0000\u00a0000 jours");
+	`
+	testScriptWithTestLib(SCRIPT, _undefined, t)
+}
+
+func TestIntlListFormatLocales(t *testing.T) {
+	const SCRIPT = `
+	var list = ["a", "b", "c"];
+	assert.sameValue(new Intl.ListFormat("de").format(list), "a, b und c");
+	assert.sameValue(new Intl.ListFormat("ja").format(list), "a、b、c");
+
+	var es = new Intl.ListFormat("es");
+	assert.sameValue(es.format(["a", "b", "interior"]), "a, b e interior");
+	assert.sameValue(es.format(["padre", "hijo"]), "padre e hijo");
+	assert.sameValue(es.format(["agua", "hielo"]), "agua y hielo");
+	assert.sameValue(es.format(["a", "b"]), "a y b");
+	assert.sameValue(new Intl.ListFormat("es", {type: "disjunction"}).format(["siete", "ocho"]), "siete u ocho");
+	assert.sameValue(new Intl.ListFormat("es", {type: "disjunction"}).format(["10", "11"]), "10 u 11");
+	assert.sameValue(new Intl.ListFormat("es", {type: "disjunction"}).format(["a", "b"]), "a o b");
+	assert.sameValue(new Intl.ListFormat("es").formatToParts(["a", "i"])[1].value, " e ");
+	`
+	testScriptWithTestLib(SCRIPT, _undefined, t)
+}
+
+func TestIntlSegmenterLocales(t *testing.T) {
+	const SCRIPT = `
+	var opts = new Intl.Segmenter("de", {granularity: "sentence"}).resolvedOptions();
+	assert.sameValue(opts.locale, "de");
+	assert.sameValue(opts.granularity, "sentence");
+	`
+	testScriptWithTestLib(SCRIPT, _undefined, t)
+}
@@ -0,0 +1,31 @@
+//go:build goja_intl_minimal
+// +build goja_intl_minimal
+
+package goja
+
+import (
+	"testing"
+)
+
+func TestIntlMinimalFallback(t *testing.T) {
+	const SCRIPT = `
+	var d = new Date(Date.UTC(2006, 0, 2, 15, 4, 5));
+	var utc = {timeZone: "UTC"};
+
+	// Only English is bundled, the other locales fall back to the default one.
+	assert.sameValue(new Intl.DateTimeFormat("de", utc).format(d), "1/2/2006");
+	assert.sameValue(new Intl.DateTimeFormat("de", utc).resolvedOptions().locale, "en-US");
+	assert.sameValue(new Intl.DateTimeFormat("en-GB", utc).format(d), "02/01/2006");
+	assert.sameValue(d.toLocaleString("ja", utc), "1/2/2006, 3:04:05 PM");
+	assert.sameValue(new Intl.NumberFormat("de").format(1234.5), "1,234.5");
+	assert.sameValue(new Intl.ListFormat("fr").format(["a", "b"]), "a and b");
+	assert.sameValue(new Intl.RelativeTimeFormat("ru").format(-2, "year"), "2 years ago");
+	assert.sameValue(new Intl.PluralRules("ru").resolvedOptions().locale, "en-US");
+	assert.sameValue(new Intl.Segmenter("de").resolvedOptions().locale, "en-US");
+	assert.sameValue(Intl.DateTimeFormat.supportedLocalesOf(["de", "en-GB", "fr"]).join(), "en-GB");
+
+	// The collation does not depend on the bundled data.
+	assert.sameValue(new Intl.Collator("sv").resolvedOptions().locale, "sv");
+	`
+	testScriptWithTestLib(SCRIPT, _undefined, t)
+}
@@ -0,0 +1,848 @@
+package goja
+
+import (
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode"
+
+	"github.com/dop251/goja/unistring"
+	"golang.org/x/text/currency"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+	"golang.org/x/text/number"
+)
+
+const (
+	intlRoundingFractionDigits = iota
+	intlRoundingSignificantDigits
+	intlRoundingCompact
+)
+
+// intlNumberSymbols holds the locale-specific number symbols. They are obtained by formatting sample numbers with
+// golang.org/x/text/number so that any locale known to it gets the correct separators and grouping.
+type intlNumberSymbols struct {
+	decimal, group, minus        string
+	primaryGroup, secondaryGroup int
+	percentPrefix, percentSuffix string
+}
+
+// intlNumberFormat contains the resolved state of an Intl.NumberFormat. It is also used directly by
+// Number.prototype.toLocaleString().
+type intlNumberFormat struct {
+	locale  language.Tag
+	data    *intlLocaleData
+	symbols *intlNumberSymbols
+
+	style                        string
+	currency, currencyDisplay    string
+	currencySign, currencySymbol string
+	unit, unitDisplay            string
+	unitNames                    *intlUnitNames
+	notation, compactDisplay     string
+	signDisplay                  string
+	useGrouping                  bool
+
+	minimumIntegerDigits                               int
+	minimumFractionDigits, maximumFractionDigits       int
+	minimumSignificantDigits, maximumSignificantDigits int
+	roundingType                                       int
+}
+
+type numberFormatObject struct {
+	baseObject
+	f           *intlNumberFormat
+	boundFormat *Object
+}
+
+// intlDecimal is a non-negative decimal number equal to 0.digits * 10^exp. Zero has no digits.
+type intlDecimal struct {
+	digits []byte
+	exp    int
+}
+
+var intlNumberSymbolsCache sync.Map
+
+func getIntlNumberSymbols(tag language.Tag) *intlNumberSymbols {
+	key := tag.String()
+	if s, ok := intlNumberSymbolsCache.Load(key); ok {
+		return s.(*intlNumberSymbols)
+	}
+	s := &intlNumberSymbols{
+		decimal:        ".",
+		group:          ",",
+		minus:          "-",
+		primaryGroup:   3,
+		secondaryGroup: 3,
+		percentSuffix:  "%",
+	}
+	p := message.NewPrinter(tag)
+	sample := p.Sprint(number.Decimal(-1234567.5))
+	if idx := strings.IndexByte(sample, '1'); idx >= 0 {
+		s.minus = sample[:idx]
+		var runs []int
+		var seps []string
+		var sep strings.Builder
+		n := 0
+		for _, c := range sample[idx:] {
+			if c >= '0' && c <= '9' {
+				if sep.Len() > 0 {
+					seps = append(seps, sep.String())
+					sep.Reset()
+					runs = append(runs, n)
+					n = 0
+				}
+				n++
+			} else {
+				sep.WriteRune(c)
+			}
+		}
+		runs = append(runs, n)
+		if len(seps) > 0 && len(runs) == len(seps)+1 {
+			s.decimal = seps[len(seps)-1]
+			if len(seps) > 2 {
+				s.group = seps[0]
+				s.primaryGroup = runs[len(runs)-2]
+				s.secondaryGroup = runs[len(runs)-3]
+			} else {
+				s.group = ""
+			}
+		}
+	}
+	if percent := p.Sprint(number.Percent(0.25)); strings.Contains(percent, "25") {
+		idx := strings.Index(percent, "25")
+		s.percentPrefix, s.percentSuffix = percent[:idx], percent[idx+2:]
+	}
+	actual, _ := intlNumberSymbolsCache.LoadOrStore(key, s)
+	return actual.(*intlNumberSymbols)
+}
+
+func newIntlDecimal(f float64) intlDecimal {
+	if f == 0 {
+		return intlDecimal{}
+	}
+	s := strconv.FormatFloat(f, 'e', -1, 64)
+	idx := strings.IndexByte(s, 'e')
+	exp, _ := strconv.Atoi(s[idx+1:])
+	digits := make([]byte, 0, idx)
+	for i := 0; i < idx; i++ {
+		if s[i] != '.' {
+			digits = append(digits, s[i])
+		}
+	}
+	d := intlDecimal{digits: digits, exp: exp + 1}
+	d.trim()
+	return d
+}
+
+func (d *intlDecimal) trim() {
+	l := len(d.digits)
+	for l > 0 && d.digits[l-1] == '0' {
+		l--
+	}
+	d.digits = d.digits[:l]
+	if l == 0 {
+		d.exp = 0
+	}
+}
+
+func (d *intlDecimal) isZero() bool {
+	return len(d.digits) == 0
+}
+
+// roundAt rounds the number (half away from zero) so that it has at most pos digits.
+func (d *intlDecimal) roundAt(pos int) {
+	if pos >= len(d.digits) {
+		return
+	}
+	if pos < 0 {
+		d.digits = d.digits[:0]
+		d.exp = 0
+		return
+	}
+	roundUp := d.digits[pos] >= '5'
+	d.digits = d.digits[:pos]
+	if roundUp {
+		i := pos - 1
+		for i >= 0 && d.digits[i] == '9' {
+			i--
+		}
+		if i < 0 {
+			d.digits = append(d.digits[:0], '1')
+			d.exp++
+		} else {
+			d.digits[i]++
+			d.digits = d.digits[:i+1]
+		}
+	}
+	d.trim()
+}
+
+func intlIsWellFormedCurrency(code string) bool {
+	if len(code) != 3 {
+		return false
+	}
+	for i := 0; i < len(code); i++ {
+		if c := code[i] | 0x20; c < 'a' || c > 'z' {
+			return false
+		}
+	}
+	return true
+}
+
+func intlUnitNamesFor(unit string) *intlUnitNames {
+	if n := intlUnits[unit]; n != nil {
+		return n
+	}
+	if n := intlCompoundUnits[unit]; n != nil {
+		return n
+	}
+	idx := strings.Index(unit, "-per-")
+	if idx < 0 {
+		return nil
+	}
+	num, den := intlUnits[unit[:idx]], intlUnits[unit[idx+len("-per-"):]]
+	if num == nil || den == nil {
+		return nil
+	}
+	symbol := func(pattern string) string {
+		return strings.TrimSpace(strings.Replace(pattern, "{0}", "", 1))
+	}
+	names := &intlUnitNames{
+		short:      num.short + "/" + symbol(den.short),
+		narrow:     num.narrow + "/" + symbol(den.narrow),
+		long:       num.long + " per " + symbol(den.long),
+		longPlural: num.longPlural + " per " + symbol(den.long),
+	}
+	if num.shortPlural != "" {
+		names.shortPlural = num.shortPlural + "/" + symbol(den.short)
+	}
+	return names
+}
+
+func intlIsEnglish(tag language.Tag) bool {
+	base, _ := tag.Base()
+	return base.String() == "en"
+}
+
+// newIntlNumberFormat implements the InitializeNumberFormat abstract operation
+// (https://tc39.es/ecma402/#sec-initializenumberformat).
+func (r *Runtime) newIntlNumberFormat(locales, options Value) *intlNumberFormat {
+	locale, _, data := r.intlResolveLocale(locales)
+	opts := r.intlCoerceOptions(options)
+	f := &intlNumberFormat{
+		locale:  locale,
+		data:    data,
+		symbols: getIntlNumberSymbols(locale),
+	}
+
+	r.intlGetOption(opts, "localeMatcher", []string{"lookup", "best fit"}, "best fit")
+	r.intlGetOption(opts, "numberingSystem", nil, "")
+
+	f.style = r.intlGetOption(opts, "style", []string{"decimal", "percent", "currency", "unit"}, "decimal")
+	cur := r.intlGetOption(opts, "currency", nil, "")
+	if cur != "" && !intlIsWellFormedCurrency(cur) {
+		panic(r.newError(r.getRangeError(), "Invalid currency code : %s", cur))
+	}
+	currencyDisplay := r.intlGetOption(opts, "currencyDisplay", []string{"code", "symbol", "narrowSymbol", "name"}, "symbol")
+	currencySign := r.intlGetOption(opts, "currencySign", []string{"standard", "accounting"}, "standard")
+	unit := r.intlGetOption(opts, "unit", nil, "")
+	if unit != "" && intlUnitNamesFor(unit) == nil {
+		panic(r.newError(r.getRangeError(), "Invalid unit argument for Intl.NumberFormat() '%s'", unit))
+	}
+	unitDisplay := r.intlGetOption(opts, "unitDisplay", []string{"short", "narrow", "long"}, "short")
+
+	mnfdDefault, mxfdDefault := 0, 3
+	switch f.style {
+	case "currency":
+		if cur == "" {
+			panic(r.NewTypeError("Currency code is required with currency style."))
+		}
+		f.currency = strings.ToUpper(cur)
+		f.currencyDisplay, f.currencySign = currencyDisplay, currencySign
+		digits := 2
+		if u, err := currency.ParseISO(f.currency); err == nil {
+			digits, _ = currency.Standard.Rounding(u)
+			p := message.NewPrinter(locale)
+			switch currencyDisplay {
+			case "symbol":
+				f.currencySymbol = p.Sprint(currency.Symbol(u))
+			case "narrowSymbol":
+				f.currencySymbol = p.Sprint(currency.NarrowSymbol(u))
+			}
+		}
+		if f.currencySymbol == "" {
+			f.currencySymbol = f.currency
+		}
+		mnfdDefault, mxfdDefault = digits, digits
+	case "percent":
+		mxfdDefault = 0
+	case "unit":
+		if unit == "" {
+			panic(r.NewTypeError("Unit is required with unit style."))
+		}
+		f.unit, f.unitDisplay = unit, unitDisplay
+		f.unitNames = intlUnitNamesFor(unit)
+	}
+
+	f.notation = r.intlGetOption(opts, "notation", []string{"standard", "scientific", "engineering", "compact"}, "standard")
+	r.intlSetNumberFormatDigitOptions(f, opts, mnfdDefault, mxfdDefault)
+	compactDisplay := r.intlGetOption(opts, "compactDisplay", []string{"short", "long"}, "short")
+	if f.notation == "compact" {
+		f.compactDisplay = compactDisplay
+	}
+	f.useGrouping = true
+	if b, set := r.intlGetBoolOption(opts, "useGrouping"); set {
+		f.useGrouping = b
+	}
+	f.signDisplay = r.intlGetOption(opts, "signDisplay", []string{"auto", "never", "always", "exceptZero", "negative"}, "auto")
+	return f
+}
+
+// intlSetNumberFormatDigitOptions implements SetNumberFormatDigitOptions
+// (https://tc39.es/ecma402/#sec-setnfdigitoptions).
+func (r *Runtime) intlSetNumberFormatDigitOptions(f *intlNumberFormat, opts *Object, mnfdDefault, mxfdDefault int) {
+	f.minimumIntegerDigits = r.intlGetNumberOption(opts, "minimumIntegerDigits", 1, 21, 1)
+	get := func(name unistring.String) Value {
+		if opts == nil {
+			return _undefined
+		}
+		return nilSafe(opts.self.getStr(name, nil))
+	}
+	mnfd := get("minimumFractionDigits")
+	mxfd := get("maximumFractionDigits")
+	mnsd := get("minimumSignificantDigits")
+	mxsd := get("maximumSignificantDigits")
+
+	if mnsd != _undefined || mxsd != _undefined {
+		f.roundingType = intlRoundingSignificantDigits
+		f.minimumSignificantDigits = r.intlDefaultNumberOption(mnsd, "minimumSignificantDigits", 1, 21, 1)
+		f.maximumSignificantDigits = r.intlDefaultNumberOption(mxsd, "maximumSignificantDigits", f.minimumSignificantDigits, 21, 21)
+		return
+	}
+	if mnfd == _undefined && mxfd == _undefined && f.notation == "compact" {
+		f.roundingType = intlRoundingCompact
+		return
+	}
+	f.roundingType = intlRoundingFractionDigits
+	minimum := r.intlDefaultNumberOption(mnfd, "minimumFractionDigits", 0, 20, -1)
+	maximum := r.intlDefaultNumberOption(mxfd, "maximumFractionDigits", 0, 20, -1)
+	switch {
+	case minimum < 0 && maximum < 0:
+		minimum, maximum = mnfdDefault, mxfdDefault
+	case minimum < 0:
+		minimum = mnfdDefault
+		if maximum < minimum {
+			minimum = maximum
+		}
+	case maximum < 0:
+		maximum = mxfdDefault
+		if maximum < minimum {
+			maximum = minimum
+		}
+	case minimum > maximum:
+		panic(r.newError(r.getRangeError(), "maximumFractionDigits value is out of range."))
+	}
+	f.minimumFractionDigits, f.maximumFractionDigits = minimum, maximum
+}
+
+func (f *intlNumberFormat) round(d *intlDecimal) {
+	switch f.roundingType {
+	case intlRoundingSignificantDigits:
+		d.roundAt(f.maximumSignificantDigits)
+	case intlRoundingCompact:
+		if d.exp < 2 {
+			d.roundAt(2)
+		} else {
+			d.roundAt(d.exp)
+		}
+	default:
+		d.roundAt(d.exp + f.maximumFractionDigits)
+	}
+}
+
+func (f *intlNumberFormat) digitParts(d intlDecimal, parts []intlPart) []intlPart {
+	var b strings.Builder
+	for i := 0; i < d.exp; i++ {
+		if i < len(d.digits) {
+			b.WriteByte(d.digits[i])
+		} else {
+			b.WriteByte('0')
+		}
+	}
+	integer := b.String()
+	if len(integer) < f.minimumIntegerDigits {
+		integer = strings.Repeat("0", f.minimumIntegerDigits-len(integer)) + integer
+	}
+
+	b.Reset()
+	if d.exp < 0 && len(d.digits) > 0 {
+		b.WriteString(strings.Repeat("0", -d.exp))
+		b.Write(d.digits)
+	} else if d.exp >= 0 && d.exp < len(d.digits) {
+		b.Write(d.digits[d.exp:])
+	}
+	minFraction := f.minimumFractionDigits
+	if f.roundingType == intlRoundingSignificantDigits {
+		significant := len(d.digits)
+		if d.exp > significant {
+			significant = d.exp
+		}
+		if significant == 0 {
+			significant = 1
+		}
+		minFraction = b.Len() + f.minimumSignificantDigits - significant
+	}
+	for b.Len() < minFraction {
+		b.WriteByte('0')
+	}
+	fraction := b.String()
+
+	s := f.symbols
+	minGrouping := f.data.minGrouping
+	if minGrouping < 1 {
+		minGrouping = 1
+	}
+	if f.useGrouping && s.group != "" && len(integer)-s.primaryGroup >= minGrouping {
+		var groups []string
+		rest := integer[:len(integer)-s.primaryGroup]
+		for len(rest) > s.secondaryGroup {
+			groups = append(groups, rest[len(rest)-s.secondaryGroup:])
+			rest = rest[:len(rest)-s.secondaryGroup]
+		}
+		parts = append(parts, intlPart{typ: "integer", value: rest})
+		for i := len(groups) - 1; i >= 0; i-- {
+			parts = append(parts, intlPart{typ: "group", value: s.group}, intlPart{typ: "integer", value: groups[i]})
+		}
+		parts = append(parts, intlPart{typ: "group", value: s.group}, intlPart{typ: "integer", value: integer[len(integer)-s.primaryGroup:]})
+	} else {
+		parts = append(parts, intlPart{typ: "integer", value: integer})
+	}
+	if fraction != "" {
+		parts = append(parts, intlPart{typ: "decimal", value: s.decimal}, intlPart{typ: "fraction", value: fraction})
+	}
+	return parts
+}
+
+func intlFindCompactUnit(units []intlCompactUnit, magnitude int) *intlCompactUnit {
+	var res *intlCompactUnit
+	for i := range units {
+		if units[i].exp <= magnitude {
+			res = &units[i]
+		}
+	}
+	return res
+}
+
+// intlAffixParts splits an affix into the leading and trailing whitespace (literal) and the rest (typ).
+func intlAffixParts(affix, typ string, parts []intlPart) []intlPart {
+	core := strings.TrimFunc(affix, unicode.IsSpace)
+	if core == "" {
+		if affix != "" {
+			parts = append(parts, intlPart{typ: "literal", value: affix})
+		}
+		return parts
+	}
+	idx := strings.Index(affix, core)
+	if idx > 0 {
+		parts = append(parts, intlPart{typ: "literal", value: affix[:idx]})
+	}
+	parts = append(parts, intlPart{typ: typ, value: core})
+	if rest := affix[idx+len(core):]; rest != "" {
+		parts = append(parts, intlPart{typ: "literal", value: rest})
+	}
+	return parts
+}
+
+// numberParts formats the absolute value of a finite x. It returns the parts and whether the value was
+// rounded to zero.
+func (f *intlNumberFormat) numberParts(x float64) ([]intlPart, bool) {
+	d := newIntlDecimal(math.Abs(x))
+	if f.style == "percent" && !d.isZero() {
+		d.exp += 2
+	}
+	parts := make([]intlPart, 0, 8)
+	switch f.notation {
+	case "scientific", "engineering":
+		step := 1
+		if f.notation == "engineering" {
+			step = 3
+		}
+		e := 0
+		if !d.isZero() {
+			e = d.exp - 1
+			if r := e % step; r < 0 {
+				e -= r + step
+			} else {
+				e -= r
+			}
+			d.exp -= e
+			f.round(&d)
+			if d.exp > step {
+				d.exp -= step
+				e += step
+			}
+		}
+		parts = f.digitParts(d, parts)
+		parts = append(parts, intlPart{typ: "exponentSeparator", value: "E"})
+		if e < 0 {
+			parts = append(parts, intlPart{typ: "exponentMinusSign", value: f.symbols.minus})
+			e = -e
+		}
+		parts = append(parts, intlPart{typ: "exponentInteger", value: strconv.Itoa(e)})
+	case "compact":
+		units := f.data.compactShort
+		if f.compactDisplay == "long" && f.data.compactLong != nil {
+			units = f.data.compactLong
+		}
+		var unit *intlCompactUnit
+		if !d.isZero() {
+			magnitude := d.exp - 1
+			unit = intlFindCompactUnit(units, magnitude)
+			if unit != nil {
+				d.exp -= unit.exp
+			}
+			f.round(&d)
+			newMagnitude := d.exp - 1
+			if unit != nil {
+				newMagnitude += unit.exp
+			}
+			if newMagnitude != magnitude {
+				if u := intlFindCompactUnit(units, newMagnitude); u != unit {
+					if unit != nil {
+						d.exp += unit.exp
+					}
+					if u != nil {
+						d.exp -= u.exp
+					}
+					unit = u
+					f.round(&d)
+				}
+			}
+		}
+		parts = f.digitParts(d, parts)
+		if unit != nil {
+			parts = intlAffixParts(unit.suffix, "compact", parts)
+		}
+	default:
+		f.round(&d)
+		parts = f.digitParts(d, parts)
+	}
+	return parts, d.isZero()
+}
+
+func (f *intlNumberFormat) formatToParts(x float64) []intlPart {
+	var num []intlPart
+	zero := false
+	negative := math.Signbit(x)
+	switch {
+	case math.IsNaN(x):
+		num = []intlPart{{typ: "nan", value: "NaN"}}
+		negative = false
+	case math.IsInf(x, 0):
+		num = []intlPart{{typ: "infinity", value: "∞"}}
+	default:
+		num, zero = f.numberParts(x)
+	}
+
+	var sign *intlPart
+	minus := &intlPart{typ: "minusSign", value: f.symbols.minus}
+	plus := &intlPart{typ: "plusSign", value: "+"}
+	switch f.signDisplay {
+	case "auto":
+		if negative {
+			sign = minus
+		}
+	case "always":
+		if negative {
+			sign = minus
+		} else {
+			sign = plus
+		}
+	case "exceptZero":
+		if !zero && !math.IsNaN(x) {
+			if negative {
+				sign = minus
+			} else {
+				sign = plus
+			}
+		}
+	case "negative":
+		if negative && !zero {
+			sign = minus
+		}
+	}
+
+	parts := make([]intlPart, 0, len(num)+4)
+	switch f.style {
+	case "percent":
+		if sign != nil {
+			parts = append(parts, *sign)
+		}
+		parts = intlAffixParts(f.symbols.percentPrefix, "percentSign", parts)
+		parts = append(parts, num...)
+		parts = intlAffixParts(f.symbols.percentSuffix, "percentSign", parts)
+	case "currency":
+		if f.currencyDisplay == "name" {
+			if sign != nil {
+				parts = append(parts, *sign)
+			}
+			name := f.currency
+			if names, exists := intlCurrencyNames[f.currency]; exists && intlIsEnglish(f.locale) {
+				if intlPartsString(num) == "1" {
+					name = names[0]
+				} else {
+					name = names[1]
+				}
+			}
+			parts = append(parts, num...)
+			parts = append(parts, intlPart{typ: "literal", value: " "}, intlPart{typ: "currency", value: name})
+			break
+		}
+		pattern := f.data.currencyPattern
+		if sign == minus && f.currencySign == "accounting" && f.data.accountingPattern != "" {
+			pattern = f.data.accountingPattern
+			sign = nil
+		}
+		if sign != nil {
+			parts = append(parts, *sign)
+		}
+		symbol := f.currencySymbol
+		for i, c := range pattern {
+			switch c {
+			case '0':
+				parts = append(parts, num...)
+			case '¤':
+				parts = append(parts, intlPart{typ: "currency", value: symbol})
+				if next := pattern[i+len("¤"):]; strings.HasPrefix(next, "0") {
+					last := []rune(symbol)
+					if unicode.IsLetter(last[len(last)-1]) {
+						parts = append(parts, intlPart{typ: "literal", value: " "})
+					}
+				}
+			default:
+				if l := len(parts); l > 0 && parts[l-1].typ == "literal" {
+					parts[l-1].value += string(c)
+				} else {
+					parts = append(parts, intlPart{typ: "literal", value: string(c)})
+				}
+			}
+		}
+	case "unit":
+		if sign != nil {
+			parts = append(parts, *sign)
+		}
+		names := f.unitNames
+		pattern := names.short
+		switch {
+		case f.unitDisplay == "narrow":
+			pattern = names.narrow
+		case f.unitDisplay == "long" && intlIsEnglish(f.locale):
+			pattern = names.long
+			if intlPartsString(num) != "1" {
+				pattern = names.longPlural
+			}
+		case names.shortPlural != "" && intlIsEnglish(f.locale) && intlPartsString(num) != "1":
+			pattern = names.shortPlural
+		}
+		idx := strings.Index(pattern, "{0}")
+		parts = intlAffixParts(pattern[:idx], "unit", parts)
+		parts = append(parts, num...)
+		parts = intlAffixParts(pattern[idx+len("{0}"):], "unit", parts)
+	default:
+		if sign != nil {
+			parts = append(parts, *sign)
+		}
+		parts = append(parts, num...)
+	}
+	return parts
+}
+
+func intlPartsString(parts []intlPart) string {
+	var b strings.Builder
+	for _, part := range parts {
+		b.WriteString(part.value)
+	}
+	return b.String()
+}
+
+func (f *intlNumberFormat) format(x float64) string {
+	return intlPartsString(f.formatToParts(x))
+}
+
+func (r *Runtime) toNumberFormat(v Value, method string) *numberFormatObject {
+	if o, ok := v.(*Object); ok {
+		if nf, ok := o.self.(*numberFormatObject); ok {
+			return nf
+		}
+	}
+	panic(r.NewTypeError("Method Intl.NumberFormat.prototype.%s called on incompatible receiver %s", method, r.objectproto_toString(FunctionCall{This: v})))
+}
+
+func (r *Runtime) builtin_newNumberFormat(args []Value, newTarget *Object) *Object {
+	var locales, options Value = _undefined, _undefined
+	if len(args) > 0 {
+		locales = args[0]
+	}
+	if len(args) > 1 {
+		options = args[1]
+	}
+	proto := r.getNumberFormatPrototype()
+	if newTarget != nil {
+		proto = r.getPrototypeFromCtor(newTarget, r.getNumberFormat(), proto)
+	}
+	o := &Object{runtime: r}
+	nf := &numberFormatObject{
+		f: r.newIntlNumberFormat(locales, options),
+	}
+	nf.class = classObject
+	nf.val = o
+	nf.extensible = true
+	o.self = nf
+	nf.prototype = proto
+	nf.init()
+	return o
+}
+
+func (r *Runtime) numberFormatProto_getFormat(call FunctionCall) Value {
+	nf := r.toNumberFormat(call.This, "format")
+	if nf.boundFormat == nil {
+		f := nf.f
+		nf.boundFormat = r.newNativeFunc(func(call FunctionCall) Value {
+			return newStringValue(f.format(call.Argument(0).ToFloat()))
+		}, "", 1)
+	}
+	return nf.boundFormat
+}
+
+func (r *Runtime) numberFormatProto_formatToParts(call FunctionCall) Value {
+	nf := r.toNumberFormat(call.This, "formatToParts")
+	return r.intlPartsToArray(nf.f.formatToParts(call.Argument(0).ToFloat()))
+}
+
+func (r *Runtime) numberFormatProto_resolvedOptions(call FunctionCall) Value {
+	f := r.toNumberFormat(call.This, "resolvedOptions").f
+	res := r.NewObject()
+	put := func(name unistring.String, value string) {
+		if value != "" {
+			res.self._putProp(name, newStringValue(value), true, true, true)
+		}
+	}
+	putInt := func(name unistring.String, value int) {
+		res.self._putProp(name, intToValue(int64(value)), true, true, true)
+	}
+	put("locale", f.locale.String())
+	put("numberingSystem", "latn")
+	put("style", f.style)
+	put("currency", f.currency)
+	put("currencyDisplay", f.currencyDisplay)
+	put("currencySign", f.currencySign)
+	put("unit", f.unit)
+	put("unitDisplay", f.unitDisplay)
+	putInt("minimumIntegerDigits", f.minimumIntegerDigits)
+	if f.roundingType == intlRoundingSignificantDigits {
+		putInt("minimumSignificantDigits", f.minimumSignificantDigits)
+		putInt("maximumSignificantDigits", f.maximumSignificantDigits)
+	} else {
+		putInt("minimumFractionDigits", f.minimumFractionDigits)
+		putInt("maximumFractionDigits", f.maximumFractionDigits)
+	}
+	res.self._putProp("useGrouping", r.toBoolean(f.useGrouping), true, true, true)
+	put("notation", f.notation)
+	put("compactDisplay", f.compactDisplay)
+	put("signDisplay", f.signDisplay)
+	return res
+}
+
+func (r *Runtime) numberFormat_supportedLocalesOf(call FunctionCall) Value {
+	return r.intlSupportedLocales(call.Argument(0), call.Argument(1), intlMatchLocale)
+}
+
+func createNumberFormatProtoTemplate() *objectTemplate {
+	t := newObjectTemplate()
+	t.protoFactory = func(r *Runtime) *Object {
+		return r.global.ObjectPrototype
+	}
+
+	t.putStr("constructor", func(r *Runtime) Value { return valueProp(r.getNumberFormat(), true, false, true) })
+	t.putStr("format", func(r *Runtime) Value {
+		return &valueProperty{
+			getterFunc:   r.newNativeFunc(r.numberFormatProto_getFormat, "get format", 0),
+			accessor:     true,
+			configurable: true,
+		}
+	})
+	t.putStr("formatToParts", func(r *Runtime) Value {
+		return r.methodProp(r.numberFormatProto_formatToParts, "formatToParts", 1)
+	})
+	t.putStr("resolvedOptions", func(r *Runtime) Value {
+		return r.methodProp(r.numberFormatProto_resolvedOptions, "resolvedOptions", 0)
+	})
+
+	t.putSym(SymToStringTag, func(r *Runtime) Value {
+		return valueProp(asciiString("Intl.NumberFormat"), false, false, true)
+	})
+
+	return t
+}
+
+var numberFormatProtoTemplate *objectTemplate
+var numberFormatProtoTemplateOnce sync.Once
+
+func getNumberFormatProtoTemplate() *objectTemplate {
+	numberFormatProtoTemplateOnce.Do(func() {
+		numberFormatProtoTemplate = createNumberFormatProtoTemplate()
+	})
+	return numberFormatProtoTemplate
+}
+
+func (r *Runtime) getNumberFormatPrototype() *Object {
+	ret := r.global.NumberFormatPrototype
+	if ret == nil {
+		ret = &Object{runtime: r}
+		r.global.NumberFormatPrototype = ret
+		r.newTemplatedObject(getNumberFormatProtoTemplate(), ret)
+	}
+	return ret
+}
+
+func createNumberFormatTemplate() *objectTemplate {
+	t := newObjectTemplate()
+	t.protoFactory = func(r *Runtime) *Object {
+		return r.getFunctionPrototype()
+	}
+
+	t.putStr("name", func(r *Runtime) Value { return valueProp(asciiString("NumberFormat"), false, false, true) })
+	t.putStr("length", func(r *Runtime) Value { return valueProp(intToValue(0), false, false, true) })
+
+	t.putStr("prototype", func(r *Runtime) Value { return valueProp(r.getNumberFormatPrototype(), false, false, false) })
+
+	t.putStr("supportedLocalesOf", func(r *Runtime) Value {
+		return r.methodProp(r.numberFormat_supportedLocalesOf, "supportedLocalesOf", 1)
+	})
+
+	return t
+}
+
+var numberFormatTemplate *objectTemplate
+var numberFormatTemplateOnce sync.Once
+
+func getNumberFormatTemplate() *objectTemplate {
+	numberFormatTemplateOnce.Do(func() {
+		numberFormatTemplate = createNumberFormatTemplate()
+	})
+	return numberFormatTemplate
+}
+
+func (r *Runtime) getNumberFormat() *Object {
+	ret := r.global.NumberFormat
+	if ret == nil {
+		ret = &Object{runtime: r}
+		r.global.NumberFormat = ret
+		r.newTemplatedFuncObject(getNumberFormatTemplate(), ret, func(call FunctionCall) Value {
+			return r.builtin_newNumberFormat(call.Arguments, nil)
+		}, r.builtin_newNumberFormat)
+	}
+	return ret
+}
@@ -0,0 +1,308 @@
+package goja
+
+import (
+	"math"
+	"sync"
+
+	"github.com/dop251/goja/unistring"
+	"golang.org/x/text/feature/plural"
+	"golang.org/x/text/language"
+)
+
+// Indexed by plural.Form.
+var intlPluralFormNames = [...]string{
+	plural.Other: "other",
+	plural.Zero:  "zero",
+	plural.One:   "one",
+	plural.Two:   "two",
+	plural.Few:   "few",
+	plural.Many:  "many",
+}
+
+// The order in which the categories are listed by resolvedOptions().
+var intlPluralFormOrder = [...]plural.Form{plural.Zero, plural.One, plural.Two, plural.Few, plural.Many, plural.Other}
+
+// intlPluralRules contains the resolved state of an Intl.PluralRules. It is also used by
+// Intl.RelativeTimeFormat to choose the form of a unit.
+type intlPluralRules struct {
+	locale language.Tag
+	typ    string
+	rules  *plural.Rules
+	// Only the digit options are used, they determine the digits the plural rules are applied to.
+	nf *intlNumberFormat
+}
+
+type pluralRulesObject struct {
+	baseObject
+	p *intlPluralRules
+}
+
+// intlPluralCategory returns the plural category for the formatted (absolute) number parts.
+func intlPluralCategory(rules *plural.Rules, tag language.Tag, parts []intlPart) string {
+	var digits []byte
+	exp, scale := 0, 0
+	for _, part := range parts {
+		switch part.typ {
+		case "integer":
+			for i := 0; i < len(part.value); i++ {
+				digits = append(digits, part.value[i]-'0')
+			}
+			exp += len(part.value)
+		case "fraction":
+			for i := 0; i < len(part.value); i++ {
+				digits = append(digits, part.value[i]-'0')
+			}
+			scale += len(part.value)
+		}
+	}
+	return intlPluralFormNames[intlMatchPlural(rules, tag, digits, exp, scale)]
+}
+
+// intlMatchPlural is plural.Rules.MatchDigits() with the corrections for the rules that have changed in the
+// CLDR versions newer than the one golang.org/x/text is based on.
+func intlMatchPlural(rules *plural.Rules, tag language.Tag, digits []byte, exp, scale int) plural.Form {
+	if rules == plural.Cardinal && scale == 0 && intlIsMillions(digits, exp) {
+		// many: e = 0 and i != 0 and i % 1000000 = 0 and v = 0
+		switch base, _ := tag.Base(); base.String() {
+		case "ca", "es", "fr", "it", "pt":
+			return plural.Many
+		}
+	}
+	return rules.MatchDigits(tag, digits, exp, scale)
+}
+
+// intlIsMillions returns true if the integer represented by the digits is a non-zero multiple of 1000000.
+func intlIsMillions(digits []byte, exp int) bool {
+	if exp < 7 {
+		return false
+	}
+	nonZero := false
+	for i := 0; i < exp; i++ {
+		var d byte
+		if i < len(digits) {
+			d = digits[i]
+		}
+		if d != 0 {
+			if i >= exp-6 {
+				return false
+			}
+			nonZero = true
+		}
+	}
+	return nonZero
+}
+
+// newIntlPluralRules implements the InitializePluralRules abstract operation
+// (https://tc39.es/ecma402/#sec-initializepluralrules).
+func (r *Runtime) newIntlPluralRules(locales, options Value) *intlPluralRules {
+	locale, _, data := r.intlResolveLocale(locales)
+	opts := r.intlCoerceOptions(options)
+	p := &intlPluralRules{
+		locale: locale,
+		nf: &intlNumberFormat{
+			locale:   locale,
+			data:     data,
+			symbols:  getIntlNumberSymbols(locale),
+			style:    "decimal",
+			notation: "standard",
+		},
+	}
+	r.intlGetOption(opts, "localeMatcher", []string{"lookup", "best fit"}, "best fit")
+	p.typ = r.intlGetOption(opts, "type", []string{"cardinal", "ordinal"}, "cardinal")
+	if p.typ == "ordinal" {
+		p.rules = plural.Ordinal
+	} else {
+		p.rules = plural.Cardinal
+	}
+	r.intlSetNumberFormatDigitOptions(p.nf, opts, 0, 3)
+	return p
+}
+
+func (p *intlPluralRules) selectCategory(x float64) string {
+	if math.IsNaN(x) || math.IsInf(x, 0) {
+		return "other"
+	}
+	parts, _ := p.nf.numberParts(x)
+	return intlPluralCategory(p.rules, p.locale, parts)
+}
+
+// categories returns the plural categories used by the locale, they are determined by sampling.
+func (p *intlPluralRules) categories() []string {
+	var seen [len(intlPluralFormNames)]bool
+	check := func(digits []byte, exp, scale int) {
+		seen[intlMatchPlural(p.rules, p.locale, digits, exp, scale)] = true
+	}
+	for i := 0; i <= 200; i++ {
+		if i < 10 {
+			check([]byte{byte(i)}, 1, 0)
+		} else if i < 100 {
+			check([]byte{byte(i / 10), byte(i % 10)}, 2, 0)
+		} else {
+			check([]byte{byte(i / 100), byte(i / 10 % 10), byte(i % 10)}, 3, 0)
+		}
+	}
+	check([]byte{1, 0, 0, 0, 0, 0, 0}, 7, 0)
+	check([]byte{0, 5}, 1, 1)
+	check([]byte{1, 5}, 1, 1)
+	check([]byte{2, 5}, 1, 1)
+	var res []string
+	for _, form := range intlPluralFormOrder {
+		if seen[form] {
+			res = append(res, intlPluralFormNames[form])
+		}
+	}
+	return res
+}
+
+func (r *Runtime) toPluralRules(v Value, method string) *pluralRulesObject {
+	if o, ok := v.(*Object); ok {
+		if p, ok := o.self.(*pluralRulesObject); ok {
+			return p
+		}
+	}
+	panic(r.NewTypeError("Method Intl.PluralRules.prototype.%s called on incompatible receiver %s", method, r.objectproto_toString(FunctionCall{This: v})))
+}
+
+func (r *Runtime) builtin_newPluralRules(args []Value, newTarget *Object) *Object {
+	if newTarget == nil {
+		panic(r.needNew("Intl.PluralRules"))
+	}
+	var locales, options Value = _undefined, _undefined
+	if len(args) > 0 {
+		locales = args[0]
+	}
+	if len(args) > 1 {
+		options = args[1]
+	}
+	proto := r.getPrototypeFromCtor(newTarget, r.getPluralRules(), r.getPluralRulesPrototype())
+	o := &Object{runtime: r}
+	p := &pluralRulesObject{
+		p: r.newIntlPluralRules(locales, options),
+	}
+	p.class = classObject
+	p.val = o
+	p.extensible = true
+	o.self = p
+	p.prototype = proto
+	p.init()
+	return o
+}
+
+func (r *Runtime) pluralRulesProto_select(call FunctionCall) Value {
+	p := r.toPluralRules(call.This, "select").p
+	return asciiString(p.selectCategory(call.Argument(0).ToFloat()))
+}
+
+func (r *Runtime) pluralRulesProto_resolvedOptions(call FunctionCall) Value {
+	p := r.toPluralRules(call.This, "resolvedOptions").p
+	res := r.NewObject()
+	put := func(name unistring.String, value Value) {
+		res.self._putProp(name, value, true, true, true)
+	}
+	put("locale", newStringValue(p.locale.String()))
+	put("type", asciiString(p.typ))
+	f := p.nf
+	put("minimumIntegerDigits", intToValue(int64(f.minimumIntegerDigits)))
+	if f.roundingType == intlRoundingSignificantDigits {
+		put("minimumSignificantDigits", intToValue(int64(f.minimumSignificantDigits)))
+		put("maximumSignificantDigits", intToValue(int64(f.maximumSignificantDigits)))
+	} else {
+		put("minimumFractionDigits", intToValue(int64(f.minimumFractionDigits)))
+		put("maximumFractionDigits", intToValue(int64(f.maximumFractionDigits)))
+	}
+	categories := p.categories()
+	values := make([]Value, len(categories))
+	for i, c := range categories {
+		values[i] = asciiString(c)
+	}
+	put("pluralCategories", r.newArrayValues(values))
+	return res
+}
+
+func (r *Runtime) pluralRules_supportedLocalesOf(call FunctionCall) Value {
+	return r.intlSupportedLocales(call.Argument(0), call.Argument(1), intlMatchLocale)
+}
+
+func createPluralRulesProtoTemplate() *objectTemplate {
+	t := newObjectTemplate()
+	t.protoFactory = func(r *Runtime) *Object {
+		return r.global.ObjectPrototype
+	}
+
+	t.putStr("constructor", func(r *Runtime) Value { return valueProp(r.getPluralRules(), true, false, true) })
+	t.putStr("select", func(r *Runtime) Value { return r.methodProp(r.pluralRulesProto_select, "select", 1) })
+	t.putStr("resolvedOptions", func(r *Runtime) Value {
+		return r.methodProp(r.pluralRulesProto_resolvedOptions, "resolvedOptions", 0)
+	})
+
+	t.putSym(SymToStringTag, func(r *Runtime) Value {
+		return valueProp(asciiString("Intl.PluralRules"), false, false, true)
+	})
+
+	return t
+}
+
+var pluralRulesProtoTemplate *objectTemplate
+var pluralRulesProtoTemplateOnce sync.Once
+
+func getPluralRulesProtoTemplate() *objectTemplate {
+	pluralRulesProtoTemplateOnce.Do(func() {
+		pluralRulesProtoTemplate = createPluralRulesProtoTemplate()
+	})
+	return pluralRulesProtoTemplate
+}
+
+func (r *Runtime) getPluralRulesPrototype() *Object {
+	ret := r.global.PluralRulesPrototype
+	if ret == nil {
+		ret = &Object{runtime: r}
+		r.global.PluralRulesPrototype = ret
+		r.newTemplatedObject(getPluralRulesProtoTemplate(), ret)
+	}
+	return ret
+}
+
+func createPluralRulesTemplate() *objectTemplate {
+	t := newObjectTemplate()
+	t.protoFactory = func(r *Runtime) *Object {
+		return r.getFunctionPrototype()
+	}
+
+	t.putStr("name", func(r *Runtime) Value { return valueProp(asciiString("PluralRules"), false, false, true) })
+	t.putStr("length", func(r *Runtime) Value { return valueProp(intToValue(0), false, false, true) })
+
+	t.putStr("prototype", func(r *Runtime) Value { return valueProp(r.getPluralRulesPrototype(), false, false, false) })
+
+	t.putStr("supportedLocalesOf", func(r *Runtime) Value {
+		return r.methodProp(r.pluralRules_supportedLocalesOf, "supportedLocalesOf", 1)
+	})
+
+	return t
+}
+
+var pluralRulesTemplate *objectTemplate
+var pluralRulesTemplateOnce sync.Once
+
+func getPluralRulesTemplate() *objectTemplate {
+	pluralRulesTemplateOnce.Do(func() {
+		pluralRulesTemplate = createPluralRulesTemplate()
+	})
+	return pluralRulesTemplate
+}
+
+func (r *Runtime) getPluralRules() *Object {
+	ret := r.global.PluralRules
+	if ret == nil {
+		ret = &Object{runtime: r}
+		r.global.PluralRules = ret
+		r.newTemplatedFuncObject(getPluralRulesTemplate(), ret, func(call FunctionCall) Value {
+			return r.builtin_newPluralRules(call.Arguments, nil)
+		}, func(args []Value, newTarget *Object) *Object {
+			if newTarget == nil {
+				newTarget = ret
+			}
+			return r.builtin_newPluralRules(args, newTarget)
+		})
+	}
+	return ret
+}
@@ -0,0 +1,273 @@
+package goja
+
+import (
+	"math"
+	"strings"
+	"sync"
+
+	"github.com/dop251/goja/unistring"
+	"golang.org/x/text/feature/plural"
+	"golang.org/x/text/language"
+)
+
+var intlRelativeTimeUnits = []string{"second", "minute", "hour", "day", "week", "month", "quarter", "year"}
+
+var intlRelativeTimeStyles = map[string]int{
+	"long":   intlWidthLong,
+	"short":  intlWidthShort,
+	"narrow": intlWidthNarrow,
+}
+
+// intlRelativeTimeFormat contains the resolved state of an Intl.RelativeTimeFormat.
+type intlRelativeTimeFormat struct {
+	locale         language.Tag
+	style, numeric string
+	units          map[string]*intlRelativeTimeUnit
+
+	nf *intlNumberFormat
+	pr *intlPluralRules
+}
+
+type relativeTimeFormatObject struct {
+	baseObject
+	f *intlRelativeTimeFormat
+}
+
+// newIntlRelativeTimeFormat implements the InitializeRelativeTimeFormat abstract operation
+// (https://tc39.es/ecma402/#sec-InitializeRelativeTimeFormat).
+func (r *Runtime) newIntlRelativeTimeFormat(locales, options Value) *intlRelativeTimeFormat {
+	locale, _, data := r.intlResolveLocale(locales)
+	opts := r.intlCoerceOptions(options)
+	r.intlGetOption(opts, "localeMatcher", []string{"lookup", "best fit"}, "best fit")
+	r.intlGetOption(opts, "numberingSystem", nil, "")
+	f := &intlRelativeTimeFormat{
+		locale: locale,
+		nf: &intlNumberFormat{
+			locale:                locale,
+			data:                  data,
+			symbols:               getIntlNumberSymbols(locale),
+			style:                 "decimal",
+			notation:              "standard",
+			signDisplay:           "auto",
+			useGrouping:           true,
+			minimumIntegerDigits:  1,
+			maximumFractionDigits: 3,
+			roundingType:          intlRoundingFractionDigits,
+		},
+	}
+	f.pr = &intlPluralRules{
+		locale: locale,
+		typ:    "cardinal",
+		rules:  plural.Cardinal,
+		nf:     f.nf,
+	}
+	f.style = r.intlGetOption(opts, "style", []string{"long", "short", "narrow"}, "long")
+	f.numeric = r.intlGetOption(opts, "numeric", []string{"always", "auto"}, "always")
+	style := intlRelativeTimeStyles[f.style]
+	for f.units = data.relativeTime[style]; f.units == nil && style > intlWidthLong; style-- {
+		f.units = data.relativeTime[style-1]
+	}
+	return f
+}
+
+func (r *Runtime) intlRelativeTimeUnit(v Value) string {
+	unit := v.String()
+	singular := strings.TrimSuffix(unit, "s")
+	for _, u := range intlRelativeTimeUnits {
+		if u == singular {
+			return u
+		}
+	}
+	panic(r.newError(r.getRangeError(), "Invalid unit argument for format() '%s'", unit))
+}
+
+// formatToParts implements the PartitionRelativeTimePattern abstract operation
+// (https://tc39.es/ecma402/#sec-PartitionRelativeTimePattern).
+func (f *intlRelativeTimeFormat) formatToParts(value float64, unit string) []intlPart {
+	u := f.units[unit]
+	if f.numeric == "auto" && value == math.Trunc(value) && math.Abs(value) <= 2 {
+		if s, exists := u.relative[int(value)]; exists {
+			return []intlPart{{typ: "literal", value: s}}
+		}
+	}
+	patterns := u.future
+	if value < 0 || value == 0 && math.Signbit(value) {
+		patterns = u.past
+	}
+	num, _ := f.nf.numberParts(value)
+	pattern, exists := patterns[intlPluralCategory(f.pr.rules, f.locale, num)]
+	if !exists {
+		pattern = patterns["other"]
+	}
+	for i := range num {
+		num[i].unit = unit
+	}
+	idx := strings.Index(pattern, "{0}")
+	parts := make([]intlPart, 0, len(num)+2)
+	if idx > 0 {
+		parts = append(parts, intlPart{typ: "literal", value: pattern[:idx]})
+	}
+	parts = append(parts, num...)
+	if rest := pattern[idx+len("{0}"):]; rest != "" {
+		parts = append(parts, intlPart{typ: "literal", value: rest})
+	}
+	return parts
+}
+
+func (r *Runtime) toRelativeTimeFormat(v Value, method string) *relativeTimeFormatObject {
+	if o, ok := v.(*Object); ok {
+		if f, ok := o.self.(*relativeTimeFormatObject); ok {
+			return f
+		}
+	}
+	panic(r.NewTypeError("Method Intl.RelativeTimeFormat.prototype.%s called on incompatible receiver %s", method, r.objectproto_toString(FunctionCall{This: v})))
+}
+
+func (r *Runtime) builtin_newRelativeTimeFormat(args []Value, newTarget *Object) *Object {
+	if newTarget == nil {
+		panic(r.needNew("Intl.RelativeTimeFormat"))
+	}
+	var locales, options Value = _undefined, _undefined
+	if len(args) > 0 {
+		locales = args[0]
+	}
+	if len(args) > 1 {
+		options = args[1]
+	}
+	proto := r.getPrototypeFromCtor(newTarget, r.getRelativeTimeFormat(), r.getRelativeTimeFormatPrototype())
+	o := &Object{runtime: r}
+	f := &relativeTimeFormatObject{
+		f: r.newIntlRelativeTimeFormat(locales, options),
+	}
+	f.class = classObject
+	f.val = o
+	f.extensible = true
+	o.self = f
+	f.prototype = proto
+	f.init()
+	return o
+}
+
+func (r *Runtime) relativeTimeFormatParts(call FunctionCall, method string) []intlPart {
+	f := r.toRelativeTimeFormat(call.This, method).f
+	value := call.Argument(0).ToFloat()
+	unit := call.Argument(1).toString()
+	if math.IsNaN(value) || math.IsInf(value, 0) {
+		panic(r.newError(r.getRangeError(), "Invalid time value: %s", call.Argument(0).String()))
+	}
+	return f.formatToParts(value, r.intlRelativeTimeUnit(unit))
+}
+
+func (r *Runtime) relativeTimeFormatProto_format(call FunctionCall) Value {
+	return newStringValue(intlPartsString(r.relativeTimeFormatParts(call, "format")))
+}
+
+func (r *Runtime) relativeTimeFormatProto_formatToParts(call FunctionCall) Value {
+	return r.intlPartsToArray(r.relativeTimeFormatParts(call, "formatToParts"))
+}
+
+func (r *Runtime) relativeTimeFormatProto_resolvedOptions(call FunctionCall) Value {
+	f := r.toRelativeTimeFormat(call.This, "resolvedOptions").f
+	res := r.NewObject()
+	put := func(name unistring.String, value Value) {
+		res.self._putProp(name, value, true, true, true)
+	}
+	put("locale", newStringValue(f.locale.String()))
+	put("style", asciiString(f.style))
+	put("numeric", asciiString(f.numeric))
+	put("numberingSystem", asciiString("latn"))
+	return res
+}
+
+func (r *Runtime) relativeTimeFormat_supportedLocalesOf(call FunctionCall) Value {
+	return r.intlSupportedLocales(call.Argument(0), call.Argument(1), intlMatchLocale)
+}
+
+func createRelativeTimeFormatProtoTemplate() *objectTemplate {
+	t := newObjectTemplate()
+	t.protoFactory = func(r *Runtime) *Object {
+		return r.global.ObjectPrototype
+	}
+
+	t.putStr("constructor", func(r *Runtime) Value { return valueProp(r.getRelativeTimeFormat(), true, false, true) })
+	t.putStr("format", func(r *Runtime) Value { return r.methodProp(r.relativeTimeFormatProto_format, "format", 2) })
+	t.putStr("formatToParts", func(r *Runtime) Value {
+		return r.methodProp(r.relativeTimeFormatProto_formatToParts, "formatToParts", 2)
+	})
+	t.putStr("resolvedOptions", func(r *Runtime) Value {
+		return r.methodProp(r.relativeTimeFormatProto_resolvedOptions, "resolvedOptions", 0)
+	})
+
+	t.putSym(SymToStringTag, func(r *Runtime) Value {
+		return valueProp(asciiString("Intl.RelativeTimeFormat"), false, false, true)
+	})
+
+	return t
+}
+
+var relativeTimeFormatProtoTemplate *objectTemplate
+var relativeTimeFormatProtoTemplateOnce sync.Once
+
+func getRelativeTimeFormatProtoTemplate() *objectTemplate {
+	relativeTimeFormatProtoTemplateOnce.Do(func() {
+		relativeTimeFormatProtoTemplate = createRelativeTimeFormatProtoTemplate()
+	})
+	return relativeTimeFormatProtoTemplate
+}
+
+func (r *Runtime) getRelativeTimeFormatPrototype() *Object {
+	ret := r.global.RelativeTimeFormatPrototype
+	if ret == nil {
+		ret = &Object{runtime: r}
+		r.global.RelativeTimeFormatPrototype = ret
+		r.newTemplatedObject(getRelativeTimeFormatProtoTemplate(), ret)
+	}
+	return ret
+}
+
+func createRelativeTimeFormatTemplate() *objectTemplate {
+	t := newObjectTemplate()
+	t.protoFactory = func(r *Runtime) *Object {
+		return r.getFunctionPrototype()
+	}
+
+	t.putStr("name", func(r *Runtime) Value { return valueProp(asciiString("RelativeTimeFormat"), false, false, true) })
+	t.putStr("length", func(r *Runtime) Value { return valueProp(intToValue(0), false, false, true) })
+
+	t.putStr("prototype", func(r *Runtime) Value {
+		return valueProp(r.getRelativeTimeFormatPrototype(), false, false, false)
+	})
+
+	t.putStr("supportedLocalesOf", func(r *Runtime) Value {
+		return r.methodProp(r.relativeTimeFormat_supportedLocalesOf, "supportedLocalesOf", 1)
+	})
+
+	return t
+}
+
+var relativeTimeFormatTemplate *objectTemplate
+var relativeTimeFormatTemplateOnce sync.Once
+
+func getRelativeTimeFormatTemplate() *objectTemplate {
+	relativeTimeFormatTemplateOnce.Do(func() {
+		relativeTimeFormatTemplate = createRelativeTimeFormatTemplate()
+	})
+	return relativeTimeFormatTemplate
+}
+
+func (r *Runtime) getRelativeTimeFormat() *Object {
+	ret := r.global.RelativeTimeFormat
+	if ret == nil {
+		ret = &Object{runtime: r}
+		r.global.RelativeTimeFormat = ret
+		r.newTemplatedFuncObject(getRelativeTimeFormatTemplate(), ret, func(call FunctionCall) Value {
+			return r.builtin_newRelativeTimeFormat(call.Arguments, nil)
+		}, func(args []Value, newTarget *Object) *Object {
+			if newTarget == nil {
+				newTarget = ret
+			}
+			return r.builtin_newRelativeTimeFormat(args, newTarget)
+		})
+	}
+	return ret
+}
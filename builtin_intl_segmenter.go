@@ -0,0 +1,347 @@
+package goja
+
+import (
+	"io"
+	"math"
+	"sort"
+	"sync"
+
+	"github.com/dop251/goja/unistring"
+	"golang.org/x/text/language"
+)
+
+// intlSegmenter contains the resolved state of an Intl.Segmenter.
+type intlSegmenter struct {
+	locale      language.Tag
+	granularity string
+}
+
+type segmenterObject struct {
+	baseObject
+	s *intlSegmenter
+}
+
+// intlSegments holds a string split into segments.
+type intlSegments struct {
+	granularity string
+	input       String
+	runes       []rune
+	// offsets[i] is the UTF-16 index of runes[i], offsets[len(runes)] is the length of the string.
+	offsets []int
+	// Indexes of the segment boundaries in runes, including 0 and len(runes).
+	breaks []int
+}
+
+type segmentsObject struct {
+	baseObject
+	segments *intlSegments
+}
+
+type segmentIterObject struct {
+	baseObject
+	segments *intlSegments
+	// index into segments.breaks of the start of the next segment
+	pos int
+}
+
+func (s *intlSegmenter) segment(input String) *intlSegments {
+	segments := &intlSegments{
+		granularity: s.granularity,
+		input:       input,
+	}
+	reader := input.Reader()
+	offset := 0
+	for {
+		r, size, err := reader.ReadRune()
+		if err == io.EOF {
+			break
+		}
+		segments.runes = append(segments.runes, r)
+		segments.offsets = append(segments.offsets, offset)
+		offset += size
+	}
+	segments.offsets = append(segments.offsets, offset)
+	switch s.granularity {
+	case "word":
+		segments.breaks = intlWordBreaks(segments.runes)
+	case "sentence":
+		segments.breaks = intlSentenceBreaks(segments.runes)
+	default:
+		segments.breaks = intlGraphemeBreaks(segments.runes)
+	}
+	return segments
+}
+
+// segmentAt returns the index into breaks of the start of the segment containing the UTF-16 index idx.
+func (s *intlSegments) segmentAt(idx int) int {
+	n := sort.Search(len(s.breaks), func(i int) bool {
+		return s.offsets[s.breaks[i]] > idx
+	})
+	return n - 1
+}
+
+// createSegmentDataObject implements the CreateSegmentDataObject abstract operation
+// (https://tc39.es/ecma402/#sec-createsegmentdataobject).
+func (r *Runtime) createSegmentDataObject(s *intlSegments, n int) Value {
+	start, end := s.breaks[n], s.breaks[n+1]
+	o := r.NewObject()
+	o.self._putProp("segment", s.input.Substring(s.offsets[start], s.offsets[end]), true, true, true)
+	o.self._putProp("index", intToValue(int64(s.offsets[start])), true, true, true)
+	o.self._putProp("input", s.input, true, true, true)
+	if s.granularity == "word" {
+		o.self._putProp("isWordLike", r.toBoolean(intlIsWordLike(s.runes[start:end])), true, true, true)
+	}
+	return o
+}
+
+func (r *Runtime) newIntlSegmenter(locales, options Value) *intlSegmenter {
+	locale, _, _ := r.intlResolveLocale(locales)
+	opts := r.intlCoerceOptions(options)
+	r.intlGetOption(opts, "localeMatcher", []string{"lookup", "best fit"}, "best fit")
+	return &intlSegmenter{
+		locale:      locale,
+		granularity: r.intlGetOption(opts, "granularity", []string{"grapheme", "word", "sentence"}, "grapheme"),
+	}
+}
+
+func (r *Runtime) toSegmenter(v Value, method string) *segmenterObject {
+	if o, ok := v.(*Object); ok {
+		if s, ok := o.self.(*segmenterObject); ok {
+			return s
+		}
+	}
+	panic(r.NewTypeError("Method Intl.Segmenter.prototype.%s called on incompatible receiver %s", method, r.objectproto_toString(FunctionCall{This: v})))
+}
+
+func (r *Runtime) builtin_newSegmenter(args []Value, newTarget *Object) *Object {
+	if newTarget == nil {
+		panic(r.needNew("Intl.Segmenter"))
+	}
+	var locales, options Value = _undefined, _undefined
+	if len(args) > 0 {
+		locales = args[0]
+	}
+	if len(args) > 1 {
+		options = args[1]
+	}
+	proto := r.getPrototypeFromCtor(newTarget, r.getSegmenter(), r.getSegmenterPrototype())
+	o := &Object{runtime: r}
+	s := &segmenterObject{
+		s: r.newIntlSegmenter(locales, options),
+	}
+	s.class = classObject
+	s.val = o
+	s.extensible = true
+	o.self = s
+	s.prototype = proto
+	s.init()
+	return o
+}
+
+func (r *Runtime) segmenterProto_segment(call FunctionCall) Value {
+	s := r.toSegmenter(call.This, "segment").s
+	o := &Object{runtime: r}
+	so := &segmentsObject{
+		segments: s.segment(call.Argument(0).toString()),
+	}
+	so.class = classObject
+	so.val = o
+	so.extensible = true
+	o.self = so
+	so.prototype = r.getSegmentsPrototype()
+	so.init()
+	return o
+}
+
+func (r *Runtime) segmenterProto_resolvedOptions(call FunctionCall) Value {
+	s := r.toSegmenter(call.This, "resolvedOptions").s
+	res := r.NewObject()
+	put := func(name unistring.String, value Value) {
+		res.self._putProp(name, value, true, true, true)
+	}
+	put("locale", newStringValue(s.locale.String()))
+	put("granularity", asciiString(s.granularity))
+	return res
+}
+
+func (r *Runtime) segmenter_supportedLocalesOf(call FunctionCall) Value {
+	return r.intlSupportedLocales(call.Argument(0), call.Argument(1), intlMatchLocale)
+}
+
+func (r *Runtime) toSegments(v Value, method string) *intlSegments {
+	if o, ok := v.(*Object); ok {
+		if s, ok := o.self.(*segmentsObject); ok {
+			return s.segments
+		}
+	}
+	panic(r.NewTypeError("Method %%Segments.prototype%%.%s called on incompatible receiver %s", method, r.objectproto_toString(FunctionCall{This: v})))
+}
+
+func (r *Runtime) segmentsProto_containing(call FunctionCall) Value {
+	s := r.toSegments(call.This, "containing")
+	n := call.Argument(0).ToNumber().ToFloat()
+	if math.IsNaN(n) {
+		n = 0
+	}
+	n = math.Trunc(n)
+	if n < 0 || n >= float64(s.input.Length()) {
+		return _undefined
+	}
+	return r.createSegmentDataObject(s, s.segmentAt(int(n)))
+}
+
+func (r *Runtime) segmentsProto_iterator(call FunctionCall) Value {
+	s := r.toSegments(call.This, "[Symbol.iterator]")
+	o := &Object{runtime: r}
+	si := &segmentIterObject{
+		segments: s,
+	}
+	si.class = classObject
+	si.val = o
+	si.extensible = true
+	o.self = si
+	si.prototype = r.getSegmentIteratorPrototype()
+	si.init()
+	return o
+}
+
+func (si *segmentIterObject) next() Value {
+	r := si.val.runtime
+	s := si.segments
+	if si.pos >= len(s.breaks)-1 {
+		return r.createIterResultObject(_undefined, true)
+	}
+	res := r.createSegmentDataObject(s, si.pos)
+	si.pos++
+	return r.createIterResultObject(res, false)
+}
+
+func (r *Runtime) segmentIteratorProto_next(call FunctionCall) Value {
+	thisObj := r.toObject(call.This)
+	if iter, ok := thisObj.self.(*segmentIterObject); ok {
+		return iter.next()
+	}
+	panic(r.NewTypeError("Method %s.prototype.next called on incompatible receiver %s", classSegmentIterator, r.objectproto_toString(FunctionCall{This: thisObj})))
+}
+
+func (r *Runtime) createSegmentsPrototype(val *Object) objectImpl {
+	o := newBaseObjectObj(val, r.global.ObjectPrototype, classObject)
+
+	o._putProp("containing", r.newNativeFunc(r.segmentsProto_containing, "containing", 1), true, false, true)
+	o._putSym(SymIterator, valueProp(r.newNativeFunc(r.segmentsProto_iterator, "[Symbol.iterator]", 0), true, false, true))
+
+	return o
+}
+
+func (r *Runtime) getSegmentsPrototype() *Object {
+	var o *Object
+	if o = r.global.SegmentsPrototype; o == nil {
+		o = &Object{runtime: r}
+		r.global.SegmentsPrototype = o
+		o.self = r.createSegmentsPrototype(o)
+	}
+	return o
+}
+
+func (r *Runtime) createSegmentIteratorPrototype(val *Object) objectImpl {
+	o := newBaseObjectObj(val, r.getIteratorPrototype(), classObject)
+
+	o._putProp("next", r.newNativeFunc(r.segmentIteratorProto_next, "next", 0), true, false, true)
+	o._putSym(SymToStringTag, valueProp(asciiString(classSegmentIterator), false, false, true))
+
+	return o
+}
+
+func (r *Runtime) getSegmentIteratorPrototype() *Object {
+	var o *Object
+	if o = r.global.SegmentIteratorPrototype; o == nil {
+		o = &Object{runtime: r}
+		r.global.SegmentIteratorPrototype = o
+		o.self = r.createSegmentIteratorPrototype(o)
+	}
+	return o
+}
+
+func createSegmenterProtoTemplate() *objectTemplate {
+	t := newObjectTemplate()
+	t.protoFactory = func(r *Runtime) *Object {
+		return r.global.ObjectPrototype
+	}
+
+	t.putStr("constructor", func(r *Runtime) Value { return valueProp(r.getSegmenter(), true, false, true) })
+	t.putStr("segment", func(r *Runtime) Value { return r.methodProp(r.segmenterProto_segment, "segment", 1) })
+	t.putStr("resolvedOptions", func(r *Runtime) Value {
+		return r.methodProp(r.segmenterProto_resolvedOptions, "resolvedOptions", 0)
+	})
+
+	t.putSym(SymToStringTag, func(r *Runtime) Value {
+		return valueProp(asciiString("Intl.Segmenter"), false, false, true)
+	})
+
+	return t
+}
+
+var segmenterProtoTemplate *objectTemplate
+var segmenterProtoTemplateOnce sync.Once
+
+func getSegmenterProtoTemplate() *objectTemplate {
+	segmenterProtoTemplateOnce.Do(func() {
+		segmenterProtoTemplate = createSegmenterProtoTemplate()
+	})
+	return segmenterProtoTemplate
+}
+
+func (r *Runtime) getSegmenterPrototype() *Object {
+	ret := r.global.SegmenterPrototype
+	if ret == nil {
+		ret = &Object{runtime: r}
+		r.global.SegmenterPrototype = ret
+		r.newTemplatedObject(getSegmenterProtoTemplate(), ret)
+	}
+	return ret
+}
+
+func createSegmenterTemplate() *objectTemplate {
+	t := newObjectTemplate()
+	t.protoFactory = func(r *Runtime) *Object {
+		return r.getFunctionPrototype()
+	}
+
+	t.putStr("name", func(r *Runtime) Value { return valueProp(asciiString("Segmenter"), false, false, true) })
+	t.putStr("length", func(r *Runtime) Value { return valueProp(intToValue(0), false, false, true) })
+
+	t.putStr("prototype", func(r *Runtime) Value { return valueProp(r.getSegmenterPrototype(), false, false, false) })
+
+	t.putStr("supportedLocalesOf", func(r *Runtime) Value {
+		return r.methodProp(r.segmenter_supportedLocalesOf, "supportedLocalesOf", 1)
+	})
+
+	return t
+}
+
+var segmenterTemplate *objectTemplate
+var segmenterTemplateOnce sync.Once
+
+func getSegmenterTemplate() *objectTemplate {
+	segmenterTemplateOnce.Do(func() {
+		segmenterTemplate = createSegmenterTemplate()
+	})
+	return segmenterTemplate
+}
+
+func (r *Runtime) getSegmenter() *Object {
+	ret := r.global.Segmenter
+	if ret == nil {
+		ret = &Object{runtime: r}
+		r.global.Segmenter = ret
+		r.newTemplatedFuncObject(getSegmenterTemplate(), ret, func(call FunctionCall) Value {
+			return r.builtin_newSegmenter(call.Arguments, nil)
+		}, func(args []Value, newTarget *Object) *Object {
+			if newTarget == nil {
+				newTarget = ret
+			}
+			return r.builtin_newSegmenter(args, newTarget)
+		})
+	}
+	return ret
+}
@@ -0,0 +1,370 @@
+package goja
+
+import (
+	"testing"
+)
+
+func TestIntlDateTimeFormat(t *testing.T) {
+	const SCRIPT = `
+	var d = new Date(Date.UTC(2006, 0, 2, 15, 4, 5, 123));
+	var utc = {timeZone: "UTC"};
+
+	assert.sameValue(new Intl.DateTimeFormat("en-US", utc).format(d), "1/2/2006");
+	assert.sameValue(Intl.DateTimeFormat("en-GB", utc).format(d), "02/01/2006");
+	assert.sameValue(new Intl.DateTimeFormat("en", {timeZone: "UTC", dateStyle: "full", timeStyle: "long"}).format(d), "Monday, January 2, 2006 at 3:04:05 PM UTC");
+	assert.sameValue(new Intl.DateTimeFormat("en", {timeZone: "UTC", dateStyle: "medium", timeStyle: "short", hour12: false}).format(d), "Jan 2, 2006, 15:04");
+	assert.sameValue(new Intl.DateTimeFormat("en", {timeZone: "UTC", month: "short", day: "numeric", hour: "2-digit", minute: "2-digit"}).format(d), "Jan 2, 03:04 PM");
+	assert.sameValue(new Intl.DateTimeFormat("en", {timeZone: "UTC", hour: "numeric", minute: "numeric", second: "numeric", fractionalSecondDigits: 3}).format(d), "3:04:05.123 PM");
+	assert.sameValue(new Intl.DateTimeFormat("en", {timeZone: "America/New_York", hour: "numeric", timeZoneName: "short"}).format(d), "10 AM GMT-5");
+	assert.sameValue(new Intl.DateTimeFormat("en", {timeZone: "+05:30", hour: "numeric", minute: "numeric"}).format(d), "8:34 PM");
+	assert.sameValue(new Intl.DateTimeFormat("en", {timeZone: "UTC", year: "numeric", era: "short"}).format(new Date(Date.UTC(-50, 0, 1))), "51 BC");
+
+	var parts = new Intl.DateTimeFormat("en", utc).formatToParts(d);
+	assert.sameValue(parts.map(function(p) { return p.type; }).join(), "month,literal,day,literal,year");
+	assert.sameValue(parts.map(function(p) { return p.value; }).join(""), "1/2/2006");
+
+	var opts = new Intl.DateTimeFormat("en-u-hc-h23", {timeZone: "UTC", hour: "numeric", minute: "numeric"}).resolvedOptions();
+	assert.sameValue(opts.locale, "en");
+	assert.sameValue(opts.timeZone, "UTC");
+	assert.sameValue(opts.hourCycle, "h23");
+	assert.sameValue(opts.hour12, false);
+	assert.sameValue(opts.year, undefined);
+
+	// time zone names are case-insensitive, the canonical spelling is reported
+	var ny = new Intl.DateTimeFormat("en", {timeZone: "america/new_york", hour: "numeric"});
+	assert.sameValue(ny.resolvedOptions().timeZone, "America/New_York");
+	assert.sameValue(ny.format(d), "10 AM");
+	assert.sameValue(new Intl.DateTimeFormat("en", {timeZone: "EUROPE/LONDON"}).resolvedOptions().timeZone, "Europe/London");
+	assert.sameValue(new Intl.DateTimeFormat("en", {timeZone: "etc/utc"}).resolvedOptions().timeZone, "UTC");
+
+	assert.sameValue(Object.prototype.toString.call(new Intl.DateTimeFormat()), "[object Intl.DateTimeFormat]");
+
+	var dtf = new Intl.DateTimeFormat("en", utc);
+	assert.sameValue(dtf.format, dtf.format, "format is cached");
+	assert.sameValue([d].map(dtf.format)[0], "1/2/2006");
+
+	assert.throws(RangeError, function() { dtf.format(NaN); });
+	assert.throws(RangeError, function() { new Intl.DateTimeFormat("en", {timeZone: "Nowhere/Nothing"}); });
+	assert.throws(RangeError, function() { new Intl.DateTimeFormat("en", {timeZone: "local"}); });
+	assert.throws(RangeError, function() { new Intl.DateTimeFormat("en", {month: "tiny"}); });
+	assert.throws(TypeError, function() { new Intl.DateTimeFormat("en", {dateStyle: "short", month: "long"}); });
+	assert.throws(TypeError, function() { Intl.DateTimeFormat.prototype.formatToParts.call({}, d); });
+	`
+	testScriptWithTestLib(SCRIPT, _undefined, t)
+}
+
+func TestDateToLocaleString(t *testing.T) {
+	const SCRIPT = `
+	var d = new Date(Date.UTC(2006, 0, 2, 15, 4, 5));
+	var utc = {timeZone: "UTC"};
+
+	assert.sameValue(d.toLocaleString("en-US", utc), "1/2/2006, 3:04:05 PM");
+	assert.sameValue(d.toLocaleDateString("en-GB", utc), "02/01/2006");
+	assert.sameValue(d.toLocaleDateString("en", {timeZone: "UTC", dateStyle: "full"}), "Monday, January 2, 2006");
+	assert.sameValue(d.toLocaleTimeString("en", utc), "3:04:05 PM");
+	assert.sameValue(d.toLocaleTimeString("en", {timeZone: "UTC", hour: "numeric"}), "3 PM");
+	assert.sameValue(d.toLocaleTimeString("en", {timeZone: "UTC", hour: "numeric", dayPeriod: "short"}), "3 in the afternoon");
+	assert.sameValue(d.toLocaleDateString("en", {timeZone: "UTC", hour: "numeric"}), "1/2/2006, 3 PM");
+
+	assert.sameValue(new Date(NaN).toLocaleString(), "Invalid Date");
+	assert.throws(TypeError, function() { d.toLocaleDateString("en", {timeStyle: "short"}); });
+	assert.throws(TypeError, function() { d.toLocaleTimeString("en", {dateStyle: "short"}); });
+	assert.throws(RangeError, function() { d.toLocaleString("not a locale"); });
+	`
+	testScriptWithTestLib(SCRIPT, _undefined, t)
+}
+
+func TestIntlNumberFormat(t *testing.T) {
+	const SCRIPT = `
+	function fmt(n, locale, opts) {
+		return new Intl.NumberFormat(locale, opts).format(n);
+	}
+
+	assert.sameValue(fmt(1234567.891, "en"), "1,234,567.891");
+	assert.sameValue(fmt(1234567.5, "en-IN"), "12,34,567.5");
+	assert.sameValue(fmt(1234567, "en", {useGrouping: false}), "1234567");
+
+	assert.sameValue(fmt(1234.5, "en-US", {style: "currency", currency: "USD"}), "$1,234.50");
+	assert.sameValue(fmt(-1234.5, "en", {style: "currency", currency: "USD", currencySign: "accounting"}), "($1,234.50)");
+	assert.sameValue(fmt(1234.5, "en", {style: "currency", currency: "eur", currencyDisplay: "code"}), "EUR\u00a01,234.50");
+	assert.sameValue(fmt(2, "en", {style: "currency", currency: "USD", currencyDisplay: "name", maximumFractionDigits: 0}), "2 US dollars");
+
+	assert.sameValue(fmt(0.256, "en", {style: "percent"}), "26%");
+
+	assert.sameValue(fmt(16, "en", {style: "unit", unit: "kilometer-per-hour"}), "16 km/h");
+	assert.sameValue(fmt(1, "en", {style: "unit", unit: "liter", unitDisplay: "long"}), "1 liter");
+	assert.sameValue(fmt(5, "en", {style: "unit", unit: "megabyte-per-second", unitDisplay: "long"}), "5 megabytes per second");
+
+	assert.sameValue([999, 1234, 12345, 999999, 1500000, 2.5e9].map(function(n) {
+		return fmt(n, "en", {notation: "compact"});
+	}).join(), "999,1.2K,12K,1M,1.5M,2.5B");
+	assert.sameValue(fmt(1234567, "en", {notation: "compact", compactDisplay: "long"}), "1.2 million");
+	assert.sameValue(fmt(123456, "en", {notation: "scientific"}), "1.235E5");
+	assert.sameValue(fmt(0.00012345, "en", {notation: "engineering"}), "123.45E-6");
+
+	assert.sameValue(fmt(1.005, "en", {maximumFractionDigits: 2}), "1.01");
+	assert.sameValue(fmt(1234.5678, "en", {maximumSignificantDigits: 3}), "1,230");
+	assert.sameValue(fmt(1.5, "en", {minimumSignificantDigits: 4}), "1.500");
+	assert.sameValue(fmt(5, "en", {minimumIntegerDigits: 3}), "005");
+	assert.sameValue([-0, 0, 5, -5, NaN, Infinity].map(function(n) {
+		return fmt(n, "en", {signDisplay: "exceptZero"});
+	}).join(" "), "0 0 +5 -5 NaN +∞");
+
+	var opts = new Intl.NumberFormat("en", {style: "currency", currency: "usd"}).resolvedOptions();
+	assert.sameValue(opts.currency, "USD");
+	assert.sameValue(opts.minimumFractionDigits, 2);
+	assert.sameValue(opts.maximumFractionDigits, 2);
+	assert.sameValue(opts.minimumSignificantDigits, undefined);
+	assert.sameValue(new Intl.NumberFormat("en", {maximumFractionDigits: 1}).resolvedOptions().minimumFractionDigits, 0);
+
+	var nf = new Intl.NumberFormat("en");
+	assert.sameValue(nf.format, nf.format, "format is cached");
+	assert.sameValue(Object.prototype.toString.call(nf), "[object Intl.NumberFormat]");
+
+	assert.throws(TypeError, function() { new Intl.NumberFormat("en", {style: "currency"}); });
+	assert.throws(TypeError, function() { new Intl.NumberFormat("en", {style: "unit"}); });
+	assert.throws(RangeError, function() { new Intl.NumberFormat("en", {style: "currency", currency: "EURO"}); });
+	assert.throws(RangeError, function() { new Intl.NumberFormat("en", {style: "unit", unit: "parsec"}); });
+	assert.throws(RangeError, function() { new Intl.NumberFormat("en", {minimumFractionDigits: 2, maximumFractionDigits: 1}); });
+	`
+	testScriptWithTestLib(SCRIPT, _undefined, t)
+}
+
+func TestNumberToLocaleString(t *testing.T) {
+	const SCRIPT = `
+	assert.sameValue((1234567.891).toLocaleString(), "1,234,567.891");
+	assert.sameValue((-0).toLocaleString(), "-0");
+	assert.sameValue(new Number(1e21).toLocaleString("en"), "1,000,000,000,000,000,000,000");
+	assert.throws(TypeError, function() { Number.prototype.toLocaleString.call("1"); });
+	`
+	testScriptWithTestLib(SCRIPT, _undefined, t)
+}
+
+func TestIntlCollator(t *testing.T) {
+	const SCRIPT = `
+	function sorted(arr, locale, opts) {
+		return arr.slice().sort(new Intl.Collator(locale, opts).compare).join();
+	}
+
+	assert.sameValue(sorted(["b", "a", "B", "A", "ä", "z"], "en"), "a,A,ä,b,B,z");
+	assert.sameValue(sorted(["b", "a", "B", "A", "ä", "z"], "en", {caseFirst: "upper"}), "A,a,ä,B,b,z");
+	assert.sameValue(sorted(["b", "a", "ä", "z"], "sv"), "a,b,z,ä");
+	assert.sameValue(sorted(["b", "a", "ä", "z"], "de-AT"), "a,ä,b,z");
+	assert.sameValue(sorted(["item10", "item2", "item1"], "en", {numeric: true}), "item1,item2,item10");
+	assert.sameValue(sorted(["item10", "item2", "item1"], "en-u-kn-true"), "item1,item2,item10");
+
+	function cmp(a, b, sensitivity) {
+		return new Intl.Collator("en", {sensitivity: sensitivity}).compare(a, b);
+	}
+	assert.sameValue(cmp("a", "Á", "base"), 0);
+	assert.sameValue(cmp("a", "b", "base"), -1);
+	assert.sameValue(cmp("a", "A", "accent"), 0);
+	assert.sameValue(cmp("a", "á", "accent"), -1);
+	assert.sameValue(cmp("a", "á", "case"), 0);
+	assert.sameValue(cmp("a", "A", "case"), -1);
+	assert.sameValue(cmp("a", "A", "variant"), -1);
+	assert.sameValue(new Intl.Collator("en", {ignorePunctuation: true}).compare("a-b", "ab"), 0);
+
+	var opts = new Intl.Collator("de-u-kf-upper").resolvedOptions();
+	assert.sameValue(opts.locale, "de");
+	assert.sameValue(opts.caseFirst, "upper");
+	assert.sameValue(opts.sensitivity, "variant");
+	assert.sameValue(opts.numeric, false);
+	assert.sameValue(new Intl.Collator("xx").resolvedOptions().locale, "en-US");
+	assert.sameValue(Intl.Collator.supportedLocalesOf(["sv", "xx", "de-AT"]).join(), "sv,de-AT");
+
+	var c = new Intl.Collator();
+	assert.sameValue(c.compare, c.compare, "compare is cached");
+	assert.sameValue(Object.prototype.toString.call(c), "[object Intl.Collator]");
+	assert.throws(RangeError, function() { new Intl.Collator("en", {sensitivity: "none"}); });
+	`
+	testScriptWithTestLib(SCRIPT, _undefined, t)
+}
+
+func TestStringLocaleCompare(t *testing.T) {
+	const SCRIPT = `
+	assert.sameValue("a".localeCompare("B"), -1);
+	assert.sameValue("ä".localeCompare("z", "sv"), 1);
+	assert.sameValue("ä".localeCompare("z", "de"), -1);
+	assert.sameValue("a".localeCompare("A", undefined, {sensitivity: "base"}), 0);
+	assert.sameValue("10".localeCompare("9", "en", {numeric: true}), 1);
+	`
+	testScriptWithTestLib(SCRIPT, _undefined, t)
+}
+
+func TestRuntimeSetDefaultLocale(t *testing.T) {
+	vm := New()
+	if err := vm.SetDefaultLocale("not a locale!"); err == nil {
+		t.Fatal("Expected an error")
+	}
+	if err := vm.SetDefaultLocale("sv-SE"); err != nil {
+		t.Fatal(err)
+	}
+	v, err := vm.RunString(`
+	[
+		"ä".localeCompare("z"),
+		new Intl.Collator().resolvedOptions().locale,
+		new Intl.NumberFormat().format(1234.5),
+		new Date(Date.UTC(2006, 0, 2)).toLocaleDateString(undefined, {timeZone: "UTC"})
+	].join("|")
+	`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// There is no bundled data for Swedish, so only the collation is affected.
+	if s := v.String(); s != "1|sv-SE|1,234.5|1/2/2006" {
+		t.Fatal(s)
+	}
+}
+
+func TestIntlPluralRules(t *testing.T) {
+	const SCRIPT = `
+	var pr = new Intl.PluralRules("en");
+	assert.sameValue(pr.select(0), "other");
+	assert.sameValue(pr.select(1), "one");
+	assert.sameValue(pr.select(2), "other");
+	assert.sameValue(pr.select(1.5), "other");
+	assert.sameValue(new Intl.PluralRules("en", {minimumFractionDigits: 1}).select(1), "other");
+
+	var ord = new Intl.PluralRules("en", {type: "ordinal"});
+	assert.sameValue([1, 2, 3, 4, 11, 21, 22, 23, 101].map(function(n) { return ord.select(n); }).join(), "one,two,few,other,other,one,two,few,one");
+
+	assert.sameValue(ord.resolvedOptions().pluralCategories.join(), "one,two,few,other");
+
+	assert.sameValue(Object.prototype.toString.call(pr), "[object Intl.PluralRules]");
+	assert.throws(TypeError, function() { Intl.PluralRules(); });
+	assert.throws(RangeError, function() { new Intl.PluralRules("en", {type: "none"}); });
+	`
+	testScriptWithTestLib(SCRIPT, _undefined, t)
+}
+
+func TestIntlRelativeTimeFormat(t *testing.T) {
+	const SCRIPT = `
+	var rtf = new Intl.RelativeTimeFormat("en");
+	assert.sameValue(rtf.format(3, "day"), "in 3 days");
+	assert.sameValue(rtf.format(-3, "days"), "3 days ago");
+	assert.sameValue(rtf.format(1, "hour"), "in 1 hour");
+	assert.sameValue(rtf.format(-1, "day"), "1 day ago");
+	assert.sameValue(rtf.format(-0, "second"), "0 seconds ago");
+	assert.sameValue(rtf.format(1234.5, "year"), "in 1,234.5 years");
+
+	var auto = new Intl.RelativeTimeFormat("en", {numeric: "auto"});
+	assert.sameValue(auto.format(-1, "day"), "yesterday");
+	assert.sameValue(auto.format(0, "year"), "this year");
+	assert.sameValue(auto.format(1, "week"), "next week");
+	assert.sameValue(auto.format(-2, "day"), "2 days ago");
+	assert.sameValue(new Intl.RelativeTimeFormat("en", {style: "short"}).format(-2, "month"), "2 mo. ago");
+	assert.sameValue(new Intl.RelativeTimeFormat("en", {style: "narrow"}).format(5, "minute"), "in 5 min.");
+
+	var parts = rtf.formatToParts(100, "day");
+	assert.sameValue(parts.length, 3);
+	assert.sameValue(parts[0].type, "literal");
+	assert.sameValue(parts[0].value, "in ");
+	assert.sameValue(parts[1].type, "integer");
+	assert.sameValue(parts[1].value, "100");
+	assert.sameValue(parts[1].unit, "day");
+	assert.sameValue(parts[2].value, " days");
+	assert.sameValue(parts[2].unit, undefined);
+
+	var opts = auto.resolvedOptions();
+	assert.sameValue(opts.locale, "en");
+	assert.sameValue(opts.style, "long");
+	assert.sameValue(opts.numeric, "auto");
+	assert.sameValue(opts.numberingSystem, "latn");
+
+	assert.throws(RangeError, function() { rtf.format(1, "decade"); });
+	assert.throws(RangeError, function() { rtf.format(NaN, "day"); });
+	assert.throws(TypeError, function() { Intl.RelativeTimeFormat(); });
+	`
+	testScriptWithTestLib(SCRIPT, _undefined, t)
+}
+
+func TestIntlListFormat(t *testing.T) {
+	const SCRIPT = `
+	var list = ["a", "b", "c"];
+	assert.sameValue(new Intl.ListFormat("en").format(list), "a, b, and c");
+	assert.sameValue(new Intl.ListFormat("en").format(["a", "b"]), "a and b");
+	assert.sameValue(new Intl.ListFormat("en").format(["a"]), "a");
+	assert.sameValue(new Intl.ListFormat("en").format([]), "");
+	assert.sameValue(new Intl.ListFormat("en").format(), "");
+	assert.sameValue(new Intl.ListFormat("en").format(["a", "b", "c", "d"]), "a, b, c, and d");
+	assert.sameValue(new Intl.ListFormat("en-GB").format(list), "a, b and c");
+	assert.sameValue(new Intl.ListFormat("en", {type: "disjunction"}).format(list), "a, b, or c");
+	assert.sameValue(new Intl.ListFormat("en", {style: "short"}).format(list), "a, b, & c");
+	assert.sameValue(new Intl.ListFormat("en", {type: "unit", style: "narrow"}).format(list), "a b c");
+	assert.sameValue(new Intl.ListFormat("en").format(new Set(["x", "y"])), "x and y");
+
+	var parts = new Intl.ListFormat("en").formatToParts(["a", "b"]);
+	assert.sameValue(parts.map(function(p) { return p.type + ":" + p.value; }).join("|"), "element:a|literal: and |element:b");
+
+	var opts = new Intl.ListFormat("en", {type: "unit"}).resolvedOptions();
+	assert.sameValue(opts.locale, "en");
+	assert.sameValue(opts.type, "unit");
+	assert.sameValue(opts.style, "long");
+
+	assert.throws(TypeError, function() { new Intl.ListFormat("en").format(["a", 1]); });
+	assert.throws(TypeError, function() { Intl.ListFormat(); });
+	assert.throws(RangeError, function() { new Intl.ListFormat("en", {style: "tiny"}); });
+	`
+	testScriptWithTestLib(SCRIPT, _undefined, t)
+}
+
+func TestIntlSegmenter(t *testing.T) {
+	const SCRIPT = `
+	function segments(granularity, s) {
+		var res = [];
+		for (var seg of new Intl.Segmenter("en", {granularity: granularity}).segment(s)) {
+			res.push(seg.segment);
+		}
+		return res;
+	}
+
+	assert.sameValue(segments("grapheme", "aéb").join("|"), ["a", "é", "b"].join("|"));
+	assert.sameValue(segments("grapheme", "\u{1F468}‍\u{1F469}‍\u{1F467}!").join("|"), ["\u{1F468}‍\u{1F469}‍\u{1F467}", "!"].join("|"));
+	assert.sameValue(segments("grapheme", "\u{1F1FA}\u{1F1F8}\u{1F1EB}\u{1F1F7}\u{1F1E9}").join("|"), ["\u{1F1FA}\u{1F1F8}", "\u{1F1EB}\u{1F1F7}", "\u{1F1E9}"].join("|"));
+	assert.sameValue(segments("grapheme", "\u{1F44D}\u{1F3FD}\r\n각").join("|"), ["\u{1F44D}\u{1F3FD}", "\r\n", "각"].join("|"));
+	assert.sameValue(segments("grapheme", "").join("|"), [].join("|"));
+
+	assert.sameValue(segments("word", "Hello, world! It's 3.14 e.g. foo_bar").join("|"), ["Hello", ",", " ", "world", "!", " ", "It's", " ", "3.14", " ", "e.g", ".", " ", "foo_bar"].join("|"));
+	assert.sameValue(segments("word", "日本語").join("|"), ["日", "本", "語"].join("|"));
+	assert.sameValue(segments("word", "カタカナ  x").join("|"), ["カタカナ", "  ", "x"].join("|"));
+
+	assert.sameValue(segments("sentence", "Hello there. How are you? I'm fine (really).  Mr. Smith is e.g. here.\nNext").join("|"),
+		["Hello there. ", "How are you? ", "I'm fine (really).  ", "Mr. ", "Smith is e.g. here.\n", "Next"].join("|"));
+
+	var word = new Intl.Segmenter("en", {granularity: "word"}).segment("Hi, you");
+	var all = Array.from(word);
+	assert.sameValue(all.length, 4);
+	assert.sameValue(all[0].segment, "Hi");
+	assert.sameValue(all[0].index, 0);
+	assert.sameValue(all[0].input, "Hi, you");
+	assert.sameValue(all[0].isWordLike, true);
+	assert.sameValue(all[1].isWordLike, false);
+	assert.sameValue(all[3].index, 4);
+
+	var seg = word.containing(5);
+	assert.sameValue(seg.segment, "you");
+	assert.sameValue(seg.index, 4);
+	assert.sameValue(word.containing(2).segment, ",");
+	assert.sameValue(word.containing(-1), undefined);
+	assert.sameValue(word.containing(7), undefined);
+	assert.sameValue(word.containing().segment, "Hi");
+
+	var graphemes = new Intl.Segmenter().segment("a\u{1F600}b");
+	assert.sameValue(graphemes.containing(2).segment, "\u{1F600}");
+	assert.sameValue(graphemes.containing(3).index, 3);
+	assert.sameValue(graphemes.containing(0).isWordLike, undefined);
+
+	var iter = graphemes[Symbol.iterator]();
+	assert.sameValue(Object.prototype.toString.call(iter), "[object Segmenter String Iterator]");
+	assert.sameValue(Object.getPrototypeOf(Object.getPrototypeOf(iter)), Object.getPrototypeOf(Object.getPrototypeOf([][Symbol.iterator]())));
+	assert.sameValue(iter.next().value.segment, "a");
+
+	assert.sameValue(new Intl.Segmenter("en", {granularity: "sentence"}).resolvedOptions().granularity, "sentence");
+	assert.sameValue(new Intl.Segmenter().resolvedOptions().granularity, "grapheme");
+	assert.sameValue(Object.prototype.toString.call(new Intl.Segmenter()), "[object Intl.Segmenter]");
+	assert.throws(TypeError, function() { Intl.Segmenter(); });
+	assert.throws(RangeError, function() { new Intl.Segmenter("en", {granularity: "line"}); });
+	`
+	testScriptWithTestLib(SCRIPT, _undefined, t)
+}
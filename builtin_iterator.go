@@ -0,0 +1,585 @@
+package goja
+
+import (
+	"math"
+)
+
+type iteratorHelperState uint8
+
+const (
+	iteratorHelperSuspendedStart iteratorHelperState = iota
+	iteratorHelperSuspendedYield
+	iteratorHelperExecuting
+	iteratorHelperCompleted
+)
+
+// iteratorHelperObject is an Iterator Helper object (https://tc39.es/ecma262/#sec-iterator-helper-objects).
+// Instead of a generator it uses a step function that produces values on demand.
+type iteratorHelperObject struct {
+	baseObject
+	iterated *iteratorRecord
+	// step returns the next value, ok is false when there are no more values.
+	step func() (value Value, ok bool)
+	// The current inner iterator of flatMap().
+	inner *iteratorRecord
+	state iteratorHelperState
+}
+
+// iteratorWrapObject is an object created by Iterator.from() for iterators that do not inherit from
+// %Iterator.prototype% (https://tc39.es/ecma262/#sec-%wrapforvaliditeratorprototype%-object).
+type iteratorWrapObject struct {
+	baseObject
+	iterated *iteratorRecord
+}
+
+func (it *iteratorHelperObject) next() Value {
+	r := it.val.runtime
+	switch it.state {
+	case iteratorHelperExecuting:
+		panic(r.NewTypeError("Generator is already running"))
+	case iteratorHelperCompleted:
+		return r.createIterResultObject(_undefined, true)
+	}
+	it.state = iteratorHelperExecuting
+	finished := true
+	defer func() {
+		if finished {
+			it.state = iteratorHelperCompleted
+		}
+	}()
+	value, ok := it.step()
+	if !ok {
+		return r.createIterResultObject(_undefined, true)
+	}
+	finished = false
+	it.state = iteratorHelperSuspendedYield
+	return r.createIterResultObject(value, false)
+}
+
+func (it *iteratorHelperObject) _return() Value {
+	r := it.val.runtime
+	switch it.state {
+	case iteratorHelperExecuting:
+		panic(r.NewTypeError("Generator is already running"))
+	case iteratorHelperCompleted:
+		return r.createIterResultObject(_undefined, true)
+	}
+	it.state = iteratorHelperCompleted
+	if inner := it.inner; inner != nil {
+		it.inner = nil
+		it.iterated.closeOnPanic(inner.returnIter)
+	}
+	it.iterated.returnIter()
+	return r.createIterResultObject(_undefined, true)
+}
+
+// stepValue implements the IteratorStepValue abstract operation (https://tc39.es/ecma262/#sec-iteratorstepvalue).
+func (ir *iteratorRecord) stepValue() (Value, bool) {
+	r := ir.iterator.runtime
+	if ir.next == nil {
+		panic(r.NewTypeError("iterator.next is missing or not a function"))
+	}
+	res, ok := ir.next(FunctionCall{This: ir.iterator}).(*Object)
+	if !ok {
+		panic(r.NewTypeError("Iterator result is not an object"))
+	}
+	if iteratorComplete(res) {
+		return nil, false
+	}
+	return iteratorValue(res), true
+}
+
+// closeOnPanic runs f and closes the iterator if f throws.
+func (ir *iteratorRecord) closeOnPanic(f func()) {
+	if ret := tryFunc(f); ret != nil {
+		_ = tryFunc(ir.returnIter)
+		panic(ret)
+	}
+}
+
+// getIteratorDirect implements the GetIteratorDirect abstract operation
+// (https://tc39.es/ecma262/#sec-getiteratordirect).
+func (r *Runtime) getIteratorDirect(obj *Object) *iteratorRecord {
+	ir := &iteratorRecord{
+		iterator: obj,
+	}
+	if next, ok := obj.self.getStr("next", nil).(*Object); ok {
+		ir.next, _ = next.self.assertCallable()
+	}
+	return ir
+}
+
+// getIteratorFlattenable implements the GetIteratorFlattenable abstract operation
+// (https://tc39.es/ecma262/#sec-getiteratorflattenable).
+func (r *Runtime) getIteratorFlattenable(v Value, iterateStrings bool) *iteratorRecord {
+	if _, ok := v.(*Object); !ok {
+		if _, isString := v.(String); !isString || !iterateStrings {
+			panic(r.NewTypeError("%s is not an object", v.String()))
+		}
+	}
+	iter := v
+	if method := toMethod(r.getV(v, SymIterator)); method != nil {
+		iter = method(FunctionCall{This: v})
+	}
+	iterObj, ok := iter.(*Object)
+	if !ok {
+		panic(r.NewTypeError("%s is not an object", iter.String()))
+	}
+	return r.getIteratorDirect(iterObj)
+}
+
+func (r *Runtime) newIteratorHelper(iterated *iteratorRecord) *iteratorHelperObject {
+	o := &Object{runtime: r}
+	it := &iteratorHelperObject{
+		iterated: iterated,
+	}
+	it.class = classObject
+	it.val = o
+	it.extensible = true
+	o.self = it
+	it.prototype = r.getIteratorHelperPrototype()
+	it.init()
+	return it
+}
+
+func (r *Runtime) toIteratorThis(v Value, method string) *Object {
+	if o, ok := v.(*Object); ok {
+		return o
+	}
+	panic(r.NewTypeError("Method Iterator.prototype.%s called on incompatible receiver %s", method, v.String()))
+}
+
+// iteratorCallbackArg returns the callable argument of an iterator helper. If the argument is not
+// callable the iterator is closed before the TypeError is thrown.
+func (r *Runtime) iteratorCallbackArg(o *Object, arg Value) func(FunctionCall) Value {
+	if obj, ok := arg.(*Object); ok {
+		if call, ok := obj.self.assertCallable(); ok {
+			return call
+		}
+	}
+	(&iteratorRecord{iterator: o}).closeOnPanic(func() {
+		panic(r.NewTypeError("%s is not a function", arg.String()))
+	})
+	return nil
+}
+
+// iteratorLimitArg validates the limit argument of take() and drop(), closing the iterator if it is invalid.
+func (r *Runtime) iteratorLimitArg(o *Object, arg Value) (limit float64) {
+	(&iteratorRecord{iterator: o}).closeOnPanic(func() {
+		limit = arg.ToNumber().ToFloat()
+		if math.IsNaN(limit) {
+			panic(r.newError(r.getRangeError(), "%s must be positive", arg.String()))
+		}
+		limit = math.Trunc(limit)
+		if limit < 0 {
+			panic(r.newError(r.getRangeError(), "%s must be positive", arg.String()))
+		}
+	})
+	return
+}
+
+func (r *Runtime) iteratorProto_map(call FunctionCall) Value {
+	o := r.toIteratorThis(call.This, "map")
+	mapper := r.iteratorCallbackArg(o, call.Argument(0))
+	ir := r.getIteratorDirect(o)
+	it := r.newIteratorHelper(ir)
+	var counter int64
+	it.step = func() (Value, bool) {
+		value, ok := ir.stepValue()
+		if !ok {
+			return nil, false
+		}
+		ir.closeOnPanic(func() {
+			value = mapper(FunctionCall{This: _undefined, Arguments: []Value{value, intToValue(counter)}})
+		})
+		counter++
+		return value, true
+	}
+	return it.val
+}
+
+func (r *Runtime) iteratorProto_filter(call FunctionCall) Value {
+	o := r.toIteratorThis(call.This, "filter")
+	predicate := r.iteratorCallbackArg(o, call.Argument(0))
+	ir := r.getIteratorDirect(o)
+	it := r.newIteratorHelper(ir)
+	var counter int64
+	it.step = func() (Value, bool) {
+		for {
+			value, ok := ir.stepValue()
+			if !ok {
+				return nil, false
+			}
+			var selected bool
+			ir.closeOnPanic(func() {
+				selected = predicate(FunctionCall{This: _undefined, Arguments: []Value{value, intToValue(counter)}}).ToBoolean()
+			})
+			counter++
+			if selected {
+				return value, true
+			}
+		}
+	}
+	return it.val
+}
+
+func (r *Runtime) iteratorProto_take(call FunctionCall) Value {
+	o := r.toIteratorThis(call.This, "take")
+	remaining := r.iteratorLimitArg(o, call.Argument(0))
+	ir := r.getIteratorDirect(o)
+	it := r.newIteratorHelper(ir)
+	it.step = func() (Value, bool) {
+		if remaining == 0 {
+			ir.returnIter()
+			return nil, false
+		}
+		if !math.IsInf(remaining, 1) {
+			remaining--
+		}
+		return ir.stepValue()
+	}
+	return it.val
+}
+
+func (r *Runtime) iteratorProto_drop(call FunctionCall) Value {
+	o := r.toIteratorThis(call.This, "drop")
+	remaining := r.iteratorLimitArg(o, call.Argument(0))
+	ir := r.getIteratorDirect(o)
+	it := r.newIteratorHelper(ir)
+	it.step = func() (Value, bool) {
+		for ; remaining > 0; remaining-- {
+			if _, ok := ir.stepValue(); !ok {
+				return nil, false
+			}
+		}
+		return ir.stepValue()
+	}
+	return it.val
+}
+
+func (r *Runtime) iteratorProto_flatMap(call FunctionCall) Value {
+	o := r.toIteratorThis(call.This, "flatMap")
+	mapper := r.iteratorCallbackArg(o, call.Argument(0))
+	ir := r.getIteratorDirect(o)
+	it := r.newIteratorHelper(ir)
+	var counter int64
+	it.step = func() (Value, bool) {
+		for {
+			if inner := it.inner; inner != nil {
+				var value Value
+				var ok bool
+				ir.closeOnPanic(func() {
+					value, ok = inner.stepValue()
+				})
+				if ok {
+					return value, true
+				}
+				it.inner = nil
+			}
+			value, ok := ir.stepValue()
+			if !ok {
+				return nil, false
+			}
+			ir.closeOnPanic(func() {
+				mapped := mapper(FunctionCall{This: _undefined, Arguments: []Value{value, intToValue(counter)}})
+				it.inner = r.getIteratorFlattenable(mapped, false)
+			})
+			counter++
+		}
+	}
+	return it.val
+}
+
+func (r *Runtime) iteratorProto_reduce(call FunctionCall) Value {
+	o := r.toIteratorThis(call.This, "reduce")
+	reducer := r.iteratorCallbackArg(o, call.Argument(0))
+	ir := r.getIteratorDirect(o)
+	var accumulator Value
+	var counter int64
+	if len(call.Arguments) > 1 {
+		accumulator = call.Arguments[1]
+	} else {
+		value, ok := ir.stepValue()
+		if !ok {
+			panic(r.NewTypeError("Reduce of empty iterator with no initial value"))
+		}
+		accumulator = value
+		counter = 1
+	}
+	for {
+		value, ok := ir.stepValue()
+		if !ok {
+			return accumulator
+		}
+		ir.closeOnPanic(func() {
+			accumulator = reducer(FunctionCall{This: _undefined, Arguments: []Value{accumulator, value, intToValue(counter)}})
+		})
+		counter++
+	}
+}
+
+func (r *Runtime) iteratorProto_toArray(call FunctionCall) Value {
+	ir := r.getIteratorDirect(r.toIteratorThis(call.This, "toArray"))
+	var values []Value
+	for {
+		value, ok := ir.stepValue()
+		if !ok {
+			return r.newArrayValues(values)
+		}
+		values = append(values, value)
+	}
+}
+
+func (r *Runtime) iteratorProto_forEach(call FunctionCall) Value {
+	o := r.toIteratorThis(call.This, "forEach")
+	fn := r.iteratorCallbackArg(o, call.Argument(0))
+	ir := r.getIteratorDirect(o)
+	var counter int64
+	for {
+		value, ok := ir.stepValue()
+		if !ok {
+			return _undefined
+		}
+		ir.closeOnPanic(func() {
+			fn(FunctionCall{This: _undefined, Arguments: []Value{value, intToValue(counter)}})
+		})
+		counter++
+	}
+}
+
+// iteratorFind calls predicate for each value until it returns a value for which accept returns true,
+// in which case the iterator is closed and the value is returned.
+func (r *Runtime) iteratorFind(call FunctionCall, method string, accept func(bool) bool) (Value, bool) {
+	o := r.toIteratorThis(call.This, method)
+	predicate := r.iteratorCallbackArg(o, call.Argument(0))
+	ir := r.getIteratorDirect(o)
+	var counter int64
+	for {
+		value, ok := ir.stepValue()
+		if !ok {
+			return nil, false
+		}
+		var result bool
+		ir.closeOnPanic(func() {
+			result = predicate(FunctionCall{This: _undefined, Arguments: []Value{value, intToValue(counter)}}).ToBoolean()
+		})
+		if accept(result) {
+			ir.returnIter()
+			return value, true
+		}
+		counter++
+	}
+}
+
+func (r *Runtime) iteratorProto_some(call FunctionCall) Value {
+	_, found := r.iteratorFind(call, "some", func(result bool) bool { return result })
+	return r.toBoolean(found)
+}
+
+func (r *Runtime) iteratorProto_every(call FunctionCall) Value {
+	_, found := r.iteratorFind(call, "every", func(result bool) bool { return !result })
+	return r.toBoolean(!found)
+}
+
+func (r *Runtime) iteratorProto_find(call FunctionCall) Value {
+	if value, found := r.iteratorFind(call, "find", func(result bool) bool { return result }); found {
+		return value
+	}
+	return _undefined
+}
+
+func (r *Runtime) iteratorProto_getConstructor(FunctionCall) Value {
+	return r.getIteratorConstructor()
+}
+
+func (r *Runtime) iteratorProto_getToStringTag(FunctionCall) Value {
+	return asciiString(classIterator)
+}
+
+// iteratorProtoSetter returns a setter that implements the SetterThatIgnoresPrototypeProperties
+// abstract operation (https://tc39.es/ecma262/#sec-SetterThatIgnoresPrototypeProperties).
+func (r *Runtime) iteratorProtoSetter(p Value) func(FunctionCall) Value {
+	return func(call FunctionCall) Value {
+		o, ok := call.This.(*Object)
+		if !ok {
+			panic(r.NewTypeError("Cannot set property %s of %s", p.String(), call.This.String()))
+		}
+		if o == r.getIteratorPrototype() {
+			panic(r.NewTypeError("Cannot assign to read only property '%s' of %s", p.String(), r.objectproto_toString(FunctionCall{This: o})))
+		}
+		if o.getOwnProp(p) == nil {
+			createDataPropertyOrThrow(o, p, call.Argument(0))
+		} else {
+			o.set(p, call.Argument(0), o, true)
+		}
+		return _undefined
+	}
+}
+
+func (r *Runtime) iteratorHelperProto_next(call FunctionCall) Value {
+	thisObj := r.toObject(call.This)
+	if it, ok := thisObj.self.(*iteratorHelperObject); ok {
+		return it.next()
+	}
+	panic(r.NewTypeError("Method %s.prototype.next called on incompatible receiver %s", classIteratorHelper, r.objectproto_toString(FunctionCall{This: thisObj})))
+}
+
+func (r *Runtime) iteratorHelperProto_return(call FunctionCall) Value {
+	thisObj := r.toObject(call.This)
+	if it, ok := thisObj.self.(*iteratorHelperObject); ok {
+		return it._return()
+	}
+	panic(r.NewTypeError("Method %s.prototype.return called on incompatible receiver %s", classIteratorHelper, r.objectproto_toString(FunctionCall{This: thisObj})))
+}
+
+func (r *Runtime) toIteratorWrap(v Value, method string) *iteratorRecord {
+	thisObj := r.toObject(v)
+	if w, ok := thisObj.self.(*iteratorWrapObject); ok {
+		return w.iterated
+	}
+	panic(r.NewTypeError("Method %%WrapForValidIteratorPrototype%%.%s called on incompatible receiver %s", method, r.objectproto_toString(FunctionCall{This: thisObj})))
+}
+
+func (r *Runtime) iteratorWrapProto_next(call FunctionCall) Value {
+	ir := r.toIteratorWrap(call.This, "next")
+	if ir.next == nil {
+		panic(r.NewTypeError("iterator.next is missing or not a function"))
+	}
+	return ir.next(FunctionCall{This: ir.iterator})
+}
+
+func (r *Runtime) iteratorWrapProto_return(call FunctionCall) Value {
+	iter := r.toIteratorWrap(call.This, "return").iterator
+	retMethod := toMethod(iter.self.getStr("return", nil))
+	if retMethod == nil {
+		return r.createIterResultObject(_undefined, true)
+	}
+	return retMethod(FunctionCall{This: iter})
+}
+
+func (r *Runtime) iterator_from(call FunctionCall) Value {
+	ir := r.getIteratorFlattenable(call.Argument(0), true)
+	proto := r.getIteratorPrototype()
+	for p := ir.iterator.self.proto(); p != nil; p = p.self.proto() {
+		if p == proto {
+			return ir.iterator
+		}
+	}
+	o := &Object{runtime: r}
+	w := &iteratorWrapObject{
+		iterated: ir,
+	}
+	w.class = classObject
+	w.val = o
+	w.extensible = true
+	o.self = w
+	w.prototype = r.getWrapForValidIteratorPrototype()
+	w.init()
+	return o
+}
+
+func (r *Runtime) builtin_newIterator(args []Value, newTarget *Object) *Object {
+	if newTarget == nil || newTarget == r.global.Iterator {
+		panic(r.NewTypeError("Abstract class Iterator not directly constructable"))
+	}
+	proto := r.getPrototypeFromCtor(newTarget, r.global.Iterator, r.getIteratorPrototype())
+	return r.newBaseObject(proto, classObject).val
+}
+
+func (r *Runtime) createIterProto(val *Object) objectImpl {
+	defer r.endBuiltinInit(r.beginBuiltinInit())
+	o := newBaseObjectObj(val, r.global.ObjectPrototype, classObject)
+
+	o.setOwnStr("constructor", &valueProperty{
+		getterFunc:   r.newNativeFunc(r.iteratorProto_getConstructor, "get constructor", 0),
+		setterFunc:   r.newNativeFunc(r.iteratorProtoSetter(asciiString("constructor")), "set constructor", 1),
+		accessor:     true,
+		configurable: true,
+	}, true)
+	o._putProp("drop", r.newNativeFunc(r.iteratorProto_drop, "drop", 1), true, false, true)
+	o._putProp("every", r.newNativeFunc(r.iteratorProto_every, "every", 1), true, false, true)
+	o._putProp("filter", r.newNativeFunc(r.iteratorProto_filter, "filter", 1), true, false, true)
+	o._putProp("find", r.newNativeFunc(r.iteratorProto_find, "find", 1), true, false, true)
+	o._putProp("flatMap", r.newNativeFunc(r.iteratorProto_flatMap, "flatMap", 1), true, false, true)
+	o._putProp("forEach", r.newNativeFunc(r.iteratorProto_forEach, "forEach", 1), true, false, true)
+	o._putProp("map", r.newNativeFunc(r.iteratorProto_map, "map", 1), true, false, true)
+	o._putProp("reduce", r.newNativeFunc(r.iteratorProto_reduce, "reduce", 1), true, false, true)
+	o._putProp("some", r.newNativeFunc(r.iteratorProto_some, "some", 1), true, false, true)
+	o._putProp("take", r.newNativeFunc(r.iteratorProto_take, "take", 1), true, false, true)
+	o._putProp("toArray", r.newNativeFunc(r.iteratorProto_toArray, "toArray", 0), true, false, true)
+
+	o._putSym(SymIterator, valueProp(r.newNativeFunc(r.returnThis, "[Symbol.iterator]", 0), true, false, true))
+	o._putSym(SymToStringTag, &valueProperty{
+		getterFunc:   r.newNativeFunc(r.iteratorProto_getToStringTag, "get [Symbol.toStringTag]", 0),
+		setterFunc:   r.newNativeFunc(r.iteratorProtoSetter(SymToStringTag), "set [Symbol.toStringTag]", 1),
+		accessor:     true,
+		configurable: true,
+	})
+	return o
+}
+
+func (r *Runtime) getIteratorPrototype() *Object {
+	var o *Object
+	if o = r.global.IteratorPrototype; o == nil {
+		o = &Object{runtime: r}
+		r.global.IteratorPrototype = o
+		o.self = r.createIterProto(o)
+	}
+	return o
+}
+
+func (r *Runtime) createIteratorHelperProto(val *Object) objectImpl {
+	o := newBaseObjectObj(val, r.getIteratorPrototype(), classObject)
+
+	o._putProp("next", r.newNativeFunc(r.iteratorHelperProto_next, "next", 0), true, false, true)
+	o._putProp("return", r.newNativeFunc(r.iteratorHelperProto_return, "return", 0), true, false, true)
+	o._putSym(SymToStringTag, valueProp(asciiString(classIteratorHelper), false, false, true))
+
+	return o
+}
+
+func (r *Runtime) getIteratorHelperPrototype() *Object {
+	var o *Object
+	if o = r.global.IteratorHelperPrototype; o == nil {
+		o = &Object{runtime: r}
+		r.global.IteratorHelperPrototype = o
+		o.self = r.createIteratorHelperProto(o)
+	}
+	return o
+}
+
+func (r *Runtime) createWrapForValidIteratorProto(val *Object) objectImpl {
+	o := newBaseObjectObj(val, r.getIteratorPrototype(), classObject)
+
+	o._putProp("next", r.newNativeFunc(r.iteratorWrapProto_next, "next", 0), true, false, true)
+	o._putProp("return", r.newNativeFunc(r.iteratorWrapProto_return, "return", 0), true, false, true)
+
+	return o
+}
+
+func (r *Runtime) getWrapForValidIteratorPrototype() *Object {
+	var o *Object
+	if o = r.global.WrapForValidIteratorPrototype; o == nil {
+		o = &Object{runtime: r}
+		r.global.WrapForValidIteratorPrototype = o
+		o.self = r.createWrapForValidIteratorProto(o)
+	}
+	return o
+}
+
+func (r *Runtime) createIterator(val *Object) objectImpl {
+	o := r.newNativeConstructOnly(val, r.builtin_newIterator, r.getIteratorPrototype(), "Iterator", 0)
+	o._putProp("from", r.newNativeFunc(r.iterator_from, "from", 1), true, false, true)
+
+	return o
+}
+
+func (r *Runtime) getIteratorConstructor() *Object {
+	ret := r.global.Iterator
+	if ret == nil {
+		ret = &Object{runtime: r}
+		r.global.Iterator = ret
+		ret.self = r.createIterator(ret)
+	}
+	return ret
+}
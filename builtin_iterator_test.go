@@ -0,0 +1,140 @@
+package goja
+
+import (
+	"testing"
+)
+
+func TestIteratorHelpers(t *testing.T) {
+	const SCRIPT = `
+	function* gen(n) {
+		for (var i = 1; i <= n; i++) {
+			yield i;
+		}
+	}
+
+	assert(compareArray(gen(5).map(function(x, i) { return x * 10 + i; }).toArray(), [10, 21, 32, 43, 54]), "map");
+	assert(compareArray(gen(6).filter(function(x) { return x % 2 === 0; }).toArray(), [2, 4, 6]), "filter");
+	assert(compareArray(gen(Infinity).take(3).toArray(), [1, 2, 3]), "take");
+	assert(compareArray(gen(5).drop(3).toArray(), [4, 5]), "drop");
+	assert(compareArray(gen(3).flatMap(function(x) { return [x, x]; }).toArray(), [1, 1, 2, 2, 3, 3]), "flatMap");
+	assert.sameValue(gen(4).reduce(function(acc, x) { return acc + x; }), 10, "reduce");
+	assert.sameValue(gen(4).reduce(function(acc, x) { return acc + x; }, "") , "1234", "reduce with initial value");
+	assert.throws(TypeError, function() { gen(0).reduce(function() {}); }, "reduce of empty iterator");
+	assert.sameValue(gen(5).some(function(x) { return x > 4; }), true, "some");
+	assert.sameValue(gen(5).every(function(x) { return x > 4; }), false, "every");
+	assert.sameValue(gen(5).find(function(x) { return x > 2; }), 3, "find");
+	var sum = 0;
+	gen(3).forEach(function(x) { sum += x; });
+	assert.sameValue(sum, 6, "forEach");
+
+	// laziness
+	var log = [];
+	var it = gen(Infinity).map(function(x) { log.push(x); return x; });
+	assert.sameValue(log.length, 0, "map is lazy");
+	assert.sameValue(it.next().value, 1);
+	assert.sameValue(log.join(), "1");
+
+	// return() closes the underlying iterator
+	var closed = false;
+	var underlying = {
+		__proto__: Iterator.prototype,
+		next: function() { return {value: 1, done: false}; },
+		return: function() { closed = true; return {}; }
+	};
+	var helper = underlying.map(function(x) { return x; });
+	helper.next();
+	var res = helper.return();
+	assert.sameValue(res.done, true);
+	assert(closed, "underlying iterator is closed");
+	assert.sameValue(helper.next().done, true, "helper is done after return()");
+
+	closed = false;
+	assert.throws(TypeError, function() { underlying.map(1); });
+	assert(closed, "underlying iterator is closed on an invalid argument");
+	closed = false;
+	assert.throws(RangeError, function() { underlying.take(-1); });
+	assert(closed, "underlying iterator is closed on an invalid limit");
+	closed = false;
+	assert.throws(Error, function() { underlying.filter(function() { throw new Error(); }).next(); });
+	assert(closed, "underlying iterator is closed when the callback throws");
+
+	assert.sameValue(Object.prototype.toString.call(gen(1).map(function() {})), "[object Iterator Helper]");
+	assert.sameValue(Iterator.prototype[Symbol.toStringTag], "Iterator");
+	assert.sameValue(Iterator.prototype.constructor, Iterator);
+	assert.sameValue(Object.getPrototypeOf(gen(1)).__proto__.__proto__, Iterator.prototype);
+	assert.throws(TypeError, function() { new Iterator(); });
+	assert.throws(TypeError, function() { Iterator(); });
+	class MyIterator extends Iterator {}
+	assert(new MyIterator() instanceof Iterator, "subclass");
+	`
+	testScriptWithTestLib(SCRIPT, _undefined, t)
+}
+
+func TestIteratorFrom(t *testing.T) {
+	const SCRIPT = `
+	assert(compareArray(Iterator.from("ab").toArray(), ["a", "b"]), "string");
+	assert(compareArray(Iterator.from(new Set([1, 2])).map(function(x) { return x * 2; }).toArray(), [2, 4]), "iterable");
+
+	var arrIter = [1, 2][Symbol.iterator]();
+	assert.sameValue(Iterator.from(arrIter), arrIter, "iterators inheriting from Iterator.prototype are returned as is");
+
+	var i = 0;
+	var plain = {
+		next: function() { i++; return {value: i, done: i > 3}; }
+	};
+	var wrapped = Iterator.from(plain);
+	assert(wrapped !== plain, "plain iterators are wrapped");
+	assert(wrapped instanceof Iterator, "wrapped");
+	assert(compareArray(wrapped.toArray(), [1, 2, 3]), "wrapped values");
+	assert.sameValue(wrapped.return().done, true, "return() without underlying return");
+
+	assert.throws(TypeError, function() { Iterator.from(1); });
+	assert.throws(TypeError, function() { [1].values().flatMap(function(x) { return "ab"; }).toArray(); }, "flatMap rejects strings");
+	`
+	testScriptWithTestLib(SCRIPT, _undefined, t)
+}
+
+type testGoIterResult struct {
+	Value int
+	Done  bool
+}
+
+type testGoIter struct {
+	n, max int
+}
+
+func (it *testGoIter) Next() testGoIterResult {
+	if it.n >= it.max {
+		return testGoIterResult{Done: true}
+	}
+	it.n++
+	return testGoIterResult{Value: it.n}
+}
+
+func TestIteratorHelpersGo(t *testing.T) {
+	r := New()
+	r.SetFieldNameMapper(UncapFieldNameMapper())
+	s := make([]interface{}, 1000)
+	for i := range s {
+		s[i] = i
+	}
+	r.Set("s", s)
+	it := &testGoIter{max: 1000000}
+	r.Set("it", it)
+	_, err := r.RunString(`
+	var res = s.values().filter(function(x) { return x % 100 === 0; }).take(3).toArray();
+	if (res.join() !== "0,100,200") {
+		throw new Error("unexpected result: " + res);
+	}
+	res = Iterator.from(it).map(function(x) { return x * 2; }).take(2).toArray();
+	if (res.join() !== "2,4") {
+		throw new Error("unexpected result: " + res);
+	}
+	`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if it.n != 2 {
+		t.Fatalf("Go iterator was advanced %d times", it.n)
+	}
+}
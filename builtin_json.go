@@ -81,6 +81,7 @@ func (r *Runtime) builtinJSON_decodeValue(d *json.Decoder) (Value, error) {
 
 func (r *Runtime) builtinJSON_decodeObject(d *json.Decoder) (*Object, error) {
 	object := r.NewObject()
+	o := object.self.(*baseObject)
 	for {
 		key, end, err := r.builtinJSON_decodeObjectKey(d)
 		if err != nil {
@@ -94,7 +95,11 @@ func (r *Runtime) builtinJSON_decodeObject(d *json.Decoder) (*Object, error) {
 			return nil, err
 		}
 
-		object.self._putProp(unistring.NewFromString(key), value, true, true, true)
+		name := unistring.NewFromString(key)
+		if _, exists := o.values[name]; !exists {
+			o.checkPropLimit()
+		}
+		o._putProp(name, value, true, true, true)
 	}
 	return object, nil
 }
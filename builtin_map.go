@@ -138,13 +138,23 @@ func (r *Runtime) mapProto_has(call FunctionCall) Value {
 	return valueFalse
 }
 
+// mapSet sets the entry of a Map or a Set accounting for the allocation if it is a new one
+// (see Runtime.SetMemoryLimit()).
+func (r *Runtime) mapSet(m *orderedMap, key, value Value) {
+	size := m.size
+	m.set(key, value)
+	if m.size > size {
+		r.trackAlloc(memSizeMapEntry)
+	}
+}
+
 func (r *Runtime) mapProto_set(call FunctionCall) Value {
 	thisObj := r.toObject(call.This)
 	mo, ok := thisObj.self.(*mapObject)
 	if !ok {
 		panic(r.NewTypeError("Method Map.prototype.set called on incompatible receiver %s", r.objectproto_toString(FunctionCall{This: thisObj})))
 	}
-	mo.m.set(call.Argument(0), call.Argument(1))
+	r.mapSet(mo.m, call.Argument(0), call.Argument(1))
 	return call.This
 }
 
@@ -221,7 +231,7 @@ func (r *Runtime) builtin_newMap(args []Value, newTarget *Object) *Object {
 					itemObj := r.toObject(item)
 					k := nilSafe(itemObj.self.getIdx(i0, nil))
 					v := nilSafe(itemObj.self.getIdx(i1, nil))
-					mo.m.set(k, v)
+					r.mapSet(mo.m, k, v)
 				})
 			} else {
 				iter.iterate(func(item Value) {
@@ -268,6 +278,7 @@ func (r *Runtime) mapIterProto_next(call FunctionCall) Value {
 }
 
 func (r *Runtime) createMapProto(val *Object) objectImpl {
+	defer r.endBuiltinInit(r.beginBuiltinInit())
 	o := newBaseObjectObj(val, r.global.ObjectPrototype, classObject)
 
 	o._putProp("constructor", r.getMap(), true, false, true)
@@ -295,8 +306,19 @@ func (r *Runtime) createMapProto(val *Object) objectImpl {
 	return o
 }
 
+func (r *Runtime) map_groupBy(call FunctionCall) Value {
+	keys, groups := r.groupBy(call.Argument(0), call.Argument(1), false)
+	o := r.builtin_newMap(nil, r.getMap())
+	m := o.self.(*mapObject).m
+	for i, key := range keys {
+		r.mapSet(m, key, r.newArrayValues(groups[i]))
+	}
+	return o
+}
+
 func (r *Runtime) createMap(val *Object) objectImpl {
 	o := r.newNativeConstructOnly(val, r.builtin_newMap, r.getMapPrototype(), "Map", 0)
+	o._putProp("groupBy", r.newNativeFunc(r.map_groupBy, "groupBy", 2), true, false, true)
 	r.putSpeciesReturnThis(o)
 
 	return o
@@ -62,6 +62,33 @@ func TestMapEvilIterator(t *testing.T) {
 	testScriptWithTestLib(SCRIPT, _undefined, t)
 }
 
+func TestMapGroupBy(t *testing.T) {
+	const SCRIPT = `
+	var key = {};
+	var m = Map.groupBy([1, -0, 0, 2, 3], function(x) {
+		return x === 0 ? -0 : x > 1 ? key : "one";
+	});
+	assert(m instanceof Map, "result is a Map");
+	assert.sameValue(m.size, 3);
+	assert(compareArray(m.get("one"), [1]), "one");
+	assert(compareArray(m.get(0), [-0, 0]), "-0 is normalised");
+	assert(compareArray(m.get(key), [2, 3]), "object key");
+	assert(compareArray(Array.from(m.keys()), ["one", 0, key]), "key order");
+
+	var closed = false;
+	var iterable = {};
+	iterable[Symbol.iterator] = function() {
+		return {
+			next: function() { return {value: 1, done: false}; },
+			return: function() { closed = true; return {}; }
+		};
+	};
+	assert.throws(Error, function() { Map.groupBy(iterable, function() { throw new Error(); }); });
+	assert(closed, "iterator is closed when the callback throws");
+	`
+	testScriptWithTestLib(SCRIPT, _undefined, t)
+}
+
 func TestMapExportToNilMap(t *testing.T) {
 	vm := New()
 	var m map[int]interface{}
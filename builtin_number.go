@@ -89,6 +89,12 @@ func (r *Runtime) numberproto_toString(call FunctionCall) Value {
 	return asciiString(ftoa.FToBaseStr(num, radix))
 }
 
+func (r *Runtime) numberproto_toLocaleString(call FunctionCall) Value {
+	num := r.toNumber(call.This).ToFloat()
+	f := r.newIntlNumberFormat(call.Argument(0), call.Argument(1))
+	return newStringValue(f.format(num))
+}
+
 func (r *Runtime) numberproto_toFixed(call FunctionCall) Value {
 	num := r.toNumber(call.This).ToFloat()
 	prec := call.Argument(0).ToInteger()
@@ -206,7 +212,7 @@ func createNumberProtoTemplate() *objectTemplate {
 
 	t.putStr("toExponential", func(r *Runtime) Value { return r.methodProp(r.numberproto_toExponential, "toExponential", 1) })
 	t.putStr("toFixed", func(r *Runtime) Value { return r.methodProp(r.numberproto_toFixed, "toFixed", 1) })
-	t.putStr("toLocaleString", func(r *Runtime) Value { return r.methodProp(r.numberproto_toString, "toLocaleString", 0) })
+	t.putStr("toLocaleString", func(r *Runtime) Value { return r.methodProp(r.numberproto_toLocaleString, "toLocaleString", 0) })
 	t.putStr("toPrecision", func(r *Runtime) Value { return r.methodProp(r.numberproto_toPrecision, "toPrecision", 1) })
 	t.putStr("toString", func(r *Runtime) Value { return r.methodProp(r.numberproto_toString, "toString", 1) })
 	t.putStr("valueOf", func(r *Runtime) Value { return r.methodProp(r.numberproto_valueOf, "valueOf", 0) })
@@ -455,6 +455,68 @@ func (r *Runtime) objectproto_propertyIsEnumerable(call FunctionCall) Value {
 	return valueTrue
 }
 
+func (r *Runtime) objectproto_defineAccessor(call FunctionCall, setter bool) Value {
+	o := call.This.ToObject(r)
+	fn := call.Argument(1)
+	if _, ok := assertCallable(fn); !ok {
+		panic(r.NewTypeError("Object.prototype.__define%s__: Expecting function", accessorKind(setter)))
+	}
+	desc := PropertyDescriptor{
+		Enumerable:   FLAG_TRUE,
+		Configurable: FLAG_TRUE,
+	}
+	if setter {
+		desc.Setter = fn
+	} else {
+		desc.Getter = fn
+	}
+	o.defineOwnProperty(toPropertyKey(call.Argument(0)), desc, true)
+	return _undefined
+}
+
+func (r *Runtime) objectproto_lookupAccessor(call FunctionCall, setter bool) Value {
+	o := call.This.ToObject(r)
+	p := toPropertyKey(call.Argument(0))
+	for ; o != nil; o = o.self.proto() {
+		if pv := o.getOwnProp(p); pv != nil {
+			if prop, ok := pv.(*valueProperty); ok && prop.accessor {
+				if setter {
+					if prop.setterFunc != nil {
+						return prop.setterFunc
+					}
+				} else if prop.getterFunc != nil {
+					return prop.getterFunc
+				}
+			}
+			return _undefined
+		}
+	}
+	return _undefined
+}
+
+func accessorKind(setter bool) string {
+	if setter {
+		return "Setter"
+	}
+	return "Getter"
+}
+
+func (r *Runtime) objectproto_defineGetter(call FunctionCall) Value {
+	return r.objectproto_defineAccessor(call, false)
+}
+
+func (r *Runtime) objectproto_defineSetter(call FunctionCall) Value {
+	return r.objectproto_defineAccessor(call, true)
+}
+
+func (r *Runtime) objectproto_lookupGetter(call FunctionCall) Value {
+	return r.objectproto_lookupAccessor(call, false)
+}
+
+func (r *Runtime) objectproto_lookupSetter(call FunctionCall) Value {
+	return r.objectproto_lookupAccessor(call, true)
+}
+
 func (r *Runtime) objectproto_toString(call FunctionCall) Value {
 	switch o := call.This.(type) {
 	case valueNull:
@@ -596,6 +658,43 @@ func (r *Runtime) object_hasOwn(call FunctionCall) Value {
 	}
 }
 
+// groupBy implements the GroupBy abstract operation (https://tc39.es/ecma262/#sec-groupby).
+// If propertyKeys is true the keys are coerced to property keys, otherwise they are compared using SameValueZero.
+// The groups are returned in the order in which their keys were first seen.
+func (r *Runtime) groupBy(items, callback Value, propertyKeys bool) (keys []Value, groups [][]Value) {
+	r.checkObjectCoercible(items)
+	callbackFn := r.toCallable(callback)
+	indexes := newOrderedMap(r.getHash())
+	var k int64
+	r.getIterator(items, nil).iterate(func(value Value) {
+		key := callbackFn(FunctionCall{This: _undefined, Arguments: []Value{value, intToValue(k)}})
+		if propertyKeys {
+			key = toPropertyKey(key)
+		} else if key == _negativeZero {
+			key = intToValue(0)
+		}
+		if idx := indexes.get(key); idx != nil {
+			i := idx.ToInteger()
+			groups[i] = append(groups[i], value)
+		} else {
+			indexes.set(key, intToValue(int64(len(keys))))
+			keys = append(keys, key)
+			groups = append(groups, []Value{value})
+		}
+		k++
+	})
+	return
+}
+
+func (r *Runtime) object_groupBy(call FunctionCall) Value {
+	keys, groups := r.groupBy(call.Argument(0), call.Argument(1), true)
+	result := r.newBaseObject(nil, classObject).val
+	for i, key := range keys {
+		createDataPropertyOrThrow(result, key, r.newArrayValues(groups[i]))
+	}
+	return result
+}
+
 func createObjectTemplate() *objectTemplate {
 	t := newObjectTemplate()
 	t.protoFactory = func(r *Runtime) *Object {
@@ -635,6 +734,7 @@ func createObjectTemplate() *objectTemplate {
 	t.putStr("values", func(r *Runtime) Value { return r.methodProp(r.object_values, "values", 1) })
 	t.putStr("fromEntries", func(r *Runtime) Value { return r.methodProp(r.object_fromEntries, "fromEntries", 1) })
 	t.putStr("hasOwn", func(r *Runtime) Value { return r.methodProp(r.object_hasOwn, "hasOwn", 2) })
+	t.putStr("groupBy", func(r *Runtime) Value { return r.methodProp(r.object_groupBy, "groupBy", 2) })
 
 	return t
 }
@@ -698,6 +798,18 @@ func createObjectProtoTemplate() *objectTemplate {
 	t.putStr("propertyIsEnumerable", func(r *Runtime) Value {
 		return r.methodProp(r.objectproto_propertyIsEnumerable, "propertyIsEnumerable", 1)
 	})
+	t.putStr("__defineGetter__", func(r *Runtime) Value {
+		return r.methodProp(r.objectproto_defineGetter, "__defineGetter__", 2)
+	})
+	t.putStr("__defineSetter__", func(r *Runtime) Value {
+		return r.methodProp(r.objectproto_defineSetter, "__defineSetter__", 2)
+	})
+	t.putStr("__lookupGetter__", func(r *Runtime) Value {
+		return r.methodProp(r.objectproto_lookupGetter, "__lookupGetter__", 1)
+	})
+	t.putStr("__lookupSetter__", func(r *Runtime) Value {
+		return r.methodProp(r.objectproto_lookupSetter, "__lookupSetter__", 1)
+	})
 	t.putStr(__proto__, func(r *Runtime) Value {
 		return &valueProperty{
 			accessor:     true,
@@ -30,6 +30,18 @@ type PromiseRejectionTracker func(p *Promise, operation PromiseRejectionOperatio
 
 type jobCallback struct {
 	callback func(FunctionCall) Value
+	// obj is the function object the callback has been obtained from (if any), see WriteHeapSnapshot()
+	obj *Object
+}
+
+// promiseJob is either a PromiseReactionJob (if reaction is set) or a PromiseResolveThenableJob.
+type promiseJob struct {
+	reaction *promiseReaction
+	argument Value
+
+	promise  *Promise
+	thenable Value
+	then     *jobCallback
 }
 
 type promiseCapability struct {
@@ -70,6 +82,54 @@ func (p *Promise) Result() Value {
 	return p.result
 }
 
+// Then registers callbacks that are called when the Promise is fulfilled or rejected, either of them can be nil.
+// The callbacks are called from promise reaction jobs, in the same order relative to other reactions as if
+// they were registered using Promise.prototype.then(). If Then is called outside the Runtime execution context
+// and the Promise is already settled, the job is run before Then returns, otherwise the jobs are run when the
+// job queue is processed next.
+//
+// Like Promise.prototype.then() it returns a derived Promise which is fulfilled with undefined when the callback
+// returns or, if the respective callback is nil, settled in the same way as this Promise. In particular, if this
+// Promise is rejected and onRejected is nil, the derived Promise is rejected too, which is reported to the
+// PromiseRejectionTracker (see Runtime.SetPromiseRejectionTracker()) unless it is handled in turn.
+//
+// WARNING: Like Promise in general, this method is not goroutine-safe. See Runtime.NewPromise() for details.
+func (p *Promise) Then(onFulfilled, onRejected func(Value)) (*Promise, error) {
+	r := p.val.runtime
+	handler := func(f func(Value)) *jobCallback {
+		if f == nil {
+			return nil
+		}
+		return &jobCallback{callback: func(call FunctionCall) Value {
+			f(call.Argument(0))
+			return _undefined
+		}}
+	}
+	var res *Promise
+	err := r.runWrapped(func() {
+		capability := r.newPromiseCapability(r.getPromise())
+		res = capability.promise.self.(*Promise)
+		p.addReactions(&promiseReaction{
+			capability: capability,
+			typ:        promiseReactionFulfill,
+			handler:    handler(onFulfilled),
+		}, &promiseReaction{
+			capability: capability,
+			typ:        promiseReactionReject,
+			handler:    handler(onRejected),
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// Catch is a shortcut for Then(nil, onRejected).
+func (p *Promise) Catch(onRejected func(Value)) (*Promise, error) {
+	return p.Then(nil, onRejected)
+}
+
 func (p *Promise) toValue(r *Runtime) Value {
 	if p == nil || p.val == nil {
 		return _null
@@ -102,8 +162,9 @@ func (p *Promise) createResolvingFunctions() (resolve, reject *Object) {
 					return p.reject(ex.val)
 				}
 				if call, ok := assertCallable(thenAction); ok {
-					job := r.newPromiseResolveThenableJob(p, resolution, &jobCallback{callback: call})
-					r.enqueuePromiseJob(job)
+					then := &jobCallback{callback: call}
+					then.obj, _ = thenAction.(*Object)
+					r.enqueuePromiseJob(promiseJob{promise: p, thenable: resolution, then: then})
 					return _undefined
 				}
 			}
@@ -161,71 +222,111 @@ func (p *Promise) addReactions(fulfillReaction *promiseReaction, rejectReaction
 		p.fulfillReactions = append(p.fulfillReactions, fulfillReaction)
 		p.rejectReactions = append(p.rejectReactions, rejectReaction)
 	case PromiseStateFulfilled:
-		r.enqueuePromiseJob(r.newPromiseReactionJob(fulfillReaction, p.result))
+		r.enqueuePromiseReactionJob(fulfillReaction, p.result)
 	default:
 		reason := p.result
 		if !p.handled {
 			r.trackPromiseRejection(p, PromiseRejectionHandle)
 		}
-		r.enqueuePromiseJob(r.newPromiseReactionJob(rejectReaction, reason))
+		r.enqueuePromiseReactionJob(rejectReaction, reason)
 	}
 	p.handled = true
 }
 
-func (r *Runtime) newPromiseResolveThenableJob(p *Promise, thenable Value, then *jobCallback) func() {
-	return func() {
-		resolve, reject := p.createResolvingFunctions()
-		ex := r.vm.try(func() {
-			r.callJobCallback(then, thenable, resolve, reject)
-		})
-		if ex != nil {
-			if fn, ok := reject.self.assertCallable(); ok {
-				fn(FunctionCall{Arguments: []Value{ex.val}})
-			}
+func (r *Runtime) promiseResolveThenableJob(p *Promise, thenable Value, then *jobCallback) {
+	resolve, reject := p.createResolvingFunctions()
+	ex := r.vm.try(func() {
+		r.callJobCallback(then, thenable, resolve, reject)
+	})
+	if ex != nil {
+		if fn, ok := reject.self.assertCallable(); ok {
+			fn(FunctionCall{Arguments: []Value{ex.val}})
 		}
 	}
 }
 
-func (r *Runtime) enqueuePromiseJob(job func()) {
+func (r *Runtime) enqueuePromiseJob(job promiseJob) {
 	r.jobQueue = append(r.jobQueue, job)
 }
 
+func (r *Runtime) enqueuePromiseReactionJob(reaction *promiseReaction, argument Value) {
+	r.enqueuePromiseJob(promiseJob{reaction: reaction, argument: argument})
+}
+
+func (r *Runtime) runPromiseJob(job *promiseJob) {
+	if job.reaction != nil {
+		r.promiseReactionJob(job.reaction, job.argument)
+	} else {
+		r.promiseResolveThenableJob(job.promise, job.thenable, job.then)
+	}
+}
+
+// values returns the JavaScript values retained by the job, see WriteHeapSnapshot().
+func (job *promiseJob) values() []Value {
+	if job.reaction != nil {
+		return append(job.reaction.values(), job.argument)
+	}
+	values := []Value{job.promise.val, job.thenable}
+	if job.then.obj != nil {
+		values = append(values, job.then.obj)
+	}
+	return values
+}
+
 func (r *Runtime) triggerPromiseReactions(reactions []*promiseReaction, argument Value) {
 	for _, reaction := range reactions {
-		r.enqueuePromiseJob(r.newPromiseReactionJob(reaction, argument))
+		r.enqueuePromiseReactionJob(reaction, argument)
 	}
 }
 
-func (r *Runtime) newPromiseReactionJob(reaction *promiseReaction, argument Value) func() {
-	return func() {
-		var handlerResult Value
-		fulfill := false
-		if reaction.handler == nil {
-			handlerResult = argument
-			if reaction.typ == promiseReactionFulfill {
-				fulfill = true
-			}
-		} else {
-			if tracker := r.asyncContextTracker; tracker != nil {
-				tracker.Resumed(reaction.asyncCtx)
-			}
-			ex := r.vm.try(func() {
-				handlerResult = r.callJobCallback(reaction.handler, _undefined, argument)
-				fulfill = true
-			})
-			if ex != nil {
-				handlerResult = ex.val
-			}
-			if tracker := r.asyncContextTracker; tracker != nil {
-				tracker.Exited()
-			}
+// values returns the JavaScript values retained by the reaction.
+func (pr *promiseReaction) values() []Value {
+	var values []Value
+	if pr.handler != nil && pr.handler.obj != nil {
+		values = append(values, pr.handler.obj)
+	}
+	if c := pr.capability; c != nil {
+		if c.promise != nil {
+			values = append(values, c.promise)
 		}
-		if reaction.capability != nil {
-			if fulfill {
-				reaction.capability.resolve(handlerResult)
-			} else {
-				reaction.capability.reject(handlerResult)
-			}
+		if c.resolveObj != nil {
+			values = append(values, c.resolveObj)
+		}
+		if c.rejectObj != nil {
+			values = append(values, c.rejectObj)
+		}
+	}
+	return values
+}
+
+func (r *Runtime) promiseReactionJob(reaction *promiseReaction, argument Value) {
+	var handlerResult Value
+	fulfill := false
+	if reaction.handler == nil {
+		handlerResult = argument
+		if reaction.typ == promiseReactionFulfill {
+			fulfill = true
+		}
+	} else {
+		if tracker := r.asyncContextTracker; tracker != nil {
+			tracker.Resumed(reaction.asyncCtx)
+		}
+		ex := r.vm.try(func() {
+			handlerResult = r.callJobCallback(reaction.handler, _undefined, argument)
+			fulfill = true
+		})
+		if ex != nil {
+			handlerResult = ex.val
+		}
+		if tracker := r.asyncContextTracker; tracker != nil {
+			tracker.Exited()
+		}
+	}
+	if reaction.capability != nil {
+		if fulfill {
+			reaction.capability.resolve(handlerResult)
+		} else {
+			reaction.capability.reject(handlerResult)
 		}
 	}
 }
@@ -313,10 +414,10 @@ func (r *Runtime) newPromiseCapability(c *Object) *promiseCapability {
 func (r *Runtime) performPromiseThen(p *Promise, onFulfilled, onRejected Value, resultCapability *promiseCapability) Value {
 	var onFulfilledJobCallback, onRejectedJobCallback *jobCallback
 	if f, ok := assertCallable(onFulfilled); ok {
-		onFulfilledJobCallback = &jobCallback{callback: f}
+		onFulfilledJobCallback = &jobCallback{callback: f, obj: onFulfilled.(*Object)}
 	}
 	if f, ok := assertCallable(onRejected); ok {
-		onRejectedJobCallback = &jobCallback{callback: f}
+		onRejectedJobCallback = &jobCallback{callback: f, obj: onRejected.(*Object)}
 	}
 	fulfillReaction := &promiseReaction{
 		capability: resultCapability,
@@ -543,6 +644,16 @@ func (r *Runtime) promise_reject(call FunctionCall) Value {
 	return pcap.promise
 }
 
+func (r *Runtime) promise_withResolvers(call FunctionCall) Value {
+	c := r.toObject(call.This)
+	pcap := r.newPromiseCapability(c)
+	obj := r.NewObject()
+	createDataPropertyOrThrow(obj, asciiString("promise"), pcap.promise)
+	createDataPropertyOrThrow(obj, asciiString("resolve"), pcap.resolveObj)
+	createDataPropertyOrThrow(obj, asciiString("reject"), pcap.rejectObj)
+	return obj
+}
+
 func (r *Runtime) promise_resolve(call FunctionCall) Value {
 	return r.promiseResolve(r.toObject(call.This), call.Argument(0))
 }
@@ -569,6 +680,7 @@ func (r *Runtime) createPromise(val *Object) objectImpl {
 	o._putProp("race", r.newNativeFunc(r.promise_race, "race", 1), true, false, true)
 	o._putProp("reject", r.newNativeFunc(r.promise_reject, "reject", 1), true, false, true)
 	o._putProp("resolve", r.newNativeFunc(r.promise_resolve, "resolve", 1), true, false, true)
+	o._putProp("withResolvers", r.newNativeFunc(r.promise_withResolvers, "withResolvers", 0), true, false, true)
 
 	r.putSpeciesReturnThis(o)
 
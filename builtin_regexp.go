@@ -1247,6 +1247,7 @@ func (r *Runtime) getRegExp() *Object {
 func (r *Runtime) getRegExpPrototype() *Object {
 	ret := r.global.RegExpPrototype
 	if ret == nil {
+		defer r.endBuiltinInit(r.beginBuiltinInit())
 		o := r.newGuardedObject(r.global.ObjectPrototype, classObject)
 		ret = o.val
 		r.global.RegExpPrototype = ret
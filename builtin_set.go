@@ -2,6 +2,7 @@ package goja
 
 import (
 	"fmt"
+	"math"
 	"reflect"
 )
 
@@ -116,7 +117,7 @@ func (r *Runtime) setProto_add(call FunctionCall) Value {
 		panic(r.NewTypeError("Method Set.prototype.add called on incompatible receiver %s", r.objectproto_toString(FunctionCall{This: thisObj})))
 	}
 
-	so.m.set(call.Argument(0), nil)
+	r.mapSet(so.m, call.Argument(0), nil)
 	return call.This
 }
 
@@ -192,6 +193,230 @@ func (r *Runtime) setProto_values(call FunctionCall) Value {
 	return r.createSetIterator(call.This, iterationKindValue)
 }
 
+// setRecord is the Set Record produced by GetSetRecord (https://tc39.es/ecma262/#sec-getsetrecord).
+type setRecord struct {
+	obj  *Object
+	size float64
+	has  func(FunctionCall) Value
+	keys func(FunctionCall) Value
+
+	// Set if obj is a Set that uses the built-in has(), which allows skipping the function calls.
+	set *setObject
+}
+
+func (r *Runtime) getSetRecord(v Value) *setRecord {
+	obj, ok := v.(*Object)
+	if !ok {
+		panic(r.NewTypeError("The argument must be a set-like object"))
+	}
+	numSize := nilSafe(obj.self.getStr("size", nil)).ToNumber()
+	if IsNaN(numSize) {
+		panic(r.NewTypeError("The 'size' property must be a number"))
+	}
+	size := math.Trunc(numSize.ToFloat())
+	if size < 0 {
+		panic(r.newError(r.getRangeError(), "'%s' is an invalid size", numSize.String()))
+	}
+	hasVal := obj.self.getStr("has", nil)
+	rec := &setRecord{
+		obj:  obj,
+		size: size,
+		has:  r.toCallable(hasVal),
+		keys: r.toCallable(obj.self.getStr("keys", nil)),
+	}
+	if so, ok := obj.self.(*setObject); ok && hasVal == r.global.setHas {
+		rec.set = so
+	}
+	return rec
+}
+
+func (rec *setRecord) contains(v Value) bool {
+	if rec.set != nil {
+		return rec.set.m.has(v)
+	}
+	return rec.has(FunctionCall{This: rec.obj, Arguments: []Value{v}}).ToBoolean()
+}
+
+// iterateKeys steps through the iterator returned by keys() until it is exhausted or step returns false,
+// in which case the iterator is closed.
+func (rec *setRecord) iterateKeys(r *Runtime, step func(Value) bool) {
+	iter, ok := rec.keys(FunctionCall{This: rec.obj}).(*Object)
+	if !ok {
+		panic(r.NewTypeError("keys() returned a non-object"))
+	}
+	ir := &iteratorRecord{
+		iterator: iter,
+		next:     r.toCallable(iter.self.getStr("next", nil)),
+	}
+	for {
+		res := r.toObject(ir.next(FunctionCall{This: ir.iterator}))
+		if iteratorComplete(res) {
+			break
+		}
+		if !step(iteratorValue(res)) {
+			ir.returnIter()
+			break
+		}
+	}
+}
+
+func (r *Runtime) toSetObject(v Value, method string) *setObject {
+	thisObj := r.toObject(v)
+	so, ok := thisObj.self.(*setObject)
+	if !ok {
+		panic(r.NewTypeError("Method Set.prototype.%s called on incompatible receiver %s", method, r.objectproto_toString(FunctionCall{This: thisObj})))
+	}
+	return so
+}
+
+// newSetFromMap creates a new Set with the standard prototype that takes ownership of m.
+func (r *Runtime) newSetFromMap(m *orderedMap) *Object {
+	o := &Object{runtime: r}
+	so := &setObject{}
+	so.class = classObject
+	so.val = o
+	so.extensible = true
+	o.self = so
+	so.prototype = r.getSetPrototype()
+	so.init()
+	so.m = m
+	return o
+}
+
+func (m *orderedMap) copyKeys() *orderedMap {
+	res := newOrderedMap(m.hash)
+	iter := m.newIter()
+	for entry := iter.next(); entry != nil; entry = iter.next() {
+		res.set(entry.key, nil)
+	}
+	return res
+}
+
+func (r *Runtime) copySetKeys(m *orderedMap) *orderedMap {
+	res := m.copyKeys()
+	r.trackAlloc(memSizeN(res.size, memSizeMapEntry))
+	return res
+}
+
+func (r *Runtime) setProto_union(call FunctionCall) Value {
+	so := r.toSetObject(call.This, "union")
+	other := r.getSetRecord(call.Argument(0))
+	res := r.copySetKeys(so.m)
+	other.iterateKeys(r, func(v Value) bool {
+		r.mapSet(res, v, nil)
+		return true
+	})
+	return r.newSetFromMap(res)
+}
+
+func (r *Runtime) setProto_intersection(call FunctionCall) Value {
+	so := r.toSetObject(call.This, "intersection")
+	other := r.getSetRecord(call.Argument(0))
+	res := newOrderedMap(so.m.hash)
+	if float64(so.m.size) <= other.size {
+		iter := so.m.newIter()
+		for entry := iter.next(); entry != nil; entry = iter.next() {
+			key := entry.key
+			if other.contains(key) {
+				r.mapSet(res, key, nil)
+			}
+		}
+	} else {
+		other.iterateKeys(r, func(v Value) bool {
+			if so.m.has(v) {
+				r.mapSet(res, v, nil)
+			}
+			return true
+		})
+	}
+	return r.newSetFromMap(res)
+}
+
+func (r *Runtime) setProto_difference(call FunctionCall) Value {
+	so := r.toSetObject(call.This, "difference")
+	other := r.getSetRecord(call.Argument(0))
+	res := r.copySetKeys(so.m)
+	if float64(so.m.size) <= other.size {
+		iter := so.m.newIter()
+		for entry := iter.next(); entry != nil; entry = iter.next() {
+			key := entry.key
+			if other.contains(key) {
+				res.remove(key)
+			}
+		}
+	} else {
+		other.iterateKeys(r, func(v Value) bool {
+			res.remove(v)
+			return true
+		})
+	}
+	return r.newSetFromMap(res)
+}
+
+func (r *Runtime) setProto_symmetricDifference(call FunctionCall) Value {
+	so := r.toSetObject(call.This, "symmetricDifference")
+	other := r.getSetRecord(call.Argument(0))
+	res := r.copySetKeys(so.m)
+	other.iterateKeys(r, func(v Value) bool {
+		if so.m.has(v) {
+			res.remove(v)
+		} else {
+			r.mapSet(res, v, nil)
+		}
+		return true
+	})
+	return r.newSetFromMap(res)
+}
+
+func (r *Runtime) setProto_isSubsetOf(call FunctionCall) Value {
+	so := r.toSetObject(call.This, "isSubsetOf")
+	other := r.getSetRecord(call.Argument(0))
+	if float64(so.m.size) > other.size {
+		return valueFalse
+	}
+	iter := so.m.newIter()
+	for entry := iter.next(); entry != nil; entry = iter.next() {
+		if !other.contains(entry.key) {
+			return valueFalse
+		}
+	}
+	return valueTrue
+}
+
+func (r *Runtime) setProto_isSupersetOf(call FunctionCall) Value {
+	so := r.toSetObject(call.This, "isSupersetOf")
+	other := r.getSetRecord(call.Argument(0))
+	if float64(so.m.size) < other.size {
+		return valueFalse
+	}
+	res := true
+	other.iterateKeys(r, func(v Value) bool {
+		res = so.m.has(v)
+		return res
+	})
+	return r.toBoolean(res)
+}
+
+func (r *Runtime) setProto_isDisjointFrom(call FunctionCall) Value {
+	so := r.toSetObject(call.This, "isDisjointFrom")
+	other := r.getSetRecord(call.Argument(0))
+	if float64(so.m.size) <= other.size {
+		iter := so.m.newIter()
+		for entry := iter.next(); entry != nil; entry = iter.next() {
+			if other.contains(entry.key) {
+				return valueFalse
+			}
+		}
+		return valueTrue
+	}
+	res := true
+	other.iterateKeys(r, func(v Value) bool {
+		res = !so.m.has(v)
+		return res
+	})
+	return r.toBoolean(res)
+}
+
 func (r *Runtime) builtin_newSet(args []Value, newTarget *Object) *Object {
 	if newTarget == nil {
 		panic(r.needNew("Set"))
@@ -213,11 +438,11 @@ func (r *Runtime) builtin_newSet(args []Value, newTarget *Object) *Object {
 			if adder == r.global.setAdder {
 				if stdArr != nil {
 					for _, v := range stdArr.values {
-						so.m.set(v, nil)
+						r.mapSet(so.m, v, nil)
 					}
 				} else {
 					r.getIterator(arg, nil).iterate(func(item Value) {
-						so.m.set(item, nil)
+						r.mapSet(so.m, item, nil)
 					})
 				}
 			} else {
@@ -272,6 +497,7 @@ func (r *Runtime) setIterProto_next(call FunctionCall) Value {
 }
 
 func (r *Runtime) createSetProto(val *Object) objectImpl {
+	defer r.endBuiltinInit(r.beginBuiltinInit())
 	o := newBaseObjectObj(val, r.global.ObjectPrototype, classObject)
 
 	o._putProp("constructor", r.getSet(), true, false, true)
@@ -281,7 +507,8 @@ func (r *Runtime) createSetProto(val *Object) objectImpl {
 	o._putProp("clear", r.newNativeFunc(r.setProto_clear, "clear", 0), true, false, true)
 	o._putProp("delete", r.newNativeFunc(r.setProto_delete, "delete", 1), true, false, true)
 	o._putProp("forEach", r.newNativeFunc(r.setProto_forEach, "forEach", 1), true, false, true)
-	o._putProp("has", r.newNativeFunc(r.setProto_has, "has", 1), true, false, true)
+	r.global.setHas = r.newNativeFunc(r.setProto_has, "has", 1)
+	o._putProp("has", r.global.setHas, true, false, true)
 	o.setOwnStr("size", &valueProperty{
 		getterFunc:   r.newNativeFunc(r.setProto_getSize, "get size", 0),
 		accessor:     true,
@@ -293,6 +520,14 @@ func (r *Runtime) createSetProto(val *Object) objectImpl {
 	o._putProp("values", valuesFunc, true, false, true)
 	o._putProp("keys", valuesFunc, true, false, true)
 	o._putProp("entries", r.newNativeFunc(r.setProto_entries, "entries", 0), true, false, true)
+
+	o._putProp("union", r.newNativeFunc(r.setProto_union, "union", 1), true, false, true)
+	o._putProp("intersection", r.newNativeFunc(r.setProto_intersection, "intersection", 1), true, false, true)
+	o._putProp("difference", r.newNativeFunc(r.setProto_difference, "difference", 1), true, false, true)
+	o._putProp("symmetricDifference", r.newNativeFunc(r.setProto_symmetricDifference, "symmetricDifference", 1), true, false, true)
+	o._putProp("isSubsetOf", r.newNativeFunc(r.setProto_isSubsetOf, "isSubsetOf", 1), true, false, true)
+	o._putProp("isSupersetOf", r.newNativeFunc(r.setProto_isSupersetOf, "isSupersetOf", 1), true, false, true)
+	o._putProp("isDisjointFrom", r.newNativeFunc(r.setProto_isDisjointFrom, "isDisjointFrom", 1), true, false, true)
 	o._putSym(SymIterator, valueProp(valuesFunc, true, false, true))
 	o._putSym(SymToStringTag, valueProp(asciiString(classSet), false, false, true))
 
@@ -178,3 +178,72 @@ func TestSetGetAdderGetIteratorOrder(t *testing.T) {
 	`
 	testScript(SCRIPT, valueTrue, t)
 }
+
+func TestSetMethods(t *testing.T) {
+	const SCRIPT = `
+	function values(s) {
+		return Array.from(s).join(",");
+	}
+	var a = new Set([1, 2, 3, 4]);
+	var b = new Set([3, 4, 5]);
+
+	assert.sameValue(values(a.union(b)), "1,2,3,4,5", "union");
+	assert.sameValue(values(a.intersection(b)), "3,4", "intersection");
+	assert.sameValue(values(b.intersection(a)), "3,4", "intersection (larger argument)");
+	assert.sameValue(values(a.difference(b)), "1,2", "difference");
+	assert.sameValue(values(a.symmetricDifference(b)), "1,2,5", "symmetricDifference");
+	assert.sameValue(new Set([3]).isSubsetOf(b), true, "isSubsetOf");
+	assert.sameValue(a.isSubsetOf(b), false, "isSubsetOf (false)");
+	assert.sameValue(a.isSupersetOf(new Set([1, 2])), true, "isSupersetOf");
+	assert.sameValue(a.isSupersetOf(b), false, "isSupersetOf (false)");
+	assert.sameValue(a.isDisjointFrom(new Set([7, 8])), true, "isDisjointFrom");
+	assert.sameValue(a.isDisjointFrom(b), false, "isDisjointFrom (false)");
+
+	assert.sameValue(Object.getPrototypeOf(a.union(b)), Set.prototype, "result prototype");
+	assert.sameValue(values(a), "1,2,3,4", "receiver is not modified");
+
+	// set-like objects
+	var m = new Map([[2, "x"], [9, "y"]]);
+	assert.sameValue(values(a.union(m)), "1,2,3,4,9", "union with a Map");
+	assert.sameValue(values(a.intersection(m)), "2", "intersection with a Map");
+
+	var log = [];
+	var setLike = {
+		size: 2,
+		has: function(v) {
+			log.push("has " + v);
+			return v === 1 || v === 10;
+		},
+		keys: function() {
+			log.push("keys");
+			var i = 0;
+			return {
+				next: function() {
+					i++;
+					return i <= 2 ? {value: i === 1 ? 1 : 10, done: false} : {done: true};
+				},
+				return: function() {
+					log.push("return");
+					return {};
+				}
+			};
+		}
+	};
+	assert.sameValue(new Set([1]).isDisjointFrom(setLike), false);
+	assert.sameValue(log.join(), "has 1", "uses has() when the receiver is smaller");
+	log = [];
+	assert.sameValue(new Set([1, 2, 3]).isSupersetOf(setLike), false);
+	assert.sameValue(log.join(), "keys,return", "closes the iterator");
+	assert.sameValue(values(new Set([0]).union({size: 1, has: function() {}, keys: function() {
+		return [-0][Symbol.iterator]();
+	}})), "0", "-0 is normalised");
+
+	assert.throws(TypeError, function() { a.union([1]); }, "array is not set-like");
+	assert.throws(TypeError, function() { a.union({size: NaN, has: function() {}, keys: function() {}}); });
+	assert.throws(RangeError, function() { a.union({size: -1, has: function() {}, keys: function() {}}); });
+	assert.throws(TypeError, function() { a.union({size: 1, has: 1, keys: function() {}}); });
+	assert.throws(TypeError, function() { Set.prototype.union.call({}, b); });
+	assert.sameValue(Set.prototype.isSubsetOf.length, 1);
+	`
+	testScriptWithTestLib(SCRIPT, _undefined, t)
+}
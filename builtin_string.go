@@ -9,20 +9,9 @@ import (
 	"unicode/utf8"
 
 	"github.com/dop251/goja/parser"
-	"golang.org/x/text/collate"
-	"golang.org/x/text/language"
 	"golang.org/x/text/unicode/norm"
 )
 
-func (r *Runtime) collator() *collate.Collator {
-	collator := r._collator
-	if collator == nil {
-		collator = collate.New(language.Und)
-		r._collator = collator
-	}
-	return collator
-}
-
 func toString(arg Value) String {
 	if s, ok := arg.(String); ok {
 		return s
@@ -183,6 +172,16 @@ func (r *Runtime) stringproto_at(call FunctionCall) Value {
 	return s.Substring(int(pos), int(pos+1))
 }
 
+func (r *Runtime) stringproto_isWellFormed(call FunctionCall) Value {
+	r.checkObjectCoercible(call.This)
+	return r.toBoolean(call.This.toString().IsWellFormed())
+}
+
+func (r *Runtime) stringproto_toWellFormed(call FunctionCall) Value {
+	r.checkObjectCoercible(call.This)
+	return call.This.toString().ToWellFormed()
+}
+
 func (r *Runtime) stringproto_charAt(call FunctionCall) Value {
 	r.checkObjectCoercible(call.This)
 	s := call.This.toString()
@@ -251,7 +250,9 @@ func (r *Runtime) stringproto_concat(call FunctionCall) Value {
 		}
 	}
 
+	r.checkStringLength(totalLen)
 	if allAscii {
+		r.trackAlloc(uint64(totalLen))
 		var buf strings.Builder
 		buf.Grow(totalLen)
 		for _, s := range strs {
@@ -259,6 +260,7 @@ func (r *Runtime) stringproto_concat(call FunctionCall) Value {
 		}
 		return asciiString(buf.String())
 	} else {
+		r.trackAlloc(memSizeN(totalLen, 2))
 		buf := make([]uint16, totalLen+1)
 		buf[0] = unistring.BOM
 		pos := 1
@@ -372,9 +374,16 @@ func (r *Runtime) stringproto_lastIndexOf(call FunctionCall) Value {
 
 func (r *Runtime) stringproto_localeCompare(call FunctionCall) Value {
 	r.checkObjectCoercible(call.This)
-	this := norm.NFD.String(call.This.toString().String())
-	that := norm.NFD.String(call.Argument(0).toString().String())
-	return intToValue(int64(r.collator().CompareString(this, that)))
+	this := call.This.toString()
+	that := call.Argument(0).toString()
+	locales, options := call.Argument(1), call.Argument(2)
+	var collator *intlCollator
+	if locales == _undefined && options == _undefined {
+		collator = r.collator()
+	} else {
+		collator = r.newIntlCollator(locales, options)
+	}
+	return intToValue(int64(collator.compare(this, that)))
 }
 
 func (r *Runtime) stringproto_match(call FunctionCall) Value {
@@ -502,8 +511,10 @@ func (r *Runtime) _stringPad(call FunctionCall, start bool) Value {
 		fillerAscii = " "
 		filler = fillerAscii
 	}
+	r.checkStringLength(toIntStrict(maxLength))
 	remaining := toIntStrict(maxLength - stringLength)
 	if fillerUnicode == nil && strUnicode == nil {
+		r.trackAlloc(uint64(maxLength))
 		fl := fillerAscii.Length()
 		var sb strings.Builder
 		sb.Grow(toIntStrict(maxLength))
@@ -522,6 +533,7 @@ func (r *Runtime) _stringPad(call FunctionCall, start bool) Value {
 		}
 		return asciiString(sb.String())
 	}
+	r.trackAlloc(memSizeN(toIntStrict(maxLength), 2))
 	var sb unicodeStringBuilder
 	sb.ensureStarted(toIntStrict(maxLength))
 	if !start {
@@ -565,8 +577,12 @@ func (r *Runtime) stringproto_repeat(call FunctionCall) Value {
 		return stringEmpty
 	}
 	num := toIntStrict(numInt)
+	if r.maxStringLen > 0 && int64(num) > int64(r.maxStringLen/s.Length()) {
+		panic(r.newError(r.getRangeError(), "Invalid string length"))
+	}
 	a, u := devirtualizeString(s)
 	if u == nil {
+		r.trackAlloc(memSizeN(num, uint64(len(a))))
 		var sb strings.Builder
 		sb.Grow(len(a) * num)
 		for i := 0; i < num; i++ {
@@ -575,6 +591,7 @@ func (r *Runtime) stringproto_repeat(call FunctionCall) Value {
 		return asciiString(sb.String())
 	}
 
+	r.trackAlloc(memSizeN(num, memSizeN(u.Length(), 2)))
 	var sb unicodeStringBuilder
 	sb.Grow(u.Length() * num)
 	for i := 0; i < num; i++ {
@@ -1015,6 +1032,7 @@ func createStringProtoTemplate() *objectTemplate {
 	t.putStr("endsWith", func(r *Runtime) Value { return r.methodProp(r.stringproto_endsWith, "endsWith", 1) })
 	t.putStr("includes", func(r *Runtime) Value { return r.methodProp(r.stringproto_includes, "includes", 1) })
 	t.putStr("indexOf", func(r *Runtime) Value { return r.methodProp(r.stringproto_indexOf, "indexOf", 1) })
+	t.putStr("isWellFormed", func(r *Runtime) Value { return r.methodProp(r.stringproto_isWellFormed, "isWellFormed", 0) })
 	t.putStr("lastIndexOf", func(r *Runtime) Value { return r.methodProp(r.stringproto_lastIndexOf, "lastIndexOf", 1) })
 	t.putStr("localeCompare", func(r *Runtime) Value { return r.methodProp(r.stringproto_localeCompare, "localeCompare", 1) })
 	t.putStr("match", func(r *Runtime) Value { return r.methodProp(r.stringproto_match, "match", 1) })
@@ -1035,6 +1053,7 @@ func createStringProtoTemplate() *objectTemplate {
 	t.putStr("toLowerCase", func(r *Runtime) Value { return r.methodProp(r.stringproto_toLowerCase, "toLowerCase", 0) })
 	t.putStr("toString", func(r *Runtime) Value { return r.methodProp(r.stringproto_toString, "toString", 0) })
 	t.putStr("toUpperCase", func(r *Runtime) Value { return r.methodProp(r.stringproto_toUpperCase, "toUpperCase", 0) })
+	t.putStr("toWellFormed", func(r *Runtime) Value { return r.methodProp(r.stringproto_toWellFormed, "toWellFormed", 0) })
 	t.putStr("trim", func(r *Runtime) Value { return r.methodProp(r.stringproto_trim, "trim", 0) })
 	t.putStr("trimEnd", func(r *Runtime) Value { return valueProp(r.getStringproto_trimEnd(), true, false, true) })
 	t.putStr("trimStart", func(r *Runtime) Value { return valueProp(r.getStringproto_trimStart(), true, false, true) })
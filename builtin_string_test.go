@@ -276,3 +276,17 @@ func TestValueStringBuilder(t *testing.T) {
 	})
 
 }
+
+func TestStringProtoWellFormed(t *testing.T) {
+	const SCRIPT = `
+	assert.sameValue("abc".isWellFormed(), true);
+	assert.sameValue("\u00e9\ud83d\ude00".isWellFormed(), true);
+	assert.sameValue("a\ud800b".isWellFormed(), false, "lone lead surrogate");
+	assert.sameValue("\udc00".isWellFormed(), false, "lone trail surrogate");
+	assert.sameValue("a\ud800b\udc00".toWellFormed(), "a\ufffdb\ufffd");
+	assert.sameValue("\ud83d\ude00".toWellFormed(), "\ud83d\ude00");
+	assert.sameValue(String.prototype.isWellFormed.call(1), true);
+	assert.throws(TypeError, function() { String.prototype.toWellFormed.call(null); });
+	`
+	testScriptWithTestLib(SCRIPT, _undefined, t)
+}
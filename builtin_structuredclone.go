@@ -0,0 +1,265 @@
+package goja
+
+import (
+	"time"
+)
+
+type structuredCloner struct {
+	r      *Runtime
+	memory map[*Object]*Object
+}
+
+// CloneValue returns a deep copy of v created in dst using the structured clone algorithm
+// (https://html.spec.whatwg.org/multipage/structured-data.html#structured-cloning), the same way as
+// the structuredClone() global function does. It can be used to pass values between Runtimes.
+//
+// Primitive values, plain objects, arrays, Boolean, Number and String objects, Date, RegExp, Map, Set,
+// ArrayBuffer, typed arrays, DataView and Error objects are supported. Cycles and shared references are preserved.
+// Attempting to clone anything else (i.e. functions, symbols, proxies, host objects) results in a DataCloneError.
+//
+// The ArrayBuffers listed in transfer are not copied, instead their contents are moved into the clone and the
+// originals become detached. They must belong to the same Runtime as v.
+//
+// If v is an object, getters may be invoked in the Runtime it belongs to. Neither that Runtime nor dst may be
+// used concurrently while CloneValue is running.
+func CloneValue(dst *Runtime, v Value, transfer ...ArrayBuffer) (res Value, err error) {
+	src := dst
+	if obj, ok := v.(*Object); ok {
+		src = obj.runtime
+	}
+	err = src.runWrapped(func() {
+		bufs := make([]*arrayBufferObject, 0, len(transfer))
+		for _, t := range transfer {
+			if t.buf == nil || t.buf.val.runtime != src {
+				panic(dst.newDataCloneError("Value not transferable"))
+			}
+			bufs = append(bufs, t.buf)
+		}
+		res = dst.structuredClone(v, bufs)
+	})
+	return
+}
+
+func (r *Runtime) newDataCloneError(format string, args ...interface{}) *Object {
+	o := r.newError(r.getError(), format, args...).(*Object)
+	o.self._putProp("name", asciiString("DataCloneError"), true, false, true)
+	return o
+}
+
+func (r *Runtime) structuredClone(v Value, transfer []*arrayBufferObject) Value {
+	c := &structuredCloner{
+		r:      r,
+		memory: make(map[*Object]*Object),
+	}
+	for i, buf := range transfer {
+		if _, exists := c.memory[buf.val]; exists {
+			panic(r.newDataCloneError("ArrayBuffer at index %d is a duplicate of an earlier ArrayBuffer", i))
+		}
+		if buf.detached {
+			panic(r.newDataCloneError("ArrayBuffer at index %d is already detached", i))
+		}
+		c.memory[buf.val] = r._newArrayBuffer(r.getArrayBufferPrototype(), nil).val
+	}
+	res := c.clone(v)
+	for _, buf := range transfer {
+		c.memory[buf.val].self.(*arrayBufferObject).data = buf.data
+		buf.detach()
+	}
+	return res
+}
+
+func (c *structuredCloner) clone(v Value) Value {
+	r := c.r
+	obj, ok := v.(*Object)
+	if !ok {
+		if sym, ok := v.(*Symbol); ok {
+			panic(r.newDataCloneError("%s could not be cloned", sym.descriptiveString()))
+		}
+		return v
+	}
+	if res, exists := c.memory[obj]; exists {
+		return res
+	}
+	var res *Object
+	switch o := obj.self.(type) {
+	case *primitiveValueObject:
+		if sym, ok := o.pValue.(*Symbol); ok {
+			panic(r.newDataCloneError("%s could not be cloned", sym.descriptiveString()))
+		}
+		res = o.pValue.ToObject(r)
+	case *stringObject:
+		res = o.value.ToObject(r)
+	case *dateObject:
+		res = r.newDateObject(time.Time{}, false, r.getDatePrototype())
+		res.self.(*dateObject).msec = o.msec
+	case *regexpObject:
+		res = r.newRegExpp(o.pattern.clone(), o.source, r.getRegExpPrototype()).val
+	case *arrayBufferObject:
+		if o.detached {
+			panic(r.newDataCloneError("An ArrayBuffer is detached and could not be cloned"))
+		}
+		buf := r._newArrayBuffer(r.getArrayBufferPrototype(), nil)
+		r.trackAlloc(uint64(len(o.data)))
+		buf.data = allocByteSlice(len(o.data))
+		copy(buf.data, o.data)
+		res = buf.val
+	case *typedArrayObject:
+		if o.viewedArrayBuf.detached {
+			panic(r.newDataCloneError("An ArrayBuffer is detached and could not be cloned"))
+		}
+		buf := c.clone(o.viewedArrayBuf.val).(*Object).self.(*arrayBufferObject)
+		ctor, taCtor := r.typedArrayCtorFor(o.typedArray)
+		res = taCtor(buf, o.offset, o.length, r.getPrototypeFromCtor(ctor, nil, r.getTypedArrayPrototype())).val
+	case *dataViewObject:
+		if o.viewedArrayBuf.detached {
+			panic(r.newDataCloneError("An ArrayBuffer is detached and could not be cloned"))
+		}
+		buf := c.clone(o.viewedArrayBuf.val).(*Object).self.(*arrayBufferObject)
+		res = r._newDataViewObject(buf, o.byteOffset, o.byteLen, r.getDataViewPrototype()).val
+	case *errorObject:
+		res = c.cloneError(o)
+	case *mapObject:
+		res = r.builtin_newMap(nil, r.getMap())
+		c.memory[obj] = res
+		// The list of entries is copied first, so that cloning the keys or the values
+		// (which may invoke getters) does not affect the iteration.
+		entries := make([]Value, 0, o.m.size*2)
+		iter := o.m.newIter()
+		for entry := iter.next(); entry != nil; entry = iter.next() {
+			entries = append(entries, entry.key, entry.value)
+		}
+		m := res.self.(*mapObject).m
+		for i := 0; i < len(entries); i += 2 {
+			r.mapSet(m, c.clone(entries[i]), c.clone(entries[i+1]))
+		}
+		return res
+	case *setObject:
+		m := newOrderedMap(r.getHash())
+		res = r.newSetFromMap(m)
+		c.memory[obj] = res
+		keys := make([]Value, 0, o.m.size)
+		iter := o.m.newIter()
+		for entry := iter.next(); entry != nil; entry = iter.next() {
+			keys = append(keys, entry.key)
+		}
+		for _, key := range keys {
+			r.mapSet(m, c.clone(key), nil)
+		}
+		return res
+	case *arrayObject, *sparseArrayObject:
+		res = r.newArrayLength(toLength(obj.self.getStr("length", nil)))
+		c.memory[obj] = res
+		c.cloneProperties(obj, res)
+		return res
+	case *argumentsObject:
+		// the arguments object is cloned as an ordinary object
+		return c.cloneObject(obj)
+	case *baseObject:
+		if o.class != classObject && o.class != classArguments {
+			panic(r.newDataCloneError("#<%s> could not be cloned", o.class))
+		}
+		return c.cloneObject(obj)
+	default:
+		panic(r.newDataCloneError("#<%s> could not be cloned", obj.self.className()))
+	}
+	c.memory[obj] = res
+	return res
+}
+
+func (c *structuredCloner) cloneObject(obj *Object) *Object {
+	res := c.r.NewObject()
+	c.memory[obj] = res
+	c.cloneProperties(obj, res)
+	return res
+}
+
+func (c *structuredCloner) cloneProperties(src, dst *Object) {
+	for item, next := iterateEnumerableStringProperties(src)(); next != nil; item, next = next() {
+		createDataProperty(dst, item.name, c.clone(item.value))
+	}
+}
+
+func (c *structuredCloner) cloneError(o *errorObject) *Object {
+	r := c.r
+	ctor := r.getError()
+	if name, ok := o.getStr("name", nil).(String); ok {
+		switch name.String() {
+		case "EvalError":
+			ctor = r.getEvalError()
+		case "RangeError":
+			ctor = r.getRangeError()
+		case "ReferenceError":
+			ctor = r.getReferenceError()
+		case "SyntaxError":
+			ctor = r.getSyntaxError()
+		case "TypeError":
+			ctor = r.getTypeError()
+		case "URIError":
+			ctor = r.getURIError()
+		}
+	}
+	var message Value
+	switch prop := o.getOwnPropStr("message").(type) {
+	case nil:
+	case *valueProperty:
+		if !prop.accessor {
+			message = prop.value.toString()
+		}
+	default:
+		message = prop.toString()
+	}
+	res := r.newErrorObject(r.getPrototypeFromCtor(ctor, nil, r.getErrorPrototype()), classError)
+	if message != nil {
+		res._putProp("message", message, true, false, true)
+	}
+	res.stack = append([]StackFrame(nil), o.stack...)
+	return res.val
+}
+
+func (r *Runtime) typedArrayCtorFor(a typedArray) (*Object, typedArrayObjectCtor) {
+	switch a.(type) {
+	case *uint8Array:
+		return r.getUint8Array(), r.newUint8ArrayObject
+	case *uint8ClampedArray:
+		return r.getUint8ClampedArray(), r.newUint8ClampedArrayObject
+	case *int8Array:
+		return r.getInt8Array(), r.newInt8ArrayObject
+	case *uint16Array:
+		return r.getUint16Array(), r.newUint16ArrayObject
+	case *int16Array:
+		return r.getInt16Array(), r.newInt16ArrayObject
+	case *uint32Array:
+		return r.getUint32Array(), r.newUint32ArrayObject
+	case *int32Array:
+		return r.getInt32Array(), r.newInt32ArrayObject
+	case *float32Array:
+		return r.getFloat32Array(), r.newFloat32ArrayObject
+	case *float64Array:
+		return r.getFloat64Array(), r.newFloat64ArrayObject
+	}
+	panic(r.newDataCloneError("Unsupported typed array type %T", a))
+}
+
+func (r *Runtime) builtin_structuredClone(call FunctionCall) Value {
+	var transfer []*arrayBufferObject
+	if opts := call.Argument(1); opts != _undefined && opts != _null {
+		optsObj, ok := opts.(*Object)
+		if !ok {
+			panic(r.NewTypeError("The options argument must be an object"))
+		}
+		if t := optsObj.self.getStr("transfer", nil); t != nil && t != _undefined {
+			for _, item := range r.iterableToList(t, nil) {
+				itemObj, ok := item.(*Object)
+				if !ok {
+					panic(r.NewTypeError("Transfer list items must be objects"))
+				}
+				buf, ok := itemObj.self.(*arrayBufferObject)
+				if !ok || itemObj.runtime != r {
+					panic(r.newDataCloneError("Value not transferable"))
+				}
+				transfer = append(transfer, buf)
+			}
+		}
+	}
+	return r.structuredClone(call.Argument(0), transfer)
+}
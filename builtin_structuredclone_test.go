@@ -0,0 +1,188 @@
+package goja
+
+import (
+	"testing"
+)
+
+func TestStructuredClone(t *testing.T) {
+	const SCRIPT = `
+	var o = {a: 1, s: "str", n: null, u: undefined, nested: {arr: [1, , 3]}};
+	o.self = o;
+	o.nested.parent = o;
+	var c = structuredClone(o);
+	assert(c !== o, "copy");
+	assert.sameValue(c.a, 1);
+	assert.sameValue(c.s, "str");
+	assert.sameValue(c.n, null);
+	assert("u" in c, "undefined property");
+	assert.sameValue(c.self, c, "cycle");
+	assert.sameValue(c.nested.parent, c, "nested cycle");
+	assert.sameValue(c.nested.arr.length, 3);
+	assert(!(1 in c.nested.arr), "hole");
+	assert(Array.isArray(c.nested.arr), "array");
+
+	var shared = {};
+	c = structuredClone([shared, shared]);
+	assert.sameValue(c[0], c[1], "shared references");
+
+	var d = new Date(2020, 1, 1);
+	c = structuredClone(d);
+	assert(c instanceof Date, "Date");
+	assert.sameValue(c.getTime(), d.getTime());
+
+	var re = /ab+c/gi;
+	re.lastIndex = 3;
+	c = structuredClone(re);
+	assert(c instanceof RegExp, "RegExp");
+	assert.sameValue(c.source, "ab+c");
+	assert.sameValue(c.flags, "gi");
+	assert.sameValue(c.lastIndex, 0);
+
+	var m = new Map([[1, {x: 1}], [o, "o"]]);
+	c = structuredClone(m);
+	assert(c instanceof Map, "Map");
+	assert.sameValue(c.size, 2);
+	assert.sameValue(c.get(1).x, 1);
+	var keys = Array.from(c.keys());
+	assert.sameValue(keys[1].self, keys[1], "Map key cycle");
+
+	c = structuredClone(new Set([1, "a", 1]));
+	assert(c instanceof Set, "Set");
+	assert(compareArray(Array.from(c), [1, "a"]), "Set values");
+
+	var ta = new Uint16Array([1, 2, 3, 4]);
+	var sub = ta.subarray(1, 3);
+	c = structuredClone({ta: ta, sub: sub});
+	assert(c.ta instanceof Uint16Array, "typed array");
+	assert(compareArray(c.sub, [2, 3]), "subarray");
+	assert.sameValue(c.ta.buffer, c.sub.buffer, "typed arrays share the cloned buffer");
+	assert(c.ta.buffer !== ta.buffer, "buffer is copied");
+	c.ta[0] = 42;
+	assert.sameValue(ta[0], 1, "buffer is not shared with the original");
+
+	var dv = new DataView(new ArrayBuffer(8), 2, 4);
+	c = structuredClone(dv);
+	assert(c instanceof DataView, "DataView");
+	assert.sameValue(c.byteOffset, 2);
+	assert.sameValue(c.byteLength, 4);
+
+	var err = new RangeError("boom");
+	err.extra = 1;
+	c = structuredClone(err);
+	assert(c instanceof RangeError, "RangeError");
+	assert.sameValue(c.message, "boom");
+	assert.sameValue(c.extra, undefined);
+	assert.sameValue(Object.getOwnPropertyDescriptor(c, "message").enumerable, false);
+	class MyError extends Error {}
+	c = structuredClone(new MyError("x"));
+	assert.sameValue(Object.getPrototypeOf(c), Error.prototype);
+
+	// arguments objects (both mapped and unmapped) are cloned as ordinary objects
+	c = structuredClone((function(a, b) { return arguments; })(1, {x: 2}));
+	assert.sameValue(Object.getPrototypeOf(c), Object.prototype);
+	assert.sameValue(Object.prototype.toString.call(c), "[object Object]");
+	assert(compareArray(Object.keys(c), ["0", "1"]), "arguments keys");
+	assert.sameValue(c[1].x, 2);
+	c = structuredClone((function() { "use strict"; return arguments; })("a"));
+	assert.sameValue(c[0], "a");
+	assert.sameValue(c.length, undefined);
+
+	c = structuredClone(new Number(1));
+	assert.sameValue(typeof c, "object");
+	assert.sameValue(c.valueOf(), 1);
+	c = structuredClone(new String("abc"));
+	assert.sameValue(c.valueOf(), "abc");
+
+	function check(v) {
+		try {
+			structuredClone(v);
+		} catch (e) {
+			assert.sameValue(e.name, "DataCloneError");
+			return;
+		}
+		throw new Error("DataCloneError expected for " + String(v));
+	}
+	check(function() {});
+	check(Symbol("s"));
+	check({s: Symbol()});
+	check(new WeakMap());
+	check(Promise.resolve());
+	check(new Proxy({}, {}));
+	check(Math);
+
+	var buf = new ArrayBuffer(4);
+	new Uint8Array(buf)[0] = 7;
+	c = structuredClone({buf: buf, view: new Uint8Array(buf)}, {transfer: [buf]});
+	assert.sameValue(buf.byteLength, 0, "transferred buffer is detached");
+	assert.sameValue(c.buf.byteLength, 4);
+	assert.sameValue(c.view[0], 7);
+	assert.sameValue(c.view.buffer, c.buf);
+	check(buf);
+
+	var buf1 = new ArrayBuffer(1);
+	assert.throws(Error, function() { structuredClone(buf1, {transfer: [buf1, buf1]}); });
+	assert.sameValue(buf1.byteLength, 1, "not detached on failure");
+	assert.throws(TypeError, function() { structuredClone(1, 1); });
+	assert.throws(TypeError, function() { structuredClone(1, {transfer: [1]}); });
+	`
+	testScriptWithTestLib(SCRIPT, _undefined, t)
+}
+
+func TestCloneValue(t *testing.T) {
+	r1 := New()
+	r2 := New()
+	v, err := r1.RunString(`
+	var o = {d: new Date(0), m: new Map([["k", [1, 2]]]), e: new TypeError("msg"), buf: new ArrayBuffer(2)};
+	o.self = o;
+	o;
+	`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf := v.(*Object).Get("buf").Export().(ArrayBuffer)
+	c, err := CloneValue(r2, v, buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.(*Object).runtime != r2 {
+		t.Fatal("the clone must belong to the destination runtime")
+	}
+	if !buf.Detached() {
+		t.Fatal("transferred buffer is not detached")
+	}
+	r2.Set("c", c)
+	_, err = r2.RunString(`
+	if (c.self !== c) {
+		throw new Error("cycle");
+	}
+	if (!(c.d instanceof Date) || c.d.getTime() !== 0) {
+		throw new Error("Date");
+	}
+	if (!(c.m instanceof Map) || c.m.get("k").join() !== "1,2" || !Array.isArray(c.m.get("k"))) {
+		throw new Error("Map");
+	}
+	if (!(c.e instanceof TypeError) || c.e.message !== "msg") {
+		throw new Error("Error");
+	}
+	if (!(c.buf instanceof ArrayBuffer) || c.buf.byteLength !== 2) {
+		throw new Error("ArrayBuffer");
+	}
+	`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fn, err := r1.RunString(`(function() {})`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = CloneValue(r2, fn)
+	if ex, ok := err.(*Exception); !ok || ex.Value().(*Object).Get("name").String() != "DataCloneError" {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	c, err = CloneValue(r2, r1.ToValue("primitive"))
+	if err != nil || c.String() != "primitive" {
+		t.Fatalf("Unexpected result: %v, %v", c, err)
+	}
+}
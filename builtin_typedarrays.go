@@ -81,7 +81,9 @@ func (r *Runtime) builtin_newArrayBuffer(args []Value, newTarget *Object) *Objec
 	}
 	b := r._newArrayBuffer(r.getPrototypeFromCtor(newTarget, r.getArrayBuffer(), r.getArrayBufferPrototype()), nil)
 	if len(args) > 0 {
-		b.data = allocByteSlice(r.toIndex(args[0]))
+		size := r.toIndex(args[0])
+		r.trackAlloc(uint64(size))
+		b.data = allocByteSlice(size)
 	}
 	return b.val
 }
@@ -177,6 +179,10 @@ func (r *Runtime) newDataView(args []Value, newTarget *Object) *Object {
 	} else {
 		byteLen = len(buffer.data) - byteOffset
 	}
+	return r._newDataViewObject(buffer, byteOffset, byteLen, proto).val
+}
+
+func (r *Runtime) _newDataViewObject(buffer *arrayBufferObject, byteOffset, byteLen int, proto *Object) *dataViewObject {
 	o := &Object{runtime: r}
 	b := &dataViewObject{
 		baseObject: baseObject{
@@ -191,7 +197,7 @@ func (r *Runtime) newDataView(args []Value, newTarget *Object) *Object {
 	}
 	o.self = b
 	b.init()
-	return o
+	return b
 }
 
 func (r *Runtime) dataViewProto_getBuffer(call FunctionCall) Value {
@@ -941,6 +947,31 @@ func (r *Runtime) typedArrayProto_reverse(call FunctionCall) Value {
 	panic(r.NewTypeError("Method TypedArray.prototype.reverse called on incompatible receiver %s", r.objectproto_toString(FunctionCall{This: call.This})))
 }
 
+// typedArrayCopy implements TypedArrayCreateSameType (https://tc39.es/ecma262/#sec-typedarray-create-same-type)
+// and copies the elements of ta into the new array.
+func (r *Runtime) typedArrayCopy(ta *typedArrayObject) *typedArrayObject {
+	dst := r.typedArrayCreate(ta.defaultCtor, intToValue(int64(ta.length)))
+	ta.viewedArrayBuf.ensureNotDetached(true)
+	elemSize := ta.elemSize
+	copy(dst.viewedArrayBuf.data, ta.viewedArrayBuf.data[ta.offset*elemSize:(ta.offset+ta.length)*elemSize])
+	return dst
+}
+
+func (r *Runtime) typedArrayProto_toReversed(call FunctionCall) Value {
+	if ta, ok := r.toObject(call.This).self.(*typedArrayObject); ok {
+		ta.viewedArrayBuf.ensureNotDetached(true)
+		dst := r.typedArrayCopy(ta)
+		l := dst.length
+		middle := l / 2
+		for lower := 0; lower != middle; lower++ {
+			upper := l - lower - 1
+			dst.typedArray.swap(dst.offset+lower, dst.offset+upper)
+		}
+		return dst.val
+	}
+	panic(r.NewTypeError("Method TypedArray.prototype.toReversed called on incompatible receiver %s", r.objectproto_toString(FunctionCall{This: call.This})))
+}
+
 func (r *Runtime) typedArrayProto_set(call FunctionCall) Value {
 	if ta, ok := r.toObject(call.This).self.(*typedArrayObject); ok {
 		srcObj := call.Argument(0).ToObject(r)
@@ -1098,6 +1129,25 @@ func (r *Runtime) typedArrayProto_sort(call FunctionCall) Value {
 	panic(r.NewTypeError("Method TypedArray.prototype.sort called on incompatible receiver %s", r.objectproto_toString(FunctionCall{This: call.This})))
 }
 
+func (r *Runtime) typedArrayProto_toSorted(call FunctionCall) Value {
+	if ta, ok := r.toObject(call.This).self.(*typedArrayObject); ok {
+		var compareFn func(FunctionCall) Value
+		if arg := call.Argument(0); arg != _undefined {
+			compareFn = r.toCallable(arg)
+		}
+		ta.viewedArrayBuf.ensureNotDetached(true)
+		dst := r.typedArrayCopy(ta)
+		ctx := typedArraySortCtx{
+			ta:      dst,
+			compare: compareFn,
+		}
+
+		sort.Stable(&ctx)
+		return dst.val
+	}
+	panic(r.NewTypeError("Method TypedArray.prototype.toSorted called on incompatible receiver %s", r.objectproto_toString(FunctionCall{This: call.This})))
+}
+
 func (r *Runtime) typedArrayProto_subarray(call FunctionCall) Value {
 	if ta, ok := r.toObject(call.This).self.(*typedArrayObject); ok {
 		l := int64(ta.length)
@@ -1121,6 +1171,7 @@ func (r *Runtime) typedArrayProto_subarray(call FunctionCall) Value {
 func (r *Runtime) typedArrayProto_toLocaleString(call FunctionCall) Value {
 	if ta, ok := r.toObject(call.This).self.(*typedArrayObject); ok {
 		length := ta.length
+		locales, options := call.Argument(0), call.Argument(1)
 		var buf StringBuilder
 		for i := 0; i < length; i++ {
 			ta.viewedArrayBuf.ensureNotDetached(true)
@@ -1128,7 +1179,7 @@ func (r *Runtime) typedArrayProto_toLocaleString(call FunctionCall) Value {
 				buf.WriteRune(',')
 			}
 			item := ta.typedArray.get(ta.offset + i)
-			r.writeItemLocaleString(item, &buf)
+			r.writeItemLocaleString(item, locales, options, &buf)
 		}
 		return buf.String()
 	}
@@ -1157,6 +1208,26 @@ func (r *Runtime) newTypedArray([]Value, *Object) *Object {
 	panic(r.NewTypeError("Abstract class TypedArray not directly constructable"))
 }
 
+func (r *Runtime) typedArrayProto_with(call FunctionCall) Value {
+	if ta, ok := r.toObject(call.This).self.(*typedArrayObject); ok {
+		ta.viewedArrayBuf.ensureNotDetached(true)
+		length := int64(ta.length)
+		relativeIndex := call.Argument(0).ToInteger()
+		actualIndex := relativeIndex
+		if actualIndex < 0 {
+			actualIndex = length + relativeIndex
+		}
+		value := ta.typedArray.toRaw(call.Argument(1))
+		if !ta.isValidIntegerIndex(int(actualIndex)) || actualIndex < 0 || actualIndex >= length {
+			panic(r.newError(r.getRangeError(), "Invalid typed array index"))
+		}
+		dst := r.typedArrayCopy(ta)
+		dst.typedArray.setRaw(dst.offset+int(actualIndex), value)
+		return dst.val
+	}
+	panic(r.NewTypeError("Method TypedArray.prototype.with called on incompatible receiver %s", r.objectproto_toString(FunctionCall{This: call.This})))
+}
+
 func (r *Runtime) typedArray_from(call FunctionCall) Value {
 	c := r.toObject(call.This)
 	var mapFc func(call FunctionCall) Value
@@ -1218,6 +1289,7 @@ func (r *Runtime) allocateTypedArray(newTarget *Object, length int, taCtor typed
 	buf := r._newArrayBuffer(r.getArrayBufferPrototype(), nil)
 	ta := taCtor(buf, 0, length, r.getPrototypeFromCtor(newTarget, nil, proto))
 	if length > 0 {
+		r.trackAlloc(memSizeN(length, uint64(ta.elemSize)))
 		buf.data = allocByteSlice(length * ta.elemSize)
 	}
 	return ta
@@ -1330,6 +1402,7 @@ func (r *Runtime) _newTypedArrayFromTypedArray(src *typedArrayObject, newTarget
 
 	arrayBuffer := r.getArrayBuffer()
 	dst.viewedArrayBuf.prototype = r.getPrototypeFromCtor(r.speciesConstructorObj(src.viewedArrayBuf.val, arrayBuffer), arrayBuffer, r.getArrayBufferPrototype())
+	r.trackAlloc(memSizeN(l, uint64(dst.elemSize)))
 	dst.viewedArrayBuf.data = allocByteSlice(toIntStrict(int64(l) * int64(dst.elemSize)))
 	src.viewedArrayBuf.ensureNotDetached(true)
 	if src.defaultCtor == dst.defaultCtor {
@@ -1543,8 +1616,11 @@ func createTypedArrayProtoTemplate() *objectTemplate {
 	t.putStr("sort", func(r *Runtime) Value { return r.methodProp(r.typedArrayProto_sort, "sort", 1) })
 	t.putStr("subarray", func(r *Runtime) Value { return r.methodProp(r.typedArrayProto_subarray, "subarray", 2) })
 	t.putStr("toLocaleString", func(r *Runtime) Value { return r.methodProp(r.typedArrayProto_toLocaleString, "toLocaleString", 0) })
+	t.putStr("toReversed", func(r *Runtime) Value { return r.methodProp(r.typedArrayProto_toReversed, "toReversed", 0) })
+	t.putStr("toSorted", func(r *Runtime) Value { return r.methodProp(r.typedArrayProto_toSorted, "toSorted", 1) })
 	t.putStr("toString", func(r *Runtime) Value { return valueProp(r.getArrayToString(), true, false, true) })
 	t.putStr("values", func(r *Runtime) Value { return valueProp(r.getTypedArrayValues(), true, false, true) })
+	t.putStr("with", func(r *Runtime) Value { return r.methodProp(r.typedArrayProto_with, "with", 2) })
 
 	t.putSym(SymIterator, func(r *Runtime) Value { return valueProp(r.getTypedArrayValues(), true, false, true) })
 	t.putSym(SymToStringTag, func(r *Runtime) Value {
@@ -324,3 +324,30 @@ func TestTypedArrayDeleteUnconfigurable(t *testing.T) {
 
 	testScript(SCRIPT, _undefined, t)
 }
+
+func TestTypedArrayChangeByCopy(t *testing.T) {
+	const SCRIPT = `
+	var a = new Int16Array([3, -1, 2]);
+	var res = a.toReversed();
+	assert(res instanceof Int16Array, "toReversed type");
+	assert(compareArray(res, [2, -1, 3]), "toReversed");
+	assert(compareArray(a.toSorted(), [-1, 2, 3]), "toSorted");
+	assert(compareArray(a.toSorted(function(x, y) { return y - x; }), [3, 2, -1]), "toSorted with comparator");
+	assert(compareArray(a.with(1, 7.5), [3, 7, 2]), "with");
+	assert(compareArray(a.with(-1, 0), [3, -1, 0]), "with negative index");
+	assert(compareArray(a, [3, -1, 2]), "not modified");
+
+	var sub = new Uint8Array([1, 2, 3, 4]).subarray(1, 3);
+	assert(compareArray(sub.toReversed(), [3, 2]), "subarray toReversed");
+	assert.sameValue(sub.toReversed().buffer.byteLength, 2);
+
+	class MyArray extends Uint8Array {}
+	assert.sameValue(Object.getPrototypeOf(new MyArray(2).toSorted()), Uint8Array.prototype, "no species");
+
+	assert.throws(RangeError, function() { a.with(3, 0); });
+	assert.throws(TypeError, function() { a.toSorted(null); });
+	assert.throws(TypeError, function() { Int8Array.prototype.toReversed.call([]); });
+	assert.sameValue(Int8Array.prototype.toSpliced, undefined);
+	`
+	testScriptWithTestLib(SCRIPT, _undefined, t)
+}
@@ -70,6 +70,9 @@ type Program struct {
 	funcName unistring.String
 	src      *file.File
 	srcMap   []srcMapItem
+
+	// cov is only set for top-level programs compiled with coverage instrumentation
+	cov *coverageMap
 }
 
 type compiler struct {
@@ -84,6 +87,14 @@ type compiler struct {
 	evalVM *vm // VM used to evaluate constant expressions
 	ctxVM  *vm // VM in which an eval() code is compiled
 
+	// debug is set when compiling for a Runtime with an attached Debugger. In this mode all variables are
+	// placed in stash, so that they are accessible by name, and each statement has a source map entry.
+	debug bool
+
+	// cov collects the statements, functions and branches reported by Coverage, it is only set when compiling
+	// with coverage instrumentation.
+	cov *coverageMap
+
 	codeScratchpad []instruction
 }
 
@@ -362,10 +373,11 @@ func (c *compiler) newScope() {
 		strict = c.scope.strict
 	}
 	c.scope = &scope{
-		c:      c,
-		prg:    c.p,
-		outer:  c.scope,
-		strict: strict,
+		c:         c,
+		prg:       c.p,
+		outer:     c.scope,
+		strict:    strict,
+		dynLookup: c.debug,
 	}
 }
 
@@ -861,6 +873,29 @@ func (s *scope) makeNamesMap() map[unistring.String]uint32 {
 	return names
 }
 
+// makeSlotNames returns the names of the bindings in the order of their stash slots. It is used for
+// scopes that don't have a names map so that the stash values can still be labelled (e.g. in heap
+// snapshots). Returns nil if none of the stashed bindings has a name.
+func (s *scope) makeSlotNames() []unistring.String {
+	var names []unistring.String
+	named := false
+	for _, b := range s.bindings {
+		if b.inStash {
+			name := b.name
+			if name == thisBindingName {
+				name = ""
+			} else {
+				named = true
+			}
+			names = append(names, name)
+		}
+	}
+	if !named {
+		return nil
+	}
+	return names
+}
+
 func (s *scope) isDynamic() bool {
 	return s.dynLookup || s.dynamic
 }
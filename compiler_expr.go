@@ -1390,6 +1390,14 @@ func (e *compiledFunctionLiteral) compile() (prg *Program, name unistring.String
 	if name != "" {
 		e.c.p.funcName = name
 	}
+	if e.c.cov != nil {
+		loc := covRange{start: e.offset, end: e.offset + len(e.source)}
+		decl := loc
+		if e.name != nil {
+			decl = nodeRange(e.name)
+		}
+		e.c.cov.addFunction(name.String(), decl, loc)
+	}
 	savedBlock := e.c.block
 	defer func() {
 		e.c.block = savedBlock
@@ -1683,6 +1691,8 @@ func (e *compiledFunctionLiteral) compile() (prg *Program, name unistring.String
 			}
 			if s.isDynamic() {
 				enter1.names = s.makeNamesMap()
+			} else {
+				enter1.slotNames = s.makeSlotNames()
 			}
 			enter = &enter1
 			if enterFunc2Mark != -1 {
@@ -1703,6 +1713,8 @@ func (e *compiledFunctionLiteral) compile() (prg *Program, name unistring.String
 			}
 			if s.isDynamic() {
 				enter1.names = s.makeNamesMap()
+			} else {
+				enter1.slotNames = s.makeSlotNames()
 			}
 			enter = &enter1
 			if enterFunc2Mark != -1 {
@@ -2305,6 +2317,7 @@ func (c *compiler) compileArrowFunctionLiteral(v *ast.ArrowFunctionLiteral) *com
 	case *ast.ExpressionBody:
 		body = []ast.Statement{
 			&ast.ReturnStatement{
+				Return:   b.Expression.Idx0(),
 				Argument: b.Expression,
 			},
 		}
@@ -2602,10 +2615,13 @@ func (e *compiledConditionalExpr) emitGetter(putOnStack bool) {
 }
 
 func (c *compiler) compileConditionalExpression(v *ast.ConditionalExpression) compiledExpr {
+	if c.cov != nil {
+		c.cov.addBranch("cond-expr", nodeRange(v), nodeRange(v.Consequent), nodeRange(v.Alternate))
+	}
 	r := &compiledConditionalExpr{
 		test:       c.compileExpression(v.Test),
-		consequent: c.compileExpression(v.Consequent),
-		alternate:  c.compileExpression(v.Alternate),
+		consequent: c.compileBranchExpression(v.Consequent),
+		alternate:  c.compileBranchExpression(v.Alternate),
 	}
 	r.init(c, v.Idx0())
 	return r
@@ -2799,6 +2815,9 @@ func (e *compiledBinaryExpr) emitGetter(putOnStack bool) {
 }
 
 func (c *compiler) compileBinaryExpression(v *ast.BinaryExpression) compiledExpr {
+	if c.cov != nil && isLogicalOperator(v.Operator) {
+		c.cov.addLogical(v)
+	}
 
 	switch v.Operator {
 	case token.LOGICAL_OR:
@@ -2852,8 +2871,8 @@ func (c *compiler) compilePrivateIn(id *ast.PrivateIdentifier, right ast.Express
 
 func (c *compiler) compileLogicalOr(left, right ast.Expression, idx file.Idx) compiledExpr {
 	r := &compiledLogicalOr{
-		left:  c.compileExpression(left),
-		right: c.compileExpression(right),
+		left:  c.compileBranchExpression(left),
+		right: c.compileBranchExpression(right),
 	}
 	r.init(c, idx)
 	return r
@@ -2861,8 +2880,8 @@ func (c *compiler) compileLogicalOr(left, right ast.Expression, idx file.Idx) co
 
 func (c *compiler) compileCoalesce(left, right ast.Expression, idx file.Idx) compiledExpr {
 	r := &compiledCoalesce{
-		left:  c.compileExpression(left),
-		right: c.compileExpression(right),
+		left:  c.compileBranchExpression(left),
+		right: c.compileBranchExpression(right),
 	}
 	r.init(c, idx)
 	return r
@@ -2870,8 +2889,8 @@ func (c *compiler) compileCoalesce(left, right ast.Expression, idx file.Idx) com
 
 func (c *compiler) compileLogicalAnd(left, right ast.Expression, idx file.Idx) compiledExpr {
 	r := &compiledLogicalAnd{
-		left:  c.compileExpression(left),
-		right: c.compileExpression(right),
+		left:  c.compileBranchExpression(left),
+		right: c.compileBranchExpression(right),
 	}
 	r.init(c, idx)
 	return r
@@ -8,6 +8,17 @@ import (
 )
 
 func (c *compiler) compileStatement(v ast.Statement, needResult bool) {
+	if c.cov != nil {
+		if start := c.coverStatement(v); start != -1 {
+			defer c.endCoverageRegion(start)
+		}
+	} else if c.debug {
+		switch v.(type) {
+		case *ast.BlockStatement, *ast.FunctionDeclaration, *ast.EmptyStatement:
+		default:
+			c.addSrcMap(v)
+		}
+	}
 
 	switch v := v.(type) {
 	case *ast.BlockStatement:
@@ -52,6 +63,8 @@ func (c *compiler) compileStatement(v ast.Statement, needResult bool) {
 	case *ast.WithStatement:
 		c.compileWithStatement(v, needResult)
 	case *ast.DebuggerStatement:
+		c.addSrcMap(v)
+		c.emit(debuggerStmt)
 	default:
 		c.assert(false, int(v.Idx0())-1, "Unknown statement type: %T", v)
 		panic("unreachable")
@@ -98,6 +111,7 @@ func (c *compiler) updateEnterBlock(enter *enterBlock) {
 				stackSize++
 			}
 		}
+		enter.slotNames = scope.makeSlotNames()
 	}
 	enter.stashSize, enter.stackSize = uint32(stashSize), uint32(stackSize)
 }
@@ -171,6 +185,7 @@ func (c *compiler) compileTryStatement(v *ast.TryStatement, needResult bool) {
 			if c.scope.dynLookup || c.scope.bindings[0].inStash {
 				c.p.code[lbl+catchOffset] = &enterCatchBlock{
 					names:     enter.names,
+					slotNames: enter.slotNames,
 					stashSize: enter.stashSize,
 					stackSize: enter.stackSize,
 				}
@@ -659,6 +674,9 @@ func (c *compiler) compileContinue(label *ast.Identifier, idx file.Idx) {
 }
 
 func (c *compiler) compileIfBody(s ast.Statement, needResult bool) {
+	if c.cov != nil {
+		defer c.endCoverageRegion(c.beginCoverageRegion(int(s.Idx0()) - 1))
+	}
 	if !c.scope.strict {
 		if s, ok := s.(*ast.FunctionDeclaration); ok && !s.Function.Async && !s.Function.Generator {
 			c.compileFunction(s)
@@ -678,6 +696,9 @@ func (c *compiler) compileIfBodyDummy(s ast.Statement) {
 }
 
 func (c *compiler) compileIfStatement(v *ast.IfStatement, needResult bool) {
+	if c.cov != nil {
+		c.cov.addIf(v)
+	}
 	test := c.compileExpression(v.Test)
 	if needResult {
 		c.emit(clearResult)
@@ -1017,6 +1038,9 @@ func (c *compiler) compileSwitchStatement(v *ast.SwitchStatement, needResult boo
 		needResult: needResult,
 	}
 
+	if c.cov != nil {
+		c.cov.addSwitch(v)
+	}
 	c.compileExpression(v.Discriminant).emitGetter(true)
 
 	var funcs []*ast.FunctionDeclaration
@@ -1108,7 +1132,13 @@ func (c *compiler) compileSwitchStatement(v *ast.SwitchStatement, needResult boo
 		if s.Test != nil || i != 0 {
 			c.p.code[jumps[i]] = jump(len(c.p.code) - jumps[i])
 		}
-		c.compileStatements(s.Consequent, needResult)
+		if c.cov != nil {
+			start := c.beginCoverageRegion(int(s.Case) - 1)
+			c.compileStatements(s.Consequent, needResult)
+			c.endCoverageRegion(start)
+		} else {
+			c.compileStatements(s.Consequent, needResult)
+		}
 	}
 
 	if jumpNoMatch != -1 {
@@ -0,0 +1,637 @@
+package goja
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"sort"
+	"strconv"
+	"sync"
+	"sync/atomic"
+
+	"github.com/dop251/goja/ast"
+	"github.com/dop251/goja/file"
+	"github.com/dop251/goja/token"
+)
+
+// Coverage collects code coverage (statement, branch and function execution counts) of the Programs run by
+// the Runtimes it has been set for (see Runtime.SetCoverage()) and exports it in the LCOV and Istanbul JSON formats.
+//
+// Only the code compiled with coverage instrumentation is tracked: the scripts run by Runtime.RunString() and
+// Runtime.RunScript() while a Coverage is set, and the Programs compiled by CompileWithCoverage(). Besides, only
+// the Programs that have a name are reported, and only once they have been run by Runtime.RunProgram() or similar.
+// Code evaluated by eval() or created by the Function constructor is not tracked.
+//
+// The execution counts are recorded for each bytecode instruction and are mapped back to the source using the
+// source map of the Program. If the source has a source map of its own (see parser.WithSourceMapLoader()), the
+// reported positions and file names refer to the original source.
+//
+// A Coverage can be shared by multiple Runtimes (including the ones running concurrently), the counts of the scripts
+// with the same name are added up. The methods of Coverage are goroutine-safe.
+type Coverage struct {
+	mu    sync.Mutex
+	progs map[*Program][]uint32
+	files map[string]*coverageFile
+}
+
+// coverageFile is a script tracked by Coverage, it may have been compiled multiple times.
+type coverageFile struct {
+	m     *coverageMap
+	roots []*Program
+}
+
+type covRange struct {
+	start, end int
+}
+
+type covFunction struct {
+	name      string
+	decl, loc covRange
+}
+
+type covBranch struct {
+	typ       string
+	loc       covRange
+	locations []covRange
+	// set for 'if' statements without 'else', the count of the implicit 'else' is derived from the count of
+	// the statement and the count of the consequent
+	implicitElse bool
+}
+
+type covKey struct {
+	typ   string
+	start int
+}
+
+// coverageMap contains the source ranges of the statements, functions and branches of a script. It is filled by
+// the compiler, which also makes sure that the code of each statement and branch starts with a source map entry
+// for the start of the range, so that the execution count of the range is the count of the instruction at that
+// entry.
+type coverageMap struct {
+	src        *file.File
+	statements []covRange
+	functions  []covFunction
+	branches   []covBranch
+
+	// only used while compiling
+	seen map[covKey]struct{}
+	// logical expressions that are part of an enclosing one, their operands are reported as the locations
+	// of the enclosing expression's branch
+	nestedLogical map[*ast.BinaryExpression]struct{}
+}
+
+func nodeRange(n ast.Node) covRange {
+	return covRange{start: int(n.Idx0()) - 1, end: int(n.Idx1()) - 1}
+}
+
+func (m *coverageMap) add(typ string, start int) bool {
+	if start < 0 {
+		return false
+	}
+	k := covKey{typ: typ, start: start}
+	if _, exists := m.seen[k]; exists {
+		return false
+	}
+	if m.seen == nil {
+		m.seen = make(map[covKey]struct{})
+	}
+	m.seen[k] = struct{}{}
+	return true
+}
+
+func (m *coverageMap) addStatement(v ast.Statement) {
+	r := nodeRange(v)
+	if m.add("", r.start) {
+		m.statements = append(m.statements, r)
+	}
+}
+
+func (m *coverageMap) addFunction(name string, decl, loc covRange) {
+	if m.add("function", loc.start) {
+		m.functions = append(m.functions, covFunction{name: name, decl: decl, loc: loc})
+	}
+}
+
+func (m *coverageMap) addBranch(typ string, loc covRange, locations ...covRange) *covBranch {
+	if !m.add(typ, loc.start) {
+		return nil
+	}
+	m.branches = append(m.branches, covBranch{typ: typ, loc: loc, locations: locations})
+	return &m.branches[len(m.branches)-1]
+}
+
+func (m *coverageMap) addIf(v *ast.IfStatement) {
+	if v.Alternate != nil {
+		m.addBranch("if", nodeRange(v), nodeRange(v.Consequent), nodeRange(v.Alternate))
+	} else if b := m.addBranch("if", nodeRange(v), nodeRange(v.Consequent), nodeRange(v)); b != nil {
+		b.implicitElse = true
+	}
+}
+
+func (m *coverageMap) addSwitch(v *ast.SwitchStatement) {
+	locations := make([]covRange, 0, len(v.Body))
+	for _, s := range v.Body {
+		locations = append(locations, nodeRange(s))
+	}
+	m.addBranch("switch", nodeRange(v), locations...)
+}
+
+func isLogicalOperator(op token.Token) bool {
+	switch op {
+	case token.LOGICAL_AND, token.LOGICAL_OR, token.COALESCE:
+		return true
+	}
+	return false
+}
+
+// addLogical adds a branch for a chain of logical operators, each operand that is not a logical expression itself
+// is a location.
+func (m *coverageMap) addLogical(v *ast.BinaryExpression) {
+	if _, nested := m.nestedLogical[v]; nested {
+		return
+	}
+	var locations []covRange
+	var add func(e ast.Expression)
+	add = func(e ast.Expression) {
+		if b, ok := e.(*ast.BinaryExpression); ok && isLogicalOperator(b.Operator) {
+			if m.nestedLogical == nil {
+				m.nestedLogical = make(map[*ast.BinaryExpression]struct{})
+			}
+			m.nestedLogical[b] = struct{}{}
+			add(b.Left)
+			add(b.Right)
+			return
+		}
+		locations = append(locations, nodeRange(e))
+	}
+	add(v.Left)
+	add(v.Right)
+	m.addBranch("binary-expr", nodeRange(v), locations...)
+}
+
+func (m *coverageMap) finish(src *file.File) *coverageMap {
+	m.src = src
+	m.seen = nil
+	m.nestedLogical = nil
+	sort.Slice(m.statements, func(i, j int) bool {
+		return m.statements[i].start < m.statements[j].start
+	})
+	sort.Slice(m.functions, func(i, j int) bool {
+		return m.functions[i].loc.start < m.functions[j].loc.start
+	})
+	sort.SliceStable(m.branches, func(i, j int) bool {
+		return m.branches[i].loc.start < m.branches[j].loc.start
+	})
+	return m
+}
+
+// coveredExpr marks the code of a branch of a conditional or a logical expression as a coverage region.
+type coveredExpr struct {
+	compiledExpr
+	c      *compiler
+	offset int
+}
+
+func (e *coveredExpr) emitGetter(putOnStack bool) {
+	start := e.c.beginCoverageRegion(e.offset)
+	e.compiledExpr.emitGetter(putOnStack)
+	e.c.endCoverageRegion(start)
+}
+
+// constant returns false to prevent the enclosing expression from being folded, the branch would not be
+// reported as executed otherwise.
+func (e *coveredExpr) constant() bool {
+	return false
+}
+
+func (c *compiler) compileBranchExpression(v ast.Expression) compiledExpr {
+	expr := c.compileExpression(v)
+	if c.cov != nil {
+		return &coveredExpr{
+			compiledExpr: expr,
+			c:            c,
+			offset:       int(v.Idx0()) - 1,
+		}
+	}
+	return expr
+}
+
+// coverStatement adds the statement to the coverage map and starts its coverage region. It returns the start of
+// the region or -1 if the statement is not reported.
+func (c *compiler) coverStatement(v ast.Statement) int {
+	switch v.(type) {
+	case *ast.BlockStatement, *ast.FunctionDeclaration, *ast.EmptyStatement:
+		return -1
+	}
+	if v.Idx0() <= 0 {
+		return -1
+	}
+	c.cov.addStatement(v)
+	return c.beginCoverageRegion(int(v.Idx0()) - 1)
+}
+
+// beginCoverageRegion starts a region of code whose execution count is that of its first instruction. The region
+// must be ended by endCoverageRegion() with the returned value.
+func (c *compiler) beginCoverageRegion(offset int) int {
+	c.p.addSrcMap(offset)
+	return len(c.p.code)
+}
+
+// endCoverageRegion makes sure the region started by beginCoverageRegion() is not empty, its count would be
+// the count of the following instruction otherwise.
+func (c *compiler) endCoverageRegion(start int) {
+	if len(c.p.code) == start {
+		c.emit(nop)
+	}
+}
+
+// CompileWithCoverage is like Compile but instruments the code for coverage collection (see Coverage). The Program
+// can be run by any Runtime, the coverage is only collected by the ones that have a Coverage set.
+func CompileWithCoverage(name, src string, strict bool) (*Program, error) {
+	return compile(name, src, strict, true, nil, false, true)
+}
+
+// NewCoverage creates a new Coverage. Use Runtime.SetCoverage() to start collecting.
+func NewCoverage() *Coverage {
+	return &Coverage{}
+}
+
+// SetCoverage makes the Runtime collect the code coverage into c. The scripts compiled by RunString() and
+// RunScript() while a Coverage is set are instrumented for coverage collection. Passing nil stops the collection.
+func (r *Runtime) SetCoverage(c *Coverage) {
+	r.coverage = c
+	r.vm.cov = c
+	r.vm.covPrg = nil
+	r.vm.covCounts = nil
+}
+
+// Coverage returns the Coverage set by SetCoverage(), nil if there is none.
+func (r *Runtime) Coverage() *Coverage {
+	return r.coverage
+}
+
+// forEachNested calls f for each Program directly nested in p. isFunc is false for class field initialisers.
+func (p *Program) forEachNested(f func(prg *Program, isFunc bool)) {
+	for _, ins := range p.code {
+		switch ins := ins.(type) {
+		case newFuncInstruction:
+			f(ins.getPrg(), true)
+		case *newClass:
+			ins.forEachNested(f)
+		case *newDerivedClass:
+			ins.newClass.forEachNested(f)
+		case *newStaticFieldInit:
+			if ins.initFields != nil {
+				f(ins.initFields, false)
+			}
+		}
+	}
+}
+
+func (n *newClass) forEachNested(f func(prg *Program, isFunc bool)) {
+	if n.ctor != nil {
+		f(n.ctor, true)
+	}
+	if n.initFields != nil {
+		f(n.initFields, false)
+	}
+}
+
+func (c *Coverage) addProgram(p *Program) {
+	if p.src == nil || p.src.Name() == "" {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, exists := c.progs[p]; exists {
+		return
+	}
+	if c.progs == nil {
+		c.progs = make(map[*Program][]uint32)
+		c.files = make(map[string]*coverageFile)
+	}
+	c.addCounts(p)
+	name := p.src.Name()
+	f := c.files[name]
+	if f == nil || f.m.src.Source() != p.src.Source() {
+		f = &coverageFile{m: p.cov}
+		c.files[name] = f
+	}
+	f.roots = append(f.roots, p)
+}
+
+func (c *Coverage) addCounts(p *Program) {
+	c.progs[p] = make([]uint32, len(p.code))
+	p.forEachNested(func(prg *Program, _ bool) {
+		c.addCounts(prg)
+	})
+}
+
+func (c *Coverage) counts(p *Program) []uint32 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.progs[p]
+}
+
+// Reset sets all the counts to zero.
+func (c *Coverage) Reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, counts := range c.progs {
+		for i := range counts {
+			atomic.StoreUint32(&counts[i], 0)
+		}
+	}
+}
+
+// coverageHit is called by the vm before executing each instruction while a Coverage is set.
+func (vm *vm) coverageHit(pc int) {
+	if vm.prg != vm.covPrg {
+		vm.covPrg = vm.prg
+		vm.covCounts = vm.cov.counts(vm.prg)
+	}
+	if vm.covCounts != nil {
+		atomic.AddUint32(&vm.covCounts[pc], 1)
+	}
+}
+
+// collectHits adds up the counts of the source map entries of p and its nested Programs. The count of a source
+// position is the count of its first entry in the Program. The first entry of a function is the function's entry
+// point, its count is the number of calls.
+func (c *Coverage) collectHits(p *Program, isFunc bool, hits, fnHits map[int]uint64) {
+	if counts := c.progs[p]; counts != nil {
+		seen := make(map[int]struct{}, len(p.srcMap))
+		for i, item := range p.srcMap {
+			if item.pc >= len(counts) {
+				continue
+			}
+			n := uint64(atomic.LoadUint32(&counts[item.pc]))
+			if i == 0 && isFunc {
+				fnHits[item.srcPos] += n
+				continue
+			}
+			if _, exists := seen[item.srcPos]; exists {
+				continue
+			}
+			seen[item.srcPos] = struct{}{}
+			hits[item.srcPos] += n
+		}
+	}
+	p.forEachNested(func(prg *Program, isFunc bool) {
+		c.collectHits(prg, isFunc, hits, fnHits)
+	})
+}
+
+type covLocation struct {
+	start, end file.Position
+}
+
+type fileCoverage struct {
+	path       string
+	statements []covLocation
+	functions  []covFunctionReport
+	branches   []covBranchReport
+	s, f       []uint64
+	b          [][]uint64
+}
+
+type covFunctionReport struct {
+	name      string
+	decl, loc covLocation
+}
+
+type covBranchReport struct {
+	typ       string
+	loc       covLocation
+	locations []covLocation
+}
+
+func (m *coverageMap) location(r covRange) covLocation {
+	return covLocation{
+		start: m.src.Position(r.start),
+		end:   m.src.Position(r.end),
+	}
+}
+
+// report computes the coverage of all the tracked scripts, sorted by path.
+func (c *Coverage) report() []*fileCoverage {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	files := make(map[string]*fileCoverage)
+	get := func(path string) *fileCoverage {
+		fc := files[path]
+		if fc == nil {
+			fc = &fileCoverage{path: path}
+			files[path] = fc
+		}
+		return fc
+	}
+	for _, cf := range c.files {
+		hits := make(map[int]uint64)
+		fnHits := make(map[int]uint64)
+		for _, p := range cf.roots {
+			c.collectHits(p, false, hits, fnHits)
+		}
+		m := cf.m
+		for _, s := range m.statements {
+			loc := m.location(s)
+			fc := get(loc.start.Filename)
+			fc.statements = append(fc.statements, loc)
+			fc.s = append(fc.s, hits[s.start])
+		}
+		anon := 0
+		for _, f := range m.functions {
+			loc := m.location(f.loc)
+			fc := get(loc.start.Filename)
+			name := f.name
+			if name == "" {
+				name = "(anonymous_" + strconv.Itoa(anon) + ")"
+				anon++
+			}
+			fc.functions = append(fc.functions, covFunctionReport{
+				name: name,
+				decl: m.location(f.decl),
+				loc:  loc,
+			})
+			fc.f = append(fc.f, fnHits[f.loc.start])
+		}
+		for _, b := range m.branches {
+			loc := m.location(b.loc)
+			fc := get(loc.start.Filename)
+			br := covBranchReport{
+				typ: b.typ,
+				loc: loc,
+			}
+			counts := make([]uint64, len(b.locations))
+			for i, l := range b.locations {
+				br.locations = append(br.locations, m.location(l))
+				counts[i] = hits[l.start]
+			}
+			if b.implicitElse {
+				if total := hits[b.loc.start]; total > counts[0] {
+					counts[1] = total - counts[0]
+				} else {
+					counts[1] = 0
+				}
+			}
+			fc.branches = append(fc.branches, br)
+			fc.b = append(fc.b, counts)
+		}
+	}
+	res := make([]*fileCoverage, 0, len(files))
+	for _, fc := range files {
+		res = append(res, fc)
+	}
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].path < res[j].path
+	})
+	return res
+}
+
+// WriteLCOV writes the coverage in the LCOV tracefile format (as produced by geninfo and consumed by genhtml and
+// most CI services). Line coverage is derived from the statements, a line's count is the highest count of the
+// statements starting on it.
+func (c *Coverage) WriteLCOV(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	for _, fc := range c.report() {
+		bw.WriteString("TN:\nSF:")
+		bw.WriteString(fc.path)
+		bw.WriteByte('\n')
+
+		hit := 0
+		for _, f := range fc.functions {
+			bw.WriteString("FN:" + strconv.Itoa(f.loc.start.Line) + "," + f.name + "\n")
+		}
+		for i, f := range fc.functions {
+			bw.WriteString("FNDA:" + strconv.FormatUint(fc.f[i], 10) + "," + f.name + "\n")
+			if fc.f[i] > 0 {
+				hit++
+			}
+		}
+		bw.WriteString("FNF:" + strconv.Itoa(len(fc.functions)) + "\nFNH:" + strconv.Itoa(hit) + "\n")
+
+		total, hit := 0, 0
+		for i, b := range fc.branches {
+			line := strconv.Itoa(b.loc.start.Line)
+			for j, n := range fc.b[i] {
+				bw.WriteString("BRDA:" + line + "," + strconv.Itoa(i) + "," + strconv.Itoa(j) + "," + strconv.FormatUint(n, 10) + "\n")
+				total++
+				if n > 0 {
+					hit++
+				}
+			}
+		}
+		bw.WriteString("BRF:" + strconv.Itoa(total) + "\nBRH:" + strconv.Itoa(hit) + "\n")
+
+		lines := make(map[int]uint64)
+		for i, s := range fc.statements {
+			if n, exists := lines[s.start.Line]; !exists || fc.s[i] > n {
+				lines[s.start.Line] = fc.s[i]
+			}
+		}
+		nums := make([]int, 0, len(lines))
+		for l := range lines {
+			nums = append(nums, l)
+		}
+		sort.Ints(nums)
+		hit = 0
+		for _, l := range nums {
+			bw.WriteString("DA:" + strconv.Itoa(l) + "," + strconv.FormatUint(lines[l], 10) + "\n")
+			if lines[l] > 0 {
+				hit++
+			}
+		}
+		bw.WriteString("LF:" + strconv.Itoa(len(nums)) + "\nLH:" + strconv.Itoa(hit) + "\nend_of_record\n")
+	}
+	return bw.Flush()
+}
+
+type istanbulPosition struct {
+	Line   int `json:"line"`
+	Column int `json:"column"`
+}
+
+type istanbulLocation struct {
+	Start istanbulPosition `json:"start"`
+	End   istanbulPosition `json:"end"`
+}
+
+type istanbulFunction struct {
+	Name string           `json:"name"`
+	Decl istanbulLocation `json:"decl"`
+	Loc  istanbulLocation `json:"loc"`
+	Line int              `json:"line"`
+}
+
+type istanbulBranch struct {
+	Loc       istanbulLocation   `json:"loc"`
+	Type      string             `json:"type"`
+	Locations []istanbulLocation `json:"locations"`
+	Line      int                `json:"line"`
+}
+
+type istanbulFileCoverage struct {
+	Path         string                      `json:"path"`
+	StatementMap map[string]istanbulLocation `json:"statementMap"`
+	FnMap        map[string]istanbulFunction `json:"fnMap"`
+	BranchMap    map[string]istanbulBranch   `json:"branchMap"`
+	S            map[string]uint64           `json:"s"`
+	F            map[string]uint64           `json:"f"`
+	B            map[string][]uint64         `json:"b"`
+}
+
+// Istanbul columns are 0-based, the end column is exclusive.
+func (l covLocation) istanbul() istanbulLocation {
+	return istanbulLocation{
+		Start: istanbulPosition{Line: l.start.Line, Column: l.start.Column - 1},
+		End:   istanbulPosition{Line: l.end.Line, Column: l.end.Column - 1},
+	}
+}
+
+// WriteIstanbul writes the coverage in the Istanbul JSON format (coverage-final.json), which can be turned into
+// reports by nyc or istanbul-reports and merged with the coverage of other JavaScript code.
+func (c *Coverage) WriteIstanbul(w io.Writer) error {
+	res := make(map[string]*istanbulFileCoverage)
+	for _, fc := range c.report() {
+		ic := &istanbulFileCoverage{
+			Path:         fc.path,
+			StatementMap: make(map[string]istanbulLocation, len(fc.statements)),
+			FnMap:        make(map[string]istanbulFunction, len(fc.functions)),
+			BranchMap:    make(map[string]istanbulBranch, len(fc.branches)),
+			S:            make(map[string]uint64, len(fc.statements)),
+			F:            make(map[string]uint64, len(fc.functions)),
+			B:            make(map[string][]uint64, len(fc.branches)),
+		}
+		for i, s := range fc.statements {
+			k := strconv.Itoa(i)
+			ic.StatementMap[k] = s.istanbul()
+			ic.S[k] = fc.s[i]
+		}
+		for i, f := range fc.functions {
+			k := strconv.Itoa(i)
+			ic.FnMap[k] = istanbulFunction{
+				Name: f.name,
+				Decl: f.decl.istanbul(),
+				Loc:  f.loc.istanbul(),
+				Line: f.loc.start.Line,
+			}
+			ic.F[k] = fc.f[i]
+		}
+		for i, b := range fc.branches {
+			k := strconv.Itoa(i)
+			locations := make([]istanbulLocation, len(b.locations))
+			for j, l := range b.locations {
+				locations[j] = l.istanbul()
+			}
+			ic.BranchMap[k] = istanbulBranch{
+				Loc:       b.loc.istanbul(),
+				Type:      b.typ,
+				Locations: locations,
+				Line:      b.loc.start.Line,
+			}
+			ic.B[k] = fc.b[i]
+		}
+		res[fc.path] = ic
+	}
+	return json.NewEncoder(w).Encode(res)
+}
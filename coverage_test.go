@@ -0,0 +1,223 @@
+package goja
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+type testIstanbulCoverage map[string]*istanbulFileCoverage
+
+func istanbulCoverage(t *testing.T, c *Coverage) testIstanbulCoverage {
+	var buf bytes.Buffer
+	if err := c.WriteIstanbul(&buf); err != nil {
+		t.Fatal(err)
+	}
+	var res testIstanbulCoverage
+	if err := json.Unmarshal(buf.Bytes(), &res); err != nil {
+		t.Fatal(err)
+	}
+	return res
+}
+
+// statementCount returns the count of the statement starting at the line and column (both 1-based).
+func (fc *istanbulFileCoverage) statementCount(t *testing.T, line, col int) uint64 {
+	for k, loc := range fc.StatementMap {
+		if loc.Start.Line == line && loc.Start.Column == col-1 {
+			return fc.S[k]
+		}
+	}
+	t.Fatalf("No statement at %d:%d", line, col)
+	return 0
+}
+
+func (fc *istanbulFileCoverage) branchCounts(t *testing.T, typ string, line int) []uint64 {
+	for k, b := range fc.BranchMap {
+		if b.Type == typ && b.Line == line {
+			return fc.B[k]
+		}
+	}
+	t.Fatalf("No %s branch on line %d", typ, line)
+	return nil
+}
+
+func (fc *istanbulFileCoverage) functionCount(t *testing.T, name string) uint64 {
+	for k, f := range fc.FnMap {
+		if f.Name == name {
+			return fc.F[k]
+		}
+	}
+	t.Fatalf("No function %s", name)
+	return 0
+}
+
+func TestCoverage(t *testing.T) {
+	const SCRIPT = `function classify(n) {
+	if (n < 0) {
+		return "negative";
+	} else if (n === 0) {
+		return "zero";
+	}
+	var big = n > 100 ? "big" : "small";
+	switch (n % 3) {
+	case 0:
+		big += "!";
+	case 1:
+		break;
+	default:
+	}
+	return (n > 1000 && "huge") || big;
+}
+function unused() {
+	return 1;
+}
+[5, 0, 3, 300].map(n => classify(n));
+if (classify(1)) {}
+`
+	r := New()
+	c := NewCoverage()
+	r.SetCoverage(c)
+	_, err := r.RunScript("classify.js", SCRIPT)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = r.RunString("classify(-1)")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	res := istanbulCoverage(t, c)
+	if len(res) != 1 {
+		t.Fatalf("Unexpected files: %v", res)
+	}
+	fc := res["classify.js"]
+	if fc == nil || fc.Path != "classify.js" {
+		t.Fatal("No coverage for classify.js")
+	}
+
+	for _, s := range []struct {
+		line, col int
+		count     uint64
+	}{
+		{2, 2, 6},  // if (n < 0)
+		{3, 3, 1},  // return "negative"
+		{4, 9, 5},  // if (n === 0)
+		{5, 3, 1},  // return "zero"
+		{7, 2, 4},  // var big
+		{10, 3, 2}, // big += "!"
+		{12, 3, 3}, // break
+		{18, 2, 0}, // return 1
+		{20, 1, 1}, // [].map()
+		{20, 25, 4},
+	} {
+		if n := fc.statementCount(t, s.line, s.col); n != s.count {
+			t.Errorf("Statement at %d:%d: %d, expected %d", s.line, s.col, n, s.count)
+		}
+	}
+
+	for _, b := range []struct {
+		typ    string
+		line   int
+		counts []uint64
+	}{
+		{"if", 2, []uint64{1, 5}},
+		{"if", 4, []uint64{1, 4}},
+		{"if", 21, []uint64{1, 0}},
+		{"cond-expr", 7, []uint64{1, 3}},
+		{"switch", 8, []uint64{2, 3, 1}},
+		{"binary-expr", 15, []uint64{4, 0, 4}},
+	} {
+		counts := fc.branchCounts(t, b.typ, b.line)
+		if !compareCounts(counts, b.counts) {
+			t.Errorf("%s branch on line %d: %v, expected %v", b.typ, b.line, counts, b.counts)
+		}
+	}
+
+	if n := fc.functionCount(t, "classify"); n != 6 {
+		t.Errorf("classify: %d", n)
+	}
+	if n := fc.functionCount(t, "unused"); n != 0 {
+		t.Errorf("unused: %d", n)
+	}
+	if n := fc.functionCount(t, "(anonymous_0)"); n != 4 {
+		t.Errorf("Arrow function: %d", n)
+	}
+
+	var buf bytes.Buffer
+	if err := c.WriteLCOV(&buf); err != nil {
+		t.Fatal(err)
+	}
+	lcov := buf.String()
+	for _, line := range []string{
+		"SF:classify.js\n",
+		"FN:1,classify\n",
+		"FNDA:6,classify\n",
+		"FNDA:0,unused\n",
+		"FNF:3\nFNH:2\n",
+		"BRDA:8,3,2,1\n",
+		"BRF:14\nBRH:12\n",
+		"DA:18,0\n",
+		"DA:20,4\n",
+		"end_of_record\n",
+	} {
+		if !strings.Contains(lcov, line) {
+			t.Errorf("LCOV output does not contain %q:\n%s", line, lcov)
+		}
+	}
+}
+
+func compareCounts(a, b []uint64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestCoverageShared(t *testing.T) {
+	prg, err := CompileWithCoverage("lib.js", `
+	function check(v) {
+		return v ? "yes" : "no";
+	}
+	check(true);
+	`, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c := NewCoverage()
+	for i := 0; i < 3; i++ {
+		r := New()
+		r.SetCoverage(c)
+		if _, err := r.RunProgram(prg); err != nil {
+			t.Fatal(err)
+		}
+		// also compiled separately, the counts are added up
+		if _, err := r.RunScript("lib.js", prg.src.Source()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// no Coverage set
+	if _, err := New().RunProgram(prg); err != nil {
+		t.Fatal(err)
+	}
+
+	fc := istanbulCoverage(t, c)["lib.js"]
+	if n := fc.functionCount(t, "check"); n != 6 {
+		t.Fatalf("check: %d", n)
+	}
+	if b := fc.branchCounts(t, "cond-expr", 3); !compareCounts(b, []uint64{6, 0}) {
+		t.Fatalf("Unexpected branch counts: %v", b)
+	}
+
+	c.Reset()
+	fc = istanbulCoverage(t, c)["lib.js"]
+	if n := fc.functionCount(t, "check"); n != 0 {
+		t.Fatalf("check after Reset(): %d", n)
+	}
+}
@@ -7,14 +7,11 @@ import (
 )
 
 const (
-	dateTimeLayout       = "Mon Jan 02 2006 15:04:05 GMT-0700 (MST)"
-	utcDateTimeLayout    = "Mon, 02 Jan 2006 15:04:05 GMT"
-	isoDateTimeLayout    = "2006-01-02T15:04:05.000Z"
-	dateLayout           = "Mon Jan 02 2006"
-	timeLayout           = "15:04:05 GMT-0700 (MST)"
-	datetimeLayout_en_GB = "01/02/2006, 15:04:05"
-	dateLayout_en_GB     = "01/02/2006"
-	timeLayout_en_GB     = "15:04:05"
+	dateTimeLayout    = "Mon Jan 02 2006 15:04:05 GMT-0700 (MST)"
+	utcDateTimeLayout = "Mon, 02 Jan 2006 15:04:05 GMT"
+	isoDateTimeLayout = "2006-01-02T15:04:05.000Z"
+	dateLayout        = "Mon Jan 02 2006"
+	timeLayout        = "15:04:05 GMT-0700 (MST)"
 
 	maxTime   = 8.64e15
 	timeUnset = math.MinInt64
@@ -0,0 +1,823 @@
+package goja
+
+import (
+	"errors"
+	"sort"
+	"sync"
+	"sync/atomic"
+
+	"github.com/dop251/goja/file"
+	"github.com/dop251/goja/unistring"
+)
+
+// PauseReason describes why the execution has been paused.
+type PauseReason int
+
+const (
+	// PauseBreakpoint means one or more breakpoints have been hit.
+	PauseBreakpoint PauseReason = iota + 1
+	// PauseStep means a step requested by the previous DebugCommand has completed.
+	PauseStep
+	// PauseException means an exception has been thrown (see Debugger.SetPauseOnExceptions()).
+	PauseException
+	// PauseDebuggerStatement means a 'debugger' statement has been executed.
+	PauseDebuggerStatement
+	// PauseRequested means the pause has been requested by Debugger.Pause().
+	PauseRequested
+)
+
+func (r PauseReason) String() string {
+	switch r {
+	case PauseBreakpoint:
+		return "breakpoint"
+	case PauseStep:
+		return "step"
+	case PauseException:
+		return "exception"
+	case PauseDebuggerStatement:
+		return "debugger statement"
+	case PauseRequested:
+		return "pause"
+	}
+	return "unknown"
+}
+
+// DebugCommand is returned by a DebugHandler and determines how the execution is resumed.
+type DebugCommand int
+
+const (
+	// DebugContinue resumes the execution until the next breakpoint, exception or pause request.
+	DebugContinue DebugCommand = iota
+	// DebugStepIn pauses at the next line, entering function calls.
+	DebugStepIn
+	// DebugStepOver pauses at the next line of the current function (or of its caller if the function returns).
+	DebugStepOver
+	// DebugStepOut pauses once the current function has returned.
+	DebugStepOut
+)
+
+// ExceptionPauseMode determines whether the execution is paused when an exception is thrown.
+type ExceptionPauseMode int
+
+const (
+	// PauseOnNoExceptions disables pausing on exceptions. This is the default.
+	PauseOnNoExceptions ExceptionPauseMode = iota
+	// PauseOnUncaughtExceptions pauses on exceptions that are not caught by a JavaScript 'catch' block.
+	PauseOnUncaughtExceptions
+	// PauseOnAllExceptions pauses on every thrown exception.
+	PauseOnAllExceptions
+)
+
+// DebugHandler is called when the execution is paused. It is called synchronously in the goroutine running
+// the script, the script remains paused until the handler returns. The DebugPause and everything obtained from it
+// are only valid until then.
+type DebugHandler func(p *DebugPause) DebugCommand
+
+// DebugScriptHandler is called when a Program is run for the first time while the Debugger is attached. Like
+// DebugHandler, it is called in the goroutine running the script.
+type DebugScriptHandler func(src *file.File)
+
+// Debugger allows setting breakpoints, stepping through the code and inspecting the state of a paused Runtime.
+// It is created by Runtime.AttachDebugger().
+//
+// Local variables of functions are only visible if the code has been compiled while the debugger was attached
+// (i.e. by Runtime.RunString() or Runtime.RunScript()). Programs compiled with Compile() keep the variables that are
+// not captured by closures on the stack where they are inaccessible by name.
+//
+// Unlike the Runtime, the methods of Debugger (but not of DebugPause) are goroutine-safe, so breakpoints can be
+// modified and a pause can be requested while a script is running.
+type Debugger struct {
+	r       *Runtime
+	handler DebugHandler
+
+	mu                sync.Mutex
+	breakpoints       []*Breakpoint
+	lastID            int
+	pauseOnExceptions ExceptionPauseMode
+	version           uint32
+	pauseRequested    uint32
+	scriptHandler     DebugScriptHandler
+	loaded            []*Program
+
+	// the fields below are only accessed from the goroutine running the script
+	cacheVersion uint32
+	progs        map[*Program]*debugProgInfo
+	lastPrg      *Program
+	lastInfo     *debugProgInfo
+
+	// non-zero while the handler is running or a breakpoint condition is evaluated
+	suspended int
+
+	step      DebugCommand
+	stepDepth int
+	stepPrg   *Program
+	stepLine  int
+
+	// the caller's instruction following the call, set when DebugStepOut begins
+	stepRetPrg *Program
+	stepRetPC  int
+
+	lastException *Exception
+
+	scripts map[*file.File]struct{}
+}
+
+// Breakpoint is a line breakpoint set by Debugger.SetBreakpoint().
+type Breakpoint struct {
+	id        int
+	filename  string
+	line, col int
+	condition string
+	hits      int64
+}
+
+type debugProgInfo struct {
+	// line number at each pc where a source position starts, 0 elsewhere
+	lines []int
+	bps   map[int][]*Breakpoint
+}
+
+// DebugPause represents a paused execution. It is passed to the DebugHandler and is only valid until
+// the handler returns.
+type DebugPause struct {
+	d           *Debugger
+	vm          *vm
+	reason      PauseReason
+	breakpoints []*Breakpoint
+	exception   *Exception
+	frames      []*DebugFrame
+}
+
+// DebugFrame is a call stack frame of a paused execution. The methods of the embedded StackFrame can be used
+// to obtain the function name and the source position.
+type DebugFrame struct {
+	StackFrame
+	p   *DebugPause
+	ctx context
+}
+
+// DebugScopeType is the type of DebugScope.
+type DebugScopeType int
+
+const (
+	// DebugScopeLocal contains the variables and the arguments of the frame's function.
+	DebugScopeLocal DebugScopeType = iota
+	// DebugScopeBlock contains the lexical declarations (let, const, class) of a block.
+	DebugScopeBlock
+	// DebugScopeClosure contains the variables of an enclosing function or block.
+	DebugScopeClosure
+	// DebugScopeWith is the object of a 'with' statement.
+	DebugScopeWith
+	// DebugScopeScript contains the top-level lexical declarations (let, const, class) of the scripts.
+	DebugScopeScript
+	// DebugScopeGlobal is the global object.
+	DebugScopeGlobal
+)
+
+func (t DebugScopeType) String() string {
+	switch t {
+	case DebugScopeLocal:
+		return "local"
+	case DebugScopeBlock:
+		return "block"
+	case DebugScopeClosure:
+		return "closure"
+	case DebugScopeWith:
+		return "with"
+	case DebugScopeScript:
+		return "script"
+	case DebugScopeGlobal:
+		return "global"
+	}
+	return "unknown"
+}
+
+// DebugScope is a scope (environment record) of a DebugFrame.
+type DebugScope struct {
+	typ DebugScopeType
+	s   *stash
+	obj *Object
+}
+
+var (
+	errPauseEnded  = errors.New("the execution is no longer paused")
+	errNativeFrame = errors.New("cannot evaluate in a native frame")
+)
+
+type debugClosure interface {
+	closureStash() *stash
+}
+
+// AttachDebugger attaches a new Debugger to the Runtime replacing the current one (if any). The handler is called
+// every time the execution is paused.
+//
+// Only the code compiled by RunString() and RunScript() (as well as eval() and the Function constructor) after
+// the debugger has been attached gets the debug instrumentation which makes all local variables accessible by name.
+// Breakpoints and stepping also work for Programs created by Compile(), but see the Debugger type for the
+// limitations.
+func (r *Runtime) AttachDebugger(handler DebugHandler) *Debugger {
+	d := &Debugger{
+		r:       r,
+		handler: handler,
+	}
+	r.debugger = d
+	r.vm.dbg = d
+	return d
+}
+
+// DetachDebugger detaches the current Debugger (if any).
+func (r *Runtime) DetachDebugger() {
+	r.debugger = nil
+	r.vm.dbg = nil
+}
+
+// Debugger returns the currently attached Debugger or nil if there is none.
+func (r *Runtime) Debugger() *Debugger {
+	return r.debugger
+}
+
+// SetBreakpoint sets a breakpoint on the specified line of the source file. If column is greater than 0, the
+// breakpoint is set on the first position on the line starting at or after that column. Source maps are taken into
+// account, i.e. filename and line refer to the original source if the code has a source map.
+//
+// If condition is not empty, it is evaluated in the paused frame every time the breakpoint is reached and
+// the execution is only paused if the result is truthy.
+func (d *Debugger) SetBreakpoint(filename string, line, column int, condition string) *Breakpoint {
+	d.mu.Lock()
+	d.lastID++
+	b := &Breakpoint{
+		id:        d.lastID,
+		filename:  filename,
+		line:      line,
+		col:       column,
+		condition: condition,
+	}
+	d.breakpoints = append(d.breakpoints, b)
+	atomic.AddUint32(&d.version, 1)
+	d.mu.Unlock()
+	return b
+}
+
+// RemoveBreakpoint removes the breakpoint. It returns false if the breakpoint has already been removed.
+func (d *Debugger) RemoveBreakpoint(b *Breakpoint) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for i, b1 := range d.breakpoints {
+		if b1 == b {
+			copy(d.breakpoints[i:], d.breakpoints[i+1:])
+			d.breakpoints[len(d.breakpoints)-1] = nil
+			d.breakpoints = d.breakpoints[:len(d.breakpoints)-1]
+			atomic.AddUint32(&d.version, 1)
+			return true
+		}
+	}
+	return false
+}
+
+// ClearBreakpoints removes all breakpoints.
+func (d *Debugger) ClearBreakpoints() {
+	d.mu.Lock()
+	d.breakpoints = nil
+	atomic.AddUint32(&d.version, 1)
+	d.mu.Unlock()
+}
+
+// Breakpoints returns the list of breakpoints in the order they were set.
+func (d *Debugger) Breakpoints() []*Breakpoint {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return append([]*Breakpoint(nil), d.breakpoints...)
+}
+
+// SetPauseOnExceptions sets whether the execution should be paused when an exception is thrown.
+func (d *Debugger) SetPauseOnExceptions(mode ExceptionPauseMode) {
+	d.mu.Lock()
+	d.pauseOnExceptions = mode
+	d.mu.Unlock()
+}
+
+// Pause requests the execution to be paused at the next line. If no script is running, the pause happens
+// as soon as one starts.
+func (d *Debugger) Pause() {
+	atomic.StoreUint32(&d.pauseRequested, 1)
+}
+
+// SetScriptHandler sets a handler that is notified about the scripts being run. Scripts that have been run before
+// the handler was set are not reported.
+func (d *Debugger) SetScriptHandler(handler DebugScriptHandler) {
+	d.mu.Lock()
+	d.scriptHandler = handler
+	d.mu.Unlock()
+}
+
+// BreakpointLocation resolves the breakpoint against the scripts that have been run while the Debugger was attached
+// and returns the position the execution will be paused at. loaded is false if none of these scripts contains code
+// from the breakpoint's file. found is false if there is no code on the breakpoint's line (e.g. the line is blank,
+// contains only a comment or is past the end of the file), such a breakpoint is never hit.
+func (d *Debugger) BreakpointLocation(b *Breakpoint) (pos file.Position, loaded, found bool) {
+	d.mu.Lock()
+	progs := d.loaded
+	d.mu.Unlock()
+	var visit func(prg *Program, isFunc bool)
+	visit = func(prg *Program, isFunc bool) {
+		if prg.src == nil {
+			return
+		}
+		for _, item := range prg.srcMap {
+			if item.pc >= len(prg.code) {
+				continue
+			}
+			p := prg.src.Position(item.srcPos)
+			if p.Filename != b.filename {
+				continue
+			}
+			loaded = true
+			if p.Line == b.line && (b.col <= 0 || p.Column >= b.col) {
+				if !found || p.Column < pos.Column {
+					pos = p
+				}
+				found = true
+			}
+		}
+		prg.forEachNested(visit)
+	}
+	for _, prg := range progs {
+		visit(prg, false)
+	}
+	return
+}
+
+// ID returns the unique (within the Debugger) identifier of the breakpoint.
+func (b *Breakpoint) ID() int {
+	return b.id
+}
+
+// Filename returns the name of the source file the breakpoint has been set in.
+func (b *Breakpoint) Filename() string {
+	return b.filename
+}
+
+// Line returns the line number of the breakpoint.
+func (b *Breakpoint) Line() int {
+	return b.line
+}
+
+// Column returns the column number of the breakpoint or 0 if it's set on the whole line.
+func (b *Breakpoint) Column() int {
+	return b.col
+}
+
+// Condition returns the breakpoint condition or an empty string if it's unconditional.
+func (b *Breakpoint) Condition() string {
+	return b.condition
+}
+
+// HitCount returns the number of times the execution has been paused on this breakpoint.
+func (b *Breakpoint) HitCount() int {
+	return int(atomic.LoadInt64(&b.hits))
+}
+
+// Position returns the source position the breakpoint has been set at.
+func (b *Breakpoint) Position() file.Position {
+	return file.Position{
+		Filename: b.filename,
+		Line:     b.line,
+		Column:   b.col,
+	}
+}
+
+func (d *Debugger) progInfo(prg *Program) *debugProgInfo {
+	if v := atomic.LoadUint32(&d.version); v != d.cacheVersion {
+		d.progs = nil
+		d.lastPrg = nil
+		d.cacheVersion = v
+	}
+	if prg == d.lastPrg {
+		return d.lastInfo
+	}
+	info := d.progs[prg]
+	if info == nil {
+		info = d.buildProgInfo(prg)
+		if d.progs == nil {
+			d.progs = make(map[*Program]*debugProgInfo)
+		}
+		d.progs[prg] = info
+	}
+	d.lastPrg, d.lastInfo = prg, info
+	return info
+}
+
+func (d *Debugger) buildProgInfo(prg *Program) *debugProgInfo {
+	info := &debugProgInfo{
+		lines: make([]int, len(prg.code)),
+	}
+	if prg.src == nil {
+		return info
+	}
+	d.mu.Lock()
+	bps := d.breakpoints
+	d.mu.Unlock()
+	var resolved []bool
+	if len(bps) > 0 {
+		resolved = make([]bool, len(bps))
+	}
+	for _, item := range prg.srcMap {
+		if item.pc >= len(prg.code) {
+			continue
+		}
+		pos := prg.src.Position(item.srcPos)
+		if pos.Line <= 0 {
+			continue
+		}
+		info.lines[item.pc] = pos.Line
+		for i, b := range bps {
+			if !resolved[i] && b.line == pos.Line && (b.col <= 0 || pos.Column >= b.col) && b.filename == pos.Filename {
+				resolved[i] = true
+				if info.bps == nil {
+					info.bps = make(map[int][]*Breakpoint)
+				}
+				info.bps[item.pc] = append(info.bps[item.pc], b)
+			}
+		}
+	}
+	return info
+}
+
+func (d *Debugger) lineAt(prg *Program, pc int) int {
+	if prg == nil {
+		return 0
+	}
+	info := d.progInfo(prg)
+	if pc >= 0 && pc < len(info.lines) {
+		if l := info.lines[pc]; l > 0 {
+			return l
+		}
+	}
+	if prg.src == nil {
+		return 0
+	}
+	return prg.src.Position(prg.sourceOffset(pc)).Line
+}
+
+// onRunProgram is called by Runtime.RunProgram() before running a Program while the debugger is attached. It records
+// the scripts seen for the first time and notifies the DebugScriptHandler about them.
+func (d *Debugger) onRunProgram(p *Program) {
+	if _, exists := d.scripts[p.src]; exists {
+		return
+	}
+	if d.scripts == nil {
+		d.scripts = make(map[*file.File]struct{})
+	}
+	d.scripts[p.src] = struct{}{}
+	d.mu.Lock()
+	if p.src != nil {
+		d.loaded = append(d.loaded, p)
+	}
+	h := d.scriptHandler
+	d.mu.Unlock()
+	if h != nil {
+		h(p.src)
+	}
+}
+
+// onInstruction is called by the vm before executing each instruction while the debugger is attached.
+func (d *Debugger) onInstruction(vm *vm) {
+	if d.suspended > 0 {
+		return
+	}
+	if d.step == DebugStepOut && vm.prg == d.stepRetPrg && vm.pc == d.stepRetPC && len(vm.callStack) < d.stepDepth {
+		// the rest of the caller's statement may not have a source map entry of its own
+		d.pause(vm, PauseStep, nil, nil)
+		return
+	}
+	info := d.progInfo(vm.prg)
+	line := info.lines[vm.pc]
+	if line == 0 {
+		return
+	}
+
+	if bps := info.bps[vm.pc]; bps != nil {
+		var hit []*Breakpoint
+		for _, b := range bps {
+			if b.condition != "" && !d.checkCondition(vm, b.condition) {
+				continue
+			}
+			atomic.AddInt64(&b.hits, 1)
+			hit = append(hit, b)
+		}
+		if hit != nil {
+			d.pause(vm, PauseBreakpoint, hit, nil)
+			return
+		}
+	}
+
+	if atomic.CompareAndSwapUint32(&d.pauseRequested, 1, 0) {
+		d.pause(vm, PauseRequested, nil, nil)
+		return
+	}
+
+	if d.step != DebugContinue {
+		depth := len(vm.callStack)
+		var stop bool
+		switch d.step {
+		case DebugStepIn:
+			stop = depth != d.stepDepth || vm.prg != d.stepPrg || line != d.stepLine
+		case DebugStepOver:
+			stop = depth < d.stepDepth || depth == d.stepDepth && (vm.prg != d.stepPrg || line != d.stepLine)
+		case DebugStepOut:
+			stop = depth < d.stepDepth
+		}
+		if stop && !d.isFuncEntry(vm) {
+			d.pause(vm, PauseStep, nil, nil)
+		}
+	}
+}
+
+// isFuncEntry returns true if the vm is at the function's preamble which is mapped to the position of
+// the function declaration rather than to the first statement of the body. Steps do not stop there.
+func (d *Debugger) isFuncEntry(vm *vm) bool {
+	if vm.pc != 0 || len(vm.prg.srcMap) == 1 {
+		return false
+	}
+	switch vm.prg.code[0].(type) {
+	case *enterFunc, *enterFunc1, *enterFuncStashless:
+		return true
+	}
+	return false
+}
+
+// onException is called by the vm when an exception is thrown, before the stack is unwound.
+func (d *Debugger) onException(vm *vm, ex *Exception) {
+	if d.suspended > 0 || ex == d.lastException {
+		return
+	}
+	d.mu.Lock()
+	mode := d.pauseOnExceptions
+	d.mu.Unlock()
+	switch mode {
+	case PauseOnNoExceptions:
+		return
+	case PauseOnUncaughtExceptions:
+		for i := range vm.tryStack {
+			if vm.tryStack[i].catchPos >= 0 {
+				return
+			}
+		}
+	}
+	d.lastException = ex
+	d.pause(vm, PauseException, nil, ex)
+}
+
+func (d *Debugger) onDebuggerStatement(vm *vm) {
+	if d.suspended > 0 {
+		return
+	}
+	d.pause(vm, PauseDebuggerStatement, nil, nil)
+}
+
+func (d *Debugger) checkCondition(vm *vm, condition string) bool {
+	d.suspended++
+	defer func() {
+		d.suspended--
+	}()
+	f := &DebugFrame{}
+	vm.saveCtx(&f.ctx)
+	v, err := d.eval(f, condition)
+	return err == nil && v.ToBoolean()
+}
+
+func (d *Debugger) pause(vm *vm, reason PauseReason, bps []*Breakpoint, ex *Exception) {
+	p := &DebugPause{
+		d:           d,
+		vm:          vm,
+		reason:      reason,
+		breakpoints: bps,
+		exception:   ex,
+	}
+	d.suspended++
+	cmd := func() DebugCommand {
+		defer func() {
+			d.suspended--
+			p.vm = nil
+		}()
+		return d.handler(p)
+	}()
+	atomic.StoreUint32(&d.pauseRequested, 0)
+	d.step = cmd
+	if cmd != DebugContinue {
+		d.stepDepth = len(vm.callStack)
+		d.stepPrg = vm.prg
+		d.stepLine = d.lineAt(vm.prg, vm.pc)
+	}
+	d.stepRetPrg = nil
+	if cmd == DebugStepOut {
+		// if the function was called from native code, this is where the native call returns
+		for i := len(vm.callStack) - 1; i >= 0; i-- {
+			if ctx := &vm.callStack[i]; ctx.prg != nil {
+				d.stepRetPrg, d.stepRetPC = ctx.prg, ctx.pc+1
+				break
+			}
+		}
+	}
+}
+
+func (d *Debugger) eval(f *DebugFrame, src string) (ret Value, err error) {
+	r := d.r
+	vm := r.vm
+	defer func() {
+		if x := recover(); x != nil {
+			if ex := asUncatchableException(x); ex != nil {
+				err = ex
+			} else {
+				panic(x)
+			}
+		}
+	}()
+	ex := vm.try(func() {
+		vm.pushCtx()
+		defer vm.popCtx()
+		vm.prg, vm.stash, vm.privEnv, vm.newTarget, vm.pc, vm.sb, vm.args =
+			f.ctx.prg, f.ctx.stash, f.ctx.privEnv, f.ctx.newTarget, f.ctx.pc, f.ctx.sb, f.ctx.args
+		ret = r.eval(newStringValue(src), true, false)
+	})
+	if ex != nil {
+		err = ex
+	}
+	return
+}
+
+// Reason returns the reason of the pause.
+func (p *DebugPause) Reason() PauseReason {
+	return p.reason
+}
+
+// Breakpoints returns the breakpoints that have been hit if the reason is PauseBreakpoint.
+func (p *DebugPause) Breakpoints() []*Breakpoint {
+	return p.breakpoints
+}
+
+// Exception returns the thrown exception if the reason is PauseException.
+func (p *DebugPause) Exception() *Exception {
+	return p.exception
+}
+
+// Debugger returns the Debugger that has paused the execution.
+func (p *DebugPause) Debugger() *Debugger {
+	return p.d
+}
+
+// CallStack returns the call stack, the innermost frame first. Frames of native (Go) functions are included,
+// their Program is nil.
+func (p *DebugPause) CallStack() []*DebugFrame {
+	if p.frames == nil && p.vm != nil {
+		vm := p.vm
+		add := func(ctx *context) {
+			if ctx.prg != nil || ctx.sb > 0 {
+				f := &DebugFrame{
+					p:   p,
+					ctx: *ctx,
+				}
+				f.prg, f.pc = ctx.prg, ctx.pc
+				if ctx.prg != nil {
+					f.funcName = ctx.prg.funcName
+				} else {
+					f.funcName = getFuncName(vm.stack, ctx.sb)
+				}
+				p.frames = append(p.frames, f)
+			}
+		}
+		var ctx context
+		vm.saveCtx(&ctx)
+		add(&ctx)
+		for i := len(vm.callStack) - 1; i >= 0; i-- {
+			add(&vm.callStack[i])
+		}
+	}
+	return p.frames
+}
+
+// IsNative returns true if the frame belongs to a native (Go) function.
+func (f *DebugFrame) IsNative() bool {
+	return f.prg == nil
+}
+
+// This returns the value of 'this' in the frame.
+func (f *DebugFrame) This() Value {
+	if f.IsNative() {
+		if f.ctx.sb >= 0 && f.ctx.sb < len(f.p.vm.stack) {
+			return nilSafe(f.p.vm.stack[f.ctx.sb])
+		}
+		return _undefined
+	}
+	v, err := f.Eval("this")
+	if err != nil {
+		return _undefined
+	}
+	return v
+}
+
+// Eval evaluates the expression in the scope of the frame, as if it was an argument of a direct eval() call
+// made by the frame's function. Breakpoints are not triggered while the expression is evaluated.
+func (f *DebugFrame) Eval(src string) (Value, error) {
+	if f.p.vm == nil {
+		return nil, errPauseEnded
+	}
+	if f.IsNative() {
+		return nil, errNativeFrame
+	}
+	return f.p.d.eval(f, src)
+}
+
+// Scopes returns the scope chain of the frame starting from the innermost scope. The last element is always
+// the global object.
+func (f *DebugFrame) Scopes() []*DebugScope {
+	if f.IsNative() {
+		return nil
+	}
+	r := f.p.d.r
+	var closure *stash
+	if sb := f.ctx.sb; sb > 0 && sb <= len(f.p.vm.stack) {
+		if callee, ok := f.p.vm.stack[sb-1].(*Object); ok {
+			if fn, ok := callee.self.(debugClosure); ok {
+				closure = fn.closureStash()
+			}
+		}
+	}
+	var scopes []*DebugScope
+	inFunc := closure != nil
+	for s := f.ctx.stash; s != nil; s = s.outer {
+		if s == closure {
+			inFunc = false
+		}
+		var typ DebugScopeType
+		switch {
+		case s == &r.global.stash:
+			typ = DebugScopeScript
+		case s.obj != nil:
+			typ = DebugScopeWith
+		case inFunc && s.isVariable():
+			typ = DebugScopeLocal
+			inFunc = false
+		case inFunc || closure == nil:
+			typ = DebugScopeBlock
+		default:
+			typ = DebugScopeClosure
+		}
+		scopes = append(scopes, &DebugScope{typ: typ, s: s, obj: s.obj})
+	}
+	return append(scopes, &DebugScope{typ: DebugScopeGlobal, obj: r.globalObject})
+}
+
+// Type returns the type of the scope.
+func (s *DebugScope) Type() DebugScopeType {
+	return s.typ
+}
+
+// Object returns the binding object for DebugScopeWith and DebugScopeGlobal scopes and nil for others.
+func (s *DebugScope) Object() *Object {
+	return s.obj
+}
+
+// Names returns the names of the bindings in the scope in the order of declaration. For the scopes that have
+// a binding object the own property names of the object are returned.
+func (s *DebugScope) Names() []string {
+	if s.obj != nil {
+		return s.obj.Keys()
+	}
+	type entry struct {
+		name string
+		idx  uint32
+	}
+	entries := make([]entry, 0, len(s.s.names))
+	for name, idx := range s.s.names {
+		if name == thisBindingName {
+			continue
+		}
+		entries = append(entries, entry{name: name.String(), idx: idx &^ maskTyp})
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].idx < entries[j].idx
+	})
+	names := make([]string, len(entries))
+	for i, e := range entries {
+		names[i] = e.name
+	}
+	return names
+}
+
+// Get returns the value of the binding. It returns nil if the binding does not exist or has not been
+// initialised yet (i.e. a 'let' declaration in its temporal dead zone).
+func (s *DebugScope) Get(name string) Value {
+	n := unistring.NewFromString(name)
+	if s.obj != nil {
+		return s.obj.self.getStr(n, nil)
+	}
+	if idx, exists := s.s.names[n]; exists {
+		v := s.s.values[idx&^maskTyp]
+		if v == nil && idx&maskVar != 0 {
+			v = _undefined
+		}
+		return v
+	}
+	return nil
+}
@@ -0,0 +1,418 @@
+package goja
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/dop251/goja/file"
+)
+
+func TestDebuggerBreakpoint(t *testing.T) {
+	const SCRIPT = `
+	function add(a, b) {
+		var sum = a + b;
+		return sum;
+	}
+	let total = 0;
+	for (var i = 0; i < 3; i++) {
+		total = add(total, i);
+	}
+	total;
+	`
+	r := New()
+	var hits []string
+	d := r.AttachDebugger(func(p *DebugPause) DebugCommand {
+		if p.Reason() != PauseBreakpoint {
+			t.Fatalf("Unexpected reason: %v", p.Reason())
+		}
+		frames := p.CallStack()
+		if len(frames) != 2 {
+			t.Fatalf("Unexpected call stack length: %d", len(frames))
+		}
+		f := frames[0]
+		if f.FuncName() != "add" || f.Position().Line != 4 {
+			t.Fatalf("Unexpected frame: %s at %v", f.FuncName(), f.Position())
+		}
+		scopes := f.Scopes()
+		if scopes[0].Type() != DebugScopeLocal {
+			t.Fatalf("Unexpected scope type: %v", scopes[0].Type())
+		}
+		if names := strings.Join(scopes[0].Names(), ","); names != "a,b,sum,arguments" {
+			t.Fatalf("Unexpected names: %s", names)
+		}
+		if last := scopes[len(scopes)-1]; last.Type() != DebugScopeGlobal || last.Get("i") == nil {
+			t.Fatal("Global scope is missing")
+		}
+		var script *DebugScope
+		for _, s := range scopes {
+			if s.Type() == DebugScopeScript {
+				script = s
+			}
+		}
+		if script == nil || script.Get("total") == nil {
+			t.Fatal("Script scope is missing")
+		}
+		v, err := f.Eval("sum * 10 + i")
+		if err != nil {
+			t.Fatal(err)
+		}
+		hits = append(hits, fmt.Sprintf("%v:%v", scopes[0].Get("sum"), v))
+		if frames[1].SrcName() == "test.js" && frames[1].Position().Line != 8 {
+			t.Fatalf("Unexpected caller position: %v", frames[1].Position())
+		}
+		return DebugContinue
+	})
+	b := d.SetBreakpoint("test.js", 4, 0, "")
+	d.SetBreakpoint("test.js", 4, 0, "b == 2")
+	v, err := r.RunScript("test.js", SCRIPT)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v.ToInteger() != 3 {
+		t.Fatalf("Unexpected result: %v", v)
+	}
+	if s := strings.Join(hits, " "); s != "0:0 1:11 3:32" {
+		t.Fatalf("Unexpected hits: %s", s)
+	}
+	if b.HitCount() != 3 || d.Breakpoints()[1].HitCount() != 1 {
+		t.Fatalf("Unexpected hit counts: %d, %d", b.HitCount(), d.Breakpoints()[1].HitCount())
+	}
+
+	d.RemoveBreakpoint(b)
+	hits = nil
+	_, err = r.RunScript("test2.js", `total = 0; total += add(1, 2);`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := strings.Join(hits, " "); s != "3:33" {
+		t.Fatalf("Unexpected hits after removal: %s", s)
+	}
+}
+
+func TestDebuggerBreakpointWhileProfiling(t *testing.T) {
+	var buf strings.Builder
+	if err := StartProfile(&buf); err != nil {
+		t.Fatal(err)
+	}
+	defer StopProfile()
+	r := New()
+	var lines []int
+	d := r.AttachDebugger(func(p *DebugPause) DebugCommand {
+		lines = append(lines, p.CallStack()[0].Position().Line)
+		if len(lines) == 1 {
+			return DebugStepOver
+		}
+		return DebugContinue
+	})
+	d.SetBreakpoint("test.js", 2, 0, "")
+	_, err := r.RunScript("test.js", "var x = 1;\nx++;\nx++;\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fmt.Sprint(lines) != "[2 3]" {
+		t.Fatalf("Unexpected pauses: %v", lines)
+	}
+}
+
+func TestDebuggerStepping(t *testing.T) {
+	const SCRIPT = `var x = 1;
+function f() {
+	var y = 2;
+	return y;
+}
+debugger;
+x = f();
+x++;
+`
+	r := New()
+	var lines []int
+	commands := []DebugCommand{DebugStepOver, DebugStepIn, DebugStepIn, DebugStepOut, DebugStepOver, DebugContinue}
+	r.AttachDebugger(func(p *DebugPause) DebugCommand {
+		if len(lines) == 0 && p.Reason() != PauseDebuggerStatement {
+			t.Fatalf("Unexpected reason: %v", p.Reason())
+		}
+		lines = append(lines, p.CallStack()[0].Position().Line)
+		cmd := commands[0]
+		commands = commands[1:]
+		return cmd
+	})
+	_, err := r.RunScript("step.js", SCRIPT)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := fmt.Sprint(lines); s != "[6 7 3 4 7 8]" {
+		t.Fatalf("Unexpected pause lines: %s", s)
+	}
+}
+
+func TestDebuggerStepOut(t *testing.T) {
+	const SCRIPT = `function f(n) {
+	debugger;
+	return n * 2;
+}
+function g() {
+	var s = 1;
+	s += f(3);
+	s += [1].map(function(v) {
+		debugger;
+		return v;
+	})[0];
+	return s;
+}
+g();
+`
+	r := New()
+	var pauses []string
+	r.AttachDebugger(func(p *DebugPause) DebugCommand {
+		frame := p.CallStack()[0]
+		pauses = append(pauses, fmt.Sprintf("%s:%d", frame.FuncName(), frame.Position().Line))
+		if p.Reason() == PauseDebuggerStatement {
+			return DebugStepOut
+		}
+		if p.Reason() != PauseStep {
+			t.Fatalf("Unexpected reason: %v", p.Reason())
+		}
+		if frame.Position().Line == 7 {
+			// the assignment has not been done yet
+			if s, err := frame.Eval("s"); err != nil || s.ToInteger() != 1 {
+				t.Fatalf("Unexpected s: %v, %v", s, err)
+			}
+		}
+		return DebugContinue
+	})
+	v, err := r.RunScript("stepout.js", SCRIPT)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v.ToInteger() != 8 {
+		t.Fatalf("Unexpected result: %v", v)
+	}
+	if s := fmt.Sprint(pauses); s != "[f:2 g:7 <anonymous>:9 g:8]" {
+		t.Fatalf("Unexpected pauses: %s", s)
+	}
+}
+
+func TestDebuggerExceptions(t *testing.T) {
+	const SCRIPT = `
+	try {
+		throw new Error("caught");
+	} catch (e) {
+	}
+	function thrower() {
+		throw new TypeError("uncaught");
+	}
+	thrower();
+	`
+	r := New()
+	var msgs []string
+	d := r.AttachDebugger(func(p *DebugPause) DebugCommand {
+		if p.Reason() != PauseException {
+			t.Fatalf("Unexpected reason: %v", p.Reason())
+		}
+		msgs = append(msgs, fmt.Sprintf("%s@%d", p.Exception().Value().(*Object).Get("message"), p.CallStack()[0].Position().Line))
+		return DebugContinue
+	})
+	d.SetPauseOnExceptions(PauseOnUncaughtExceptions)
+	_, err := r.RunString(SCRIPT)
+	if err == nil {
+		t.Fatal("Expected an exception")
+	}
+	if s := strings.Join(msgs, " "); s != "uncaught@7" {
+		t.Fatalf("Unexpected pauses: %s", s)
+	}
+
+	msgs = nil
+	d.SetPauseOnExceptions(PauseOnAllExceptions)
+	_, _ = r.RunString(SCRIPT)
+	if s := strings.Join(msgs, " "); s != "caught@3 uncaught@7" {
+		t.Fatalf("Unexpected pauses: %s", s)
+	}
+}
+
+func TestDebuggerThisAndClosures(t *testing.T) {
+	const SCRIPT = `
+	function outer() {
+		var captured = "c";
+		return function inner() {
+			let block = 1;
+			{
+				const nested = captured + block;
+				debugger;
+			}
+		};
+	}
+	var o = {name: "obj", m: outer()};
+	o.m();
+	`
+	r := New()
+	paused := false
+	r.AttachDebugger(func(p *DebugPause) DebugCommand {
+		paused = true
+		f := p.CallStack()[0]
+		if name := f.This().(*Object).Get("name").String(); name != "obj" {
+			t.Fatalf("Unexpected this: %s", name)
+		}
+		var types []string
+		for _, s := range f.Scopes() {
+			types = append(types, s.Type().String())
+		}
+		if s := strings.Join(types, ","); s != "block,local,closure,script,global" {
+			t.Fatalf("Unexpected scopes: %s", s)
+		}
+		scopes := f.Scopes()
+		if v := scopes[0].Get("nested"); v == nil || v.String() != "c1" {
+			t.Fatalf("Unexpected nested: %v", v)
+		}
+		if v := scopes[2].Get("captured"); v == nil || v.String() != "c" {
+			t.Fatalf("Unexpected captured: %v", v)
+		}
+		if _, err := f.Eval("block = 2"); err != nil {
+			t.Fatal(err)
+		}
+		if v := scopes[1].Get("block"); v == nil || v.ToInteger() != 2 {
+			t.Fatalf("Unexpected block: %v", v)
+		}
+		if _, err := f.Eval("undefinedVar"); err == nil {
+			t.Fatal("Expected a ReferenceError")
+		}
+		return DebugContinue
+	})
+	_, err := r.RunString(SCRIPT)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !paused {
+		t.Fatal("The debugger statement has not paused the execution")
+	}
+}
+
+func TestDebuggerPauseRequest(t *testing.T) {
+	r := New()
+	var reason PauseReason
+	d := r.AttachDebugger(func(p *DebugPause) DebugCommand {
+		reason = p.Reason()
+		return DebugContinue
+	})
+	d.Pause()
+	_, err := r.RunString("var a = 1;\na++;")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reason != PauseRequested {
+		t.Fatalf("Unexpected reason: %v", reason)
+	}
+	r.DetachDebugger()
+	if r.Debugger() != nil {
+		t.Fatal("Debugger is still attached")
+	}
+}
+
+func TestDebuggerScriptHandler(t *testing.T) {
+	r := New()
+	d := r.AttachDebugger(func(p *DebugPause) DebugCommand {
+		return DebugContinue
+	})
+	var names []string
+	d.SetScriptHandler(func(src *file.File) {
+		names = append(names, src.Name())
+	})
+	p := MustCompile("compiled.js", "1", false)
+	for i := 0; i < 2; i++ {
+		if _, err := r.RunProgram(p); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := r.RunScript("script.js", "2"); err != nil {
+		t.Fatal(err)
+	}
+	if s := strings.Join(names, ","); s != "compiled.js,script.js" {
+		t.Fatalf("Unexpected scripts: %s", s)
+	}
+}
+
+func TestDebuggerBreakpointLocation(t *testing.T) {
+	const SCRIPT = `var x = 1;
+
+// comment
+function f() {
+	return x;
+}
+f();
+`
+	r := New()
+	d := r.AttachDebugger(func(p *DebugPause) DebugCommand {
+		return DebugContinue
+	})
+	b := d.SetBreakpoint("test.js", 5, 0, "")
+	if _, loaded, found := d.BreakpointLocation(b); loaded || found {
+		t.Fatal("Breakpoint is resolved before the script is run")
+	}
+	if _, err := r.RunScript("test.js", SCRIPT); err != nil {
+		t.Fatal(err)
+	}
+	if b.HitCount() != 1 {
+		t.Fatalf("Unexpected hit count: %d", b.HitCount())
+	}
+	for _, test := range []struct {
+		filename      string
+		line, col     int
+		loaded, found bool
+		resolvedCol   int
+	}{
+		{"test.js", 1, 0, true, true, 1},
+		{"test.js", 1, 3, true, true, 5},
+		{"test.js", 2, 0, true, false, 0},
+		{"test.js", 3, 0, true, false, 0},
+		{"test.js", 5, 0, true, true, 2},
+		{"test.js", 100, 0, true, false, 0},
+		{"other.js", 1, 0, false, false, 0},
+	} {
+		pos, loaded, found := d.BreakpointLocation(d.SetBreakpoint(test.filename, test.line, test.col, ""))
+		if loaded != test.loaded || found != test.found {
+			t.Fatalf("%s:%d: unexpected loaded, found: %v, %v", test.filename, test.line, loaded, found)
+		}
+		if found && (pos.Line != test.line || pos.Column != test.resolvedCol) {
+			t.Fatalf("%s:%d: unexpected position: %v", test.filename, test.line, pos)
+		}
+	}
+}
+
+func TestDebuggerSourceMapBreakpoint(t *testing.T) {
+	// orig.js:
+	//
+	// // comment
+	//
+	// var x = 1;
+	//
+	// x++;
+	// x;
+	const SCRIPT = "var x = 1;\nx++;\nx;\n" +
+		"//# sourceMappingURL=data:application/json;base64," +
+		"eyJ2ZXJzaW9uIjozLCJzb3VyY2VzIjpbIm9yaWcuanMiXSwibmFtZXMiOltdLCJtYXBwaW5ncyI6IkFBRUE7QUFFQTtBQUNBIn0="
+	r := New()
+	var lines []int
+	d := r.AttachDebugger(func(p *DebugPause) DebugCommand {
+		f := p.CallStack()[0]
+		lines = append(lines, f.Position().Line)
+		return DebugContinue
+	})
+	b := d.SetBreakpoint("orig.js", 5, 0, "")
+	blank := d.SetBreakpoint("orig.js", 4, 0, "")
+	v, err := r.RunScript("gen.js", SCRIPT)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v.ToInteger() != 2 {
+		t.Fatalf("Unexpected result: %v", v)
+	}
+	if b.HitCount() != 1 || blank.HitCount() != 0 || len(lines) != 1 || lines[0] != 5 {
+		t.Fatalf("Unexpected hits: %d, %d, %v", b.HitCount(), blank.HitCount(), lines)
+	}
+	if pos, loaded, found := d.BreakpointLocation(b); !found || pos.Filename != "orig.js" || pos.Line != 5 {
+		t.Fatalf("Unexpected location: %v, %v, %v", pos, loaded, found)
+	}
+	if _, loaded, found := d.BreakpointLocation(blank); !loaded || found {
+		t.Fatalf("Unexpected blank line location: %v, %v", loaded, found)
+	}
+}
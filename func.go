@@ -253,6 +253,10 @@ func (f *baseFuncObject) createInstance(newTarget *Object) *Object {
 	return f.val.runtime.newBaseObject(proto, classObject).val
 }
 
+func (f *baseJsFuncObject) closureStash() *stash {
+	return f.stash
+}
+
 func (f *baseJsFuncObject) source() String {
 	return newStringValue(f.src)
 }
@@ -739,6 +743,25 @@ func (ar *asyncRunner) start(nArgs int) {
 	r.vm.popCtx()
 }
 
+// await is the equivalent of the Await() steps (https://tc39.es/ecma262/#await) for asynchronous
+// operations implemented in Go: once v is settled onFulfilled or onRejected is called from the job queue.
+func (r *Runtime) await(v Value, onFulfilled, onRejected func(Value)) {
+	promise := r.promiseResolve(r.getPromise(), v)
+	promise.self.(*Promise).addReactions(&promiseReaction{
+		typ: promiseReactionFulfill,
+		handler: &jobCallback{callback: func(call FunctionCall) Value {
+			onFulfilled(call.Argument(0))
+			return _undefined
+		}},
+	}, &promiseReaction{
+		typ: promiseReactionReject,
+		handler: &jobCallback{callback: func(call FunctionCall) Value {
+			onRejected(call.Argument(0))
+			return _undefined
+		}},
+	})
+}
+
 type generator struct {
 	ctx execCtx
 	vm  *vm
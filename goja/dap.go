@@ -0,0 +1,754 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/dop251/goja"
+	"github.com/dop251/goja/file"
+	"github.com/dop251/goja_nodejs/console"
+)
+
+// This file implements a minimal Debug Adapter Protocol server
+// (https://microsoft.github.io/debug-adapter-protocol/specification) on top of goja.Debugger.
+// A single script is run per session in a single thread.
+
+const dapThreadID = 1
+
+type dapRequest struct {
+	Seq       int             `json:"seq"`
+	Type      string          `json:"type"`
+	Command   string          `json:"command"`
+	Arguments json.RawMessage `json:"arguments"`
+}
+
+type dapResponse struct {
+	Seq        int         `json:"seq"`
+	Type       string      `json:"type"`
+	RequestSeq int         `json:"request_seq"`
+	Success    bool        `json:"success"`
+	Command    string      `json:"command"`
+	Message    string      `json:"message,omitempty"`
+	Body       interface{} `json:"body,omitempty"`
+}
+
+type dapEvent struct {
+	Seq   int         `json:"seq"`
+	Type  string      `json:"type"`
+	Event string      `json:"event"`
+	Body  interface{} `json:"body,omitempty"`
+}
+
+type dapSource struct {
+	Name string `json:"name,omitempty"`
+	Path string `json:"path,omitempty"`
+}
+
+type dapVariable struct {
+	Name               string `json:"name"`
+	Value              string `json:"value"`
+	Type               string `json:"type,omitempty"`
+	VariablesReference int    `json:"variablesReference"`
+}
+
+// dapTask is executed in the goroutine running the script while it is paused. If resume is true, the execution
+// is resumed with the returned command.
+type dapTask func(p *goja.DebugPause) (resume bool, cmd goja.DebugCommand)
+
+type dapServer struct {
+	in *bufio.Reader
+
+	outMu sync.Mutex
+	out   io.Writer
+	seq   int
+
+	vm  *goja.Runtime
+	dbg *goja.Debugger
+
+	// 1 if the client counts lines and columns from 1 (the default), 0 otherwise
+	lineBase, colBase int
+
+	program     string
+	stopOnEntry bool
+	launched    bool
+	configured  bool
+	started     bool
+
+	mu     sync.Mutex
+	paused bool
+	entry  bool
+	tasks  chan dapTask
+	// breakpoints by source path
+	breakpoints map[string][]*goja.Breakpoint
+
+	// variable references, only accessed in dapTasks
+	refs []interface{}
+}
+
+// runDAP runs a debug session. The program to debug is taken from the 'launch' request or from the command line.
+func runDAP(addr string) error {
+	if addr == "stdio" {
+		s := newDAPServer(os.Stdin, os.Stdout)
+		s.program = flag.Arg(0)
+		return s.serve()
+	}
+	l, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	log.Printf("DAP server listening on %s", l.Addr())
+	conn, err := l.Accept()
+	l.Close()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	s := newDAPServer(conn, conn)
+	s.program = flag.Arg(0)
+	return s.serve()
+}
+
+func newDAPServer(in io.Reader, out io.Writer) *dapServer {
+	s := &dapServer{
+		in:          bufio.NewReader(in),
+		out:         out,
+		lineBase:    1,
+		colBase:     1,
+		breakpoints: make(map[string][]*goja.Breakpoint),
+		tasks:       make(chan dapTask),
+	}
+	s.vm = newRuntime(dapPrinter{s})
+	s.dbg = s.vm.AttachDebugger(s.onPause)
+	s.dbg.SetScriptHandler(s.onScript)
+	return s
+}
+
+type dapPrinter struct {
+	s *dapServer
+}
+
+func (p dapPrinter) Log(s string) {
+	p.s.output("console", s+"\n")
+}
+
+func (p dapPrinter) Warn(s string) {
+	p.s.output("console", s+"\n")
+}
+
+func (p dapPrinter) Error(s string) {
+	p.s.output("stderr", s+"\n")
+}
+
+var _ console.Printer = dapPrinter{}
+
+func (s *dapServer) serve() error {
+	defer func() {
+		close(s.tasks)
+		s.vm.Interrupt("debug session ended")
+	}()
+	for {
+		msg, err := s.readMessage()
+		if err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return err
+		}
+		var req dapRequest
+		if err := json.Unmarshal(msg, &req); err != nil {
+			return fmt.Errorf("invalid DAP message: %v", err)
+		}
+		if req.Type != "request" {
+			continue
+		}
+		if !s.handle(&req) {
+			return nil
+		}
+	}
+}
+
+func (s *dapServer) readMessage() ([]byte, error) {
+	length := -1
+	for {
+		line, err := s.in.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		line = strings.TrimRight(line, "\r\n")
+		if line == "" {
+			if length >= 0 {
+				break
+			}
+			continue
+		}
+		if strings.HasPrefix(line, "Content-Length:") {
+			length, err = strconv.Atoi(strings.TrimSpace(line[len("Content-Length:"):]))
+			if err != nil {
+				return nil, fmt.Errorf("invalid Content-Length header: %q", line)
+			}
+		}
+	}
+	buf := make([]byte, length)
+	if _, err := io.ReadFull(s.in, buf); err != nil {
+		return nil, err
+	}
+	return buf, nil
+}
+
+func (s *dapServer) send(msg interface{}) {
+	s.outMu.Lock()
+	defer s.outMu.Unlock()
+	s.seq++
+	switch m := msg.(type) {
+	case *dapResponse:
+		m.Seq = s.seq
+	case *dapEvent:
+		m.Seq = s.seq
+	}
+	b, err := json.Marshal(msg)
+	if err != nil {
+		panic(err)
+	}
+	fmt.Fprintf(s.out, "Content-Length: %d\r\n\r\n", len(b))
+	s.out.Write(b)
+}
+
+func (s *dapServer) sendEvent(event string, body interface{}) {
+	s.send(&dapEvent{Type: "event", Event: event, Body: body})
+}
+
+func (s *dapServer) output(category, text string) {
+	s.sendEvent("output", map[string]interface{}{
+		"category": category,
+		"output":   text,
+	})
+}
+
+func (s *dapServer) respond(req *dapRequest, body interface{}, err error) {
+	resp := &dapResponse{
+		Type:       "response",
+		RequestSeq: req.Seq,
+		Command:    req.Command,
+		Success:    err == nil,
+		Body:       body,
+	}
+	if err != nil {
+		resp.Message = err.Error()
+	}
+	s.send(resp)
+}
+
+func unmarshalArgs(args json.RawMessage, v interface{}) error {
+	if len(args) == 0 {
+		return nil
+	}
+	return json.Unmarshal(args, v)
+}
+
+// handle processes a request. It returns false when the session is over.
+func (s *dapServer) handle(req *dapRequest) bool {
+	var body interface{}
+	var err error
+	switch req.Command {
+	case "initialize":
+		var args struct {
+			LinesStartAt1   *bool `json:"linesStartAt1"`
+			ColumnsStartAt1 *bool `json:"columnsStartAt1"`
+		}
+		err = unmarshalArgs(req.Arguments, &args)
+		if args.LinesStartAt1 != nil && !*args.LinesStartAt1 {
+			s.lineBase = 0
+		}
+		if args.ColumnsStartAt1 != nil && !*args.ColumnsStartAt1 {
+			s.colBase = 0
+		}
+		body = map[string]interface{}{
+			"supportsConfigurationDoneRequest": true,
+			"supportsConditionalBreakpoints":   true,
+			"supportsEvaluateForHovers":        true,
+			"supportsTerminateRequest":         true,
+			"exceptionBreakpointFilters": []map[string]interface{}{
+				{"filter": "all", "label": "All Exceptions"},
+				{"filter": "uncaught", "label": "Uncaught Exceptions"},
+			},
+		}
+		s.respond(req, body, err)
+		s.sendEvent("initialized", nil)
+		return true
+	case "launch":
+		var args struct {
+			Program     string `json:"program"`
+			StopOnEntry bool   `json:"stopOnEntry"`
+		}
+		if err = unmarshalArgs(req.Arguments, &args); err == nil {
+			if args.Program == "" {
+				args.Program = s.program
+			}
+			if args.Program == "" {
+				err = errors.New("no program to launch")
+			} else {
+				s.program, err = filepath.Abs(args.Program)
+				s.stopOnEntry = args.StopOnEntry
+				s.launched = true
+			}
+		}
+		s.respond(req, nil, err)
+		s.maybeStart()
+		return true
+	case "configurationDone":
+		s.configured = true
+		s.respond(req, nil, nil)
+		s.maybeStart()
+		return true
+	case "setBreakpoints":
+		body, err = s.setBreakpoints(req.Arguments)
+	case "setExceptionBreakpoints":
+		var args struct {
+			Filters []string `json:"filters"`
+		}
+		if err = unmarshalArgs(req.Arguments, &args); err == nil {
+			mode := goja.PauseOnNoExceptions
+			for _, f := range args.Filters {
+				switch f {
+				case "all":
+					mode = goja.PauseOnAllExceptions
+				case "uncaught":
+					if mode == goja.PauseOnNoExceptions {
+						mode = goja.PauseOnUncaughtExceptions
+					}
+				}
+			}
+			s.dbg.SetPauseOnExceptions(mode)
+		}
+	case "threads":
+		body = map[string]interface{}{
+			"threads": []map[string]interface{}{{"id": dapThreadID, "name": "main"}},
+		}
+	case "stackTrace":
+		body, err = s.stackTrace()
+	case "scopes":
+		body, err = s.scopes(req.Arguments)
+	case "variables":
+		body, err = s.variables(req.Arguments)
+	case "evaluate":
+		body, err = s.evaluate(req.Arguments)
+	case "continue":
+		s.resume(req, goja.DebugContinue, map[string]interface{}{"allThreadsContinued": true})
+		return true
+	case "next":
+		s.resume(req, goja.DebugStepOver, nil)
+		return true
+	case "stepIn":
+		s.resume(req, goja.DebugStepIn, nil)
+		return true
+	case "stepOut":
+		s.resume(req, goja.DebugStepOut, nil)
+		return true
+	case "pause":
+		s.dbg.Pause()
+	case "terminate":
+		s.vm.Interrupt("terminated")
+		s.respond(req, nil, nil)
+		s.resume(nil, goja.DebugContinue, nil)
+		return true
+	case "disconnect":
+		s.respond(req, nil, nil)
+		return false
+	default:
+		err = fmt.Errorf("unsupported request: %s", req.Command)
+	}
+	s.respond(req, body, err)
+	return true
+}
+
+func (s *dapServer) maybeStart() {
+	if !s.launched || !s.configured || s.started {
+		return
+	}
+	s.started = true
+	if s.stopOnEntry {
+		s.entry = true
+		s.dbg.Pause()
+	}
+	go func() {
+		exitCode := 0
+		src, err := os.ReadFile(s.program)
+		if err == nil {
+			_, err = s.vm.RunScript(s.program, string(src))
+		}
+		if err != nil {
+			exitCode = 64
+			var msg string
+			switch err := err.(type) {
+			case *goja.Exception:
+				msg = err.String()
+			case *goja.InterruptedError:
+				msg = err.String()
+			default:
+				msg = err.Error()
+			}
+			s.output("stderr", msg+"\n")
+		}
+		s.sendEvent("exited", map[string]interface{}{"exitCode": exitCode})
+		s.sendEvent("terminated", nil)
+	}()
+}
+
+// onPause is the goja.DebugHandler. It runs the tasks sent by the request handlers until one of them resumes
+// the execution.
+func (s *dapServer) onPause(p *goja.DebugPause) goja.DebugCommand {
+	s.refs = s.refs[:0]
+	s.mu.Lock()
+	s.paused = true
+	entry := s.entry
+	s.entry = false
+	s.mu.Unlock()
+
+	body := map[string]interface{}{
+		"threadId":          dapThreadID,
+		"allThreadsStopped": true,
+	}
+	switch p.Reason() {
+	case goja.PauseBreakpoint:
+		body["reason"] = "breakpoint"
+		var ids []int
+		for _, b := range p.Breakpoints() {
+			ids = append(ids, b.ID())
+		}
+		body["hitBreakpointIds"] = ids
+	case goja.PauseStep:
+		body["reason"] = "step"
+	case goja.PauseException:
+		body["reason"] = "exception"
+		body["text"] = p.Exception().Value().String()
+	case goja.PauseDebuggerStatement:
+		body["reason"] = "pause"
+		body["description"] = "Paused on debugger statement"
+	default:
+		if entry {
+			body["reason"] = "entry"
+		} else {
+			body["reason"] = "pause"
+		}
+	}
+	s.sendEvent("stopped", body)
+
+	for task := range s.tasks {
+		if resume, cmd := task(p); resume {
+			return cmd
+		}
+	}
+	return goja.DebugContinue
+}
+
+// inspect runs f in the paused script goroutine and waits for it to complete.
+func (s *dapServer) inspect(f func(p *goja.DebugPause) error) error {
+	s.mu.Lock()
+	paused := s.paused
+	s.mu.Unlock()
+	if !paused {
+		return errors.New("the script is not paused")
+	}
+	var err error
+	done := make(chan struct{})
+	s.tasks <- func(p *goja.DebugPause) (bool, goja.DebugCommand) {
+		defer close(done)
+		err = f(p)
+		return false, goja.DebugContinue
+	}
+	<-done
+	return err
+}
+
+// resume resumes the paused script with the command. The response to req (if not nil) is sent before that,
+// so that it precedes any events caused by the resumed execution.
+func (s *dapServer) resume(req *dapRequest, cmd goja.DebugCommand, body interface{}) {
+	s.mu.Lock()
+	paused := s.paused
+	s.paused = false
+	s.mu.Unlock()
+	if req != nil {
+		if paused {
+			s.respond(req, body, nil)
+		} else {
+			s.respond(req, nil, errors.New("the script is not paused"))
+		}
+	}
+	if paused {
+		s.tasks <- func(*goja.DebugPause) (bool, goja.DebugCommand) {
+			return true, cmd
+		}
+	}
+}
+
+func (s *dapServer) setBreakpoints(arguments json.RawMessage) (interface{}, error) {
+	var args struct {
+		Source      dapSource `json:"source"`
+		Breakpoints []struct {
+			Line      int    `json:"line"`
+			Column    int    `json:"column"`
+			Condition string `json:"condition"`
+		} `json:"breakpoints"`
+	}
+	if err := unmarshalArgs(arguments, &args); err != nil {
+		return nil, err
+	}
+	path, err := filepath.Abs(strings.TrimPrefix(args.Source.Path, "file://"))
+	if err != nil {
+		return nil, err
+	}
+	// s.mu is held until the breakpoints are stored so that onScript cannot miss them
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, b := range s.breakpoints[path] {
+		s.dbg.RemoveBreakpoint(b)
+	}
+	bps := make([]*goja.Breakpoint, 0, len(args.Breakpoints))
+	res := make([]map[string]interface{}, 0, len(args.Breakpoints))
+	for _, b := range args.Breakpoints {
+		col := 0
+		if b.Column > 0 {
+			col = b.Column + 1 - s.colBase
+		}
+		bp := s.dbg.SetBreakpoint(path, b.Line+1-s.lineBase, col, b.Condition)
+		bps = append(bps, bp)
+		res = append(res, s.breakpoint(bp))
+	}
+	s.breakpoints[path] = bps
+	return map[string]interface{}{"breakpoints": res}, nil
+}
+
+// breakpoint returns the DAP representation of the breakpoint. It is only verified if it resolves to code
+// in a script that has been run.
+func (s *dapServer) breakpoint(b *goja.Breakpoint) map[string]interface{} {
+	res := map[string]interface{}{
+		"id":       b.ID(),
+		"verified": false,
+		"line":     b.Line() - 1 + s.lineBase,
+	}
+	pos, loaded, found := s.dbg.BreakpointLocation(b)
+	switch {
+	case found:
+		res["verified"] = true
+		res["line"] = pos.Line - 1 + s.lineBase
+		res["column"] = pos.Column - 1 + s.colBase
+	case loaded:
+		res["message"] = "No code at this line"
+	default:
+		res["message"] = "The script has not been loaded yet"
+	}
+	return res
+}
+
+// onScript is the goja.DebugScriptHandler. It reports the breakpoints in the script (or, if it has a source map,
+// in its original sources) which could not be verified before the script was loaded.
+func (s *dapServer) onScript(src *file.File) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for path, bps := range s.breakpoints {
+		for _, b := range bps {
+			res := s.breakpoint(b)
+			if res["verified"] == true || path == src.Name() {
+				s.sendEvent("breakpoint", map[string]interface{}{
+					"reason":     "changed",
+					"breakpoint": res,
+				})
+			}
+		}
+	}
+}
+
+func (s *dapServer) stackTrace() (interface{}, error) {
+	var frames []map[string]interface{}
+	err := s.inspect(func(p *goja.DebugPause) error {
+		for i, f := range p.CallStack() {
+			frame := map[string]interface{}{
+				"id":   i + 1,
+				"name": f.FuncName(),
+			}
+			if f.IsNative() {
+				frame["presentationHint"] = "subtle"
+			} else {
+				pos := f.Position()
+				frame["source"] = dapSource{Name: filepath.Base(pos.Filename), Path: pos.Filename}
+				frame["line"] = pos.Line - 1 + s.lineBase
+				frame["column"] = pos.Column - 1 + s.colBase
+			}
+			frames = append(frames, frame)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{
+		"stackFrames": frames,
+		"totalFrames": len(frames),
+	}, nil
+}
+
+func (s *dapServer) frame(p *goja.DebugPause, id int) (*goja.DebugFrame, error) {
+	frames := p.CallStack()
+	if id == 0 && len(frames) > 0 {
+		return frames[0], nil
+	}
+	if id < 1 || id > len(frames) {
+		return nil, fmt.Errorf("invalid frame id: %d", id)
+	}
+	return frames[id-1], nil
+}
+
+func (s *dapServer) scopes(arguments json.RawMessage) (interface{}, error) {
+	var args struct {
+		FrameID int `json:"frameId"`
+	}
+	if err := unmarshalArgs(arguments, &args); err != nil {
+		return nil, err
+	}
+	var scopes []map[string]interface{}
+	err := s.inspect(func(p *goja.DebugPause) error {
+		f, err := s.frame(p, args.FrameID)
+		if err != nil {
+			return err
+		}
+		for _, scope := range f.Scopes() {
+			name := scope.Type().String()
+			scopes = append(scopes, map[string]interface{}{
+				"name":               strings.ToUpper(name[:1]) + name[1:],
+				"variablesReference": s.addRef(scope),
+				"expensive":          scope.Type() == goja.DebugScopeGlobal,
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{"scopes": scopes}, nil
+}
+
+func (s *dapServer) addRef(v interface{}) int {
+	s.refs = append(s.refs, v)
+	return len(s.refs)
+}
+
+func (s *dapServer) variables(arguments json.RawMessage) (interface{}, error) {
+	var args struct {
+		VariablesReference int `json:"variablesReference"`
+	}
+	if err := unmarshalArgs(arguments, &args); err != nil {
+		return nil, err
+	}
+	vars := []dapVariable{}
+	err := s.inspect(func(p *goja.DebugPause) error {
+		ref := args.VariablesReference
+		if ref < 1 || ref > len(s.refs) {
+			return fmt.Errorf("invalid variables reference: %d", ref)
+		}
+		switch c := s.refs[ref-1].(type) {
+		case *goja.DebugScope:
+			if o := c.Object(); o != nil {
+				for _, name := range o.Keys() {
+					vars = append(vars, s.variable(name, o.Get(name)))
+				}
+			} else {
+				for _, name := range c.Names() {
+					vars = append(vars, s.variable(name, c.Get(name)))
+				}
+			}
+		case *goja.Object:
+			for _, name := range c.Keys() {
+				vars = append(vars, s.variable(name, c.Get(name)))
+			}
+			if proto := c.Prototype(); proto != nil {
+				vars = append(vars, s.variable("__proto__", proto))
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{"variables": vars}, nil
+}
+
+func (s *dapServer) variable(name string, v goja.Value) dapVariable {
+	res := dapVariable{Name: name}
+	res.Value, res.Type = s.describe(v)
+	if o, ok := v.(*goja.Object); ok {
+		res.VariablesReference = s.addRef(o)
+	}
+	return res
+}
+
+func (s *dapServer) describe(v goja.Value) (value, typ string) {
+	switch {
+	case v == nil:
+		return "<uninitialized>", ""
+	case goja.IsUndefined(v):
+		return "undefined", "undefined"
+	case goja.IsNull(v):
+		return "null", "object"
+	}
+	switch v := v.(type) {
+	case *goja.Object:
+		if _, ok := goja.AssertFunction(v); ok {
+			return fmt.Sprintf("function %s()", v.Get("name")), "function"
+		}
+		if v.ClassName() == "Array" {
+			return fmt.Sprintf("Array(%d)", v.Get("length").ToInteger()), "object"
+		}
+		return v.ClassName(), "object"
+	case *goja.Symbol:
+		return v.String(), "symbol"
+	}
+	switch v.Export().(type) {
+	case string:
+		return strconv.Quote(v.String()), "string"
+	case bool:
+		return v.String(), "boolean"
+	}
+	return v.String(), "number"
+}
+
+func (s *dapServer) evaluate(arguments json.RawMessage) (interface{}, error) {
+	var args struct {
+		Expression string `json:"expression"`
+		FrameID    int    `json:"frameId"`
+	}
+	if err := unmarshalArgs(arguments, &args); err != nil {
+		return nil, err
+	}
+	var res dapVariable
+	err := s.inspect(func(p *goja.DebugPause) error {
+		f, err := s.frame(p, args.FrameID)
+		if err != nil {
+			return err
+		}
+		v, err := f.Eval(args.Expression)
+		if err != nil {
+			return err
+		}
+		res = s.variable("", v)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{
+		"result":             res.Value,
+		"type":               res.Type,
+		"variablesReference": res.VariablesReference,
+	}, nil
+}
@@ -0,0 +1,205 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+type dapTestClient struct {
+	t    *testing.T
+	in   *bufio.Reader
+	out  io.Writer
+	seq  int
+	msgs chan map[string]interface{}
+}
+
+func (c *dapTestClient) request(command string, args interface{}) {
+	c.seq++
+	b, err := json.Marshal(map[string]interface{}{
+		"seq":       c.seq,
+		"type":      "request",
+		"command":   command,
+		"arguments": args,
+	})
+	if err != nil {
+		c.t.Fatal(err)
+	}
+	fmt.Fprintf(c.out, "Content-Length: %d\r\n\r\n%s", len(b), b)
+}
+
+func (c *dapTestClient) read() {
+	defer close(c.msgs)
+	for {
+		line, err := c.in.ReadString('\n')
+		if err != nil {
+			return
+		}
+		line = strings.TrimSpace(line)
+		if !strings.HasPrefix(line, "Content-Length:") {
+			continue
+		}
+		l, _ := strconv.Atoi(strings.TrimSpace(line[len("Content-Length:"):]))
+		c.in.ReadString('\n')
+		buf := make([]byte, l)
+		if _, err := io.ReadFull(c.in, buf); err != nil {
+			return
+		}
+		var m map[string]interface{}
+		if err := json.Unmarshal(buf, &m); err != nil {
+			c.t.Error(err)
+			return
+		}
+		c.msgs <- m
+	}
+}
+
+// expect waits for a response to the command or for an event with the given name, skipping 'output' events.
+func (c *dapTestClient) expect(typ, name string) map[string]interface{} {
+	for {
+		select {
+		case m, ok := <-c.msgs:
+			if !ok {
+				c.t.Fatalf("Connection closed while waiting for %s %s", typ, name)
+			}
+			if m["type"] == "event" && m["event"] == "output" && name != "output" {
+				continue
+			}
+			if m["type"] != typ || (m["command"] != name && m["event"] != name) {
+				c.t.Fatalf("Expected %s %s, got %v", typ, name, m)
+			}
+			if typ == "response" && m["success"] != true {
+				c.t.Fatalf("Request %s has failed: %v", name, m["message"])
+			}
+			body, _ := m["body"].(map[string]interface{})
+			return body
+		case <-time.After(5 * time.Second):
+			c.t.Fatalf("Timed out waiting for %s %s", typ, name)
+		}
+	}
+}
+
+func (c *dapTestClient) call(command string, args interface{}) map[string]interface{} {
+	c.request(command, args)
+	return c.expect("response", command)
+}
+
+func TestDAP(t *testing.T) {
+	dir := t.TempDir()
+	program := filepath.Join(dir, "test.js")
+	err := os.WriteFile(program, []byte(`function add(a, b) {
+	var sum = a + b;
+	return sum;
+}
+var res = add(1, {x: 2}.x);
+console.log("result", res);
+`), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	serverIn, clientOut := io.Pipe()
+	clientIn, serverOut := io.Pipe()
+	s := newDAPServer(serverIn, serverOut)
+	done := make(chan error, 1)
+	go func() {
+		done <- s.serve()
+		serverOut.Close()
+	}()
+	c := &dapTestClient{
+		t:    t,
+		in:   bufio.NewReader(clientIn),
+		out:  clientOut,
+		msgs: make(chan map[string]interface{}, 16),
+	}
+	go c.read()
+
+	body := c.call("initialize", map[string]interface{}{"adapterID": "goja"})
+	if body["supportsConditionalBreakpoints"] != true {
+		t.Fatalf("Unexpected capabilities: %v", body)
+	}
+	c.expect("event", "initialized")
+	c.call("launch", map[string]interface{}{"program": program})
+	body = c.call("setBreakpoints", map[string]interface{}{
+		"source":      map[string]interface{}{"path": program},
+		"breakpoints": []map[string]interface{}{{"line": 3}, {"line": 10}},
+	})
+	bps := body["breakpoints"].([]interface{})
+	if len(bps) != 2 || bps[0].(map[string]interface{})["verified"] != false || bps[1].(map[string]interface{})["verified"] != false {
+		t.Fatalf("Unexpected breakpoints: %v", body)
+	}
+	c.call("configurationDone", nil)
+
+	// the breakpoints are verified once the script is loaded
+	body = c.expect("event", "breakpoint")
+	if bp := body["breakpoint"].(map[string]interface{}); body["reason"] != "changed" || bp["verified"] != true || bp["line"] != float64(3) {
+		t.Fatalf("Unexpected breakpoint event: %v", body)
+	}
+	body = c.expect("event", "breakpoint")
+	if bp := body["breakpoint"].(map[string]interface{}); bp["verified"] != false || bp["message"] != "No code at this line" {
+		t.Fatalf("Unexpected breakpoint event: %v", body)
+	}
+
+	body = c.expect("event", "stopped")
+	if body["reason"] != "breakpoint" {
+		t.Fatalf("Unexpected stop reason: %v", body["reason"])
+	}
+	body = c.call("stackTrace", map[string]interface{}{"threadId": dapThreadID})
+	frames := body["stackFrames"].([]interface{})
+	top := frames[0].(map[string]interface{})
+	if top["name"] != "add" || top["line"] != float64(3) || top["source"].(map[string]interface{})["path"] != program {
+		t.Fatalf("Unexpected top frame: %v", top)
+	}
+
+	body = c.call("scopes", map[string]interface{}{"frameId": top["id"]})
+	local := body["scopes"].([]interface{})[0].(map[string]interface{})
+	if local["name"] != "Local" {
+		t.Fatalf("Unexpected scope: %v", local)
+	}
+	body = c.call("variables", map[string]interface{}{"variablesReference": local["variablesReference"]})
+	vars := make(map[string]string)
+	for _, v := range body["variables"].([]interface{}) {
+		v := v.(map[string]interface{})
+		vars[v["name"].(string)] = v["value"].(string)
+	}
+	if vars["a"] != "1" || vars["b"] != "2" || vars["sum"] != "3" {
+		t.Fatalf("Unexpected variables: %v", vars)
+	}
+
+	body = c.call("evaluate", map[string]interface{}{"expression": "({total: sum * 2})", "frameId": top["id"]})
+	if body["result"] != "Object" {
+		t.Fatalf("Unexpected evaluation result: %v", body)
+	}
+	body = c.call("variables", map[string]interface{}{"variablesReference": body["variablesReference"]})
+	if v := body["variables"].([]interface{})[0].(map[string]interface{}); v["name"] != "total" || v["value"] != "6" {
+		t.Fatalf("Unexpected object properties: %v", v)
+	}
+
+	c.call("next", map[string]interface{}{"threadId": dapThreadID})
+	body = c.expect("event", "stopped")
+	if body["reason"] != "step" {
+		t.Fatalf("Unexpected stop reason: %v", body["reason"])
+	}
+	c.call("continue", map[string]interface{}{"threadId": dapThreadID})
+
+	body = c.expect("event", "output")
+	if body["output"] != "result 3\n" {
+		t.Fatalf("Unexpected output: %v", body)
+	}
+	body = c.expect("event", "exited")
+	if body["exitCode"] != float64(0) {
+		t.Fatalf("Unexpected exit code: %v", body)
+	}
+	c.expect("event", "terminated")
+	c.call("disconnect", nil)
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+}
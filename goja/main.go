@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	crand "crypto/rand"
 	"encoding/binary"
 	"flag"
@@ -20,6 +21,7 @@ import (
 
 var cpuprofile = flag.String("cpuprofile", "", "write cpu profile to file")
 var timelimit = flag.Int("timelimit", 0, "max time to run (in seconds)")
+var dap = flag.String("dap", "", "run as a Debug Adapter Protocol server over \"stdio\" or on a TCP address (e.g. \"127.0.0.1:4711\")")
 
 func readSource(filename string) ([]byte, error) {
 	if filename == "" || filename == "-" {
@@ -49,21 +51,16 @@ func newRandSource() goja.RandSource {
 	return rand.New(rand.NewSource(seed)).Float64
 }
 
-func run() error {
-	filename := flag.Arg(0)
-	src, err := readSource(filename)
-	if err != nil {
-		return err
-	}
-
-	if filename == "" || filename == "-" {
-		filename = "<stdin>"
-	}
-
+// newRuntime creates a Runtime with the CLI globals. If printer is nil, the console output goes to the standard log.
+func newRuntime(printer console.Printer) *goja.Runtime {
 	vm := goja.New()
 	vm.SetRandSource(newRandSource())
 
-	new(require.Registry).Enable(vm)
+	registry := new(require.Registry)
+	registry.Enable(vm)
+	if printer != nil {
+		registry.RegisterNativeModule("console", console.RequireWithPrinter(printer))
+	}
 	console.Enable(vm)
 
 	vm.Set("load", func(call goja.FunctionCall) goja.Value {
@@ -78,19 +75,36 @@ func run() error {
 		return string(b), nil
 	})
 
-	if *timelimit > 0 {
-		time.AfterFunc(time.Duration(*timelimit)*time.Second, func() {
-			vm.Interrupt("timeout")
-		})
+	return vm
+}
+
+func run() error {
+	filename := flag.Arg(0)
+	src, err := readSource(filename)
+	if err != nil {
+		return err
+	}
+
+	if filename == "" || filename == "-" {
+		filename = "<stdin>"
 	}
 
+	vm := newRuntime(nil)
+
 	//log.Println("Compiling...")
 	prg, err := goja.Compile(filename, string(src), false)
 	if err != nil {
 		return err
 	}
+	ctx := context.Background()
+	if *timelimit > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, time.Duration(*timelimit)*time.Second)
+		defer cancel()
+	}
+
 	//log.Println("Running...")
-	_, err = vm.RunProgram(prg)
+	_, err = vm.RunProgramContext(ctx, prg)
 	//log.Println("Finished.")
 	return err
 }
@@ -112,6 +126,13 @@ func main() {
 		defer pprof.StopCPUProfile()
 	}
 
+	if *dap != "" {
+		if err := runDAP(*dap); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
 	if err := run(); err != nil {
 		//fmt.Printf("err type: %T\n", err)
 		switch err := err.(type) {
@@ -0,0 +1,546 @@
+package goja
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"strconv"
+
+	"github.com/dop251/goja/unistring"
+)
+
+// Node and edge types of the V8 heap snapshot format, the values are indexes into the lists in the snapshot meta.
+const (
+	heapNodeHidden = iota
+	heapNodeArray
+	heapNodeString
+	heapNodeObject
+	heapNodeCode
+	heapNodeClosure
+	heapNodeRegexp
+	heapNodeNumber
+	heapNodeNative
+	heapNodeSynthetic
+	heapNodeConsString
+	heapNodeSlicedString
+	heapNodeSymbol
+)
+
+const (
+	heapEdgeContext = iota
+	heapEdgeElement
+	heapEdgeProperty
+	heapEdgeInternal
+	heapEdgeHidden
+	heapEdgeShortcut
+	heapEdgeWeak
+)
+
+const heapSnapshotMeta = `{"node_fields":["type","name","id","self_size","edge_count","trace_node_id","detachedness"],` +
+	`"node_types":[["hidden","array","string","object","code","closure","regexp","number","native","synthetic",` +
+	`"concatenated string","sliced string","symbol","bigint","object shape"],"string","number","number","number","number","number"],` +
+	`"edge_fields":["type","name_or_index","to_node"],` +
+	`"edge_types":[["context","element","property","internal","hidden","shortcut","weak"],"string_or_number","node"],` +
+	`"trace_function_info_fields":["function_id","name","script_name","script_id","line","column"],` +
+	`"trace_node_fields":["id","function_info_index","count","size","children"],` +
+	`"sample_fields":["timestamp_us","last_assigned_id"],` +
+	`"location_fields":["object_index","script_id","line","column"]}`
+
+const (
+	heapNodeFieldCount = 7
+
+	// strings longer than this are truncated in node names
+	heapMaxStringName = 1024
+)
+
+type heapEdge struct {
+	typ  int
+	name int // string index, or element index for element and hidden edges
+	to   int // node index
+}
+
+type heapNode struct {
+	typ      int
+	name     int
+	id       uint64
+	selfSize uint64
+	edges    []heapEdge
+}
+
+type heapSnapshot struct {
+	r *Runtime
+
+	nodes     []heapNode
+	strings   []string
+	stringIdx map[string]int
+
+	objects    map[*Object]int
+	stashes    map[*stash]int
+	strValues  map[string]int
+	symbols    map[*Symbol]int
+	pendingObj []*Object
+	pendingSt  []*stash
+
+	lastID uint64
+}
+
+type heapBaser interface {
+	heapBase() *baseObject
+}
+
+func (o *baseObject) heapBase() *baseObject {
+	return o
+}
+
+// WriteHeapSnapshot writes a snapshot of the objects reachable from the global object, the global lexical scope,
+// the current scope and the stack (if called while a script is running, e.g. from a Go function), and the pending
+// promise jobs. The output is in the V8 heap snapshot format (.heapsnapshot) that can be loaded into the Memory tab
+// of Chrome DevTools.
+//
+// The graph includes the properties of objects, the variables captured by closures, the entries of Maps and Sets,
+// the values associated with WeakMap keys, promise results and reactions, and bound function and proxy targets.
+// The sizes are approximate (see SetMemoryLimit()). Primitive values other than strings and symbols are not
+// included, neither are the internals of host (Go) objects.
+//
+// The variables of a scope are only named if the code has been compiled with a Debugger attached (see
+// AttachDebugger()), otherwise they appear as indexed hidden edges.
+//
+// The identifiers of objects are stable across snapshots of the same Runtime, so snapshots can be compared.
+func (r *Runtime) WriteHeapSnapshot(w io.Writer) error {
+	hs := &heapSnapshot{
+		r:         r,
+		stringIdx: make(map[string]int),
+		objects:   make(map[*Object]int),
+		stashes:   make(map[*stash]int),
+		strValues: make(map[string]int),
+		symbols:   make(map[*Symbol]int),
+	}
+	hs.build()
+	return hs.write(w)
+}
+
+func (hs *heapSnapshot) str(s string) int {
+	if idx, exists := hs.stringIdx[s]; exists {
+		return idx
+	}
+	idx := len(hs.strings)
+	hs.strings = append(hs.strings, s)
+	hs.stringIdx[s] = idx
+	return idx
+}
+
+func (hs *heapSnapshot) nextID() uint64 {
+	// even numbers, the odd ones are used for objects
+	hs.lastID += 2
+	return hs.lastID
+}
+
+func (hs *heapSnapshot) addNode(typ int, name string, id, size uint64) int {
+	idx := len(hs.nodes)
+	hs.nodes = append(hs.nodes, heapNode{
+		typ:      typ,
+		name:     hs.str(name),
+		id:       id,
+		selfSize: size,
+	})
+	return idx
+}
+
+func (hs *heapSnapshot) addEdge(from, typ int, name string, to int) {
+	hs.nodes[from].edges = append(hs.nodes[from].edges, heapEdge{typ: typ, name: hs.str(name), to: to})
+}
+
+func (hs *heapSnapshot) addIndexedEdge(from, typ, idx, to int) {
+	hs.nodes[from].edges = append(hs.nodes[from].edges, heapEdge{typ: typ, name: idx, to: to})
+}
+
+// valueNode returns the index of the node for the value or -1 if the value is not represented in the snapshot.
+func (hs *heapSnapshot) valueNode(v Value) int {
+	switch v := v.(type) {
+	case *Object:
+		return hs.objectNode(v)
+	case *Symbol:
+		if idx, exists := hs.symbols[v]; exists {
+			return idx
+		}
+		idx := hs.addNode(heapNodeSymbol, v.descriptiveString().String(), hs.nextID(), memSizeValue)
+		hs.symbols[v] = idx
+		return idx
+	case String:
+		s := v.String()
+		if idx, exists := hs.strValues[s]; exists {
+			return idx
+		}
+		name := s
+		if len(name) > heapMaxStringName {
+			name = name[:heapMaxStringName]
+		}
+		idx := hs.addNode(heapNodeString, name, hs.nextID(), memSizeString(v))
+		hs.strValues[s] = idx
+		return idx
+	}
+	return -1
+}
+
+func (hs *heapSnapshot) edgeToValue(from, typ int, name string, v Value) {
+	if to := hs.valueNode(v); to >= 0 {
+		hs.addEdge(from, typ, name, to)
+	}
+}
+
+func (hs *heapSnapshot) indexedEdgeToValue(from, typ, idx int, v Value) {
+	if to := hs.valueNode(v); to >= 0 {
+		hs.addIndexedEdge(from, typ, idx, to)
+	}
+}
+
+func (hs *heapSnapshot) objectNode(o *Object) int {
+	if idx, exists := hs.objects[o]; exists {
+		return idx
+	}
+	typ, name := hs.describe(o)
+	idx := hs.addNode(typ, name, (o.getId()&(1<<51-1))<<1|1, memSizeObject)
+	hs.objects[o] = idx
+	hs.pendingObj = append(hs.pendingObj, o)
+	return idx
+}
+
+func (hs *heapSnapshot) stashNode(s *stash) int {
+	if idx, exists := hs.stashes[s]; exists {
+		return idx
+	}
+	idx := hs.addNode(heapNodeObject, "system / Context", hs.nextID(), memSizeObject+memSizeN(len(s.values), memSizeValue))
+	hs.stashes[s] = idx
+	hs.pendingSt = append(hs.pendingSt, s)
+	return idx
+}
+
+// dataPropStr returns the value of an own data property without invoking any getters.
+func dataPropStr(o *Object, name unistring.String) Value {
+	b, ok := o.self.(heapBaser)
+	if !ok {
+		return nil
+	}
+	v := b.heapBase().values[name]
+	if prop, ok := v.(*valueProperty); ok {
+		if prop.accessor {
+			return nil
+		}
+		return prop.value
+	}
+	return v
+}
+
+func (hs *heapSnapshot) describe(o *Object) (typ int, name string) {
+	if o == hs.r.globalObject {
+		return heapNodeObject, "global"
+	}
+	if _, ok := o.self.assertCallable(); ok {
+		name := ""
+		if n, ok := dataPropStr(o, "name").(String); ok {
+			name = n.String()
+		}
+		return heapNodeClosure, name
+	}
+	switch self := o.self.(type) {
+	case *regexpObject:
+		return heapNodeRegexp, self.source.String()
+	case *arrayObject, *sparseArrayObject:
+		return heapNodeObject, "Array"
+	}
+	if b, ok := o.self.(heapBaser); ok {
+		if proto := b.heapBase().prototype; proto != nil {
+			if ctor, ok := dataPropStr(proto, "constructor").(*Object); ok {
+				if n, ok := dataPropStr(ctor, "name").(String); ok && n.Length() > 0 {
+					return heapNodeObject, n.String()
+				}
+			}
+		}
+	}
+	return heapNodeObject, o.self.className()
+}
+
+func (hs *heapSnapshot) build() {
+	r := hs.r
+	vm := r.vm
+	root := hs.addNode(heapNodeSynthetic, "", hs.nextID(), 0)
+	gcRoots := hs.addNode(heapNodeSynthetic, "(GC roots)", hs.nextID(), 0)
+	hs.addIndexedEdge(root, heapEdgeElement, 1, gcRoots)
+	hs.addEdge(root, heapEdgeShortcut, "global", hs.objectNode(r.globalObject))
+
+	hs.addEdge(gcRoots, heapEdgeInternal, "global", hs.objectNode(r.globalObject))
+	hs.addEdge(gcRoots, heapEdgeInternal, "script_context", hs.stashNode(&r.global.stash))
+	if vm.stash != nil {
+		hs.addEdge(gcRoots, heapEdgeInternal, "current_context", hs.stashNode(vm.stash))
+	}
+	if vm.sp > 0 {
+		stack := hs.addNode(heapNodeSynthetic, "(Stack roots)", hs.nextID(), 0)
+		hs.addEdge(gcRoots, heapEdgeInternal, "stack", stack)
+		for i, v := range vm.stack[:vm.sp] {
+			hs.indexedEdgeToValue(stack, heapEdgeElement, i, v)
+		}
+		for i := range vm.callStack {
+			if s := vm.callStack[i].stash; s != nil {
+				hs.addIndexedEdge(stack, heapEdgeHidden, i, hs.stashNode(s))
+			}
+		}
+	}
+	if len(r.jobQueue) > 0 {
+		jobs := hs.addNode(heapNodeSynthetic, "(Job queue)", hs.nextID(), 0)
+		hs.addEdge(gcRoots, heapEdgeInternal, "jobs", jobs)
+		for i := range r.jobQueue {
+			node := hs.addNode(heapNodeHidden, "system / PromiseJob", hs.nextID(), memSizeObject)
+			hs.addIndexedEdge(jobs, heapEdgeElement, i, node)
+			for j, v := range r.jobQueue[i].values() {
+				hs.indexedEdgeToValue(node, heapEdgeElement, j, v)
+			}
+		}
+	}
+
+	for len(hs.pendingObj) > 0 || len(hs.pendingSt) > 0 {
+		if n := len(hs.pendingObj); n > 0 {
+			o := hs.pendingObj[n-1]
+			hs.pendingObj = hs.pendingObj[:n-1]
+			hs.expandObject(o)
+			continue
+		}
+		n := len(hs.pendingSt)
+		s := hs.pendingSt[n-1]
+		hs.pendingSt = hs.pendingSt[:n-1]
+		hs.expandStash(s)
+	}
+}
+
+func (hs *heapSnapshot) expandStash(s *stash) {
+	idx := hs.stashes[s]
+	names := make([]unistring.String, len(s.values))
+	copy(names, s.slotNames)
+	for name, i := range s.names {
+		i &^= maskTyp
+		if name != thisBindingName && int(i) < len(names) {
+			names[i] = name
+		}
+	}
+	for i, v := range s.values {
+		if names[i] != "" {
+			hs.edgeToValue(idx, heapEdgeContext, names[i].String(), v)
+		} else {
+			hs.indexedEdgeToValue(idx, heapEdgeHidden, i, v)
+		}
+	}
+	for i, v := range s.extraArgs {
+		hs.indexedEdgeToValue(idx, heapEdgeHidden, len(s.values)+i, v)
+	}
+	if s.obj != nil {
+		hs.addEdge(idx, heapEdgeInternal, "extension", hs.objectNode(s.obj))
+	}
+	if s.outer != nil {
+		hs.addEdge(idx, heapEdgeInternal, "previous", hs.stashNode(s.outer))
+	}
+}
+
+func (hs *heapSnapshot) propEdges(idx int, name string, v Value) {
+	if prop, ok := v.(*valueProperty); ok {
+		if prop.accessor {
+			if prop.getterFunc != nil {
+				hs.addEdge(idx, heapEdgeProperty, "get "+name, hs.objectNode(prop.getterFunc))
+			}
+			if prop.setterFunc != nil {
+				hs.addEdge(idx, heapEdgeProperty, "set "+name, hs.objectNode(prop.setterFunc))
+			}
+			return
+		}
+		v = prop.value
+	}
+	hs.edgeToValue(idx, heapEdgeProperty, name, v)
+}
+
+// orderedMapEntries adds a hidden array node with the entries of the map: keys and values for Maps, keys only for
+// Sets.
+func (hs *heapSnapshot) orderedMapEntries(from int, name string, m *orderedMap, withValues bool) {
+	if m == nil {
+		return
+	}
+	table := hs.addNode(heapNodeArray, name, hs.nextID(), memSizeN(m.size, memSizeProperty))
+	hs.addEdge(from, heapEdgeInternal, "table", table)
+	i := 0
+	for e := m.iterFirst; e != nil; e = e.iterNext {
+		if e.key == nil {
+			// deleted
+			continue
+		}
+		hs.indexedEdgeToValue(table, heapEdgeElement, i, e.key)
+		i++
+		if withValues {
+			hs.indexedEdgeToValue(table, heapEdgeElement, i, e.value)
+			i++
+		}
+	}
+}
+
+func (hs *heapSnapshot) expandObject(o *Object) {
+	idx := hs.objects[o]
+	node := &hs.nodes[idx]
+
+	if b, ok := o.self.(heapBaser); ok {
+		base := b.heapBase()
+		node.selfSize += memSizeN(len(base.propNames), memSizeProperty)
+		if base.prototype != nil {
+			hs.addEdge(idx, heapEdgeProperty, "__proto__", hs.objectNode(base.prototype))
+		}
+		for _, name := range base.propNames {
+			hs.propEdges(idx, name.String(), base.values[name])
+		}
+		if base.symValues != nil {
+			for e := base.symValues.iterFirst; e != nil; e = e.iterNext {
+				if sym, ok := e.key.(*Symbol); ok {
+					hs.propEdges(idx, sym.descriptiveString().String(), e.value)
+				}
+			}
+		}
+		i := 0
+		for _, elements := range base.privateElements {
+			for _, v := range elements.fields {
+				hs.indexedEdgeToValue(idx, heapEdgeHidden, i, v)
+				i++
+			}
+		}
+	}
+
+	for _, v := range o.weakRefs {
+		hs.edgeToValue(idx, heapEdgeInternal, "part of key -> value pair in WeakMap", v)
+	}
+
+	switch self := o.self.(type) {
+	case *arrayObject:
+		hs.nodes[idx].selfSize += memSizeN(len(self.values), memSizeValue)
+		for i, v := range self.values {
+			hs.indexedEdgeToValue(idx, heapEdgeElement, i, v)
+		}
+	case *sparseArrayObject:
+		hs.nodes[idx].selfSize += memSizeN(len(self.items), memSizeSparseItem)
+		for _, item := range self.items {
+			hs.indexedEdgeToValue(idx, heapEdgeElement, int(item.idx), item.value)
+		}
+	case *mapObject:
+		hs.orderedMapEntries(idx, "system / OrderedHashMap", self.m, true)
+	case *setObject:
+		hs.orderedMapEntries(idx, "system / OrderedHashSet", self.m, false)
+	case *Promise:
+		if self.result != nil {
+			hs.edgeToValue(idx, heapEdgeInternal, "result", self.result)
+		}
+		if len(self.fulfillReactions) > 0 {
+			reactions := hs.addNode(heapNodeArray, "system / PromiseReactions", hs.nextID(),
+				memSizeN(len(self.fulfillReactions)+len(self.rejectReactions), memSizeObject))
+			hs.addEdge(idx, heapEdgeInternal, "reactions", reactions)
+			i := 0
+			for _, list := range [][]*promiseReaction{self.fulfillReactions, self.rejectReactions} {
+				for _, reaction := range list {
+					for _, v := range reaction.values() {
+						hs.indexedEdgeToValue(reactions, heapEdgeElement, i, v)
+						i++
+					}
+				}
+			}
+		}
+	case *proxyObject:
+		if self.target != nil {
+			hs.addEdge(idx, heapEdgeInternal, "target", hs.objectNode(self.target))
+		}
+		if h, ok := self.handler.(*jsProxyHandler); ok && h.handler != nil {
+			hs.addEdge(idx, heapEdgeInternal, "handler", hs.objectNode(h.handler))
+		}
+	case *boundFuncObject:
+		if self.wrapped != nil {
+			hs.addEdge(idx, heapEdgeInternal, "bound_function", hs.objectNode(self.wrapped))
+		}
+	case *arrayBufferObject:
+		hs.nodes[idx].selfSize += uint64(len(self.data))
+	case *typedArrayObject:
+		if self.viewedArrayBuf != nil {
+			hs.addEdge(idx, heapEdgeInternal, "buffer", hs.objectNode(self.viewedArrayBuf.val))
+		}
+	case *dataViewObject:
+		if self.viewedArrayBuf != nil {
+			hs.addEdge(idx, heapEdgeInternal, "buffer", hs.objectNode(self.viewedArrayBuf.val))
+		}
+	case *stringObject:
+		hs.edgeToValue(idx, heapEdgeInternal, "value", self.value)
+	case *primitiveValueObject:
+		hs.edgeToValue(idx, heapEdgeInternal, "value", self.pValue)
+	case *methodFuncObject:
+		if self.homeObject != nil {
+			hs.addEdge(idx, heapEdgeInternal, "home_object", hs.objectNode(self.homeObject))
+		}
+	}
+
+	if c, ok := o.self.(debugClosure); ok {
+		if s := c.closureStash(); s != nil {
+			hs.addEdge(idx, heapEdgeInternal, "context", hs.stashNode(s))
+		}
+	}
+}
+
+func (hs *heapSnapshot) write(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	edgeCount := 0
+	for i := range hs.nodes {
+		edgeCount += len(hs.nodes[i].edges)
+	}
+	bw.WriteString(`{"snapshot":{"meta":`)
+	bw.WriteString(heapSnapshotMeta)
+	bw.WriteString(`,"node_count":`)
+	bw.WriteString(strconv.Itoa(len(hs.nodes)))
+	bw.WriteString(`,"edge_count":`)
+	bw.WriteString(strconv.Itoa(edgeCount))
+	bw.WriteString(`,"trace_function_count":0},` + "\n" + `"nodes":[`)
+	var buf []byte
+	for i, n := range hs.nodes {
+		buf = buf[:0]
+		if i > 0 {
+			buf = append(buf, ",\n"...)
+		}
+		buf = strconv.AppendInt(buf, int64(n.typ), 10)
+		buf = append(buf, ',')
+		buf = strconv.AppendInt(buf, int64(n.name), 10)
+		buf = append(buf, ',')
+		buf = strconv.AppendUint(buf, n.id, 10)
+		buf = append(buf, ',')
+		buf = strconv.AppendUint(buf, n.selfSize, 10)
+		buf = append(buf, ',')
+		buf = strconv.AppendInt(buf, int64(len(n.edges)), 10)
+		buf = append(buf, ",0,0"...)
+		bw.Write(buf)
+	}
+	bw.WriteString("],\n" + `"edges":[`)
+	first := true
+	for _, n := range hs.nodes {
+		for _, e := range n.edges {
+			buf = buf[:0]
+			if !first {
+				buf = append(buf, ",\n"...)
+			}
+			first = false
+			buf = strconv.AppendInt(buf, int64(e.typ), 10)
+			buf = append(buf, ',')
+			buf = strconv.AppendInt(buf, int64(e.name), 10)
+			buf = append(buf, ',')
+			buf = strconv.AppendInt(buf, int64(e.to*heapNodeFieldCount), 10)
+			bw.Write(buf)
+		}
+	}
+	bw.WriteString("],\n" + `"trace_function_infos":[],"trace_tree":[],"samples":[],"locations":[],` + "\n" + `"strings":[`)
+	for i, s := range hs.strings {
+		if i > 0 {
+			bw.WriteString(",\n")
+		}
+		b, err := json.Marshal(s)
+		if err != nil {
+			return err
+		}
+		bw.Write(b)
+	}
+	bw.WriteString("]}\n")
+	return bw.Flush()
+}
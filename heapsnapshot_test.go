@@ -0,0 +1,225 @@
+package goja
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+)
+
+type testHeapSnapshot struct {
+	Snapshot struct {
+		NodeCount int `json:"node_count"`
+		EdgeCount int `json:"edge_count"`
+	} `json:"snapshot"`
+	Nodes   []int    `json:"nodes"`
+	Edges   []int    `json:"edges"`
+	Strings []string `json:"strings"`
+
+	firstEdge []int
+}
+
+type testHeapEdge struct {
+	typ  int
+	name string
+	to   int
+}
+
+func (s *testHeapSnapshot) init(t *testing.T) {
+	if len(s.Nodes) != s.Snapshot.NodeCount*heapNodeFieldCount || len(s.Edges) != s.Snapshot.EdgeCount*3 {
+		t.Fatalf("Inconsistent counts: %d nodes, %d edges", len(s.Nodes), len(s.Edges))
+	}
+	s.firstEdge = make([]int, s.Snapshot.NodeCount+1)
+	for i := 0; i < s.Snapshot.NodeCount; i++ {
+		s.firstEdge[i+1] = s.firstEdge[i] + s.Nodes[i*heapNodeFieldCount+4]
+	}
+	if s.firstEdge[s.Snapshot.NodeCount] != s.Snapshot.EdgeCount {
+		t.Fatal("The sum of edge counts does not match edge_count")
+	}
+}
+
+func (s *testHeapSnapshot) name(node int) string {
+	return s.Strings[s.Nodes[node*heapNodeFieldCount+1]]
+}
+
+func (s *testHeapSnapshot) typ(node int) int {
+	return s.Nodes[node*heapNodeFieldCount]
+}
+
+func (s *testHeapSnapshot) edges(node int) []testHeapEdge {
+	var res []testHeapEdge
+	for i := s.firstEdge[node]; i < s.firstEdge[node+1]; i++ {
+		e := testHeapEdge{
+			typ: s.Edges[i*3],
+			to:  s.Edges[i*3+2] / heapNodeFieldCount,
+		}
+		if e.typ == heapEdgeElement || e.typ == heapEdgeHidden {
+			e.name = "#"
+		} else {
+			e.name = s.Strings[s.Edges[i*3+1]]
+		}
+		res = append(res, e)
+	}
+	return res
+}
+
+func (s *testHeapSnapshot) find(typ int, name string) int {
+	for i := 0; i < s.Snapshot.NodeCount; i++ {
+		if s.typ(i) == typ && s.name(i) == name {
+			return i
+		}
+	}
+	return -1
+}
+
+func (s *testHeapSnapshot) edge(node int, typ int, name string) int {
+	for _, e := range s.edges(node) {
+		if e.typ == typ && e.name == name {
+			return e.to
+		}
+	}
+	return -1
+}
+
+func takeHeapSnapshot(t *testing.T, r *Runtime) *testHeapSnapshot {
+	var buf bytes.Buffer
+	if err := r.WriteHeapSnapshot(&buf); err != nil {
+		t.Fatal(err)
+	}
+	var s testHeapSnapshot
+	if err := json.Unmarshal(buf.Bytes(), &s); err != nil {
+		t.Fatal(err)
+	}
+	s.init(t)
+	return &s
+}
+
+func TestHeapSnapshot(t *testing.T) {
+	const SCRIPT = `
+	class Leaky {
+		constructor() {
+			this.payload = "leaked payload";
+		}
+	}
+	function makeHolder() {
+		const captured = new Leaky();
+		return function holder() {
+			return captured;
+		};
+	}
+	var holder = makeHolder();
+	var m = new Map([[{mapKey: 1}, new Leaky()]]);
+	var wm = new WeakMap();
+	var wmKey = {};
+	wm.set(wmKey, {weakValue: true});
+	var pending = new Promise(() => {});
+	pending.then(function onPending() {});
+	var arr = [1, "element", Symbol("sym")];
+	`
+	r := New()
+	_, err := r.RunString(SCRIPT)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := takeHeapSnapshot(t, r)
+
+	global := s.edge(0, heapEdgeShortcut, "global")
+	if global < 0 || s.name(global) != "global" {
+		t.Fatal("No global object")
+	}
+
+	holder := s.edge(global, heapEdgeProperty, "holder")
+	if holder < 0 || s.typ(holder) != heapNodeClosure || s.name(holder) != "holder" {
+		t.Fatal("No holder closure")
+	}
+	ctx := s.edge(holder, heapEdgeInternal, "context")
+	if ctx < 0 || s.name(ctx) != "system / Context" {
+		t.Fatal("No closure context")
+	}
+	leaky := s.edge(ctx, heapEdgeContext, "captured")
+	if leaky < 0 {
+		t.Fatalf("No context edge for the captured variable: %v", s.edges(ctx))
+	}
+	if s.name(leaky) != "Leaky" {
+		t.Fatalf("Unexpected captured object: %s", s.name(leaky))
+	}
+	if p := s.edge(leaky, heapEdgeProperty, "payload"); p < 0 || s.typ(p) != heapNodeString || s.name(p) != "leaked payload" {
+		t.Fatal("No payload")
+	}
+
+	m := s.edge(global, heapEdgeProperty, "m")
+	table := s.edge(m, heapEdgeInternal, "table")
+	if table < 0 {
+		t.Fatal("No Map table")
+	}
+	entries := s.edges(table)
+	if len(entries) != 2 || s.name(entries[0].to) != "Object" || s.name(entries[1].to) != "Leaky" {
+		t.Fatalf("Unexpected Map entries: %v", entries)
+	}
+
+	wmKey := s.edge(global, heapEdgeProperty, "wmKey")
+	if v := s.edge(wmKey, heapEdgeInternal, "part of key -> value pair in WeakMap"); v < 0 || s.name(v) != "Object" {
+		t.Fatal("No WeakMap value")
+	}
+
+	pending := s.edge(global, heapEdgeProperty, "pending")
+	reactions := s.edge(pending, heapEdgeInternal, "reactions")
+	if reactions < 0 {
+		t.Fatal("No promise reactions")
+	}
+	found := false
+	for _, e := range s.edges(reactions) {
+		if s.name(e.to) == "onPending" {
+			found = true
+		}
+	}
+	if !found {
+		t.Fatal("The reaction handler is missing")
+	}
+
+	arr := s.edge(global, heapEdgeProperty, "arr")
+	var elems []string
+	for _, e := range s.edges(arr) {
+		if e.typ == heapEdgeElement {
+			elems = append(elems, s.name(e.to))
+		}
+	}
+	if len(elems) != 2 || elems[0] != "element" || elems[1] != "Symbol(sym)" {
+		t.Fatalf("Unexpected array elements: %v", elems)
+	}
+	if proto := s.edge(arr, heapEdgeProperty, "__proto__"); proto < 0 {
+		t.Fatal("No __proto__")
+	}
+
+	s1 := takeHeapSnapshot(t, r)
+	holder1 := s1.edge(s1.edge(0, heapEdgeShortcut, "global"), heapEdgeProperty, "holder")
+	if s1.Nodes[holder1*heapNodeFieldCount+2] != s.Nodes[holder*heapNodeFieldCount+2] {
+		t.Fatal("Object ids are not stable")
+	}
+}
+
+func TestHeapSnapshotJobQueue(t *testing.T) {
+	r := New()
+	var s *testHeapSnapshot
+	r.Set("snapshot", func() {
+		s = takeHeapSnapshot(t, r)
+	})
+	_, err := r.RunString(`
+	Promise.resolve({queued: true}).then(function queuedHandler() {});
+	snapshot();
+	`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	jobs := s.find(heapNodeSynthetic, "(Job queue)")
+	if jobs < 0 {
+		t.Fatal("No job queue")
+	}
+	job := s.edges(jobs)[0].to
+	var names []string
+	for _, e := range s.edges(job) {
+		names = append(names, s.name(e.to))
+	}
+	if len(names) == 0 || names[0] != "queuedHandler" {
+		t.Fatalf("Unexpected job references: %v", names)
+	}
+}
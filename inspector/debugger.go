@@ -0,0 +1,358 @@
+package inspector
+
+import (
+	"encoding/json"
+	"regexp"
+
+	"github.com/dop251/goja"
+)
+
+type location struct {
+	ScriptID     string `json:"scriptId"`
+	LineNumber   int    `json:"lineNumber"`
+	ColumnNumber int    `json:"columnNumber"`
+}
+
+type scope struct {
+	Type   string        `json:"type"`
+	Object *remoteObject `json:"object"`
+}
+
+type debugCallFrame struct {
+	CallFrameID  string        `json:"callFrameId"`
+	FunctionName string        `json:"functionName"`
+	Location     location      `json:"location"`
+	URL          string        `json:"url"`
+	ScopeChain   []scope       `json:"scopeChain"`
+	This         *remoteObject `json:"this"`
+}
+
+func init() {
+	register("Debugger.enable", debuggerEnable)
+	register("Debugger.disable", func(s *session, _ json.RawMessage) (interface{}, error) {
+		s.mu.Lock()
+		s.debuggerEnabled = false
+		s.mu.Unlock()
+		s.i.resume(goja.DebugContinue)
+		return nil, nil
+	})
+	register("Debugger.setBreakpointByUrl", debuggerSetBreakpointByURL)
+	register("Debugger.setBreakpoint", debuggerSetBreakpoint)
+	register("Debugger.removeBreakpoint", debuggerRemoveBreakpoint)
+	register("Debugger.getPossibleBreakpoints", debuggerGetPossibleBreakpoints)
+	register("Debugger.resume", resumeWith(goja.DebugContinue))
+	register("Debugger.stepOver", resumeWith(goja.DebugStepOver))
+	register("Debugger.stepInto", resumeWith(goja.DebugStepIn))
+	register("Debugger.stepOut", resumeWith(goja.DebugStepOut))
+	register("Debugger.pause", func(s *session, _ json.RawMessage) (interface{}, error) {
+		s.i.dbg.Pause()
+		return nil, nil
+	})
+	register("Debugger.setPauseOnExceptions", debuggerSetPauseOnExceptions)
+	registerAsync("Debugger.evaluateOnCallFrame", debuggerEvaluateOnCallFrame)
+	register("Debugger.getScriptSource", debuggerGetScriptSource)
+	register("Debugger.setAsyncCallStackDepth", noop)
+	register("Debugger.setBlackboxPatterns", noop)
+	register("Debugger.setBreakpointsActive", noop)
+	register("Debugger.setSkipAllPauses", noop)
+}
+
+func debuggerEnable(s *session, _ json.RawMessage) (interface{}, error) {
+	s.mu.Lock()
+	s.debuggerEnabled = true
+	s.mu.Unlock()
+	for _, sc := range s.i.allScripts() {
+		s.scriptParsed(sc)
+	}
+	return map[string]string{"debuggerId": s.i.id}, nil
+}
+
+func (s *session) scriptParsed(sc *script) {
+	s.event("Debugger.scriptParsed", map[string]interface{}{
+		"scriptId":           sc.id,
+		"url":                sc.url,
+		"startLine":          0,
+		"startColumn":        0,
+		"endLine":            sc.lines,
+		"endColumn":          0,
+		"executionContextId": executionContextID,
+		"hash":               "",
+	})
+}
+
+// setBreakpoint sets a goja breakpoint (lines and columns are 0-based) and associates it with the protocol
+// breakpoint id.
+func (s *session) setBreakpoint(id, url string, line, column int, condition string) *goja.Breakpoint {
+	b := s.i.dbg.SetBreakpoint(url, line+1, column+1, condition)
+	s.mu.Lock()
+	s.breakpoints[id] = append(s.breakpoints[id], b)
+	s.bpIDs[b] = id
+	s.mu.Unlock()
+	return b
+}
+
+func (s *session) breakpointLocation(b *goja.Breakpoint, sc *script) location {
+	loc := location{
+		ScriptID:   sc.id,
+		LineNumber: b.Line() - 1,
+	}
+	if b.Column() > 0 {
+		loc.ColumnNumber = b.Column() - 1
+	}
+	return loc
+}
+
+func debuggerSetBreakpointByURL(s *session, params json.RawMessage) (interface{}, error) {
+	var p struct {
+		LineNumber   int    `json:"lineNumber"`
+		URL          string `json:"url"`
+		URLRegex     string `json:"urlRegex"`
+		ColumnNumber int    `json:"columnNumber"`
+		Condition    string `json:"condition"`
+	}
+	if err := unmarshalParams(params, &p); err != nil {
+		return nil, err
+	}
+	var urls []string
+	var id string
+	switch {
+	case p.URL != "":
+		urls = append(urls, p.URL)
+		id = "1:" + itoa(p.LineNumber) + ":" + itoa(p.ColumnNumber) + ":" + p.URL
+	case p.URLRegex != "":
+		re, err := regexp.Compile(p.URLRegex)
+		if err != nil {
+			return nil, newProtocolError("Incorrect url regex: %v", err)
+		}
+		for _, sc := range s.i.allScripts() {
+			if re.MatchString(sc.url) {
+				urls = append(urls, sc.url)
+			}
+		}
+		id = "2:" + itoa(p.LineNumber) + ":" + itoa(p.ColumnNumber) + ":" + p.URLRegex
+	default:
+		return nil, newProtocolError("Either url or urlRegex must be specified.")
+	}
+	s.mu.Lock()
+	_, exists := s.breakpoints[id]
+	s.mu.Unlock()
+	if exists {
+		return nil, newProtocolError("Breakpoint at specified location already exists.")
+	}
+	locations := []location{}
+	for _, url := range urls {
+		b := s.setBreakpoint(id, url, p.LineNumber, p.ColumnNumber, p.Condition)
+		if sc := s.i.scriptByURL(url); sc != nil {
+			locations = append(locations, s.breakpointLocation(b, sc))
+		}
+	}
+	if len(urls) == 0 {
+		s.mu.Lock()
+		s.breakpoints[id] = nil
+		s.mu.Unlock()
+	}
+	return map[string]interface{}{
+		"breakpointId": id,
+		"locations":    locations,
+	}, nil
+}
+
+func debuggerSetBreakpoint(s *session, params json.RawMessage) (interface{}, error) {
+	var p struct {
+		Location  location `json:"location"`
+		Condition string   `json:"condition"`
+	}
+	if err := unmarshalParams(params, &p); err != nil {
+		return nil, err
+	}
+	sc := s.i.scriptByID(p.Location.ScriptID)
+	if sc == nil {
+		return nil, newProtocolError("Script not found")
+	}
+	id := p.Location.ScriptID + ":" + itoa(p.Location.LineNumber) + ":" + itoa(p.Location.ColumnNumber)
+	s.mu.Lock()
+	_, exists := s.breakpoints[id]
+	s.mu.Unlock()
+	if exists {
+		return nil, newProtocolError("Breakpoint at specified location already exists.")
+	}
+	b := s.setBreakpoint(id, sc.url, p.Location.LineNumber, p.Location.ColumnNumber, p.Condition)
+	return map[string]interface{}{
+		"breakpointId":   id,
+		"actualLocation": s.breakpointLocation(b, sc),
+	}, nil
+}
+
+func debuggerRemoveBreakpoint(s *session, params json.RawMessage) (interface{}, error) {
+	var p struct {
+		BreakpointID string `json:"breakpointId"`
+	}
+	if err := unmarshalParams(params, &p); err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	bps := s.breakpoints[p.BreakpointID]
+	delete(s.breakpoints, p.BreakpointID)
+	for _, b := range bps {
+		delete(s.bpIDs, b)
+	}
+	s.mu.Unlock()
+	for _, b := range bps {
+		s.i.dbg.RemoveBreakpoint(b)
+	}
+	return nil, nil
+}
+
+func debuggerGetPossibleBreakpoints(s *session, params json.RawMessage) (interface{}, error) {
+	var p struct {
+		Start location `json:"start"`
+	}
+	if err := unmarshalParams(params, &p); err != nil {
+		return nil, err
+	}
+	// The exact positions are not known until the code is compiled, any position on a line is accepted.
+	return map[string]interface{}{
+		"locations": []location{p.Start},
+	}, nil
+}
+
+func resumeWith(cmd goja.DebugCommand) methodHandler {
+	return func(s *session, _ json.RawMessage) (interface{}, error) {
+		s.i.resume(cmd)
+		return nil, nil
+	}
+}
+
+func debuggerSetPauseOnExceptions(s *session, params json.RawMessage) (interface{}, error) {
+	var p struct {
+		State string `json:"state"`
+	}
+	if err := unmarshalParams(params, &p); err != nil {
+		return nil, err
+	}
+	switch p.State {
+	case "none":
+		s.i.dbg.SetPauseOnExceptions(goja.PauseOnNoExceptions)
+	case "uncaught":
+		s.i.dbg.SetPauseOnExceptions(goja.PauseOnUncaughtExceptions)
+	case "all", "caught":
+		s.i.dbg.SetPauseOnExceptions(goja.PauseOnAllExceptions)
+	default:
+		return nil, &protocolError{code: errCodeInvalidParams, msg: "Unknown pause on exceptions mode: " + p.State}
+	}
+	return nil, nil
+}
+
+func debuggerEvaluateOnCallFrame(s *session, params json.RawMessage) (interface{}, error) {
+	var p struct {
+		CallFrameID   string `json:"callFrameId"`
+		Expression    string `json:"expression"`
+		ObjectGroup   string `json:"objectGroup"`
+		ReturnByValue bool   `json:"returnByValue"`
+	}
+	if err := unmarshalParams(params, &p); err != nil {
+		return nil, err
+	}
+	f, ok := s.object(p.CallFrameID).(*goja.DebugFrame)
+	if !ok {
+		return nil, newProtocolError("Could not find call frame with given id")
+	}
+	var res *evaluateResult
+	err := s.i.exec(func(pause *goja.DebugPause) {
+		v, err := f.Eval(p.Expression)
+		res = s.evalResult(v, err, p.ObjectGroup, p.ReturnByValue)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func debuggerGetScriptSource(s *session, params json.RawMessage) (interface{}, error) {
+	var p struct {
+		ScriptID string `json:"scriptId"`
+	}
+	if err := unmarshalParams(params, &p); err != nil {
+		return nil, err
+	}
+	sc := s.i.scriptByID(p.ScriptID)
+	if sc == nil {
+		return nil, newProtocolError("No script for id: %s", p.ScriptID)
+	}
+	return map[string]string{"scriptSource": sc.source}, nil
+}
+
+func scopeType(t goja.DebugScopeType) string {
+	switch t {
+	case goja.DebugScopeLocal:
+		return "local"
+	case goja.DebugScopeBlock:
+		return "block"
+	case goja.DebugScopeClosure:
+		return "closure"
+	case goja.DebugScopeWith:
+		return "with"
+	case goja.DebugScopeScript:
+		return "script"
+	}
+	return "global"
+}
+
+// pausedParams builds the parameters of the Debugger.paused event. It must be called in the goroutine that owns
+// the Runtime.
+func (s *session) pausedParams(p *goja.DebugPause) map[string]interface{} {
+	frames := []debugCallFrame{}
+	for _, f := range p.CallStack() {
+		if f.IsNative() {
+			continue
+		}
+		pos := f.Position()
+		url := pos.Filename
+		if url == "" {
+			url = f.SrcName()
+		}
+		cf := debugCallFrame{
+			CallFrameID:  s.addObject(backtraceGroup, f),
+			FunctionName: f.FuncName(),
+			Location: location{
+				ScriptID:     s.i.scriptFor(s, url).id,
+				LineNumber:   pos.Line - 1,
+				ColumnNumber: pos.Column - 1,
+			},
+			URL:  url,
+			This: s.remote(f.This(), backtraceGroup, false),
+		}
+		for _, sc := range f.Scopes() {
+			cf.ScopeChain = append(cf.ScopeChain, scope{
+				Type: scopeType(sc.Type()),
+				Object: &remoteObject{
+					Type:        "object",
+					ClassName:   "Object",
+					Description: "Object",
+					ObjectID:    s.addObject(backtraceGroup, sc),
+				},
+			})
+		}
+		frames = append(frames, cf)
+	}
+	params := map[string]interface{}{
+		"callFrames": frames,
+		"reason":     "other",
+	}
+	switch p.Reason() {
+	case goja.PauseException:
+		params["reason"] = "exception"
+		params["data"] = s.remote(p.Exception().Value(), backtraceGroup, false)
+	case goja.PauseBreakpoint:
+		var ids []string
+		s.mu.Lock()
+		for _, b := range p.Breakpoints() {
+			if id, exists := s.bpIDs[b]; exists {
+				ids = append(ids, id)
+			}
+		}
+		s.mu.Unlock()
+		params["hitBreakpoints"] = ids
+	}
+	return params
+}
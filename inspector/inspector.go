@@ -0,0 +1,386 @@
+/*
+Package inspector exposes a goja.Runtime over the Chrome DevTools Protocol, so that Chrome DevTools
+(chrome://inspect), VS Code or any other CDP client can be used to debug, profile and evaluate code in it.
+
+The Debugger, Runtime and Profiler domains are supported. The protocol is served on a WebSocket together with
+the HTTP endpoints used for target discovery (/json, /json/list and /json/version):
+
+	r := goja.New()
+	insp := inspector.New(r, inspector.Options{Name: "my script"})
+	go http.ListenAndServe("127.0.0.1:9229", insp)
+	insp.WaitForDebugger()
+	r.RunScript("script.js", src)
+
+Only one client can be connected at a time, a new connection replaces the existing one.
+
+The endpoint is not authenticated and gives full control over the Runtime, it should only be bound to a loopback
+interface. To protect against DNS rebinding, requests are rejected unless the Host header is localhost or an IP
+address, and WebSocket connections are rejected if they come from a web page other than the DevTools frontend.
+*/
+package inspector
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/dop251/goja"
+	"github.com/dop251/goja/file"
+)
+
+// Options configure an Inspector.
+type Options struct {
+	// Name is shown in the list of the debugging targets.
+	Name string
+
+	// Executor, if set, is used to run code in the Runtime when it is not paused, e.g. when an expression
+	// is entered in the DevTools console while the Runtime is idle. It must call the function in the goroutine
+	// that owns the Runtime (typically by submitting it to an event loop) and it may return before the function
+	// is called. If Executor is nil, such requests fail unless the Runtime is paused.
+	Executor func(func())
+}
+
+// Inspector serves the Chrome DevTools Protocol for a Runtime. It implements http.Handler.
+type Inspector struct {
+	r    *goja.Runtime
+	dbg  *goja.Debugger
+	prof *goja.Profiler
+	opts Options
+	id   string
+
+	getOwnPropertyNames      goja.Callable
+	getOwnPropertyDescriptor goja.Callable
+
+	mu      sync.Mutex
+	sess    *session
+	scripts map[string]*script
+	// scriptList contains the scripts in the order they have been parsed
+	scriptList []*script
+	lastScript int
+	// closed when the current pause ends, nil if the Runtime is not paused
+	pauseDone chan struct{}
+	waiting   chan struct{}
+
+	tasks chan task
+}
+
+type script struct {
+	id     string
+	url    string
+	source string
+	lines  int
+}
+
+// a task is run by the goroutine that owns the Runtime while it's paused
+type task func(p *goja.DebugPause) (cmd goja.DebugCommand, resume bool)
+
+var (
+	errBusy = errors.New("the runtime is busy")
+)
+
+// New creates an Inspector for the Runtime and attaches a Debugger to it (replacing the current one if any).
+// It also wraps the methods of the 'console' global object (creating it if it does not exist) so that the messages
+// are reported to the client, and sets a goja.Profiler for the Runtime unless it already has one.
+//
+// New must be called in the goroutine that owns the Runtime.
+func New(r *goja.Runtime, opts Options) *Inspector {
+	var b [16]byte
+	_, _ = rand.Read(b[:])
+	i := &Inspector{
+		r:       r,
+		opts:    opts,
+		id:      hex.EncodeToString(b[:]),
+		scripts: make(map[string]*script),
+		waiting: make(chan struct{}),
+		tasks:   make(chan task),
+	}
+	if i.opts.Name == "" {
+		i.opts.Name = "goja"
+	}
+	objectCtor := r.GlobalObject().Get("Object").(*goja.Object)
+	i.getOwnPropertyNames, _ = goja.AssertFunction(objectCtor.Get("getOwnPropertyNames"))
+	i.getOwnPropertyDescriptor, _ = goja.AssertFunction(objectCtor.Get("getOwnPropertyDescriptor"))
+	if r.Profiler() == nil {
+		r.SetProfiler(goja.NewProfiler())
+	}
+	i.prof = r.Profiler()
+	i.dbg = r.AttachDebugger(i.onPause)
+	i.dbg.SetScriptHandler(i.onScript)
+	i.wrapConsole()
+	return i
+}
+
+// ListenAndServe listens on the TCP network address addr and serves the protocol. It always returns
+// a non-nil error.
+func (i *Inspector) ListenAndServe(addr string) error {
+	return http.ListenAndServe(addr, i)
+}
+
+// WaitForDebugger blocks until a client connects and signals that it's ready (i.e. has set the breakpoints),
+// which allows debugging a script from its very beginning.
+func (i *Inspector) WaitForDebugger() {
+	<-i.waiting
+}
+
+// Close disconnects the current client (if any).
+func (i *Inspector) Close() {
+	i.mu.Lock()
+	s := i.sess
+	i.mu.Unlock()
+	if s != nil {
+		s.close()
+	}
+}
+
+type target struct {
+	Description          string `json:"description"`
+	DevtoolsFrontendUrl  string `json:"devtoolsFrontendUrl"`
+	ID                   string `json:"id"`
+	Title                string `json:"title"`
+	Type                 string `json:"type"`
+	URL                  string `json:"url"`
+	WebSocketDebuggerUrl string `json:"webSocketDebuggerUrl"`
+}
+
+func (i *Inspector) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if !isAllowedHost(req.Host) {
+		http.Error(w, "Host header is not allowed", http.StatusForbidden)
+		return
+	}
+	switch strings.TrimSuffix(req.URL.Path, "/") {
+	case "/json", "/json/list":
+		wsAddr := req.Host + "/" + i.id
+		writeJSON(w, []target{{
+			Description:          "goja instance",
+			DevtoolsFrontendUrl:  "devtools://devtools/bundled/js_app.html?experiments=true&v8only=true&ws=" + wsAddr,
+			ID:                   i.id,
+			Title:                i.opts.Name,
+			Type:                 "node",
+			URL:                  "file://",
+			WebSocketDebuggerUrl: "ws://" + wsAddr,
+		}})
+	case "/json/version":
+		writeJSON(w, map[string]string{
+			"Browser":          "goja",
+			"Protocol-Version": "1.3",
+		})
+	case "/" + i.id:
+		if !isWsUpgrade(req) {
+			http.Error(w, "WebSocket connection expected", http.StatusBadRequest)
+			return
+		}
+		if !isAllowedOrigin(req.Header.Get("Origin")) {
+			http.Error(w, "Origin is not allowed", http.StatusForbidden)
+			return
+		}
+		conn, err := wsUpgrade(w, req)
+		if err != nil {
+			return
+		}
+		s := newSession(i, conn)
+		i.mu.Lock()
+		old := i.sess
+		i.sess = s
+		i.mu.Unlock()
+		if old != nil {
+			old.close()
+		}
+		s.serve()
+	default:
+		http.NotFound(w, req)
+	}
+}
+
+// isAllowedHost reports whether the Host header is localhost or an IP address. A web page served from a domain
+// that resolves to a loopback address (DNS rebinding) has its own domain name in the header, so it cannot access
+// the endpoints (see CVE-2018-7160).
+func isAllowedHost(host string) bool {
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	host = strings.TrimSuffix(strings.TrimPrefix(host, "["), "]")
+	if net.ParseIP(host) != nil {
+		return true
+	}
+	return strings.EqualFold(host, "localhost") || strings.EqualFold(host, "localhost6")
+}
+
+// isAllowedOrigin reports whether a WebSocket connection with the Origin header may be accepted. Browsers always
+// send it, so only the DevTools frontend is allowed, other clients (such as VS Code) do not send it at all.
+func isAllowedOrigin(origin string) bool {
+	return origin == "" || strings.HasPrefix(origin, "devtools://") || strings.HasPrefix(origin, "chrome-devtools://")
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func (i *Inspector) session() *session {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	return i.sess
+}
+
+func (i *Inspector) endSession(s *session) {
+	i.mu.Lock()
+	if i.sess == s {
+		i.sess = nil
+	}
+	i.mu.Unlock()
+}
+
+// exec runs f in the goroutine that owns the Runtime. The DebugPause is nil if the Runtime is not paused.
+func (i *Inspector) exec(f func(p *goja.DebugPause)) error {
+	i.mu.Lock()
+	pauseDone := i.pauseDone
+	i.mu.Unlock()
+	if pauseDone != nil {
+		done := make(chan struct{})
+		t := func(p *goja.DebugPause) (goja.DebugCommand, bool) {
+			defer close(done)
+			f(p)
+			return goja.DebugContinue, false
+		}
+		select {
+		case i.tasks <- t:
+			<-done
+			return nil
+		case <-pauseDone:
+		}
+	}
+	if i.opts.Executor == nil {
+		return errBusy
+	}
+	done := make(chan struct{})
+	i.opts.Executor(func() {
+		defer close(done)
+		f(nil)
+	})
+	<-done
+	return nil
+}
+
+// resume ends the current pause. It returns false if the Runtime is not paused.
+func (i *Inspector) resume(cmd goja.DebugCommand) bool {
+	i.mu.Lock()
+	pauseDone := i.pauseDone
+	i.mu.Unlock()
+	if pauseDone == nil {
+		return false
+	}
+	select {
+	case i.tasks <- func(*goja.DebugPause) (goja.DebugCommand, bool) { return cmd, true }:
+		return true
+	case <-pauseDone:
+		return false
+	}
+}
+
+func (i *Inspector) onPause(p *goja.DebugPause) goja.DebugCommand {
+	s := i.session()
+	if s == nil || !s.isDebuggerEnabled() {
+		return goja.DebugContinue
+	}
+	pauseDone := make(chan struct{})
+	i.mu.Lock()
+	i.pauseDone = pauseDone
+	i.mu.Unlock()
+	defer func() {
+		i.mu.Lock()
+		i.pauseDone = nil
+		i.mu.Unlock()
+		close(pauseDone)
+		s.releaseGroup(backtraceGroup)
+		s.event("Debugger.resumed", struct{}{})
+	}()
+
+	s.event("Debugger.paused", s.pausedParams(p))
+	for {
+		select {
+		case t := <-i.tasks:
+			if cmd, resume := t(p); resume {
+				return cmd
+			}
+		case <-s.done:
+			return goja.DebugContinue
+		}
+	}
+}
+
+func (i *Inspector) onScript(src *file.File) {
+	sc, added := i.addScript(src.Name(), src.Source())
+	if added {
+		if s := i.session(); s != nil && s.isDebuggerEnabled() {
+			s.scriptParsed(sc)
+		}
+	}
+}
+
+func (i *Inspector) addScript(url, source string) (*script, bool) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	if sc := i.scripts[url]; sc != nil {
+		if sc.source == "" && source != "" {
+			sc.source = source
+		}
+		return sc, false
+	}
+	i.lastScript++
+	sc := &script{
+		id:     itoa(i.lastScript),
+		url:    url,
+		source: source,
+		lines:  strings.Count(source, "\n"),
+	}
+	i.scripts[url] = sc
+	i.scriptList = append(i.scriptList, sc)
+	return sc, true
+}
+
+// scriptFor returns the script for the url, adding it (and notifying the client) if it's not known yet.
+func (i *Inspector) scriptFor(s *session, url string) *script {
+	sc, added := i.addScript(url, "")
+	if added {
+		s.scriptParsed(sc)
+	}
+	return sc
+}
+
+func (i *Inspector) scriptByID(id string) *script {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	for _, sc := range i.scriptList {
+		if sc.id == id {
+			return sc
+		}
+	}
+	return nil
+}
+
+func (i *Inspector) scriptByURL(url string) *script {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	return i.scripts[url]
+}
+
+func (i *Inspector) allScripts() []*script {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	return append([]*script(nil), i.scriptList...)
+}
+
+func (i *Inspector) runIfWaiting() {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	select {
+	case <-i.waiting:
+	default:
+		close(i.waiting)
+	}
+}
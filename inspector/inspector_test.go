@@ -0,0 +1,367 @@
+package inspector
+
+import (
+	"bufio"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dop251/goja"
+)
+
+type testClient struct {
+	t      *testing.T
+	conn   net.Conn
+	br     *bufio.Reader
+	lastID int64
+	msgs   chan map[string]interface{}
+	queue  []map[string]interface{}
+}
+
+func dialTestClient(t *testing.T, wsURL string) *testClient {
+	u := strings.TrimPrefix(wsURL, "ws://")
+	slash := strings.IndexByte(u, '/')
+	conn, err := net.Dial("tcp", u[:slash])
+	if err != nil {
+		t.Fatal(err)
+	}
+	var key [16]byte
+	_, _ = rand.Read(key[:])
+	k := base64.StdEncoding.EncodeToString(key[:])
+	fmt.Fprintf(conn, "GET %s HTTP/1.1\r\nHost: %s\r\nUpgrade: websocket\r\nConnection: Upgrade\r\n"+
+		"Sec-WebSocket-Key: %s\r\nSec-WebSocket-Version: 13\r\n\r\n", u[slash:], u[:slash], k)
+	br := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(br, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != http.StatusSwitchingProtocols || resp.Header.Get("Sec-WebSocket-Accept") != wsAcceptKey(k) {
+		t.Fatalf("Unexpected handshake response: %v", resp)
+	}
+	c := &testClient{
+		t:    t,
+		conn: conn,
+		br:   br,
+		msgs: make(chan map[string]interface{}, 64),
+	}
+	go c.read()
+	return c
+}
+
+func (c *testClient) read() {
+	defer close(c.msgs)
+	ws := &wsConn{conn: c.conn, br: c.br}
+	for {
+		msg, err := ws.ReadMessage()
+		if err != nil {
+			return
+		}
+		var m map[string]interface{}
+		if err := json.Unmarshal(msg, &m); err != nil {
+			c.t.Error(err)
+			return
+		}
+		c.msgs <- m
+	}
+}
+
+// write sends a masked text frame, split in two fragments to exercise the continuation handling.
+func (c *testClient) write(msg []byte) {
+	half := len(msg) / 2
+	for i, part := range [][]byte{msg[:half], msg[half:]} {
+		var hdr []byte
+		op := byte(wsOpText)
+		if i > 0 {
+			op = wsOpContinuation | 0x80
+		}
+		hdr = append(hdr, op)
+		if l := len(part); l < 126 {
+			hdr = append(hdr, 0x80|byte(l))
+		} else {
+			hdr = append(hdr, 0x80|127)
+			var b [8]byte
+			binary.BigEndian.PutUint64(b[:], uint64(l))
+			hdr = append(hdr, b[:]...)
+		}
+		mask := [4]byte{1, 2, 3, 4}
+		hdr = append(hdr, mask[:]...)
+		masked := make([]byte, len(part))
+		for j := range part {
+			masked[j] = part[j] ^ mask[j&3]
+		}
+		if _, err := c.conn.Write(append(hdr, masked...)); err != nil {
+			c.t.Fatal(err)
+		}
+	}
+}
+
+func (c *testClient) next() map[string]interface{} {
+	select {
+	case m, ok := <-c.msgs:
+		if !ok {
+			c.t.Fatal("Connection closed")
+		}
+		return m
+	case <-time.After(10 * time.Second):
+		c.t.Fatal("Timed out waiting for a message")
+	}
+	return nil
+}
+
+func (c *testClient) send(method string, params interface{}) int64 {
+	c.lastID++
+	b, err := json.Marshal(map[string]interface{}{
+		"id":     c.lastID,
+		"method": method,
+		"params": params,
+	})
+	if err != nil {
+		c.t.Fatal(err)
+	}
+	c.write(b)
+	return c.lastID
+}
+
+func (c *testClient) response(id int64) (map[string]interface{}, map[string]interface{}) {
+	for {
+		m := c.next()
+		if m["id"] == float64(id) {
+			res, _ := m["result"].(map[string]interface{})
+			e, _ := m["error"].(map[string]interface{})
+			return res, e
+		}
+		c.queue = append(c.queue, m)
+	}
+}
+
+func (c *testClient) call(method string, params interface{}) map[string]interface{} {
+	res, e := c.response(c.send(method, params))
+	if e != nil {
+		c.t.Fatalf("%s has failed: %v", method, e["message"])
+	}
+	return res
+}
+
+// event waits for the event skipping all the other events.
+func (c *testClient) event(method string) map[string]interface{} {
+	for len(c.queue) > 0 {
+		m := c.queue[0]
+		c.queue = c.queue[1:]
+		if m["method"] == method {
+			return m["params"].(map[string]interface{})
+		}
+	}
+	for {
+		m := c.next()
+		if m["method"] == method {
+			return m["params"].(map[string]interface{})
+		}
+	}
+}
+
+func (c *testClient) properties(objectID interface{}) map[string]interface{} {
+	res := c.call("Runtime.getProperties", map[string]interface{}{"objectId": objectID, "ownProperties": true})
+	props := make(map[string]interface{})
+	for _, p := range res["result"].([]interface{}) {
+		p := p.(map[string]interface{})
+		props[p["name"].(string)] = p["value"].(map[string]interface{})["value"]
+	}
+	return props
+}
+
+func TestInspector(t *testing.T) {
+	const SCRIPT = `function add(a, b) {
+	var sum = a + b;
+	return sum;
+}
+console.log("sum", add(1, 2));
+`
+	r := goja.New()
+	jobs := make(chan func(), 1)
+	insp := New(r, Options{
+		Name: "test",
+		Executor: func(f func()) {
+			jobs <- f
+		},
+	})
+	srv := httptest.NewServer(insp)
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL + "/json/list")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var targets []target
+	err = json.NewDecoder(resp.Body).Decode(&targets)
+	resp.Body.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(targets) != 1 || targets[0].Title != "test" {
+		t.Fatalf("Unexpected targets: %v", targets)
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		insp.WaitForDebugger()
+		_, err := r.RunScript("test.js", SCRIPT)
+		done <- err
+		for f := range jobs {
+			f()
+		}
+	}()
+
+	c := dialTestClient(t, targets[0].WebSocketDebuggerUrl)
+	defer c.conn.Close()
+	c.call("Runtime.enable", nil)
+	if ctx := c.event("Runtime.executionContextCreated")["context"].(map[string]interface{}); ctx["name"] != "test" {
+		t.Fatalf("Unexpected context: %v", ctx)
+	}
+	c.call("Debugger.enable", nil)
+	res := c.call("Debugger.setBreakpointByUrl", map[string]interface{}{"url": "test.js", "lineNumber": 2})
+	bpID := res["breakpointId"]
+	c.call("Runtime.runIfWaitingForDebugger", nil)
+
+	sp := c.event("Debugger.scriptParsed")
+	if sp["url"] != "test.js" {
+		t.Fatalf("Unexpected script: %v", sp)
+	}
+	src := c.call("Debugger.getScriptSource", map[string]interface{}{"scriptId": sp["scriptId"]})
+	if src["scriptSource"] != SCRIPT {
+		t.Fatalf("Unexpected source: %v", src)
+	}
+
+	paused := c.event("Debugger.paused")
+	if hit := paused["hitBreakpoints"].([]interface{}); len(hit) != 1 || hit[0] != bpID {
+		t.Fatalf("Unexpected hitBreakpoints: %v", paused["hitBreakpoints"])
+	}
+	top := paused["callFrames"].([]interface{})[0].(map[string]interface{})
+	loc := top["location"].(map[string]interface{})
+	if top["functionName"] != "add" || loc["lineNumber"] != float64(2) || loc["scriptId"] != sp["scriptId"] {
+		t.Fatalf("Unexpected top frame: %v", top)
+	}
+	local := top["scopeChain"].([]interface{})[0].(map[string]interface{})
+	if local["type"] != "local" {
+		t.Fatalf("Unexpected scope: %v", local)
+	}
+	props := c.properties(local["object"].(map[string]interface{})["objectId"])
+	if props["a"] != float64(1) || props["b"] != float64(2) || props["sum"] != float64(3) {
+		t.Fatalf("Unexpected variables: %v", props)
+	}
+	res = c.call("Debugger.evaluateOnCallFrame", map[string]interface{}{
+		"callFrameId": top["callFrameId"],
+		"expression":  "sum * 2",
+	})
+	if v := res["result"].(map[string]interface{}); v["type"] != "number" || v["value"] != float64(6) {
+		t.Fatalf("Unexpected result: %v", v)
+	}
+
+	c.call("Debugger.resume", nil)
+	c.event("Debugger.resumed")
+	args := c.event("Runtime.consoleAPICalled")["args"].([]interface{})
+	if len(args) != 2 || args[0].(map[string]interface{})["value"] != "sum" || args[1].(map[string]interface{})["value"] != float64(3) {
+		t.Fatalf("Unexpected console arguments: %v", args)
+	}
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+	c.call("Debugger.removeBreakpoint", map[string]interface{}{"breakpointId": bpID})
+
+	res = c.call("Runtime.evaluate", map[string]interface{}{"expression": "({total: add(2, 3)})"})
+	obj := res["result"].(map[string]interface{})
+	if obj["type"] != "object" || obj["className"] != "Object" {
+		t.Fatalf("Unexpected result: %v", obj)
+	}
+	if props := c.properties(obj["objectId"]); props["total"] != float64(5) {
+		t.Fatalf("Unexpected properties: %v", props)
+	}
+	res = c.call("Runtime.evaluate", map[string]interface{}{"expression": "throw new Error('boom')"})
+	if res["exceptionDetails"] == nil {
+		t.Fatalf("Expected exceptionDetails: %v", res)
+	}
+
+	c.call("Profiler.enable", nil)
+	c.call("Profiler.start", nil)
+	c.call("Runtime.evaluate", map[string]interface{}{
+		"expression": "function busy() { var t = Date.now(); while (Date.now() - t < 200) {} } busy();",
+	})
+	res = c.call("Profiler.stop", nil)
+	var names []string
+	for _, n := range res["profile"].(map[string]interface{})["nodes"].([]interface{}) {
+		names = append(names, n.(map[string]interface{})["callFrame"].(map[string]interface{})["functionName"].(string))
+	}
+	if !strings.Contains(strings.Join(names, ","), "busy") {
+		t.Fatalf("Unexpected profile nodes: %v", names)
+	}
+
+	_, e := c.response(c.send("Unknown.method", nil))
+	if e == nil || e["code"] != float64(errCodeMethodNotFound) {
+		t.Fatalf("Unexpected error: %v", e)
+	}
+	close(jobs)
+}
+
+func TestInspectorHostAndOrigin(t *testing.T) {
+	insp := New(goja.New(), Options{})
+	srv := httptest.NewServer(insp)
+	defer srv.Close()
+	addr := strings.TrimPrefix(srv.URL, "http://")
+	_, port, _ := net.SplitHostPort(addr)
+
+	for host, status := range map[string]int{
+		addr:                       http.StatusOK,
+		"localhost:" + port:        http.StatusOK,
+		"[::1]:" + port:            http.StatusOK,
+		"evil.example:" + port:     http.StatusForbidden,
+		"localhost.evil.example":   http.StatusForbidden,
+		"127.0.0.1.nip.io:" + port: http.StatusForbidden,
+	} {
+		req, err := http.NewRequest("GET", srv.URL+"/json", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.Host = host
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != status {
+			t.Fatalf("Host %s: unexpected status %d", host, resp.StatusCode)
+		}
+	}
+
+	for origin, status := range map[string]int{
+		"":                     http.StatusSwitchingProtocols,
+		"devtools://devtools":  http.StatusSwitchingProtocols,
+		"http://evil.example":  http.StatusForbidden,
+		"http://localhost:123": http.StatusForbidden,
+	} {
+		conn, err := net.Dial("tcp", addr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var hdr string
+		if origin != "" {
+			hdr = "Origin: " + origin + "\r\n"
+		}
+		fmt.Fprintf(conn, "GET /%s HTTP/1.1\r\nHost: %s\r\nUpgrade: websocket\r\nConnection: Upgrade\r\n"+
+			"Sec-WebSocket-Key: dGhlIHNhbXBsZSBub25jZQ==\r\nSec-WebSocket-Version: 13\r\n%s\r\n", insp.id, addr, hdr)
+		resp, err := http.ReadResponse(bufio.NewReader(conn), nil)
+		conn.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if resp.StatusCode != status {
+			t.Fatalf("Origin %q: unexpected status %d", origin, resp.StatusCode)
+		}
+	}
+}
@@ -0,0 +1,179 @@
+package inspector
+
+import (
+	"bytes"
+	"encoding/json"
+	"sort"
+	"time"
+
+	"github.com/google/pprof/profile"
+)
+
+type profileState struct {
+	buf   bytes.Buffer
+	start time.Time
+}
+
+type profileNode struct {
+	ID            int            `json:"id"`
+	CallFrame     callFrame      `json:"callFrame"`
+	HitCount      int64          `json:"hitCount"`
+	Children      []int          `json:"children,omitempty"`
+	PositionTicks []positionTick `json:"positionTicks,omitempty"`
+
+	childMap map[*profile.Function]*profileNode
+	ticks    map[int64]int
+}
+
+type positionTick struct {
+	Line  int64 `json:"line"`
+	Ticks int   `json:"ticks"`
+}
+
+type cpuProfile struct {
+	Nodes      []*profileNode `json:"nodes"`
+	StartTime  int64          `json:"startTime"`
+	EndTime    int64          `json:"endTime"`
+	Samples    []int          `json:"samples"`
+	TimeDeltas []int64        `json:"timeDeltas"`
+}
+
+func init() {
+	register("Profiler.enable", noop)
+	register("Profiler.disable", func(s *session, _ json.RawMessage) (interface{}, error) {
+		s.stopProfile()
+		return nil, nil
+	})
+	register("Profiler.setSamplingInterval", func(s *session, params json.RawMessage) (interface{}, error) {
+		var p struct {
+			Interval int64 `json:"interval"`
+		}
+		if err := unmarshalParams(params, &p); err != nil {
+			return nil, err
+		}
+		s.i.prof.SetInterval(time.Duration(p.Interval) * time.Microsecond)
+		return nil, nil
+	})
+	register("Profiler.start", profilerStart)
+	register("Profiler.stop", profilerStop)
+}
+
+func profilerStart(s *session, _ json.RawMessage) (interface{}, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.profile != nil {
+		return nil, nil
+	}
+	ps := &profileState{start: time.Now()}
+	if err := s.i.prof.Start(&ps.buf); err != nil {
+		return nil, newProtocolError("%v", err)
+	}
+	s.profile = ps
+	return nil, nil
+}
+
+// stopProfile stops the profiling started by the session (if any) and returns the collected data.
+func (s *session) stopProfile() *profileState {
+	s.mu.Lock()
+	ps := s.profile
+	s.profile = nil
+	s.mu.Unlock()
+	if ps != nil {
+		s.i.prof.Stop()
+	}
+	return ps
+}
+
+func profilerStop(s *session, _ json.RawMessage) (interface{}, error) {
+	ps := s.stopProfile()
+	if ps == nil {
+		return nil, newProtocolError("No recording profiles found")
+	}
+	end := time.Now()
+	p, err := profile.Parse(&ps.buf)
+	if err != nil {
+		return nil, newProtocolError("Could not parse the profile: %v", err)
+	}
+	return map[string]interface{}{
+		"profile": s.convertProfile(p, ps.start, end),
+	}, nil
+}
+
+// convertProfile converts a pprof profile (as produced by goja.Profiler) into the DevTools format. Only the
+// number of samples in each stack is known, so the samples are laid out sequentially using the sampling period.
+func (s *session) convertProfile(p *profile.Profile, start, end time.Time) *cpuProfile {
+	cp := &cpuProfile{
+		StartTime:  start.UnixNano() / int64(time.Microsecond),
+		EndTime:    end.UnixNano() / int64(time.Microsecond),
+		Samples:    []int{},
+		TimeDeltas: []int64{},
+	}
+	root := &profileNode{
+		ID: 1,
+		CallFrame: callFrame{
+			FunctionName: "(root)",
+			ScriptID:     "0",
+			LineNumber:   -1,
+			ColumnNumber: -1,
+		},
+	}
+	cp.Nodes = append(cp.Nodes, root)
+	interval := p.Period / int64(time.Microsecond)
+	if interval <= 0 {
+		interval = 1
+	}
+	for _, smpl := range p.Sample {
+		node := root
+		var line int64
+		// locations are stored leaf first
+		for i := len(smpl.Location) - 1; i >= 0; i-- {
+			loc := smpl.Location[i]
+			if len(loc.Line) == 0 {
+				continue
+			}
+			fn := loc.Line[0].Function
+			line = loc.Line[0].Line
+			child := node.childMap[fn]
+			if child == nil {
+				child = &profileNode{
+					ID: len(cp.Nodes) + 1,
+					CallFrame: callFrame{
+						FunctionName: fn.Name,
+						ScriptID:     s.i.scriptFor(s, fn.Filename).id,
+						URL:          fn.Filename,
+						LineNumber:   int(line) - 1,
+						ColumnNumber: -1,
+					},
+				}
+				if node.childMap == nil {
+					node.childMap = make(map[*profile.Function]*profileNode)
+				}
+				node.childMap[fn] = child
+				node.Children = append(node.Children, child.ID)
+				cp.Nodes = append(cp.Nodes, child)
+			}
+			node = child
+		}
+		count := smpl.Value[0]
+		node.HitCount += count
+		if node != root {
+			if node.ticks == nil {
+				node.ticks = make(map[int64]int)
+			}
+			node.ticks[line] += int(count)
+		}
+		for j := int64(0); j < count; j++ {
+			cp.Samples = append(cp.Samples, node.ID)
+			cp.TimeDeltas = append(cp.TimeDeltas, interval)
+		}
+	}
+	for _, n := range cp.Nodes {
+		for line, ticks := range n.ticks {
+			n.PositionTicks = append(n.PositionTicks, positionTick{Line: line, Ticks: ticks})
+		}
+		sort.Slice(n.PositionTicks, func(i, j int) bool {
+			return n.PositionTicks[i].Line < n.PositionTicks[j].Line
+		})
+	}
+	return cp
+}
@@ -0,0 +1,564 @@
+package inspector
+
+import (
+	"encoding/json"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/dop251/goja"
+)
+
+const executionContextID = 1
+
+type remoteObject struct {
+	Type                string          `json:"type"`
+	Subtype             string          `json:"subtype,omitempty"`
+	ClassName           string          `json:"className,omitempty"`
+	Value               json.RawMessage `json:"value,omitempty"`
+	UnserializableValue string          `json:"unserializableValue,omitempty"`
+	Description         string          `json:"description,omitempty"`
+	ObjectID            string          `json:"objectId,omitempty"`
+}
+
+type exceptionDetails struct {
+	ExceptionID  int           `json:"exceptionId"`
+	Text         string        `json:"text"`
+	LineNumber   int           `json:"lineNumber"`
+	ColumnNumber int           `json:"columnNumber"`
+	Exception    *remoteObject `json:"exception,omitempty"`
+}
+
+type evaluateResult struct {
+	Result           *remoteObject     `json:"result"`
+	ExceptionDetails *exceptionDetails `json:"exceptionDetails,omitempty"`
+}
+
+type propertyDescriptor struct {
+	Name         string        `json:"name"`
+	Value        *remoteObject `json:"value,omitempty"`
+	Writable     bool          `json:"writable"`
+	Get          *remoteObject `json:"get,omitempty"`
+	Set          *remoteObject `json:"set,omitempty"`
+	Configurable bool          `json:"configurable"`
+	Enumerable   bool          `json:"enumerable"`
+	IsOwn        bool          `json:"isOwn"`
+}
+
+type internalPropertyDescriptor struct {
+	Name  string        `json:"name"`
+	Value *remoteObject `json:"value"`
+}
+
+type callFrame struct {
+	FunctionName string `json:"functionName"`
+	ScriptID     string `json:"scriptId"`
+	URL          string `json:"url"`
+	LineNumber   int    `json:"lineNumber"`
+	ColumnNumber int    `json:"columnNumber"`
+}
+
+type stackTrace struct {
+	CallFrames []callFrame `json:"callFrames"`
+}
+
+func init() {
+	register("Runtime.enable", runtimeEnable)
+	register("Runtime.disable", func(s *session, _ json.RawMessage) (interface{}, error) {
+		s.mu.Lock()
+		s.runtimeEnabled = false
+		s.mu.Unlock()
+		return nil, nil
+	})
+	registerAsync("Runtime.evaluate", runtimeEvaluate)
+	registerAsync("Runtime.getProperties", runtimeGetProperties)
+	registerAsync("Runtime.callFunctionOn", runtimeCallFunctionOn)
+	register("Runtime.releaseObject", func(s *session, params json.RawMessage) (interface{}, error) {
+		var p struct {
+			ObjectID string `json:"objectId"`
+		}
+		if err := unmarshalParams(params, &p); err != nil {
+			return nil, err
+		}
+		s.releaseObject(p.ObjectID)
+		return nil, nil
+	})
+	register("Runtime.releaseObjectGroup", func(s *session, params json.RawMessage) (interface{}, error) {
+		var p struct {
+			ObjectGroup string `json:"objectGroup"`
+		}
+		if err := unmarshalParams(params, &p); err != nil {
+			return nil, err
+		}
+		s.releaseGroup(p.ObjectGroup)
+		return nil, nil
+	})
+	register("Runtime.runIfWaitingForDebugger", func(s *session, _ json.RawMessage) (interface{}, error) {
+		s.i.runIfWaiting()
+		return nil, nil
+	})
+	register("Runtime.getIsolateId", func(s *session, _ json.RawMessage) (interface{}, error) {
+		return map[string]string{"id": s.i.id}, nil
+	})
+	register("Runtime.compileScript", noop)
+	register("Runtime.discardConsoleEntries", noop)
+	register("Runtime.setAsyncCallStackDepth", noop)
+	register("Runtime.addBinding", noop)
+}
+
+func runtimeEnable(s *session, _ json.RawMessage) (interface{}, error) {
+	s.mu.Lock()
+	s.runtimeEnabled = true
+	s.mu.Unlock()
+	s.event("Runtime.executionContextCreated", map[string]interface{}{
+		"context": map[string]interface{}{
+			"id":       executionContextID,
+			"origin":   "",
+			"name":     s.i.opts.Name,
+			"uniqueId": s.i.id,
+			"auxData": map[string]interface{}{
+				"isDefault": true,
+			},
+		},
+	})
+	return nil, nil
+}
+
+// topFrame returns the innermost non-native frame of the pause.
+func topFrame(p *goja.DebugPause) *goja.DebugFrame {
+	for _, f := range p.CallStack() {
+		if !f.IsNative() {
+			return f
+		}
+	}
+	return nil
+}
+
+func (s *session) evalResult(v goja.Value, err error, group string, byValue bool) *evaluateResult {
+	if err != nil {
+		details := &exceptionDetails{
+			ExceptionID: 1,
+			Text:        "Uncaught",
+		}
+		if ex, ok := err.(*goja.Exception); ok {
+			details.Exception = s.remote(ex.Value(), group, false)
+			details.Text = "Uncaught " + ex.Value().String()
+		} else {
+			details.Text = err.Error()
+		}
+		return &evaluateResult{
+			Result:           details.Exception,
+			ExceptionDetails: details,
+		}
+	}
+	return &evaluateResult{Result: s.remote(v, group, byValue)}
+}
+
+func runtimeEvaluate(s *session, params json.RawMessage) (interface{}, error) {
+	var p struct {
+		Expression    string `json:"expression"`
+		ObjectGroup   string `json:"objectGroup"`
+		ReturnByValue bool   `json:"returnByValue"`
+	}
+	if err := unmarshalParams(params, &p); err != nil {
+		return nil, err
+	}
+	var res *evaluateResult
+	err := s.i.exec(func(pause *goja.DebugPause) {
+		var v goja.Value
+		var err error
+		if pause != nil {
+			if f := topFrame(pause); f != nil {
+				v, err = f.Eval(p.Expression)
+			} else {
+				err = errBusy
+			}
+		} else {
+			v, err = s.i.r.RunString(p.Expression)
+		}
+		res = s.evalResult(v, err, p.ObjectGroup, p.ReturnByValue)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+type callArgument struct {
+	Value               json.RawMessage `json:"value"`
+	UnserializableValue string          `json:"unserializableValue"`
+	ObjectID            string          `json:"objectId"`
+}
+
+func (s *session) argValue(a callArgument) (goja.Value, error) {
+	r := s.i.r
+	switch {
+	case a.ObjectID != "":
+		if v, ok := s.object(a.ObjectID).(goja.Value); ok {
+			return v, nil
+		}
+		return nil, newProtocolError("Could not find object with given id")
+	case a.UnserializableValue != "":
+		switch a.UnserializableValue {
+		case "NaN":
+			return goja.NaN(), nil
+		case "Infinity":
+			return goja.PositiveInf(), nil
+		case "-Infinity":
+			return goja.NegativeInf(), nil
+		case "-0":
+			return r.ToValue(math.Copysign(0, -1)), nil
+		}
+		return nil, newProtocolError("Unsupported value: %s", a.UnserializableValue)
+	case len(a.Value) > 0:
+		var v interface{}
+		if err := json.Unmarshal(a.Value, &v); err != nil {
+			return nil, err
+		}
+		return r.ToValue(v), nil
+	}
+	return goja.Undefined(), nil
+}
+
+func runtimeCallFunctionOn(s *session, params json.RawMessage) (interface{}, error) {
+	var p struct {
+		FunctionDeclaration string         `json:"functionDeclaration"`
+		ObjectID            string         `json:"objectId"`
+		Arguments           []callArgument `json:"arguments"`
+		ReturnByValue       bool           `json:"returnByValue"`
+		ObjectGroup         string         `json:"objectGroup"`
+	}
+	if err := unmarshalParams(params, &p); err != nil {
+		return nil, err
+	}
+	var res *evaluateResult
+	var perr error
+	err := s.i.exec(func(pause *goja.DebugPause) {
+		this := goja.Undefined()
+		if p.ObjectID != "" {
+			v, ok := s.object(p.ObjectID).(goja.Value)
+			if !ok {
+				perr = newProtocolError("Could not find object with given id")
+				return
+			}
+			this = v
+		}
+		args := make([]goja.Value, len(p.Arguments))
+		for i, a := range p.Arguments {
+			v, err := s.argValue(a)
+			if err != nil {
+				perr = err
+				return
+			}
+			args[i] = v
+		}
+		src := "(" + p.FunctionDeclaration + ")"
+		var fn goja.Value
+		var err error
+		if pause != nil {
+			if f := topFrame(pause); f != nil {
+				fn, err = f.Eval(src)
+			} else {
+				err = errBusy
+			}
+		} else {
+			fn, err = s.i.r.RunString(src)
+		}
+		var v goja.Value
+		if err == nil {
+			if call, ok := goja.AssertFunction(fn); ok {
+				v, err = call(this, args...)
+			} else {
+				perr = newProtocolError("Given expression does not evaluate to a function")
+				return
+			}
+		}
+		res = s.evalResult(v, err, p.ObjectGroup, p.ReturnByValue)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if perr != nil {
+		return nil, perr
+	}
+	return res, nil
+}
+
+func runtimeGetProperties(s *session, params json.RawMessage) (interface{}, error) {
+	var p struct {
+		ObjectID               string `json:"objectId"`
+		AccessorPropertiesOnly bool   `json:"accessorPropertiesOnly"`
+	}
+	if err := unmarshalParams(params, &p); err != nil {
+		return nil, err
+	}
+	o := s.object(p.ObjectID)
+	if o == nil {
+		return nil, newProtocolError("Could not find object with given id")
+	}
+	result := []propertyDescriptor{}
+	var internal []internalPropertyDescriptor
+	if p.AccessorPropertiesOnly {
+		return map[string]interface{}{"result": result}, nil
+	}
+	group := s.groupOf(p.ObjectID)
+	err := s.i.exec(func(*goja.DebugPause) {
+		switch o := o.(type) {
+		case *goja.DebugScope:
+			if obj := o.Object(); obj != nil {
+				result = s.properties(obj, group)
+				return
+			}
+			for _, name := range o.Names() {
+				v := o.Get(name)
+				if v == nil {
+					continue
+				}
+				result = append(result, propertyDescriptor{
+					Name:         name,
+					Value:        s.remote(v, group, false),
+					Writable:     true,
+					Enumerable:   true,
+					Configurable: false,
+					IsOwn:        true,
+				})
+			}
+		case *goja.Object:
+			result = s.properties(o, group)
+			if proto := o.Prototype(); proto != nil {
+				internal = append(internal, internalPropertyDescriptor{
+					Name:  "[[Prototype]]",
+					Value: s.remote(proto, group, false),
+				})
+			}
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{
+		"result":             result,
+		"internalProperties": internal,
+	}, nil
+}
+
+func (s *session) groupOf(id string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for group, ids := range s.groups {
+		for _, id1 := range ids {
+			if id1 == id {
+				return group
+			}
+		}
+	}
+	return ""
+}
+
+// properties returns the own string-keyed properties of the object. Accessors are not invoked.
+func (s *session) properties(o *goja.Object, group string) []propertyDescriptor {
+	r := s.i.r
+	var result []propertyDescriptor
+	names, err := s.i.getOwnPropertyNames(nil, o)
+	if err != nil {
+		return result
+	}
+	var list []interface{}
+	_ = r.ExportTo(names, &list)
+	for _, n := range list {
+		name, _ := n.(string)
+		d, err := s.i.getOwnPropertyDescriptor(nil, o, r.ToValue(name))
+		if err != nil {
+			continue
+		}
+		desc, ok := d.(*goja.Object)
+		if !ok {
+			continue
+		}
+		pd := propertyDescriptor{
+			Name:         name,
+			Configurable: desc.Get("configurable").ToBoolean(),
+			Enumerable:   desc.Get("enumerable").ToBoolean(),
+			IsOwn:        true,
+		}
+		if get, set := desc.Get("get"), desc.Get("set"); get != nil || set != nil {
+			if get != nil && !goja.IsUndefined(get) {
+				pd.Get = s.remote(get, group, false)
+			}
+			if set != nil && !goja.IsUndefined(set) {
+				pd.Set = s.remote(set, group, false)
+			}
+		} else {
+			pd.Value = s.remote(desc.Get("value"), group, false)
+			pd.Writable = desc.Get("writable").ToBoolean()
+		}
+		result = append(result, pd)
+	}
+	return result
+}
+
+func marshalRaw(v interface{}) json.RawMessage {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil
+	}
+	return b
+}
+
+func (s *session) remotePrimitive(v goja.Value, group string) *remoteObject {
+	if sym, ok := v.(*goja.Symbol); ok {
+		return &remoteObject{Type: "symbol", Description: sym.String(), ObjectID: s.addObject(group, v)}
+	}
+	switch e := v.Export().(type) {
+	case bool:
+		return &remoteObject{Type: "boolean", Value: marshalRaw(e), Description: v.String()}
+	case string:
+		return &remoteObject{Type: "string", Value: marshalRaw(e), Description: e}
+	case int64:
+		return &remoteObject{Type: "number", Value: marshalRaw(e), Description: v.String()}
+	case float64:
+		ro := &remoteObject{Type: "number", Description: v.String()}
+		switch {
+		case math.IsNaN(e) || math.IsInf(e, 0):
+			ro.UnserializableValue = v.String()
+		case e == 0 && math.Signbit(e):
+			ro.UnserializableValue = "-0"
+			ro.Description = "-0"
+		default:
+			ro.Value = marshalRaw(e)
+		}
+		return ro
+	}
+	return &remoteObject{Type: "undefined"}
+}
+
+// remote converts a value into a RemoteObject, registering objects in the group. It must be called
+// in the goroutine that owns the Runtime.
+func (s *session) remote(v goja.Value, group string, byValue bool) *remoteObject {
+	if v == nil || goja.IsUndefined(v) {
+		return &remoteObject{Type: "undefined"}
+	}
+	if goja.IsNull(v) {
+		return &remoteObject{Type: "object", Subtype: "null", Value: json.RawMessage("null")}
+	}
+	o, ok := v.(*goja.Object)
+	if !ok {
+		return s.remotePrimitive(v, group)
+	}
+	ro := &remoteObject{
+		Type:      "object",
+		ClassName: o.ClassName(),
+	}
+	if _, isFunc := goja.AssertFunction(o); isFunc {
+		ro.Type = "function"
+		ro.ClassName = "Function"
+		ro.Description = o.String()
+	} else {
+		switch ro.ClassName {
+		case "Array":
+			ro.Subtype = "array"
+			ro.Description = "Array(" + strconv.FormatInt(o.Get("length").ToInteger(), 10) + ")"
+		case "Error":
+			ro.Subtype = "error"
+			if stack := o.Get("stack"); stack != nil && !goja.IsUndefined(stack) {
+				ro.Description = stack.String()
+			} else {
+				ro.Description = o.String()
+			}
+		case "Date":
+			ro.Subtype = "date"
+			ro.Description = o.String()
+		case "RegExp":
+			ro.Subtype = "regexp"
+			ro.Description = o.String()
+		case "Map":
+			ro.Subtype = "map"
+			ro.Description = "Map(" + strconv.FormatInt(o.Get("size").ToInteger(), 10) + ")"
+		case "Set":
+			ro.Subtype = "set"
+			ro.Description = "Set(" + strconv.FormatInt(o.Get("size").ToInteger(), 10) + ")"
+		case "Promise":
+			ro.Subtype = "promise"
+			ro.Description = "Promise"
+		default:
+			ro.Description = ro.ClassName
+		}
+	}
+	if byValue {
+		if b, err := json.Marshal(o); err == nil {
+			ro.Value = b
+			return ro
+		}
+	}
+	ro.ObjectID = s.addObject(group, o)
+	return ro
+}
+
+var consoleTypes = []struct {
+	method, typ string
+}{
+	{"log", "log"},
+	{"info", "info"},
+	{"warn", "warning"},
+	{"error", "error"},
+	{"debug", "debug"},
+	{"trace", "trace"},
+}
+
+func (i *Inspector) wrapConsole() {
+	r := i.r
+	var console *goja.Object
+	if c, ok := r.GlobalObject().Get("console").(*goja.Object); ok {
+		console = c
+	} else {
+		console = r.NewObject()
+		_ = r.GlobalObject().Set("console", console)
+	}
+	for _, ct := range consoleTypes {
+		orig, _ := goja.AssertFunction(console.Get(ct.method))
+		typ := ct.typ
+		_ = console.Set(ct.method, func(call goja.FunctionCall) goja.Value {
+			if s := i.session(); s != nil && s.isRuntimeEnabled() {
+				s.consoleAPICalled(typ, call.Arguments)
+			}
+			if orig != nil {
+				if _, err := orig(call.This, call.Arguments...); err != nil {
+					panic(err)
+				}
+			}
+			return goja.Undefined()
+		})
+	}
+}
+
+func (s *session) consoleAPICalled(typ string, args []goja.Value) {
+	remoteArgs := make([]*remoteObject, len(args))
+	for i, arg := range args {
+		remoteArgs[i] = s.remote(arg, consoleGroup, false)
+	}
+	var frames []callFrame
+	for _, f := range s.i.r.CaptureCallStack(0, nil) {
+		pos := f.Position()
+		if pos.Filename == "" && f.SrcName() == "<native>" {
+			continue
+		}
+		url := pos.Filename
+		if url == "" {
+			url = f.SrcName()
+		}
+		frames = append(frames, callFrame{
+			FunctionName: f.FuncName(),
+			ScriptID:     s.i.scriptFor(s, url).id,
+			URL:          url,
+			LineNumber:   pos.Line - 1,
+			ColumnNumber: pos.Column - 1,
+		})
+	}
+	s.event("Runtime.consoleAPICalled", map[string]interface{}{
+		"type":               typ,
+		"args":               remoteArgs,
+		"executionContextId": executionContextID,
+		"timestamp":          float64(time.Now().UnixNano()) / float64(time.Millisecond),
+		"stackTrace":         stackTrace{CallFrames: frames},
+	})
+}
@@ -0,0 +1,250 @@
+package inspector
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"sync"
+
+	"github.com/dop251/goja"
+)
+
+type request struct {
+	ID     int64           `json:"id"`
+	Method string          `json:"method"`
+	Params json.RawMessage `json:"params"`
+}
+
+type response struct {
+	ID     int64       `json:"id"`
+	Result interface{} `json:"result,omitempty"`
+	Error  *respError  `json:"error,omitempty"`
+}
+
+type respError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+type eventMsg struct {
+	Method string      `json:"method"`
+	Params interface{} `json:"params"`
+}
+
+const (
+	errCodeInvalidParams  = -32602
+	errCodeMethodNotFound = -32601
+	errCodeServerError    = -32000
+)
+
+type protocolError struct {
+	code int
+	msg  string
+}
+
+func (e *protocolError) Error() string {
+	return e.msg
+}
+
+func newProtocolError(format string, args ...interface{}) error {
+	return &protocolError{code: errCodeServerError, msg: fmt.Sprintf(format, args...)}
+}
+
+type methodHandler func(s *session, params json.RawMessage) (interface{}, error)
+
+type method struct {
+	handler methodHandler
+	// the handler may block waiting for the Runtime, so it's called in a separate goroutine
+	async bool
+}
+
+var methods = map[string]method{}
+
+func register(name string, h methodHandler) {
+	methods[name] = method{handler: h}
+}
+
+func registerAsync(name string, h methodHandler) {
+	methods[name] = method{handler: h, async: true}
+}
+
+func noop(*session, json.RawMessage) (interface{}, error) {
+	return struct{}{}, nil
+}
+
+const (
+	backtraceGroup = "backtrace"
+	consoleGroup   = "console"
+)
+
+type session struct {
+	i    *Inspector
+	conn *wsConn
+
+	done      chan struct{}
+	closeOnce sync.Once
+
+	mu              sync.Mutex
+	runtimeEnabled  bool
+	debuggerEnabled bool
+	breakpoints     map[string][]*goja.Breakpoint
+	bpIDs           map[*goja.Breakpoint]string
+	objects         map[string]interface{}
+	groups          map[string][]string
+	lastObjectID    int
+
+	profile *profileState
+}
+
+func newSession(i *Inspector, conn *wsConn) *session {
+	return &session{
+		i:           i,
+		conn:        conn,
+		done:        make(chan struct{}),
+		breakpoints: make(map[string][]*goja.Breakpoint),
+		bpIDs:       make(map[*goja.Breakpoint]string),
+		objects:     make(map[string]interface{}),
+		groups:      make(map[string][]string),
+	}
+}
+
+func (s *session) serve() {
+	defer s.cleanup()
+	for {
+		msg, err := s.conn.ReadMessage()
+		if err != nil {
+			return
+		}
+		var req request
+		if err := json.Unmarshal(msg, &req); err != nil {
+			continue
+		}
+		m, exists := methods[req.Method]
+		if !exists {
+			s.respond(req.ID, nil, &protocolError{code: errCodeMethodNotFound, msg: "'" + req.Method + "' wasn't found"})
+			continue
+		}
+		if m.async {
+			go s.call(req, m.handler)
+		} else {
+			s.call(req, m.handler)
+		}
+	}
+}
+
+func (s *session) call(req request, h methodHandler) {
+	res, err := h(s, req.Params)
+	s.respond(req.ID, res, err)
+}
+
+func (s *session) respond(id int64, res interface{}, err error) {
+	r := response{ID: id}
+	if err != nil {
+		code := errCodeServerError
+		if pe, ok := err.(*protocolError); ok {
+			code = pe.code
+		}
+		r.Error = &respError{Code: code, Message: err.Error()}
+	} else if res == nil {
+		r.Result = struct{}{}
+	} else {
+		r.Result = res
+	}
+	s.send(r)
+}
+
+func (s *session) event(method string, params interface{}) {
+	s.send(eventMsg{Method: method, Params: params})
+}
+
+func (s *session) send(v interface{}) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return
+	}
+	_ = s.conn.WriteMessage(b)
+}
+
+func (s *session) close() {
+	s.closeOnce.Do(func() {
+		close(s.done)
+		s.conn.Close()
+	})
+}
+
+func (s *session) cleanup() {
+	s.close()
+	s.i.endSession(s)
+	s.mu.Lock()
+	bps := s.bpIDs
+	s.breakpoints = make(map[string][]*goja.Breakpoint)
+	s.bpIDs = make(map[*goja.Breakpoint]string)
+	s.objects = make(map[string]interface{})
+	s.groups = make(map[string][]string)
+	s.debuggerEnabled = false
+	s.runtimeEnabled = false
+	s.mu.Unlock()
+	for b := range bps {
+		s.i.dbg.RemoveBreakpoint(b)
+	}
+	s.i.dbg.SetPauseOnExceptions(goja.PauseOnNoExceptions)
+	s.stopProfile()
+}
+
+func (s *session) isDebuggerEnabled() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.debuggerEnabled
+}
+
+func (s *session) isRuntimeEnabled() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.runtimeEnabled
+}
+
+// addObject registers an object (a goja.Value, a *goja.DebugFrame or a *goja.DebugScope) and returns its id.
+func (s *session) addObject(group string, o interface{}) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.lastObjectID++
+	id := strconv.Itoa(s.lastObjectID)
+	s.objects[id] = o
+	s.groups[group] = append(s.groups[group], id)
+	return id
+}
+
+func (s *session) object(id string) interface{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.objects[id]
+}
+
+func (s *session) releaseObject(id string) {
+	s.mu.Lock()
+	delete(s.objects, id)
+	s.mu.Unlock()
+}
+
+func (s *session) releaseGroup(group string) {
+	s.mu.Lock()
+	for _, id := range s.groups[group] {
+		delete(s.objects, id)
+	}
+	delete(s.groups, group)
+	s.mu.Unlock()
+}
+
+func unmarshalParams(data json.RawMessage, v interface{}) error {
+	if len(data) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return &protocolError{code: errCodeInvalidParams, msg: "Invalid parameters: " + err.Error()}
+	}
+	return nil
+}
+
+func itoa(i int) string {
+	return strconv.Itoa(i)
+}
@@ -0,0 +1,213 @@
+package inspector
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// A minimal implementation of the server side of the WebSocket protocol (RFC 6455) which is sufficient for
+// the DevTools clients: text messages, fragmentation, ping and close. Extensions are not supported.
+
+const wsGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+const (
+	wsOpContinuation = 0x0
+	wsOpText         = 0x1
+	wsOpBinary       = 0x2
+	wsOpClose        = 0x8
+	wsOpPing         = 0x9
+	wsOpPong         = 0xA
+)
+
+const wsMaxMessageSize = 64 << 20
+
+var errWsClosed = errors.New("websocket: connection closed")
+
+type wsConn struct {
+	conn net.Conn
+	br   *bufio.Reader
+
+	mu     sync.Mutex
+	closed bool
+}
+
+func headerContains(h http.Header, name, token string) bool {
+	for _, v := range h.Values(name) {
+		for _, t := range strings.Split(v, ",") {
+			if strings.EqualFold(strings.TrimSpace(t), token) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func wsAcceptKey(key string) string {
+	h := sha1.New()
+	h.Write([]byte(key + wsGUID))
+	return base64.StdEncoding.EncodeToString(h.Sum(nil))
+}
+
+func isWsUpgrade(req *http.Request) bool {
+	return headerContains(req.Header, "Connection", "upgrade") && headerContains(req.Header, "Upgrade", "websocket")
+}
+
+func wsUpgrade(w http.ResponseWriter, req *http.Request) (*wsConn, error) {
+	key := req.Header.Get("Sec-WebSocket-Key")
+	if req.Method != http.MethodGet || key == "" || req.Header.Get("Sec-WebSocket-Version") != "13" {
+		http.Error(w, "Bad WebSocket handshake", http.StatusBadRequest)
+		return nil, errors.New("websocket: bad handshake")
+	}
+	hj, ok := w.(http.Hijacker)
+	if !ok {
+		http.Error(w, "Connection hijacking is not supported", http.StatusInternalServerError)
+		return nil, errors.New("websocket: response does not implement http.Hijacker")
+	}
+	conn, rw, err := hj.Hijack()
+	if err != nil {
+		return nil, err
+	}
+	_, err = rw.WriteString("HTTP/1.1 101 Switching Protocols\r\nUpgrade: websocket\r\nConnection: Upgrade\r\n" +
+		"Sec-WebSocket-Accept: " + wsAcceptKey(key) + "\r\n\r\n")
+	if err == nil {
+		err = rw.Flush()
+	}
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return &wsConn{conn: conn, br: rw.Reader}, nil
+}
+
+func (c *wsConn) readFrame() (fin bool, op byte, payload []byte, err error) {
+	var hdr [8]byte
+	if _, err = io.ReadFull(c.br, hdr[:2]); err != nil {
+		return
+	}
+	fin = hdr[0]&0x80 != 0
+	op = hdr[0] & 0x0F
+	masked := hdr[1]&0x80 != 0
+	l := uint64(hdr[1] & 0x7F)
+	switch l {
+	case 126:
+		if _, err = io.ReadFull(c.br, hdr[:2]); err != nil {
+			return
+		}
+		l = uint64(binary.BigEndian.Uint16(hdr[:2]))
+	case 127:
+		if _, err = io.ReadFull(c.br, hdr[:8]); err != nil {
+			return
+		}
+		l = binary.BigEndian.Uint64(hdr[:8])
+	}
+	if l > wsMaxMessageSize {
+		err = errors.New("websocket: frame is too large")
+		return
+	}
+	var mask [4]byte
+	if masked {
+		if _, err = io.ReadFull(c.br, mask[:]); err != nil {
+			return
+		}
+	}
+	payload = make([]byte, l)
+	if _, err = io.ReadFull(c.br, payload); err != nil {
+		return
+	}
+	if masked {
+		for i := range payload {
+			payload[i] ^= mask[i&3]
+		}
+	}
+	return
+}
+
+// ReadMessage returns the next text or binary message. Control frames are handled internally.
+func (c *wsConn) ReadMessage() ([]byte, error) {
+	var msg []byte
+	started := false
+	for {
+		fin, op, payload, err := c.readFrame()
+		if err != nil {
+			return nil, err
+		}
+		switch op {
+		case wsOpPing:
+			if err := c.writeFrame(wsOpPong, payload); err != nil {
+				return nil, err
+			}
+			continue
+		case wsOpPong:
+			continue
+		case wsOpClose:
+			_ = c.writeFrame(wsOpClose, nil)
+			c.Close()
+			return nil, errWsClosed
+		case wsOpText, wsOpBinary:
+			if started {
+				return nil, errors.New("websocket: unexpected data frame")
+			}
+			started = true
+		case wsOpContinuation:
+			if !started {
+				return nil, errors.New("websocket: unexpected continuation frame")
+			}
+		default:
+			return nil, errors.New("websocket: unknown opcode")
+		}
+		if len(msg)+len(payload) > wsMaxMessageSize {
+			return nil, errors.New("websocket: message is too large")
+		}
+		msg = append(msg, payload...)
+		if fin {
+			return msg, nil
+		}
+	}
+}
+
+func (c *wsConn) writeFrame(op byte, payload []byte) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.closed {
+		return errWsClosed
+	}
+	hdr := make([]byte, 2, 10+len(payload))
+	hdr[0] = 0x80 | op
+	switch l := len(payload); {
+	case l < 126:
+		hdr[1] = byte(l)
+	case l <= 0xFFFF:
+		hdr[1] = 126
+		hdr = append(hdr, byte(l>>8), byte(l))
+	default:
+		hdr[1] = 127
+		var b [8]byte
+		binary.BigEndian.PutUint64(b[:], uint64(l))
+		hdr = append(hdr, b[:]...)
+	}
+	_, err := c.conn.Write(append(hdr, payload...))
+	return err
+}
+
+// WriteMessage sends a text message. It is safe to call from multiple goroutines.
+func (c *wsConn) WriteMessage(msg []byte) error {
+	return c.writeFrame(wsOpText, msg)
+}
+
+func (c *wsConn) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.closed {
+		return nil
+	}
+	c.closed = true
+	return c.conn.Close()
+}
@@ -0,0 +1,323 @@
+package goja
+
+import (
+	"unicode/utf8"
+
+	"golang.org/x/text/language"
+)
+
+// intlLocaleData holds the CLDR-derived data required by the Intl built-ins for a single locale.
+// Date patterns use the LDML syntax (https://unicode.org/reports/tr35/tr35-dates.html#Date_Field_Symbol_Table).
+type intlLocaleData struct {
+	tag language.Tag
+
+	months, monthsShort, monthsNarrow [12]string
+	// Stand-alone month names, only set for the locales where they differ from the format ones.
+	monthsStandalone, monthsShortStandalone *[12]string
+
+	// Sunday first
+	weekdays, weekdaysShort, weekdaysNarrow [7]string
+
+	dayPeriods [2]string
+	// BC, AD
+	eras, erasShort [2]string
+
+	// full, long, medium, short
+	dateFormats, timeFormats, dateTimeFormats [4]string
+
+	// Date patterns keyed by the skeleton (a subset of "yMMMEd" or "yMEd").
+	dateSkeletons map[string]string
+
+	time12, time24 string
+	hourCycle      string
+
+	decimal string
+
+	// Currency placement patterns, "0" stands for the number and "¤" for the currency symbol.
+	currencyPattern, accountingPattern string
+	// The minimum number of digits in the leading group required to use grouping (0 means 1).
+	minGrouping int
+	// Compact notation suffixes in the ascending order of magnitude. compactLong falls back to compactShort if not set.
+	compactShort, compactLong []intlCompactUnit
+
+	// Indexed by the list type (conjunction, disjunction, unit) and the style (long, short, narrow).
+	listPatterns [3][3]intlListPattern
+	// listContextual, if set, adjusts the literal that precedes the last element of a list depending on
+	// the element (e.g. Spanish "y" becomes "e" before a word starting with "i").
+	listContextual func(literal, next string) string
+	// Indexed by the style (long, short, narrow). The short and the narrow styles fall back to long if not set.
+	relativeTime [3]map[string]*intlRelativeTimeUnit
+}
+
+// intlListPattern holds the CLDR list patterns, "{0}" and "{1}" stand for the list elements.
+type intlListPattern struct {
+	start, middle, end, pair string
+}
+
+// intlRelativeTimeUnit holds the patterns for a single unit of Intl.RelativeTimeFormat.
+type intlRelativeTimeUnit struct {
+	// Keyed by the plural category, "{0}" stands for the number.
+	future, past map[string]string
+	// The names used with numeric: "auto", e.g. -1: "yesterday".
+	relative map[int]string
+}
+
+type intlCompactUnit struct {
+	exp    int
+	suffix string
+}
+
+const (
+	intlStyleFull = iota
+	intlStyleLong
+	intlStyleMedium
+	intlStyleShort
+)
+
+const (
+	intlWidthLong = iota
+	intlWidthShort
+	intlWidthNarrow
+)
+
+const (
+	intlListConjunction = iota
+	intlListDisjunction
+	intlListUnit
+)
+
+var intlLocales = []*intlLocaleData{
+	intlLocaleEn,
+	intlLocaleEnGB,
+}
+
+// intlListWords returns the list patterns for the languages that use the separator for all elements except
+// the last two which are joined by the word.
+func intlListWords(sep, word string) intlListPattern {
+	return intlListPattern{
+		start:  "{0}" + sep + "{1}",
+		middle: "{0}" + sep + "{1}",
+		end:    "{0}" + word + "{1}",
+		pair:   "{0}" + word + "{1}",
+	}
+}
+
+// intlListPatternsAllStyles returns list patterns that are the same for all the styles, except for
+// the narrow unit lists which are joined with spaces.
+func intlListPatternsAllStyles(conjunction, disjunction, unit intlListPattern) [3][3]intlListPattern {
+	narrowUnit := intlListWords(" ", " ")
+	return [3][3]intlListPattern{
+		{conjunction, conjunction, conjunction},
+		{disjunction, disjunction, disjunction},
+		{unit, unit, narrowUnit},
+	}
+}
+
+// intlPluralUnit builds the data for a unit where the future and the past patterns only differ by
+// a prefix and a suffix. forms contains pairs of plural categories and unit names.
+func intlPluralUnit(futurePrefix, futureSuffix, pastPrefix, pastSuffix string, relative map[int]string, forms ...string) *intlRelativeTimeUnit {
+	u := &intlRelativeTimeUnit{
+		future:   make(map[string]string, len(forms)/2),
+		past:     make(map[string]string, len(forms)/2),
+		relative: relative,
+	}
+	for i := 0; i < len(forms); i += 2 {
+		u.future[forms[i]] = futurePrefix + "{0}" + forms[i+1] + futureSuffix
+		u.past[forms[i]] = pastPrefix + "{0}" + forms[i+1] + pastSuffix
+	}
+	return u
+}
+
+func intlNarrow(name string) string {
+	_, size := utf8.DecodeRuneInString(name)
+	return name[:size]
+}
+
+func (d *intlLocaleData) monthName(month int, width int, standalone bool) string {
+	var names *[12]string
+	switch width {
+	case intlWidthLong:
+		names = &d.months
+		if standalone && d.monthsStandalone != nil {
+			names = d.monthsStandalone
+		}
+	case intlWidthShort:
+		names = &d.monthsShort
+		if standalone && d.monthsShortStandalone != nil {
+			names = d.monthsShortStandalone
+		}
+	default:
+		if n := d.monthsNarrow[month]; n != "" {
+			return n
+		}
+		if d.monthsStandalone != nil {
+			return intlNarrow(d.monthsStandalone[month])
+		}
+		return intlNarrow(d.months[month])
+	}
+	return names[month]
+}
+
+func (d *intlLocaleData) weekdayName(day int, width int) string {
+	switch width {
+	case intlWidthLong:
+		return d.weekdays[day]
+	case intlWidthShort:
+		return d.weekdaysShort[day]
+	}
+	if n := d.weekdaysNarrow[day]; n != "" {
+		return n
+	}
+	return intlNarrow(d.weekdays[day])
+}
+
+func (d *intlLocaleData) eraName(era int, width int) string {
+	switch width {
+	case intlWidthLong:
+		return d.eras[era]
+	case intlWidthShort:
+		return d.erasShort[era]
+	}
+	return intlNarrow(d.erasShort[era])
+}
+
+var intlEnMonths = [12]string{"January", "February", "March", "April", "May", "June", "July", "August", "September", "October", "November", "December"}
+var intlEnMonthsShort = [12]string{"Jan", "Feb", "Mar", "Apr", "May", "Jun", "Jul", "Aug", "Sep", "Oct", "Nov", "Dec"}
+var intlEnWeekdays = [7]string{"Sunday", "Monday", "Tuesday", "Wednesday", "Thursday", "Friday", "Saturday"}
+var intlEnWeekdaysShort = [7]string{"Sun", "Mon", "Tue", "Wed", "Thu", "Fri", "Sat"}
+
+func intlEnRelativeTime(style int) map[string]*intlRelativeTimeUnit {
+	unit := func(one, other string, relative map[int]string) *intlRelativeTimeUnit {
+		return intlPluralUnit("in ", "", "", " ago", relative, "one", " "+one, "other", " "+other)
+	}
+	this := func(name string) map[int]string {
+		return map[int]string{-1: "last " + name, 0: "this " + name, 1: "next " + name}
+	}
+	if style == intlWidthLong {
+		return map[string]*intlRelativeTimeUnit{
+			"year":    unit("year", "years", this("year")),
+			"quarter": unit("quarter", "quarters", this("quarter")),
+			"month":   unit("month", "months", this("month")),
+			"week":    unit("week", "weeks", this("week")),
+			"day":     unit("day", "days", map[int]string{-1: "yesterday", 0: "today", 1: "tomorrow"}),
+			"hour":    unit("hour", "hours", map[int]string{0: "this hour"}),
+			"minute":  unit("minute", "minutes", map[int]string{0: "this minute"}),
+			"second":  unit("second", "seconds", map[int]string{0: "now"}),
+		}
+	}
+	return map[string]*intlRelativeTimeUnit{
+		"year":    unit("yr.", "yr.", this("yr.")),
+		"quarter": unit("qtr.", "qtrs.", this("qtr.")),
+		"month":   unit("mo.", "mo.", this("mo.")),
+		"week":    unit("wk.", "wk.", this("wk.")),
+		"day":     unit("day", "days", map[int]string{-1: "yesterday", 0: "today", 1: "tomorrow"}),
+		"hour":    unit("hr.", "hr.", map[int]string{0: "this hour"}),
+		"minute":  unit("min.", "min.", map[int]string{0: "this minute"}),
+		"second":  unit("sec.", "sec.", map[int]string{0: "now"}),
+	}
+}
+
+var intlEnRelativeTimeLong = intlEnRelativeTime(intlWidthLong)
+var intlEnRelativeTimeShort = intlEnRelativeTime(intlWidthShort)
+
+var intlLocaleEn = &intlLocaleData{
+	tag: language.MustParse("en"),
+
+	months:        intlEnMonths,
+	monthsShort:   intlEnMonthsShort,
+	weekdays:      intlEnWeekdays,
+	weekdaysShort: intlEnWeekdaysShort,
+	dayPeriods:    [2]string{"AM", "PM"},
+	eras:          [2]string{"Before Christ", "Anno Domini"},
+	erasShort:     [2]string{"BC", "AD"},
+
+	dateFormats:     [4]string{"EEEE, MMMM d, y", "MMMM d, y", "MMM d, y", "M/d/yy"},
+	timeFormats:     [4]string{"h:mm:ss a zzzz", "h:mm:ss a z", "h:mm:ss a", "h:mm a"},
+	dateTimeFormats: [4]string{"{1} 'at' {0}", "{1} 'at' {0}", "{1}, {0}", "{1}, {0}"},
+	dateSkeletons: map[string]string{
+		"yMd":    "M/d/y",
+		"yM":     "M/y",
+		"Md":     "M/d",
+		"yMMMd":  "MMM d, y",
+		"yMMM":   "MMM y",
+		"MMMd":   "MMM d",
+		"yMEd":   "E, M/d/y",
+		"MEd":    "E, M/d",
+		"yMMMEd": "E, MMM d, y",
+		"MMMEd":  "E, MMM d",
+		"Ed":     "d E",
+		"y":      "y",
+		"M":      "L",
+		"MMM":    "LLL",
+		"d":      "d",
+		"E":      "ccc",
+	},
+	time12:    "h:mm:ss a",
+	time24:    "HH:mm:ss",
+	hourCycle: "h12",
+
+	decimal: ".",
+
+	currencyPattern:   "¤0",
+	accountingPattern: "(¤0)",
+	compactShort:      []intlCompactUnit{{3, "K"}, {6, "M"}, {9, "B"}, {12, "T"}},
+	compactLong:       []intlCompactUnit{{3, " thousand"}, {6, " million"}, {9, " billion"}, {12, " trillion"}},
+
+	listPatterns: [3][3]intlListPattern{
+		{intlListPattern{"{0}, {1}", "{0}, {1}", "{0}, and {1}", "{0} and {1}"}, intlListPattern{"{0}, {1}", "{0}, {1}", "{0}, & {1}", "{0} & {1}"}, intlListWords(", ", ", ")},
+		{intlListPattern{"{0}, {1}", "{0}, {1}", "{0}, or {1}", "{0} or {1}"}, intlListPattern{"{0}, {1}", "{0}, {1}", "{0}, or {1}", "{0} or {1}"}, intlListPattern{"{0}, {1}", "{0}, {1}", "{0}, or {1}", "{0} or {1}"}},
+		{intlListWords(", ", ", "), intlListWords(", ", ", "), intlListWords(" ", " ")},
+	},
+	relativeTime: [3]map[string]*intlRelativeTimeUnit{intlEnRelativeTimeLong, intlEnRelativeTimeShort, intlEnRelativeTimeShort},
+}
+
+var intlLocaleEnGB = &intlLocaleData{
+	tag: language.MustParse("en-GB"),
+
+	months:        intlEnMonths,
+	monthsShort:   intlEnMonthsShort,
+	weekdays:      intlEnWeekdays,
+	weekdaysShort: intlEnWeekdaysShort,
+	dayPeriods:    [2]string{"am", "pm"},
+	eras:          [2]string{"Before Christ", "Anno Domini"},
+	erasShort:     [2]string{"BC", "AD"},
+
+	dateFormats:     [4]string{"EEEE d MMMM y", "d MMMM y", "d MMM y", "dd/MM/y"},
+	timeFormats:     [4]string{"HH:mm:ss zzzz", "HH:mm:ss z", "HH:mm:ss", "HH:mm"},
+	dateTimeFormats: [4]string{"{1} 'at' {0}", "{1} 'at' {0}", "{1}, {0}", "{1}, {0}"},
+	dateSkeletons: map[string]string{
+		"yMd":    "dd/MM/y",
+		"yM":     "MM/y",
+		"Md":     "dd/MM",
+		"yMMMd":  "d MMM y",
+		"yMMM":   "MMM y",
+		"MMMd":   "d MMM",
+		"yMEd":   "E, dd/MM/y",
+		"MEd":    "E dd/MM",
+		"yMMMEd": "E d MMM y",
+		"MMMEd":  "E d MMM",
+		"Ed":     "E d",
+		"y":      "y",
+		"M":      "L",
+		"MMM":    "LLL",
+		"d":      "d",
+		"E":      "ccc",
+	},
+	time12:    "h:mm:ss a",
+	time24:    "HH:mm:ss",
+	hourCycle: "h23",
+
+	decimal: ".",
+
+	currencyPattern:   "¤0",
+	accountingPattern: "(¤0)",
+	compactShort:      []intlCompactUnit{{3, "K"}, {6, "M"}, {9, "B"}, {12, "T"}},
+	compactLong:       []intlCompactUnit{{3, " thousand"}, {6, " million"}, {9, " billion"}, {12, " trillion"}},
+
+	listPatterns: [3][3]intlListPattern{
+		{intlListWords(", ", " and "), intlListWords(", ", " and "), intlListWords(", ", ", ")},
+		{intlListWords(", ", " or "), intlListWords(", ", " or "), intlListWords(", ", " or ")},
+		{intlListWords(", ", ", "), intlListWords(", ", ", "), intlListWords(" ", " ")},
+	},
+	relativeTime: [3]map[string]*intlRelativeTimeUnit{intlEnRelativeTimeLong, intlEnRelativeTimeShort, intlEnRelativeTimeShort},
+}
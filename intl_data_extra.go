@@ -0,0 +1,570 @@
+//go:build !goja_intl_minimal
+// +build !goja_intl_minimal
+
+// The locales in this file can be left out by building with the goja_intl_minimal tag, in which case only
+// English is bundled.
+
+package goja
+
+import (
+	"strings"
+
+	"golang.org/x/text/language"
+)
+
+func init() {
+	intlLocales = append(intlLocales,
+		intlLocaleDe,
+		intlLocaleEs,
+		intlLocaleFr,
+		intlLocaleIt,
+		intlLocaleJa,
+		intlLocaleNl,
+		intlLocalePt,
+		intlLocaleRu,
+		intlLocaleZh,
+	)
+}
+
+var intlLocaleDe = &intlLocaleData{
+	tag: language.MustParse("de"),
+
+	months:                [12]string{"Januar", "Februar", "März", "April", "Mai", "Juni", "Juli", "August", "September", "Oktober", "November", "Dezember"},
+	monthsShort:           [12]string{"Jan.", "Feb.", "März", "Apr.", "Mai", "Juni", "Juli", "Aug.", "Sept.", "Okt.", "Nov.", "Dez."},
+	monthsShortStandalone: &[12]string{"Jan", "Feb", "Mär", "Apr", "Mai", "Jun", "Jul", "Aug", "Sep", "Okt", "Nov", "Dez"},
+	weekdays:              [7]string{"Sonntag", "Montag", "Dienstag", "Mittwoch", "Donnerstag", "Freitag", "Samstag"},
+	weekdaysShort:         [7]string{"So.", "Mo.", "Di.", "Mi.", "Do.", "Fr.", "Sa."},
+	dayPeriods:            [2]string{"AM", "PM"},
+	eras:                  [2]string{"v. Chr.", "n. Chr."},
+	erasShort:             [2]string{"v. Chr.", "n. Chr."},
+
+	dateFormats:     [4]string{"EEEE, d. MMMM y", "d. MMMM y", "dd.MM.y", "dd.MM.yy"},
+	timeFormats:     [4]string{"HH:mm:ss zzzz", "HH:mm:ss z", "HH:mm:ss", "HH:mm"},
+	dateTimeFormats: [4]string{"{1} 'um' {0}", "{1} 'um' {0}", "{1}, {0}", "{1}, {0}"},
+	dateSkeletons: map[string]string{
+		"yMd":    "d.M.y",
+		"yM":     "M/y",
+		"Md":     "d.M.",
+		"yMMMd":  "d. MMM y",
+		"yMMM":   "MMM y",
+		"MMMd":   "d. MMM",
+		"yMEd":   "E, d.M.y",
+		"MEd":    "E, d.M.",
+		"yMMMEd": "E, d. MMM y",
+		"MMMEd":  "E, d. MMM",
+		"Ed":     "E, d.",
+		"y":      "y",
+		"M":      "L",
+		"MMM":    "LLL",
+		"d":      "d",
+		"E":      "ccc",
+	},
+	time12:    "h:mm:ss a",
+	time24:    "HH:mm:ss",
+	hourCycle: "h23",
+
+	decimal: ",",
+
+	currencyPattern: "0\u00a0¤",
+	compactShort:    []intlCompactUnit{{6, "\u00a0Mio."}, {9, "\u00a0Mrd."}, {12, "\u00a0Bio."}},
+	listPatterns:    intlListPatternsAllStyles(intlListWords(", ", " und "), intlListWords(", ", " oder "), intlListWords(", ", ", ")),
+	relativeTime:    [3]map[string]*intlRelativeTimeUnit{intlRelativeTimeDe},
+}
+
+var intlLocaleEs = &intlLocaleData{
+	tag: language.MustParse("es"),
+
+	months:         [12]string{"enero", "febrero", "marzo", "abril", "mayo", "junio", "julio", "agosto", "septiembre", "octubre", "noviembre", "diciembre"},
+	monthsShort:    [12]string{"ene", "feb", "mar", "abr", "may", "jun", "jul", "ago", "sept", "oct", "nov", "dic"},
+	weekdays:       [7]string{"domingo", "lunes", "martes", "miércoles", "jueves", "viernes", "sábado"},
+	weekdaysShort:  [7]string{"dom", "lun", "mar", "mié", "jue", "vie", "sáb"},
+	weekdaysNarrow: [7]string{"D", "L", "M", "X", "J", "V", "S"},
+	dayPeriods:     [2]string{"a. m.", "p. m."},
+	eras:           [2]string{"antes de Cristo", "después de Cristo"},
+	erasShort:      [2]string{"a. C.", "d. C."},
+
+	dateFormats:     [4]string{"EEEE, d 'de' MMMM 'de' y", "d 'de' MMMM 'de' y", "d MMM y", "d/M/yy"},
+	timeFormats:     [4]string{"H:mm:ss (zzzz)", "H:mm:ss z", "H:mm:ss", "H:mm"},
+	dateTimeFormats: [4]string{"{1}, {0}", "{1}, {0}", "{1}, {0}", "{1}, {0}"},
+	dateSkeletons: map[string]string{
+		"yMd":     "d/M/y",
+		"yM":      "M/y",
+		"Md":      "d/M",
+		"yMMMd":   "d MMM y",
+		"yMMMMd":  "d 'de' MMMM 'de' y",
+		"yMMM":    "MMM y",
+		"yMMMM":   "MMMM 'de' y",
+		"MMMd":    "d MMM",
+		"MMMMd":   "d 'de' MMMM",
+		"yMEd":    "EEE, d/M/y",
+		"MEd":     "E, d/M",
+		"yMMMEd":  "EEE, d MMM y",
+		"yMMMMEd": "EEE, d 'de' MMMM 'de' y",
+		"MMMEd":   "E, d MMM",
+		"MMMMEd":  "E, d 'de' MMMM",
+		"Ed":      "E d",
+		"y":       "y",
+		"M":       "L",
+		"MMM":     "LLL",
+		"d":       "d",
+		"E":       "ccc",
+	},
+	time12:    "h:mm:ss a",
+	time24:    "H:mm:ss",
+	hourCycle: "h23",
+
+	decimal: ",",
+
+	currencyPattern: "0\u00a0¤",
+	minGrouping:     2,
+	compactShort:    []intlCompactUnit{{3, "\u00a0mil"}, {6, "\u00a0M"}, {12, "\u00a0B"}},
+	listPatterns:    intlListPatternsAllStyles(intlListWords(", ", " y "), intlListWords(", ", " o "), intlListWords(", ", ", ")),
+	listContextual:  intlListContextualEs,
+	relativeTime:    [3]map[string]*intlRelativeTimeUnit{intlRelativeTimeEs},
+}
+
+// intlListContextualEs replaces "y" with "e" before the words starting with the "i" sound and "o" with "u"
+// before the words starting with the "o" sound (including the numbers 8 and 11).
+func intlListContextualEs(literal, next string) string {
+	s := strings.ToLower(next)
+	switch literal {
+	case " y ":
+		if strings.HasPrefix(s, "h") {
+			s = s[1:]
+			for _, d := range []string{"ia", "ie", "io", "iu"} {
+				if strings.HasPrefix(s, d) {
+					// a diphthong, e.g. "hielo"
+					return literal
+				}
+			}
+		}
+		if strings.HasPrefix(s, "i") || strings.HasPrefix(s, "í") {
+			return " e "
+		}
+	case " o ":
+		if strings.HasPrefix(s, "h") {
+			s = s[1:]
+		}
+		if strings.HasPrefix(s, "o") || strings.HasPrefix(s, "ó") || strings.HasPrefix(s, "8") ||
+			strings.HasPrefix(s, "11") && (len(s) == 2 || s[2] < '0' || s[2] > '9') {
+			return " u "
+		}
+	}
+	return literal
+}
+
+var intlLocaleFr = &intlLocaleData{
+	tag: language.MustParse("fr"),
+
+	months:        [12]string{"janvier", "février", "mars", "avril", "mai", "juin", "juillet", "août", "septembre", "octobre", "novembre", "décembre"},
+	monthsShort:   [12]string{"janv.", "févr.", "mars", "avr.", "mai", "juin", "juil.", "août", "sept.", "oct.", "nov.", "déc."},
+	weekdays:      [7]string{"dimanche", "lundi", "mardi", "mercredi", "jeudi", "vendredi", "samedi"},
+	weekdaysShort: [7]string{"dim.", "lun.", "mar.", "mer.", "jeu.", "ven.", "sam."},
+	dayPeriods:    [2]string{"AM", "PM"},
+	eras:          [2]string{"av. J.-C.", "ap. J.-C."},
+	erasShort:     [2]string{"av. J.-C.", "ap. J.-C."},
+
+	dateFormats:     [4]string{"EEEE d MMMM y", "d MMMM y", "d MMM y", "dd/MM/y"},
+	timeFormats:     [4]string{"HH:mm:ss zzzz", "HH:mm:ss z", "HH:mm:ss", "HH:mm"},
+	dateTimeFormats: [4]string{"{1} 'à' {0}", "{1} 'à' {0}", "{1} {0}", "{1} {0}"},
+	dateSkeletons: map[string]string{
+		"yMd":    "dd/MM/y",
+		"yM":     "MM/y",
+		"Md":     "dd/MM",
+		"yMMMd":  "d MMM y",
+		"yMMM":   "MMM y",
+		"MMMd":   "d MMM",
+		"yMEd":   "E dd/MM/y",
+		"MEd":    "E dd/MM",
+		"yMMMEd": "E d MMM y",
+		"MMMEd":  "E d MMM",
+		"Ed":     "E d",
+		"y":      "y",
+		"M":      "L",
+		"MMM":    "LLL",
+		"d":      "d",
+		"E":      "ccc",
+	},
+	time12:    "h:mm:ss a",
+	time24:    "HH:mm:ss",
+	hourCycle: "h23",
+
+	decimal: ",",
+
+	currencyPattern:   "0\u00a0¤",
+	accountingPattern: "(0\u00a0¤)",
+	compactShort:      []intlCompactUnit{{3, "\u00a0k"}, {6, "\u00a0M"}, {9, "\u00a0Md"}, {12, "\u00a0Bn"}},
+	listPatterns:      intlListPatternsAllStyles(intlListWords(", ", " et "), intlListWords(", ", " ou "), intlListWords(", ", " et ")),
+	relativeTime:      [3]map[string]*intlRelativeTimeUnit{intlRelativeTimeFr},
+}
+
+var intlLocaleIt = &intlLocaleData{
+	tag: language.MustParse("it"),
+
+	months:        [12]string{"gennaio", "febbraio", "marzo", "aprile", "maggio", "giugno", "luglio", "agosto", "settembre", "ottobre", "novembre", "dicembre"},
+	monthsShort:   [12]string{"gen", "feb", "mar", "apr", "mag", "giu", "lug", "ago", "set", "ott", "nov", "dic"},
+	weekdays:      [7]string{"domenica", "lunedì", "martedì", "mercoledì", "giovedì", "venerdì", "sabato"},
+	weekdaysShort: [7]string{"dom", "lun", "mar", "mer", "gio", "ven", "sab"},
+	dayPeriods:    [2]string{"AM", "PM"},
+	eras:          [2]string{"avanti Cristo", "dopo Cristo"},
+	erasShort:     [2]string{"a.C.", "d.C."},
+
+	dateFormats:     [4]string{"EEEE d MMMM y", "d MMMM y", "d MMM y", "dd/MM/yy"},
+	timeFormats:     [4]string{"HH:mm:ss zzzz", "HH:mm:ss z", "HH:mm:ss", "HH:mm"},
+	dateTimeFormats: [4]string{"{1} {0}", "{1} {0}", "{1}, {0}", "{1}, {0}"},
+	dateSkeletons: map[string]string{
+		"yMd":    "d/M/y",
+		"yM":     "M/y",
+		"Md":     "d/M",
+		"yMMMd":  "d MMM y",
+		"yMMM":   "MMM y",
+		"MMMd":   "d MMM",
+		"yMEd":   "E d/M/y",
+		"MEd":    "E d/M",
+		"yMMMEd": "E d MMM y",
+		"MMMEd":  "E d MMM",
+		"Ed":     "E d",
+		"y":      "y",
+		"M":      "L",
+		"MMM":    "LLL",
+		"d":      "d",
+		"E":      "ccc",
+	},
+	time12:    "h:mm:ss a",
+	time24:    "HH:mm:ss",
+	hourCycle: "h23",
+
+	decimal: ",",
+
+	currencyPattern: "0\u00a0¤",
+	compactShort:    []intlCompactUnit{{6, "\u00a0Mln"}, {9, "\u00a0Mrd"}, {12, "\u00a0Bln"}},
+	listPatterns:    intlListPatternsAllStyles(intlListWords(", ", " e "), intlListWords(", ", " o "), intlListWords(", ", " e ")),
+	relativeTime:    [3]map[string]*intlRelativeTimeUnit{intlRelativeTimeIt},
+}
+
+var intlLocaleJa = &intlLocaleData{
+	tag: language.MustParse("ja"),
+
+	months:        [12]string{"1月", "2月", "3月", "4月", "5月", "6月", "7月", "8月", "9月", "10月", "11月", "12月"},
+	monthsShort:   [12]string{"1月", "2月", "3月", "4月", "5月", "6月", "7月", "8月", "9月", "10月", "11月", "12月"},
+	monthsNarrow:  [12]string{"1", "2", "3", "4", "5", "6", "7", "8", "9", "10", "11", "12"},
+	weekdays:      [7]string{"日曜日", "月曜日", "火曜日", "水曜日", "木曜日", "金曜日", "土曜日"},
+	weekdaysShort: [7]string{"日", "月", "火", "水", "木", "金", "土"},
+	dayPeriods:    [2]string{"午前", "午後"},
+	eras:          [2]string{"紀元前", "西暦"},
+	erasShort:     [2]string{"紀元前", "西暦"},
+
+	dateFormats:     [4]string{"y年M月d日EEEE", "y年M月d日", "y/MM/dd", "y/MM/dd"},
+	timeFormats:     [4]string{"H時mm分ss秒 zzzz", "H:mm:ss z", "H:mm:ss", "H:mm"},
+	dateTimeFormats: [4]string{"{1} {0}", "{1} {0}", "{1} {0}", "{1} {0}"},
+	dateSkeletons: map[string]string{
+		"yMd":    "y/M/d",
+		"yM":     "y/M",
+		"Md":     "M/d",
+		"yMMMd":  "y年M月d日",
+		"yMMM":   "y年M月",
+		"MMMd":   "M月d日",
+		"yMEd":   "y/M/d(E)",
+		"MEd":    "M/d(E)",
+		"yMMMEd": "y年M月d日(E)",
+		"MMMEd":  "M月d日(E)",
+		"Ed":     "d日(E)",
+		"y":      "y年",
+		"M":      "M月",
+		"MMM":    "M月",
+		"d":      "d日",
+		"E":      "ccc",
+	},
+	time12:    "aK:mm:ss",
+	time24:    "H:mm:ss",
+	hourCycle: "h23",
+
+	decimal: ".",
+
+	currencyPattern:   "¤0",
+	accountingPattern: "(¤0)",
+	compactShort:      []intlCompactUnit{{4, "万"}, {8, "億"}, {12, "兆"}},
+	listPatterns:      intlListPatternsAllStyles(intlListWords("、", "、"), intlListPattern{"{0}、{1}", "{0}、{1}", "{0}、または{1}", "{0}または{1}"}, intlListWords(" ", " ")),
+	relativeTime:      [3]map[string]*intlRelativeTimeUnit{intlRelativeTimeJa},
+}
+
+var intlLocaleNl = &intlLocaleData{
+	tag: language.MustParse("nl"),
+
+	months:        [12]string{"januari", "februari", "maart", "april", "mei", "juni", "juli", "augustus", "september", "oktober", "november", "december"},
+	monthsShort:   [12]string{"jan", "feb", "mrt", "apr", "mei", "jun", "jul", "aug", "sep", "okt", "nov", "dec"},
+	weekdays:      [7]string{"zondag", "maandag", "dinsdag", "woensdag", "donderdag", "vrijdag", "zaterdag"},
+	weekdaysShort: [7]string{"zo", "ma", "di", "wo", "do", "vr", "za"},
+	dayPeriods:    [2]string{"a.m.", "p.m."},
+	eras:          [2]string{"voor Christus", "na Christus"},
+	erasShort:     [2]string{"v.Chr.", "n.Chr."},
+
+	dateFormats:     [4]string{"EEEE d MMMM y", "d MMMM y", "d MMM y", "dd-MM-y"},
+	timeFormats:     [4]string{"HH:mm:ss zzzz", "HH:mm:ss z", "HH:mm:ss", "HH:mm"},
+	dateTimeFormats: [4]string{"{1} 'om' {0}", "{1} 'om' {0}", "{1}, {0}", "{1}, {0}"},
+	dateSkeletons: map[string]string{
+		"yMd":    "d-M-y",
+		"yM":     "M-y",
+		"Md":     "d-M",
+		"yMMMd":  "d MMM y",
+		"yMMM":   "MMM y",
+		"MMMd":   "d MMM",
+		"yMEd":   "E d-M-y",
+		"MEd":    "E d-M",
+		"yMMMEd": "E d MMM y",
+		"MMMEd":  "E d MMM",
+		"Ed":     "E d",
+		"y":      "y",
+		"M":      "L",
+		"MMM":    "LLL",
+		"d":      "d",
+		"E":      "ccc",
+	},
+	time12:    "h:mm:ss a",
+	time24:    "HH:mm:ss",
+	hourCycle: "h23",
+
+	decimal: ",",
+
+	currencyPattern:   "¤\u00a00",
+	accountingPattern: "(¤\u00a00)",
+	compactShort:      []intlCompactUnit{{3, "K"}, {6, "\u00a0mln."}, {9, "\u00a0mld."}, {12, "\u00a0bln."}},
+	listPatterns:      intlListPatternsAllStyles(intlListWords(", ", " en "), intlListWords(", ", " of "), intlListWords(", ", " en ")),
+	relativeTime:      [3]map[string]*intlRelativeTimeUnit{intlRelativeTimeNl},
+}
+
+var intlLocalePt = &intlLocaleData{
+	tag: language.MustParse("pt"),
+
+	months:        [12]string{"janeiro", "fevereiro", "março", "abril", "maio", "junho", "julho", "agosto", "setembro", "outubro", "novembro", "dezembro"},
+	monthsShort:   [12]string{"jan.", "fev.", "mar.", "abr.", "mai.", "jun.", "jul.", "ago.", "set.", "out.", "nov.", "dez."},
+	weekdays:      [7]string{"domingo", "segunda-feira", "terça-feira", "quarta-feira", "quinta-feira", "sexta-feira", "sábado"},
+	weekdaysShort: [7]string{"dom.", "seg.", "ter.", "qua.", "qui.", "sex.", "sáb."},
+	dayPeriods:    [2]string{"AM", "PM"},
+	eras:          [2]string{"antes de Cristo", "depois de Cristo"},
+	erasShort:     [2]string{"a.C.", "d.C."},
+
+	dateFormats:     [4]string{"EEEE, d 'de' MMMM 'de' y", "d 'de' MMMM 'de' y", "d 'de' MMM 'de' y", "dd/MM/y"},
+	timeFormats:     [4]string{"HH:mm:ss zzzz", "HH:mm:ss z", "HH:mm:ss", "HH:mm"},
+	dateTimeFormats: [4]string{"{1} {0}", "{1} {0}", "{1}, {0}", "{1}, {0}"},
+	dateSkeletons: map[string]string{
+		"yMd":    "dd/MM/y",
+		"yM":     "MM/y",
+		"Md":     "d/M",
+		"yMMMd":  "d 'de' MMM 'de' y",
+		"yMMM":   "MMM 'de' y",
+		"MMMd":   "d 'de' MMM",
+		"yMEd":   "E, dd/MM/y",
+		"MEd":    "E, dd/MM",
+		"yMMMEd": "E, d 'de' MMM 'de' y",
+		"MMMEd":  "E, d 'de' MMM",
+		"Ed":     "E, d",
+		"y":      "y",
+		"M":      "L",
+		"MMM":    "LLL",
+		"d":      "d",
+		"E":      "ccc",
+	},
+	time12:    "h:mm:ss a",
+	time24:    "HH:mm:ss",
+	hourCycle: "h23",
+
+	decimal: ",",
+
+	currencyPattern: "¤\u00a00",
+	compactShort:    []intlCompactUnit{{3, "\u00a0mil"}, {6, "\u00a0mi"}, {9, "\u00a0bi"}, {12, "\u00a0tri"}},
+	listPatterns:    intlListPatternsAllStyles(intlListWords(", ", " e "), intlListWords(", ", " ou "), intlListWords(", ", " e ")),
+	relativeTime:    [3]map[string]*intlRelativeTimeUnit{intlRelativeTimePt},
+}
+
+var intlLocaleRu = &intlLocaleData{
+	tag: language.MustParse("ru"),
+
+	months:                [12]string{"января", "февраля", "марта", "апреля", "мая", "июня", "июля", "августа", "сентября", "октября", "ноября", "декабря"},
+	monthsShort:           [12]string{"янв.", "февр.", "мар.", "апр.", "мая", "июн.", "июл.", "авг.", "сент.", "окт.", "нояб.", "дек."},
+	monthsStandalone:      &[12]string{"январь", "февраль", "март", "апрель", "май", "июнь", "июль", "август", "сентябрь", "октябрь", "ноябрь", "декабрь"},
+	monthsShortStandalone: &[12]string{"янв.", "февр.", "март", "апр.", "май", "июнь", "июль", "авг.", "сент.", "окт.", "нояб.", "дек."},
+	weekdays:              [7]string{"воскресенье", "понедельник", "вторник", "среда", "четверг", "пятница", "суббота"},
+	weekdaysShort:         [7]string{"вс", "пн", "вт", "ср", "чт", "пт", "сб"},
+	dayPeriods:            [2]string{"AM", "PM"},
+	eras:                  [2]string{"до Рождества Христова", "от Рождества Христова"},
+	erasShort:             [2]string{"до н. э.", "н. э."},
+
+	dateFormats:     [4]string{"EEEE, d MMMM y 'г'.", "d MMMM y 'г'.", "d MMM y 'г'.", "dd.MM.y"},
+	timeFormats:     [4]string{"HH:mm:ss zzzz", "HH:mm:ss z", "HH:mm:ss", "HH:mm"},
+	dateTimeFormats: [4]string{"{1}, {0}", "{1}, {0}", "{1}, {0}", "{1}, {0}"},
+	dateSkeletons: map[string]string{
+		"yMd":    "dd.MM.y",
+		"yM":     "MM.y",
+		"Md":     "dd.MM",
+		"yMMMd":  "d MMM y 'г'.",
+		"yMMM":   "LLL y 'г'.",
+		"MMMd":   "d MMM",
+		"yMEd":   "ccc, dd.MM.y 'г'.",
+		"MEd":    "E, dd.MM",
+		"yMMMEd": "E, d MMM y 'г'.",
+		"MMMEd":  "ccc, d MMM",
+		"Ed":     "ccc, d",
+		"y":      "y",
+		"M":      "L",
+		"MMM":    "LLL",
+		"d":      "d",
+		"E":      "ccc",
+	},
+	time12:    "h:mm:ss a",
+	time24:    "HH:mm:ss",
+	hourCycle: "h23",
+
+	decimal: ",",
+
+	currencyPattern: "0\u00a0¤",
+	compactShort:    []intlCompactUnit{{3, "\u00a0тыс."}, {6, "\u00a0млн"}, {9, "\u00a0млрд"}, {12, "\u00a0трлн"}},
+	listPatterns:    intlListPatternsAllStyles(intlListWords(", ", " и "), intlListWords(", ", " или "), intlListWords(", ", " ")),
+	relativeTime:    [3]map[string]*intlRelativeTimeUnit{intlRelativeTimeRu},
+}
+
+var intlLocaleZh = &intlLocaleData{
+	tag: language.MustParse("zh"),
+
+	months:         [12]string{"一月", "二月", "三月", "四月", "五月", "六月", "七月", "八月", "九月", "十月", "十一月", "十二月"},
+	monthsShort:    [12]string{"1月", "2月", "3月", "4月", "5月", "6月", "7月", "8月", "9月", "10月", "11月", "12月"},
+	monthsNarrow:   [12]string{"1", "2", "3", "4", "5", "6", "7", "8", "9", "10", "11", "12"},
+	weekdays:       [7]string{"星期日", "星期一", "星期二", "星期三", "星期四", "星期五", "星期六"},
+	weekdaysShort:  [7]string{"周日", "周一", "周二", "周三", "周四", "周五", "周六"},
+	weekdaysNarrow: [7]string{"日", "一", "二", "三", "四", "五", "六"},
+	dayPeriods:     [2]string{"上午", "下午"},
+	eras:           [2]string{"公元前", "公元"},
+	erasShort:      [2]string{"公元前", "公元"},
+
+	dateFormats:     [4]string{"y年M月d日EEEE", "y年M月d日", "y年M月d日", "y/M/d"},
+	timeFormats:     [4]string{"zzzz HH:mm:ss", "z HH:mm:ss", "HH:mm:ss", "HH:mm"},
+	dateTimeFormats: [4]string{"{1} {0}", "{1} {0}", "{1} {0}", "{1} {0}"},
+	dateSkeletons: map[string]string{
+		"yMd":    "y/M/d",
+		"yM":     "y/M",
+		"Md":     "M/d",
+		"yMMMd":  "y年M月d日",
+		"yMMM":   "y年M月",
+		"MMMd":   "M月d日",
+		"yMEd":   "y/M/dE",
+		"MEd":    "M/dE",
+		"yMMMEd": "y年M月d日E",
+		"MMMEd":  "M月d日E",
+		"Ed":     "d日E",
+		"y":      "y年",
+		"M":      "M月",
+		"MMM":    "LLL",
+		"d":      "d日",
+		"E":      "ccc",
+	},
+	time12:    "ah:mm:ss",
+	time24:    "HH:mm:ss",
+	hourCycle: "h23",
+
+	decimal: ".",
+
+	currencyPattern:   "¤0",
+	accountingPattern: "(¤0)",
+	compactShort:      []intlCompactUnit{{4, "万"}, {8, "亿"}, {12, "万亿"}},
+	listPatterns:      intlListPatternsAllStyles(intlListPattern{"{0}、{1}", "{0}、{1}", "{0}和{1}", "{0}和{1}"}, intlListWords("、", "或"), intlListWords("", "")),
+	relativeTime:      [3]map[string]*intlRelativeTimeUnit{intlRelativeTimeZh},
+}
+
+var intlRelativeTimeDe = map[string]*intlRelativeTimeUnit{
+	"year":    intlPluralUnit("in ", "", "vor ", "", map[int]string{-1: "letztes Jahr", 0: "dieses Jahr", 1: "nächstes Jahr"}, "one", " Jahr", "other", " Jahren"),
+	"quarter": intlPluralUnit("in ", "", "vor ", "", map[int]string{-1: "letztes Quartal", 0: "dieses Quartal", 1: "nächstes Quartal"}, "one", " Quartal", "other", " Quartalen"),
+	"month":   intlPluralUnit("in ", "", "vor ", "", map[int]string{-1: "letzten Monat", 0: "diesen Monat", 1: "nächsten Monat"}, "one", " Monat", "other", " Monaten"),
+	"week":    intlPluralUnit("in ", "", "vor ", "", map[int]string{-1: "letzte Woche", 0: "diese Woche", 1: "nächste Woche"}, "one", " Woche", "other", " Wochen"),
+	"day":     intlPluralUnit("in ", "", "vor ", "", map[int]string{-2: "vorgestern", -1: "gestern", 0: "heute", 1: "morgen", 2: "übermorgen"}, "one", " Tag", "other", " Tagen"),
+	"hour":    intlPluralUnit("in ", "", "vor ", "", map[int]string{0: "in dieser Stunde"}, "one", " Stunde", "other", " Stunden"),
+	"minute":  intlPluralUnit("in ", "", "vor ", "", map[int]string{0: "in dieser Minute"}, "one", " Minute", "other", " Minuten"),
+	"second":  intlPluralUnit("in ", "", "vor ", "", map[int]string{0: "jetzt"}, "one", " Sekunde", "other", " Sekunden"),
+}
+
+var intlRelativeTimeEs = map[string]*intlRelativeTimeUnit{
+	"year":    intlPluralUnit("dentro de ", "", "hace ", "", map[int]string{-1: "el año pasado", 0: "este año", 1: "el próximo año"}, "one", " año", "other", " años"),
+	"quarter": intlPluralUnit("dentro de ", "", "hace ", "", map[int]string{-1: "el trimestre pasado", 0: "este trimestre", 1: "el próximo trimestre"}, "one", " trimestre", "other", " trimestres"),
+	"month":   intlPluralUnit("dentro de ", "", "hace ", "", map[int]string{-1: "el mes pasado", 0: "este mes", 1: "el próximo mes"}, "one", " mes", "other", " meses"),
+	"week":    intlPluralUnit("dentro de ", "", "hace ", "", map[int]string{-1: "la semana pasada", 0: "esta semana", 1: "la próxima semana"}, "one", " semana", "other", " semanas"),
+	"day":     intlPluralUnit("dentro de ", "", "hace ", "", map[int]string{-2: "anteayer", -1: "ayer", 0: "hoy", 1: "mañana", 2: "pasado mañana"}, "one", " día", "other", " días"),
+	"hour":    intlPluralUnit("dentro de ", "", "hace ", "", map[int]string{0: "esta hora"}, "one", " hora", "other", " horas"),
+	"minute":  intlPluralUnit("dentro de ", "", "hace ", "", map[int]string{0: "este minuto"}, "one", " minuto", "other", " minutos"),
+	"second":  intlPluralUnit("dentro de ", "", "hace ", "", map[int]string{0: "ahora"}, "one", " segundo", "other", " segundos"),
+}
+
+var intlRelativeTimeFr = map[string]*intlRelativeTimeUnit{
+	"year":    intlPluralUnit("dans ", "", "il y a ", "", map[int]string{-1: "l’année dernière", 0: "cette année", 1: "l’année prochaine"}, "one", " an", "other", " ans"),
+	"quarter": intlPluralUnit("dans ", "", "il y a ", "", map[int]string{-1: "le trimestre dernier", 0: "ce trimestre", 1: "le trimestre prochain"}, "one", " trimestre", "other", " trimestres"),
+	"month":   intlPluralUnit("dans ", "", "il y a ", "", map[int]string{-1: "le mois dernier", 0: "ce mois-ci", 1: "le mois prochain"}, "one", " mois", "other", " mois"),
+	"week":    intlPluralUnit("dans ", "", "il y a ", "", map[int]string{-1: "la semaine dernière", 0: "cette semaine", 1: "la semaine prochaine"}, "one", " semaine", "other", " semaines"),
+	"day":     intlPluralUnit("dans ", "", "il y a ", "", map[int]string{-2: "avant-hier", -1: "hier", 0: "aujourd’hui", 1: "demain", 2: "après-demain"}, "one", " jour", "other", " jours"),
+	"hour":    intlPluralUnit("dans ", "", "il y a ", "", map[int]string{0: "cette heure-ci"}, "one", " heure", "other", " heures"),
+	"minute":  intlPluralUnit("dans ", "", "il y a ", "", map[int]string{0: "cette minute-ci"}, "one", " minute", "other", " minutes"),
+	"second":  intlPluralUnit("dans ", "", "il y a ", "", map[int]string{0: "maintenant"}, "one", " seconde", "other", " secondes"),
+}
+
+var intlRelativeTimeIt = map[string]*intlRelativeTimeUnit{
+	"year":    intlPluralUnit("tra ", "", "", " fa", map[int]string{-1: "anno scorso", 0: "quest’anno", 1: "anno prossimo"}, "one", " anno", "other", " anni"),
+	"quarter": intlPluralUnit("tra ", "", "", " fa", map[int]string{-1: "trimestre scorso", 0: "questo trimestre", 1: "trimestre prossimo"}, "one", " trimestre", "other", " trimestri"),
+	"month":   intlPluralUnit("tra ", "", "", " fa", map[int]string{-1: "mese scorso", 0: "questo mese", 1: "mese prossimo"}, "one", " mese", "other", " mesi"),
+	"week":    intlPluralUnit("tra ", "", "", " fa", map[int]string{-1: "settimana scorsa", 0: "questa settimana", 1: "settimana prossima"}, "one", " settimana", "other", " settimane"),
+	"day":     intlPluralUnit("tra ", "", "", " fa", map[int]string{-2: "l’altro ieri", -1: "ieri", 0: "oggi", 1: "domani", 2: "dopodomani"}, "one", " giorno", "other", " giorni"),
+	"hour":    intlPluralUnit("tra ", "", "", " fa", map[int]string{0: "quest’ora"}, "one", " ora", "other", " ore"),
+	"minute":  intlPluralUnit("tra ", "", "", " fa", map[int]string{0: "questo minuto"}, "one", " minuto", "other", " minuti"),
+	"second":  intlPluralUnit("tra ", "", "", " fa", map[int]string{0: "ora"}, "one", " secondo", "other", " secondi"),
+}
+
+var intlRelativeTimeJa = map[string]*intlRelativeTimeUnit{
+	"year":    intlPluralUnit("", "後", "", "前", map[int]string{-1: "昨年", 0: "今年", 1: "来年"}, "other", " 年"),
+	"quarter": intlPluralUnit("", "後", "", "前", map[int]string{-1: "前四半期", 0: "今四半期", 1: "翌四半期"}, "other", " 四半期"),
+	"month":   intlPluralUnit("", "後", "", "前", map[int]string{-1: "先月", 0: "今月", 1: "来月"}, "other", " か月"),
+	"week":    intlPluralUnit("", "後", "", "前", map[int]string{-1: "先週", 0: "今週", 1: "来週"}, "other", " 週間"),
+	"day":     intlPluralUnit("", "後", "", "前", map[int]string{-2: "一昨日", -1: "昨日", 0: "今日", 1: "明日", 2: "明後日"}, "other", " 日"),
+	"hour":    intlPluralUnit("", "後", "", "前", map[int]string{0: "1 時間以内"}, "other", " 時間"),
+	"minute":  intlPluralUnit("", "後", "", "前", map[int]string{0: "1 分以内"}, "other", " 分"),
+	"second":  intlPluralUnit("", "後", "", "前", map[int]string{0: "今"}, "other", " 秒"),
+}
+
+var intlRelativeTimeNl = map[string]*intlRelativeTimeUnit{
+	"year":    intlPluralUnit("over ", "", "", " geleden", map[int]string{-1: "vorig jaar", 0: "dit jaar", 1: "volgend jaar"}, "one", " jaar", "other", " jaar"),
+	"quarter": intlPluralUnit("over ", "", "", " geleden", map[int]string{-1: "vorig kwartaal", 0: "dit kwartaal", 1: "volgend kwartaal"}, "one", " kwartaal", "other", " kwartalen"),
+	"month":   intlPluralUnit("over ", "", "", " geleden", map[int]string{-1: "vorige maand", 0: "deze maand", 1: "volgende maand"}, "one", " maand", "other", " maanden"),
+	"week":    intlPluralUnit("over ", "", "", " geleden", map[int]string{-1: "vorige week", 0: "deze week", 1: "volgende week"}, "one", " week", "other", " weken"),
+	"day":     intlPluralUnit("over ", "", "", " geleden", map[int]string{-2: "eergisteren", -1: "gisteren", 0: "vandaag", 1: "morgen", 2: "overmorgen"}, "one", " dag", "other", " dagen"),
+	"hour":    intlPluralUnit("over ", "", "", " geleden", map[int]string{0: "binnen een uur"}, "one", " uur", "other", " uur"),
+	"minute":  intlPluralUnit("over ", "", "", " geleden", map[int]string{0: "binnen een minuut"}, "one", " minuut", "other", " minuten"),
+	"second":  intlPluralUnit("over ", "", "", " geleden", map[int]string{0: "nu"}, "one", " seconde", "other", " seconden"),
+}
+
+var intlRelativeTimePt = map[string]*intlRelativeTimeUnit{
+	"year":    intlPluralUnit("em ", "", "há ", "", map[int]string{-1: "ano passado", 0: "este ano", 1: "próximo ano"}, "one", " ano", "other", " anos"),
+	"quarter": intlPluralUnit("em ", "", "há ", "", map[int]string{-1: "último trimestre", 0: "este trimestre", 1: "próximo trimestre"}, "one", " trimestre", "other", " trimestres"),
+	"month":   intlPluralUnit("em ", "", "há ", "", map[int]string{-1: "mês passado", 0: "este mês", 1: "próximo mês"}, "one", " mês", "other", " meses"),
+	"week":    intlPluralUnit("em ", "", "há ", "", map[int]string{-1: "semana passada", 0: "esta semana", 1: "próxima semana"}, "one", " semana", "other", " semanas"),
+	"day":     intlPluralUnit("em ", "", "há ", "", map[int]string{-2: "anteontem", -1: "ontem", 0: "hoje", 1: "amanhã", 2: "depois de amanhã"}, "one", " dia", "other", " dias"),
+	"hour":    intlPluralUnit("em ", "", "há ", "", map[int]string{0: "esta hora"}, "one", " hora", "other", " horas"),
+	"minute":  intlPluralUnit("em ", "", "há ", "", map[int]string{0: "este minuto"}, "one", " minuto", "other", " minutos"),
+	"second":  intlPluralUnit("em ", "", "há ", "", map[int]string{0: "agora"}, "one", " segundo", "other", " segundos"),
+}
+
+var intlRelativeTimeRu = map[string]*intlRelativeTimeUnit{
+	"year":    intlPluralUnit("через ", "", "", " назад", map[int]string{-1: "в прошлом году", 0: "в этом году", 1: "в следующем году"}, "one", " год", "few", " года", "many", " лет", "other", " года"),
+	"quarter": intlPluralUnit("через ", "", "", " назад", map[int]string{-1: "в прошлом квартале", 0: "в текущем квартале", 1: "в следующем квартале"}, "one", " квартал", "few", " квартала", "many", " кварталов", "other", " квартала"),
+	"month":   intlPluralUnit("через ", "", "", " назад", map[int]string{-1: "в прошлом месяце", 0: "в этом месяце", 1: "в следующем месяце"}, "one", " месяц", "few", " месяца", "many", " месяцев", "other", " месяца"),
+	"week":    intlPluralUnit("через ", "", "", " назад", map[int]string{-1: "на прошлой неделе", 0: "на этой неделе", 1: "на следующей неделе"}, "one", " неделю", "few", " недели", "many", " недель", "other", " недели"),
+	"day":     intlPluralUnit("через ", "", "", " назад", map[int]string{-2: "позавчера", -1: "вчера", 0: "сегодня", 1: "завтра", 2: "послезавтра"}, "one", " день", "few", " дня", "many", " дней", "other", " дня"),
+	"hour":    intlPluralUnit("через ", "", "", " назад", map[int]string{0: "в этот час"}, "one", " час", "few", " часа", "many", " часов", "other", " часа"),
+	"minute":  intlPluralUnit("через ", "", "", " назад", map[int]string{0: "в эту минуту"}, "one", " минуту", "few", " минуты", "many", " минут", "other", " минуты"),
+	"second":  intlPluralUnit("через ", "", "", " назад", map[int]string{0: "сейчас"}, "one", " секунду", "few", " секунды", "many", " секунд", "other", " секунды"),
+}
+
+var intlRelativeTimeZh = map[string]*intlRelativeTimeUnit{
+	"year":    intlPluralUnit("", "后", "", "前", map[int]string{-1: "去年", 0: "今年", 1: "明年"}, "other", "年"),
+	"quarter": intlPluralUnit("", "后", "", "前", map[int]string{-1: "上季度", 0: "本季度", 1: "下季度"}, "other", "个季度"),
+	"month":   intlPluralUnit("", "后", "", "前", map[int]string{-1: "上个月", 0: "本月", 1: "下个月"}, "other", "个月"),
+	"week":    intlPluralUnit("", "后", "", "前", map[int]string{-1: "上周", 0: "本周", 1: "下周"}, "other", "周"),
+	"day":     intlPluralUnit("", "后", "", "前", map[int]string{-2: "前天", -1: "昨天", 0: "今天", 1: "明天", 2: "后天"}, "other", "天"),
+	"hour":    intlPluralUnit("", "后", "", "前", map[int]string{0: "这一时间"}, "other", "小时"),
+	"minute":  intlPluralUnit("", "后", "", "前", map[int]string{0: "此刻"}, "other", "分钟"),
+	"second":  intlPluralUnit("", "后", "", "前", map[int]string{0: "现在"}, "other", "秒钟"),
+}
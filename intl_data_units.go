@@ -0,0 +1,91 @@
+package goja
+
+// intlUnitNames holds the display patterns for a measurement unit, "{0}" stands for the number.
+// Only English names are bundled, the short and narrow forms are used as they are for the other locales and
+// the long form falls back to the short one.
+type intlUnitNames struct {
+	short, shortPlural string
+	narrow             string
+	long, longPlural   string
+}
+
+func intlSymbolUnit(symbol, narrow, long, longPlural string) *intlUnitNames {
+	return &intlUnitNames{
+		short:      "{0} " + symbol,
+		narrow:     "{0}" + narrow,
+		long:       "{0} " + long,
+		longPlural: "{0} " + longPlural,
+	}
+}
+
+// Sanctioned single units, see https://tc39.es/ecma402/#table-sanctioned-single-unit-identifiers
+var intlUnits = map[string]*intlUnitNames{
+	"acre":              intlSymbolUnit("ac", "ac", "acre", "acres"),
+	"bit":               intlSymbolUnit("bit", "bit", "bit", "bits"),
+	"byte":              intlSymbolUnit("byte", "B", "byte", "bytes"),
+	"celsius":           {short: "{0}°C", narrow: "{0}°C", long: "{0} degree Celsius", longPlural: "{0} degrees Celsius"},
+	"centimeter":        intlSymbolUnit("cm", "cm", "centimeter", "centimeters"),
+	"day":               {short: "{0} day", shortPlural: "{0} days", narrow: "{0}d", long: "{0} day", longPlural: "{0} days"},
+	"degree":            {short: "{0} deg", narrow: "{0}°", long: "{0} degree", longPlural: "{0} degrees"},
+	"fahrenheit":        {short: "{0}°F", narrow: "{0}°", long: "{0} degree Fahrenheit", longPlural: "{0} degrees Fahrenheit"},
+	"fluid-ounce":       intlSymbolUnit("fl oz", "fl oz", "fluid ounce", "fluid ounces"),
+	"foot":              {short: "{0} ft", narrow: "{0}′", long: "{0} foot", longPlural: "{0} feet"},
+	"gallon":            intlSymbolUnit("gal", "gal", "gallon", "gallons"),
+	"gigabit":           intlSymbolUnit("Gb", "Gb", "gigabit", "gigabits"),
+	"gigabyte":          intlSymbolUnit("GB", "GB", "gigabyte", "gigabytes"),
+	"gram":              intlSymbolUnit("g", "g", "gram", "grams"),
+	"hectare":           intlSymbolUnit("ha", "ha", "hectare", "hectares"),
+	"hour":              intlSymbolUnit("hr", "h", "hour", "hours"),
+	"inch":              {short: "{0} in", narrow: "{0}″", long: "{0} inch", longPlural: "{0} inches"},
+	"kilobit":           intlSymbolUnit("kb", "kb", "kilobit", "kilobits"),
+	"kilobyte":          intlSymbolUnit("kB", "kB", "kilobyte", "kilobytes"),
+	"kilogram":          intlSymbolUnit("kg", "kg", "kilogram", "kilograms"),
+	"kilometer":         intlSymbolUnit("km", "km", "kilometer", "kilometers"),
+	"liter":             intlSymbolUnit("L", "L", "liter", "liters"),
+	"megabit":           intlSymbolUnit("Mb", "Mb", "megabit", "megabits"),
+	"megabyte":          intlSymbolUnit("MB", "MB", "megabyte", "megabytes"),
+	"meter":             intlSymbolUnit("m", "m", "meter", "meters"),
+	"microsecond":       intlSymbolUnit("μs", "μs", "microsecond", "microseconds"),
+	"mile":              intlSymbolUnit("mi", "mi", "mile", "miles"),
+	"mile-scandinavian": intlSymbolUnit("smi", "smi", "mile-scandinavian", "miles-scandinavian"),
+	"milliliter":        intlSymbolUnit("mL", "mL", "milliliter", "milliliters"),
+	"millimeter":        intlSymbolUnit("mm", "mm", "millimeter", "millimeters"),
+	"millisecond":       intlSymbolUnit("ms", "ms", "millisecond", "milliseconds"),
+	"minute":            intlSymbolUnit("min", "m", "minute", "minutes"),
+	"month":             {short: "{0} mth", shortPlural: "{0} mths", narrow: "{0}m", long: "{0} month", longPlural: "{0} months"},
+	"nanosecond":        intlSymbolUnit("ns", "ns", "nanosecond", "nanoseconds"),
+	"ounce":             intlSymbolUnit("oz", "oz", "ounce", "ounces"),
+	"percent":           {short: "{0}%", narrow: "{0}%", long: "{0} percent", longPlural: "{0} percent"},
+	"petabyte":          intlSymbolUnit("PB", "PB", "petabyte", "petabytes"),
+	"pound":             intlSymbolUnit("lb", "lb", "pound", "pounds"),
+	"second":            intlSymbolUnit("sec", "s", "second", "seconds"),
+	"stone":             intlSymbolUnit("st", "st", "stone", "stones"),
+	"terabit":           intlSymbolUnit("Tb", "Tb", "terabit", "terabits"),
+	"terabyte":          intlSymbolUnit("TB", "TB", "terabyte", "terabytes"),
+	"week":              {short: "{0} wk", shortPlural: "{0} wks", narrow: "{0}w", long: "{0} week", longPlural: "{0} weeks"},
+	"yard":              intlSymbolUnit("yd", "yd", "yard", "yards"),
+	"year":              {short: "{0} yr", shortPlural: "{0} yrs", narrow: "{0}y", long: "{0} year", longPlural: "{0} years"},
+}
+
+// Compound units that have their own short forms.
+var intlCompoundUnits = map[string]*intlUnitNames{
+	"kilometer-per-hour": {short: "{0} km/h", narrow: "{0}km/h", long: "{0} kilometer per hour", longPlural: "{0} kilometers per hour"},
+	"mile-per-hour":      {short: "{0} mph", narrow: "{0}mph", long: "{0} mile per hour", longPlural: "{0} miles per hour"},
+	"meter-per-second":   {short: "{0} m/s", narrow: "{0}m/s", long: "{0} meter per second", longPlural: "{0} meters per second"},
+	"mile-per-gallon":    {short: "{0} mpg", narrow: "{0}mpg", long: "{0} mile per gallon", longPlural: "{0} miles per gallon"},
+}
+
+// English currency display names (singular, plural).
+var intlCurrencyNames = map[string][2]string{
+	"AUD": {"Australian dollar", "Australian dollars"},
+	"BRL": {"Brazilian real", "Brazilian reals"},
+	"CAD": {"Canadian dollar", "Canadian dollars"},
+	"CHF": {"Swiss franc", "Swiss francs"},
+	"CNY": {"Chinese yuan", "Chinese yuan"},
+	"EUR": {"euro", "euros"},
+	"GBP": {"British pound", "British pounds"},
+	"INR": {"Indian rupee", "Indian rupees"},
+	"JPY": {"Japanese yen", "Japanese yen"},
+	"RUB": {"Russian ruble", "Russian rubles"},
+	"USD": {"US dollar", "US dollars"},
+}
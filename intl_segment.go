@@ -0,0 +1,563 @@
+package goja
+
+import (
+	"unicode"
+)
+
+// This file implements the default grapheme cluster, word and sentence boundary rules of UAX #29
+// (https://www.unicode.org/reports/tr29/). The character properties are derived from the general categories
+// available in the unicode package, which is a close approximation of the property tables. No dictionary-based
+// segmentation is performed, i.e. each ideograph is a separate word.
+
+const (
+	gbOther = iota
+	gbCR
+	gbLF
+	gbControl
+	gbExtend
+	gbZWJ
+	gbRegionalIndicator
+	gbSpacingMark
+	gbL
+	gbV
+	gbT
+	gbLV
+	gbLVT
+)
+
+var intlExtendedPictographic = &unicode.RangeTable{
+	R16: []unicode.Range16{
+		{0x00a9, 0x00a9, 1},
+		{0x00ae, 0x00ae, 1},
+		{0x203c, 0x203c, 1},
+		{0x2049, 0x2049, 1},
+		{0x2122, 0x2122, 1},
+		{0x2139, 0x2139, 1},
+		{0x2194, 0x2199, 1},
+		{0x21a9, 0x21aa, 1},
+		{0x231a, 0x231b, 1},
+		{0x2328, 0x2328, 1},
+		{0x2388, 0x2388, 1},
+		{0x23cf, 0x23cf, 1},
+		{0x23e9, 0x23f3, 1},
+		{0x23f8, 0x23fa, 1},
+		{0x24c2, 0x24c2, 1},
+		{0x25aa, 0x25ab, 1},
+		{0x25b6, 0x25b6, 1},
+		{0x25c0, 0x25c0, 1},
+		{0x25fb, 0x25fe, 1},
+		{0x2600, 0x2605, 1},
+		{0x2607, 0x2612, 1},
+		{0x2614, 0x2685, 1},
+		{0x2690, 0x2705, 1},
+		{0x2708, 0x2712, 1},
+		{0x2714, 0x2714, 1},
+		{0x2716, 0x2716, 1},
+		{0x271d, 0x271d, 1},
+		{0x2721, 0x2721, 1},
+		{0x2728, 0x2728, 1},
+		{0x2733, 0x2734, 1},
+		{0x2744, 0x2744, 1},
+		{0x2747, 0x2747, 1},
+		{0x274c, 0x274c, 1},
+		{0x274e, 0x274e, 1},
+		{0x2753, 0x2755, 1},
+		{0x2757, 0x2757, 1},
+		{0x2763, 0x2767, 1},
+		{0x2795, 0x2797, 1},
+		{0x27a1, 0x27a1, 1},
+		{0x27b0, 0x27b0, 1},
+		{0x27bf, 0x27bf, 1},
+		{0x2934, 0x2935, 1},
+		{0x2b05, 0x2b07, 1},
+		{0x2b1b, 0x2b1c, 1},
+		{0x2b50, 0x2b50, 1},
+		{0x2b55, 0x2b55, 1},
+		{0x3030, 0x3030, 1},
+		{0x303d, 0x303d, 1},
+		{0x3297, 0x3297, 1},
+		{0x3299, 0x3299, 1},
+	},
+	R32: []unicode.Range32{
+		{0x1f000, 0x1f0ff, 1},
+		{0x1f10d, 0x1f10f, 1},
+		{0x1f12f, 0x1f12f, 1},
+		{0x1f16c, 0x1f171, 1},
+		{0x1f17e, 0x1f17f, 1},
+		{0x1f18e, 0x1f18e, 1},
+		{0x1f191, 0x1f19a, 1},
+		{0x1f1ad, 0x1f1e5, 1},
+		{0x1f201, 0x1f20f, 1},
+		{0x1f21a, 0x1f21a, 1},
+		{0x1f22f, 0x1f22f, 1},
+		{0x1f232, 0x1f23a, 1},
+		{0x1f23c, 0x1f23f, 1},
+		{0x1f249, 0x1f3fa, 1},
+		{0x1f400, 0x1f53d, 1},
+		{0x1f546, 0x1f64f, 1},
+		{0x1f680, 0x1f6ff, 1},
+		{0x1f774, 0x1f77f, 1},
+		{0x1f7d5, 0x1f7ff, 1},
+		{0x1f80c, 0x1f80f, 1},
+		{0x1f848, 0x1f84f, 1},
+		{0x1f85a, 0x1f85f, 1},
+		{0x1f888, 0x1f88f, 1},
+		{0x1f8ae, 0x1f8ff, 1},
+		{0x1f90c, 0x1f93a, 1},
+		{0x1f93c, 0x1f945, 1},
+		{0x1f947, 0x1faff, 1},
+		{0x1fc00, 0x1fffd, 1},
+	},
+}
+
+func intlIsEmojiModifier(r rune) bool {
+	return r >= 0x1f3fb && r <= 0x1f3ff
+}
+
+func intlIsRegionalIndicator(r rune) bool {
+	return r >= 0x1f1e6 && r <= 0x1f1ff
+}
+
+func intlGraphemeBreakProperty(r rune) int {
+	switch {
+	case r == '\r':
+		return gbCR
+	case r == '\n':
+		return gbLF
+	case r == 0x200d:
+		return gbZWJ
+	case r == 0x200c || intlIsEmojiModifier(r):
+		return gbExtend
+	case intlIsRegionalIndicator(r):
+		return gbRegionalIndicator
+	case r >= 0x1100 && r <= 0x115f || r >= 0xa960 && r <= 0xa97c:
+		return gbL
+	case r >= 0x1160 && r <= 0x11a7 || r >= 0xd7b0 && r <= 0xd7c6:
+		return gbV
+	case r >= 0x11a8 && r <= 0x11ff || r >= 0xd7cb && r <= 0xd7fb:
+		return gbT
+	case r >= 0xac00 && r <= 0xd7a3:
+		if (r-0xac00)%28 == 0 {
+			return gbLV
+		}
+		return gbLVT
+	case unicode.In(r, unicode.Mn, unicode.Me):
+		return gbExtend
+	case unicode.Is(unicode.Mc, r):
+		return gbSpacingMark
+	case unicode.In(r, unicode.Cc, unicode.Cf, unicode.Zl, unicode.Zp) || r >= 0xd800 && r <= 0xdfff:
+		return gbControl
+	}
+	return gbOther
+}
+
+// intlGraphemeBreaks returns the indexes of the extended grapheme cluster boundaries in runes, including 0 and
+// len(runes).
+func intlGraphemeBreaks(runes []rune) []int {
+	breaks := make([]int, 0, len(runes)+1)
+	breaks = append(breaks, 0)
+	if len(runes) == 0 {
+		return breaks
+	}
+	prev := intlGraphemeBreakProperty(runes[0])
+	// Whether the preceding characters match ExtPict Extend* (ignoring the current one)
+	pict := unicode.Is(intlExtendedPictographic, runes[0])
+	pictBeforePrev := false
+	riCount := 0
+	if prev == gbRegionalIndicator {
+		riCount = 1
+	}
+	for i := 1; i < len(runes); i++ {
+		cur := intlGraphemeBreakProperty(runes[i])
+		curPict := unicode.Is(intlExtendedPictographic, runes[i])
+		brk := true
+		switch {
+		case prev == gbCR && cur == gbLF: // GB3
+			brk = false
+		case prev == gbCR || prev == gbLF || prev == gbControl: // GB4
+		case cur == gbCR || cur == gbLF || cur == gbControl: // GB5
+		case prev == gbL && (cur == gbL || cur == gbV || cur == gbLV || cur == gbLVT): // GB6
+			brk = false
+		case (prev == gbLV || prev == gbV) && (cur == gbV || cur == gbT): // GB7
+			brk = false
+		case (prev == gbLVT || prev == gbT) && cur == gbT: // GB8
+			brk = false
+		case cur == gbExtend || cur == gbZWJ || cur == gbSpacingMark: // GB9, GB9a
+			brk = false
+		case prev == gbZWJ && curPict && pictBeforePrev: // GB11
+			brk = false
+		case prev == gbRegionalIndicator && cur == gbRegionalIndicator && riCount%2 == 1: // GB12, GB13
+			brk = false
+		}
+		if brk {
+			breaks = append(breaks, i)
+		}
+
+		pictBeforePrev = pict
+		if curPict {
+			pict = true
+		} else if cur != gbExtend || !pict {
+			pict = false
+		}
+		if cur == gbRegionalIndicator {
+			riCount++
+		} else {
+			riCount = 0
+		}
+		prev = cur
+	}
+	return append(breaks, len(runes))
+}
+
+const (
+	wbOther = iota
+	wbCR
+	wbLF
+	wbNewline
+	wbExtend
+	wbZWJ
+	wbRegionalIndicator
+	wbFormat
+	wbKatakana
+	wbHebrewLetter
+	wbALetter
+	wbSingleQuote
+	wbDoubleQuote
+	wbMidNumLet
+	wbMidLetter
+	wbMidNum
+	wbNumeric
+	wbExtendNumLet
+	wbWSegSpace
+)
+
+func intlIsKatakana(r rune) bool {
+	switch r {
+	case 0x3031, 0x3032, 0x3033, 0x3034, 0x3035, 0x309b, 0x309c, 0x30a0, 0x30fc, 0xff70:
+		return true
+	}
+	return unicode.Is(unicode.Katakana, r)
+}
+
+// intlIsIdeographic reports whether r is a letter that forms a word on its own.
+func intlIsIdeographic(r rune) bool {
+	return unicode.In(r, unicode.Han, unicode.Hiragana)
+}
+
+func intlWordBreakProperty(r rune) int {
+	switch r {
+	case '\r':
+		return wbCR
+	case '\n':
+		return wbLF
+	case 0x0b, 0x0c, 0x85, 0x2028, 0x2029:
+		return wbNewline
+	case 0x200d:
+		return wbZWJ
+	case 0x200c:
+		return wbExtend
+	case '\'':
+		return wbSingleQuote
+	case '"':
+		return wbDoubleQuote
+	case '.', 0x2018, 0x2019, 0x2024, 0xfe52, 0xff07, 0xff0e:
+		return wbMidNumLet
+	case ':', 0xb7, 0x387, 0x5f4, 0x2027, 0xfe13, 0xfe55, 0xff1a:
+		return wbMidLetter
+	case ',', ';', 0x37e, 0x589, 0x60c, 0x60d, 0x66c, 0x7f8, 0x2044, 0xfe10, 0xfe14, 0xfe50, 0xfe54, 0xff0c, 0xff1b:
+		return wbMidNum
+	case 0x202f:
+		return wbExtendNumLet
+	case 0xa0, 0x2007:
+		return wbOther
+	}
+	switch {
+	case intlIsEmojiModifier(r) || unicode.In(r, unicode.Mn, unicode.Me, unicode.Mc):
+		return wbExtend
+	case intlIsRegionalIndicator(r):
+		return wbRegionalIndicator
+	case unicode.Is(unicode.Cf, r):
+		if r == 0x200b {
+			return wbOther
+		}
+		return wbFormat
+	case intlIsKatakana(r):
+		return wbKatakana
+	case unicode.IsLetter(r):
+		if intlIsIdeographic(r) {
+			return wbOther
+		}
+		if unicode.Is(unicode.Hebrew, r) {
+			return wbHebrewLetter
+		}
+		return wbALetter
+	case unicode.Is(unicode.Nd, r):
+		return wbNumeric
+	case unicode.Is(unicode.Pc, r):
+		return wbExtendNumLet
+	case unicode.Is(unicode.Zs, r):
+		return wbWSegSpace
+	}
+	return wbOther
+}
+
+func intlIsAHLetter(p int) bool {
+	return p == wbALetter || p == wbHebrewLetter
+}
+
+func intlIsMidNumLetQ(p int) bool {
+	return p == wbMidNumLet || p == wbSingleQuote
+}
+
+func intlIsWordIgnorable(p int) bool {
+	return p == wbExtend || p == wbFormat || p == wbZWJ
+}
+
+// intlWordBreaks returns the indexes of the word boundaries in runes, including 0 and len(runes).
+func intlWordBreaks(runes []rune) []int {
+	breaks := make([]int, 0, len(runes)/2+2)
+	breaks = append(breaks, 0)
+	props := make([]int, len(runes))
+	for i, r := range runes {
+		props[i] = intlWordBreakProperty(r)
+	}
+	// prevBase returns the index of the character before i, skipping the ones ignored by WB4.
+	prevBase := func(i int) int {
+		i--
+		for i > 0 && intlIsWordIgnorable(props[i]) {
+			i--
+		}
+		return i
+	}
+	nextBase := func(i int) int {
+		i++
+		for i < len(props) && intlIsWordIgnorable(props[i]) {
+			i++
+		}
+		return i
+	}
+	propAt := func(i int) int {
+		if i < 0 || i >= len(props) {
+			return -1
+		}
+		return props[i]
+	}
+	riCount := 0
+	for i := 1; i < len(runes); i++ {
+		raw := props[i-1]
+		cur := props[i]
+		brk := true
+		switch {
+		case raw == wbCR && cur == wbLF: // WB3
+			brk = false
+		case raw == wbCR || raw == wbLF || raw == wbNewline: // WB3a
+		case cur == wbCR || cur == wbLF || cur == wbNewline: // WB3b
+		case raw == wbZWJ && unicode.Is(intlExtendedPictographic, runes[i]): // WB3c
+			brk = false
+		case raw == wbWSegSpace && cur == wbWSegSpace: // WB3d
+			brk = false
+		case intlIsWordIgnorable(cur): // WB4
+			brk = false
+		default:
+			p := prevBase(i)
+			prev := props[p]
+			prev2 := propAt(prevBase(p))
+			next := propAt(nextBase(i))
+			switch {
+			case intlIsAHLetter(prev) && intlIsAHLetter(cur): // WB5
+				brk = false
+			case intlIsAHLetter(prev) && (cur == wbMidLetter || intlIsMidNumLetQ(cur)) && intlIsAHLetter(next): // WB6
+				brk = false
+			case intlIsAHLetter(prev2) && (prev == wbMidLetter || intlIsMidNumLetQ(prev)) && intlIsAHLetter(cur): // WB7
+				brk = false
+			case prev == wbHebrewLetter && cur == wbSingleQuote: // WB7a
+				brk = false
+			case prev == wbHebrewLetter && cur == wbDoubleQuote && next == wbHebrewLetter: // WB7b
+				brk = false
+			case prev2 == wbHebrewLetter && prev == wbDoubleQuote && cur == wbHebrewLetter: // WB7c
+				brk = false
+			case prev == wbNumeric && cur == wbNumeric: // WB8
+				brk = false
+			case intlIsAHLetter(prev) && cur == wbNumeric: // WB9
+				brk = false
+			case prev == wbNumeric && intlIsAHLetter(cur): // WB10
+				brk = false
+			case prev2 == wbNumeric && (prev == wbMidNum || intlIsMidNumLetQ(prev)) && cur == wbNumeric: // WB11
+				brk = false
+			case prev == wbNumeric && (cur == wbMidNum || intlIsMidNumLetQ(cur)) && next == wbNumeric: // WB12
+				brk = false
+			case prev == wbKatakana && cur == wbKatakana: // WB13
+				brk = false
+			case (intlIsAHLetter(prev) || prev == wbNumeric || prev == wbKatakana || prev == wbExtendNumLet) && cur == wbExtendNumLet: // WB13a
+				brk = false
+			case prev == wbExtendNumLet && (intlIsAHLetter(cur) || cur == wbNumeric || cur == wbKatakana): // WB13b
+				brk = false
+			case prev == wbRegionalIndicator && cur == wbRegionalIndicator && riCount%2 == 1: // WB15, WB16
+				brk = false
+			}
+		}
+		if brk {
+			breaks = append(breaks, i)
+		}
+		if cur == wbRegionalIndicator {
+			riCount++
+		} else if !intlIsWordIgnorable(cur) {
+			riCount = 0
+		}
+	}
+	if len(runes) > 0 {
+		breaks = append(breaks, len(runes))
+	}
+	return breaks
+}
+
+// intlIsWordLike reports whether the word segment contains letters or numbers.
+func intlIsWordLike(runes []rune) bool {
+	for _, r := range runes {
+		if unicode.IsLetter(r) || unicode.IsNumber(r) {
+			return true
+		}
+	}
+	return false
+}
+
+const (
+	sbOther = iota
+	sbCR
+	sbLF
+	sbExtend
+	sbSep
+	sbFormat
+	sbSp
+	sbLower
+	sbUpper
+	sbOLetter
+	sbNumeric
+	sbATerm
+	sbSContinue
+	sbSTerm
+	sbClose
+)
+
+func intlSentenceBreakProperty(r rune) int {
+	switch r {
+	case '\r':
+		return sbCR
+	case '\n':
+		return sbLF
+	case 0x85, 0x2028, 0x2029:
+		return sbSep
+	case 0x200c, 0x200d:
+		return sbExtend
+	case '.', 0x2024, 0xfe52, 0xff0e:
+		return sbATerm
+	case '!', '?', 0x589, 0x61f, 0x6d4, 0x700, 0x701, 0x702, 0x7f9, 0x964, 0x965, 0x203c, 0x203d, 0x2047, 0x2048, 0x2049,
+		0x3002, 0xfe56, 0xfe57, 0xff01, 0xff1f, 0xff61:
+		return sbSTerm
+	case ',', '-', ':', 0x55d, 0x60c, 0x60d, 0x7f8, 0x1802, 0x1808, 0x2013, 0x2014, 0x3001, 0xfe10, 0xfe11, 0xfe13,
+		0xfe31, 0xfe32, 0xfe50, 0xfe51, 0xfe55, 0xfe58, 0xfe63, 0xff0c, 0xff0d, 0xff1a, 0xff64:
+		return sbSContinue
+	case '"', '\'':
+		return sbClose
+	case '\t', 0x0b, 0x0c:
+		return sbSp
+	}
+	switch {
+	case unicode.In(r, unicode.Mn, unicode.Me, unicode.Mc):
+		return sbExtend
+	case unicode.Is(unicode.Cf, r):
+		return sbFormat
+	case unicode.Is(unicode.Zs, r):
+		return sbSp
+	case unicode.Is(unicode.Ll, r):
+		return sbLower
+	case unicode.In(r, unicode.Lu, unicode.Lt):
+		return sbUpper
+	case unicode.IsLetter(r):
+		return sbOLetter
+	case unicode.Is(unicode.Nd, r):
+		return sbNumeric
+	case unicode.In(r, unicode.Ps, unicode.Pe, unicode.Pi, unicode.Pf):
+		return sbClose
+	}
+	return sbOther
+}
+
+func intlIsParaSep(p int) bool {
+	return p == sbSep || p == sbCR || p == sbLF
+}
+
+// intlSentenceBreaks returns the indexes of the sentence boundaries in runes, including 0 and len(runes).
+func intlSentenceBreaks(runes []rune) []int {
+	breaks := make([]int, 0, 4)
+	breaks = append(breaks, 0)
+	if len(runes) == 0 {
+		return breaks
+	}
+	// Extend and Format characters are attached to the preceding character (SB5), so the rules are applied to units
+	// that start at a base character.
+	var starts, props []int
+	for i, r := range runes {
+		p := intlSentenceBreakProperty(r)
+		if (p == sbExtend || p == sbFormat) && len(props) > 0 && !intlIsParaSep(props[len(props)-1]) {
+			continue
+		}
+		starts = append(starts, i)
+		props = append(props, p)
+	}
+	propAt := func(i int) int {
+		if i < 0 || i >= len(props) {
+			return -1
+		}
+		return props[i]
+	}
+	for j := 1; j < len(props); j++ {
+		prev, cur := props[j-1], props[j]
+		brk := false
+		switch {
+		case prev == sbCR && cur == sbLF: // SB3
+		case intlIsParaSep(prev): // SB4
+			brk = true
+		case prev == sbATerm && cur == sbNumeric: // SB6
+		case prev == sbATerm && (propAt(j-2) == sbUpper || propAt(j-2) == sbLower) && cur == sbUpper: // SB7
+		default:
+			k := j - 1
+			for k >= 0 && props[k] == sbSp {
+				k--
+			}
+			hasSp := k < j-1
+			for k >= 0 && props[k] == sbClose {
+				k--
+			}
+			term := propAt(k)
+			if term != sbATerm && term != sbSTerm {
+				break
+			}
+			if term == sbATerm {
+				// SB8
+				l := j
+				for l < len(props) {
+					p := props[l]
+					if p == sbOLetter || p == sbUpper || p == sbLower || intlIsParaSep(p) || p == sbATerm || p == sbSTerm {
+						break
+					}
+					l++
+				}
+				if propAt(l) == sbLower {
+					break
+				}
+			}
+			switch {
+			case cur == sbSContinue || cur == sbATerm || cur == sbSTerm: // SB8a
+			case !hasSp && (cur == sbClose || cur == sbSp || intlIsParaSep(cur)): // SB9
+			case cur == sbSp || intlIsParaSep(cur): // SB10
+			default: // SB11
+				brk = true
+			}
+		}
+		if brk {
+			breaks = append(breaks, starts[j])
+		}
+	}
+	return append(breaks, len(runes))
+}
@@ -1,6 +1,7 @@
 package goja
 
 import (
+	gocontext "context"
 	"fmt"
 	"math"
 	"reflect"
@@ -12,6 +13,7 @@ import (
 const (
 	classObject        = "Object"
 	classArray         = "Array"
+	classArguments     = "Arguments"
 	classWeakSet       = "WeakSet"
 	classWeakMap       = "WeakMap"
 	classMap           = "Map"
@@ -28,12 +30,16 @@ const (
 	classJSON          = "JSON"
 	classGlobal        = "global"
 	classPromise       = "Promise"
+	classIntl          = "Intl"
 
+	classIterator             = "Iterator"
+	classIteratorHelper       = "Iterator Helper"
 	classArrayIterator        = "Array Iterator"
 	classMapIterator          = "Map Iterator"
 	classSetIterator          = "Set Iterator"
 	classStringIterator       = "String Iterator"
 	classRegExpStringIterator = "RegExp String Iterator"
+	classSegmentIterator      = "Segmenter String Iterator"
 
 	classGenerator         = "Generator"
 	classGeneratorFunction = "GeneratorFunction"
@@ -249,12 +255,34 @@ func (o *primitiveValueObject) exportType() reflect.Type {
 type FunctionCall struct {
 	This      Value
 	Arguments []Value
+
+	ctx gocontext.Context
 }
 
 type ConstructorCall struct {
 	This      *Object
 	Arguments []Value
 	NewTarget *Object
+
+	ctx gocontext.Context
+}
+
+// Context returns the context.Context the current script is running with (see Runtime.RunProgramContext()),
+// or context.Background() if there is none. Host functions can use it to honor cancellation and deadlines.
+func (f FunctionCall) Context() gocontext.Context {
+	if f.ctx != nil {
+		return f.ctx
+	}
+	return gocontext.Background()
+}
+
+// Context returns the context.Context the current script is running with (see Runtime.RunProgramContext()),
+// or context.Background() if there is none.
+func (f ConstructorCall) Context() gocontext.Context {
+	if f.ctx != nil {
+		return f.ctx
+	}
+	return gocontext.Background()
 }
 
 func (f FunctionCall) Argument(idx int) Value {
@@ -273,6 +301,7 @@ func (f ConstructorCall) Argument(idx int) Value {
 
 func (o *baseObject) init() {
 	o.values = make(map[unistring.String]Value)
+	o.val.runtime.trackAlloc(memSizeObject)
 }
 
 func (o *baseObject) className() string {
@@ -487,6 +516,7 @@ func (o *baseObject) setOwnStr(name unistring.String, val Value, throw bool) boo
 			o.val.runtime.typeErrorResult(throw, "Cannot add property %s, object is not extensible", name)
 			return false
 		} else {
+			o.newPropCheck()
 			o.values[name] = val
 			names := copyNamesIfNeeded(o.propNames, 1)
 			o.propNames = append(names, name)
@@ -527,6 +557,7 @@ func (o *baseObject) setOwnSym(name *Symbol, val Value, throw bool) bool {
 			o.val.runtime.typeErrorResult(throw, "Cannot add property %s, object is not extensible", name)
 			return false
 		} else {
+			o.newPropCheck()
 			if o.symValues == nil {
 				o.symValues = newOrderedMap(nil)
 			}
@@ -756,6 +787,9 @@ Reject:
 func (o *baseObject) defineOwnPropertyStr(name unistring.String, descr PropertyDescriptor, throw bool) bool {
 	existingVal := o.values[name]
 	if v, ok := o._defineOwnProperty(name, existingVal, descr, throw); ok {
+		if existingVal == nil {
+			o.newPropCheck()
+		}
 		o.values[name] = v
 		if existingVal == nil {
 			names := copyNamesIfNeeded(o.propNames, 1)
@@ -776,6 +810,9 @@ func (o *baseObject) defineOwnPropertySym(s *Symbol, descr PropertyDescriptor, t
 		existingVal = o.symValues.get(s)
 	}
 	if v, ok := o._defineOwnProperty(s.descriptiveString().string(), existingVal, descr, throw); ok {
+		if existingVal == nil {
+			o.newPropCheck()
+		}
 		if o.symValues == nil {
 			o.symValues = newOrderedMap(nil)
 		}
@@ -785,8 +822,39 @@ func (o *baseObject) defineOwnPropertySym(s *Symbol, descr PropertyDescriptor, t
 	return false
 }
 
+// newPropCheck must be called before a new own property is added on behalf of a script. It does the memory
+// accounting and enforces the limit set by Runtime.SetMaxObjectProperties() (the built-ins initialisation and
+// the global declarations are exempt, see Runtime.beginBuiltinInit()).
+func (o *baseObject) newPropCheck() {
+	o.val.runtime.trackAlloc(memSizeProperty)
+	o.checkPropLimit()
+}
+
+// checkPropLimit enforces the limit set by Runtime.SetMaxObjectProperties() before a new own property is added.
+func (o *baseObject) checkPropLimit() {
+	r := o.val.runtime
+	if r.maxObjectProps > 0 && !r.builtinInit {
+		count := len(o.propNames)
+		if o.symValues != nil {
+			count += o.symValues.size
+		}
+		if o.val == r.globalObject {
+			// neither the built-in globals nor the bindings created by var and function declarations count
+			tmpl := getGlobalObjectTemplate()
+			count -= len(tmpl.propNames) + r.global.declProps
+			if o.symValues != nil {
+				count -= len(tmpl.symPropNames)
+			}
+		}
+		if count >= r.maxObjectProps {
+			panic(r.newError(r.getRangeError(), "Too many properties: the maximum is %d", r.maxObjectProps))
+		}
+	}
+}
+
 func (o *baseObject) _put(name unistring.String, v Value) {
 	if _, exists := o.values[name]; !exists {
+		o.val.runtime.trackAlloc(memSizeProperty)
 		names := copyNamesIfNeeded(o.propNames, 1)
 		o.propNames = append(names, name)
 	}
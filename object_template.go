@@ -109,18 +109,24 @@ func (o *templatedObject) getOwnPropStr(p unistring.String) Value {
 		return v
 	}
 	if f := o.tmpl.props[p]; f != nil {
-		v := f(o.val.runtime)
+		v := o.newProp(f)
 		o.values[p] = v
 		return v
 	}
 	return nil
 }
 
+func (o *templatedObject) newProp(f templatePropFactory) Value {
+	r := o.val.runtime
+	defer r.endBuiltinInit(r.beginBuiltinInit())
+	return f(r)
+}
+
 func (o *templatedObject) materialiseSymbols() {
 	if o.symValues == nil {
 		o.symValues = newOrderedMap(nil)
 		for _, p := range o.tmpl.symPropNames {
-			o.symValues.set(p, o.tmpl.symProps[p](o.val.runtime))
+			o.symValues.set(p, o.newProp(o.tmpl.symProps[p]))
 		}
 	}
 }
@@ -228,9 +234,12 @@ func (o *templatedObject) hasOwnPropertySym(s *Symbol) bool {
 func (o *templatedObject) defineOwnPropertyStr(name unistring.String, descr PropertyDescriptor, throw bool) bool {
 	existingVal := o.getOwnPropStr(name)
 	if v, ok := o._defineOwnProperty(name, existingVal, descr, throw); ok {
-		o.values[name] = v
 		if existingVal == nil {
 			o.materialisePropNames()
+			o.newPropCheck()
+		}
+		o.values[name] = v
+		if existingVal == nil {
 			names := copyNamesIfNeeded(o.propNames, 1)
 			o.propNames = append(names, name)
 		}
@@ -266,7 +275,7 @@ func (o *templatedObject) deleteSym(s *Symbol, throw bool) bool {
 func (o *templatedObject) materialiseProps() {
 	for name, f := range o.tmpl.props {
 		if _, exists := o.values[name]; !exists {
-			o.values[name] = f(o.val.runtime)
+			o.values[name] = o.newProp(f)
 		}
 	}
 	o.materialisePropNames()
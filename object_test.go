@@ -138,6 +138,110 @@ func TestObjectAssign(t *testing.T) {
 	testScriptWithTestLib(SCRIPT, _undefined, t)
 }
 
+func TestObjectGroupBy(t *testing.T) {
+	const SCRIPT = `
+	var g = Object.groupBy([1, 2, 3, 4, 5], function(x, i) {
+		return x % 2 === 0 ? "even" : "odd";
+	});
+	assert.sameValue(Object.getPrototypeOf(g), null, "null prototype");
+	assert(compareArray(Object.keys(g), ["odd", "even"]), "key order");
+	assert(compareArray(g.odd, [1, 3, 5]), "odd");
+	assert(compareArray(g.even, [2, 4]), "even");
+
+	var indexes = [];
+	g = Object.groupBy(new Set(["a", "bb", "cc"]), function(s, i) {
+		indexes.push(i);
+		return s.length;
+	});
+	assert(compareArray(indexes, [0, 1, 2]), "indexes");
+	assert(compareArray(g["2"], ["bb", "cc"]), "keys are coerced to property keys");
+
+	assert.throws(TypeError, function() { Object.groupBy(null, function() {}); });
+	assert.throws(TypeError, function() { Object.groupBy([], null); });
+	assert.sameValue(Array.prototype[Symbol.unscopables].groupBy, undefined, "unscopables");
+	`
+	testScriptWithTestLib(SCRIPT, _undefined, t)
+}
+
+func TestObjectProtoLegacyAccessors(t *testing.T) {
+	const SCRIPT = `
+	var o = {};
+	var getter = function() { return 42; };
+	var setter = function(v) { this._x = v; };
+	assert.sameValue(o.__defineGetter__("x", getter), undefined);
+	o.__defineSetter__("x", setter);
+	assert.sameValue(o.x, 42);
+	o.x = 1;
+	assert.sameValue(o._x, 1);
+	var desc = Object.getOwnPropertyDescriptor(o, "x");
+	assert(desc.enumerable && desc.configurable, "enumerable and configurable");
+
+	var child = Object.create(o);
+	assert.sameValue(child.__lookupGetter__("x"), getter, "inherited getter");
+	assert.sameValue(child.__lookupSetter__("x"), setter, "inherited setter");
+	child.y = 1;
+	assert.sameValue(child.__lookupGetter__("y"), undefined, "data property");
+	Object.defineProperty(child, "x", {value: 1});
+	assert.sameValue(child.__lookupGetter__("x"), undefined, "shadowed by a data property");
+
+	var sym = Symbol();
+	o.__defineGetter__(sym, getter);
+	assert.sameValue(o[sym], 42, "symbol key");
+	o.__defineGetter__(1, getter);
+	assert.sameValue(o.__lookupGetter__("1"), getter, "key is converted");
+
+	assert.throws(TypeError, function() { o.__defineGetter__("z", 1); });
+	assert.throws(TypeError, function() { Object.prototype.__lookupGetter__.call(undefined, "x"); });
+	Object.defineProperty(o, "frozen", {value: 1});
+	assert.throws(TypeError, function() { o.__defineGetter__("frozen", getter); });
+
+	var log = [];
+	var p = new Proxy(o, {
+		defineProperty: function(target, key, desc) {
+			log.push("defineProperty " + String(key));
+			return Reflect.defineProperty(target, key, desc);
+		},
+		getOwnPropertyDescriptor: function(target, key) {
+			log.push("getOwnPropertyDescriptor " + String(key));
+			return Reflect.getOwnPropertyDescriptor(target, key);
+		}
+	});
+	p.__defineGetter__("w", getter);
+	assert.sameValue(p.__lookupGetter__("w"), getter);
+	assert.sameValue(log.join(), "defineProperty w,getOwnPropertyDescriptor w", "proxy traps");
+
+	assert.sameValue(typeof "abc".__lookupGetter__("length"), "undefined", "primitive");
+	`
+	testScriptWithTestLib(SCRIPT, _undefined, t)
+}
+
+func TestObjectProtoLegacyAccessorsGo(t *testing.T) {
+	vm := New()
+	m := map[string]interface{}{}
+	vm.Set("m", m)
+	vm.Set("s", &struct{ Field int }{Field: 1})
+	_, err := vm.RunString(`
+	if (s.__lookupGetter__("Field") !== undefined) {
+		throw new Error("unexpected getter");
+	}
+	var thrown = false;
+	try {
+		m.__defineGetter__("x", function() { return 1; });
+	} catch (e) {
+		thrown = e instanceof TypeError;
+	}
+	if (!thrown) {
+		throw new Error("expected a TypeError");
+	}
+	`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, exists := m["x"]; exists {
+		t.Fatal(m)
+	}
+}
+
 func TestExportCircular(t *testing.T) {
 	vm := New()
 	o := vm.NewObject()
@@ -552,6 +552,17 @@ func (self *_parser) skipSingleLineComment() {
 	}
 }
 
+// skipHashbang skips a hashbang comment (#!...) if the source starts with one.
+// It is only allowed at the very beginning of the source, so this must be called
+// before the first token is scanned. The offsets are left intact, so the
+// positions of the following tokens are not affected.
+func (self *_parser) skipHashbang() {
+	if strings.HasPrefix(self.str, "#!") {
+		self.read()
+		self.skipSingleLineComment()
+	}
+}
+
 func (self *_parser) skipMultiLineComment() (hasLineTerminator bool) {
 	self.read()
 	for self.chr >= 0 {
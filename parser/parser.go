@@ -211,6 +211,7 @@ func (self *_parser) slice(idx0, idx1 file.Idx) string {
 func (self *_parser) parse() (*ast.Program, error) {
 	self.openScope()
 	defer self.closeScope()
+	self.skipHashbang()
 	self.next()
 	program := self.parseProgram()
 	if false {
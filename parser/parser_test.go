@@ -1319,3 +1319,34 @@ func TestParseTemplateLiteralWithTail(t *testing.T) {
 		t.Fatal(prg.Body[0])
 	}
 }
+
+func TestHashbang(t *testing.T) {
+	tt(t, func() {
+		parser := newParser("", "#!/usr/bin/env goja\nvar abc = 1;")
+		program, err := parser.parse()
+		is(err, nil)
+		is(len(program.Body), 1)
+		{
+			stmt := program.Body[0].(*ast.VariableStatement)
+			pos := parser.position(stmt.Idx0())
+			is(pos.Line, 2)
+			is(pos.Column, 1)
+			is(parser.slice(stmt.Idx0(), stmt.Idx1()), "var abc = 1")
+		}
+
+		_, err = ParseFile(nil, "", "#!/usr/bin/env goja", 0)
+		is(err, nil)
+
+		_, err = ParseFile(nil, "", "#!\r\n}", 0)
+		is(err, "(anonymous): Line 2:1 Unexpected token }")
+
+		_, err = ParseFile(nil, "", " #!/usr/bin/env goja", 0)
+		is(firstErr(err) != nil, true)
+
+		_, err = ParseFile(nil, "", "1;\n#!/usr/bin/env goja", 0)
+		is(firstErr(err) != nil, true)
+
+		_, err = ParseFunction("", "#!/usr/bin/env goja")
+		is(firstErr(err) != nil, true)
+	})
+}
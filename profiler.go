@@ -11,8 +11,8 @@ import (
 	"github.com/google/pprof/profile"
 )
 
-const profInterval = 10 * time.Millisecond
-const profMaxStackDepth = 64
+const defaultProfInterval = 10 * time.Millisecond
+const defaultProfMaxStackDepth = 64
 
 const (
 	profReqNone int32 = iota
@@ -21,20 +21,26 @@ const (
 	profReqStop
 )
 
-type _globalProfiler struct {
+// Profiler collects execution time samples from a set of Runtimes, see Runtime.SetProfiler(). The samples are
+// tagged with the label of the Runtime (see Runtime.SetProfileLabel()), so a single profile can be split by Runtime
+// using the 'runtime' tag (e.g. `go tool pprof -tagfocus runtime=tenant1`).
+//
+// The methods of Profiler are goroutine-safe.
+type Profiler struct {
 	p profiler
-	w io.Writer
 
 	enabled int32
 }
 
-var globalProfiler _globalProfiler
+var globalProfiler Profiler
 
 type profTracker struct {
 	req, finished int32
 	start, stop   time.Time
 	numFrames     int
-	frames        [profMaxStackDepth]StackFrame
+	frames        []StackFrame
+	label         string
+	p             *profiler
 }
 
 type profiler struct {
@@ -42,6 +48,14 @@ type profiler struct {
 	trackers []*profTracker
 	buf      *profBuffer
 	running  bool
+	// w is where the current profile is written to when it's stopped
+	w io.Writer
+
+	interval time.Duration
+	maxDepth int
+	// the settings of the current profile, they don't change while it is active
+	curInterval time.Duration
+	curMaxDepth int
 }
 
 type profFunc struct {
@@ -57,13 +71,23 @@ type profSampleNode struct {
 }
 
 type profBuffer struct {
-	funcs map[*Program]*profFunc
-	root  profSampleNode
+	funcs    map[*Program]*profFunc
+	roots    map[string]*profSampleNode
+	labels   []string
+	interval time.Duration
 }
 
 func (pb *profBuffer) addSample(pt *profTracker) {
 	sampleFrames := pt.frames[:pt.numFrames]
-	n := &pb.root
+	n := pb.roots[pt.label]
+	if n == nil {
+		n = &profSampleNode{}
+		if pb.roots == nil {
+			pb.roots = make(map[string]*profSampleNode)
+		}
+		pb.roots[pt.label] = n
+		pb.labels = append(pb.labels, pt.label)
+	}
 	for j := len(sampleFrames) - 1; j >= 0; j-- {
 		frame := sampleFrames[j]
 		if frame.prg == nil {
@@ -108,6 +132,11 @@ func (pb *profBuffer) addSample(pt *profTracker) {
 			Location: locs,
 			Value:    make([]int64, 2),
 		}
+		if pt.label != "" {
+			smpl.Label = map[string][]string{
+				"runtime": {pt.label},
+			}
+		}
 		n.sample = smpl
 	}
 	smpl.Value[0]++
@@ -121,7 +150,7 @@ func (pb *profBuffer) profile() *profile.Profile {
 		{Type: "cpu", Unit: "nanoseconds"},
 	}
 	pr.PeriodType = pr.SampleType[1]
-	pr.Period = int64(profInterval)
+	pr.Period = int64(pb.interval)
 	mapping := &profile.Mapping{
 		ID:   1,
 		File: "[ECMAScript code]",
@@ -167,7 +196,9 @@ func (pb *profBuffer) profile() *profile.Profile {
 			pr.Location = append(pr.Location, loc)
 		}
 	}
-	pb.addSamples(&pr, &pb.root)
+	for _, label := range pb.labels {
+		pb.addSamples(&pr, pb.roots[label])
+	}
 	return &pr
 }
 
@@ -181,7 +212,9 @@ func (pb *profBuffer) addSamples(p *profile.Profile, n *profSampleNode) {
 }
 
 func (p *profiler) run() {
-	ticker := time.NewTicker(profInterval)
+	p.mu.Lock()
+	ticker := time.NewTicker(p.curInterval)
+	p.mu.Unlock()
 	counter := 0
 
 	for ts := range ticker.C {
@@ -227,10 +260,14 @@ func (p *profiler) run() {
 	p.mu.Unlock()
 }
 
-func (p *profiler) registerVm() *profTracker {
-	pt := new(profTracker)
+func (p *profiler) registerVm(label string) *profTracker {
+	pt := &profTracker{
+		label: label,
+		p:     p,
+	}
 	p.mu.Lock()
 	if p.buf != nil {
+		pt.frames = make([]StackFrame, p.curMaxDepth)
 		p.trackers = append(p.trackers, pt)
 		if !p.running {
 			go p.run()
@@ -243,21 +280,34 @@ func (p *profiler) registerVm() *profTracker {
 	return pt
 }
 
-func (p *profiler) start() error {
+func (p *profiler) start(w io.Writer) error {
 	p.mu.Lock()
 	if p.buf != nil {
 		p.mu.Unlock()
 		return errors.New("profiler is already active")
 	}
-	p.buf = new(profBuffer)
+	p.curInterval = p.interval
+	if p.curInterval <= 0 {
+		p.curInterval = defaultProfInterval
+	}
+	p.curMaxDepth = p.maxDepth
+	if p.curMaxDepth <= 0 {
+		p.curMaxDepth = defaultProfMaxStackDepth
+	}
+	p.buf = &profBuffer{
+		interval: p.curInterval,
+	}
+	p.w = w
 	p.mu.Unlock()
 	return nil
 }
 
-func (p *profiler) stop() *profile.Profile {
+// stop stops the current profile and returns it together with the writer passed to start(). The profile is nil
+// if the profiling is not active.
+func (p *profiler) stop() (*profile.Profile, io.Writer) {
 	p.mu.Lock()
-	trackers, buf := p.trackers, p.buf
-	p.trackers, p.buf = nil, nil
+	trackers, buf, w := p.trackers, p.buf, p.w
+	p.trackers, p.buf, p.w = nil, nil, nil
 	p.mu.Unlock()
 	if buf != nil {
 		k := 0
@@ -304,11 +354,92 @@ func (p *profiler) stop() *profile.Profile {
 				}
 			}()
 		}
-		return buf.profile()
+		return buf.profile(), w
 	}
+	return nil, nil
+}
+
+// NewProfiler creates a new Profiler with the default settings: 10ms sampling interval and up to 64 stack frames
+// per sample.
+func NewProfiler() *Profiler {
+	return &Profiler{}
+}
+
+// SetInterval sets the sampling interval. It takes effect the next time the profiling is started.
+func (p *Profiler) SetInterval(interval time.Duration) {
+	p.p.mu.Lock()
+	p.p.interval = interval
+	p.p.mu.Unlock()
+}
+
+// SetMaxStackDepth sets the maximum number of stack frames recorded in a sample. It takes effect the next time
+// the profiling is started.
+func (p *Profiler) SetMaxStackDepth(depth int) {
+	p.p.mu.Lock()
+	p.p.maxDepth = depth
+	p.p.mu.Unlock()
+}
+
+// Start starts profiling the Runtimes that use this Profiler. The profile is written to w when Stop is called,
+// the format is the same as for StartProfile.
+//
+// It returns an error if profiling is already active.
+func (p *Profiler) Start(w io.Writer) error {
+	err := p.p.start(w)
+	if err != nil {
+		return err
+	}
+	atomic.StoreInt32(&p.enabled, 1)
 	return nil
 }
 
+// Stop stops the profiling and writes the profile, if it is active.
+func (p *Profiler) Stop() {
+	atomic.StoreInt32(&p.enabled, 0)
+	if pr, w := p.p.stop(); pr != nil {
+		_ = pr.Write(w)
+	}
+}
+
+func (p *Profiler) active() bool {
+	return atomic.LoadInt32(&p.enabled) == 1
+}
+
+// SetProfiler makes the Runtime report its samples to p rather than to the process-wide profiler (see StartProfile)
+// while p is active. This allows profiling one Runtime, or a set of Runtimes that share the Profiler, without
+// affecting the others. Passing nil restores the default.
+func (r *Runtime) SetProfiler(p *Profiler) {
+	r.profiler = p
+	r.vm.prof = p
+}
+
+// Profiler returns the Profiler set by SetProfiler() or by StartProfile(), nil if there is none.
+func (r *Runtime) Profiler() *Profiler {
+	return r.profiler
+}
+
+// SetProfileLabel sets the label the samples of this Runtime are tagged with (as the 'runtime' tag). It is used
+// by both the Runtime's own and the process-wide profiler.
+func (r *Runtime) SetProfileLabel(label string) {
+	r.vm.profLabel = label
+}
+
+// StartProfile starts profiling this Runtime only. If no Profiler has been set with SetProfiler(), a new one with
+// the default settings is created. See Profiler.Start().
+func (r *Runtime) StartProfile(w io.Writer) error {
+	if r.profiler == nil {
+		r.SetProfiler(NewProfiler())
+	}
+	return r.profiler.Start(w)
+}
+
+// StopProfile stops the profiling started by StartProfile() and writes the profile.
+func (r *Runtime) StopProfile() {
+	if r.profiler != nil {
+		r.profiler.Stop()
+	}
+}
+
 /*
 StartProfile enables execution time profiling for all Runtimes within the current process.
 This works similar to pprof.StartCPUProfile and produces the same format which can be consumed by `go tool pprof`.
@@ -323,28 +454,18 @@ If there are multiple functions with the same name, their names get a '.N' suffi
 because otherwise the graph view merges them together (even if they are in different mappings). This includes
 "<anonymous>" functions.
 
-The sampling period is set to 10ms.
+The sampling period is set to 10ms. Use a Profiler (see Runtime.SetProfiler()) to profile specific Runtimes or
+to change the settings.
 
 It returns an error if profiling is already active.
 */
 func StartProfile(w io.Writer) error {
-	err := globalProfiler.p.start()
-	if err != nil {
-		return err
-	}
-	globalProfiler.w = w
-	atomic.StoreInt32(&globalProfiler.enabled, 1)
-	return nil
+	return globalProfiler.Start(w)
 }
 
 /*
 StopProfile stops the current profile initiated by StartProfile, if any.
 */
 func StopProfile() {
-	atomic.StoreInt32(&globalProfiler.enabled, 0)
-	pr := globalProfiler.p.stop()
-	if pr != nil {
-		_ = pr.Write(globalProfiler.w)
-	}
-	globalProfiler.w = nil
+	globalProfiler.Stop()
 }
@@ -1,9 +1,14 @@
 package goja
 
 import (
+	"bytes"
+	"io"
+	"sync"
 	"sync/atomic"
 	"testing"
 	"time"
+
+	"github.com/google/pprof/profile"
 )
 
 func TestProfiler(t *testing.T) {
@@ -32,7 +37,7 @@ func TestProfiler(t *testing.T) {
 	time.Sleep(200 * time.Millisecond)
 
 	atomic.StoreInt32(&globalProfiler.enabled, 0)
-	pr := globalProfiler.p.stop()
+	pr, _ := globalProfiler.p.stop()
 
 	if len(pr.Sample) == 0 {
 		t.Fatal("No samples were recorded")
@@ -94,9 +99,99 @@ func TestProfiler1(t *testing.T) {
 
 	time.Sleep(500 * time.Millisecond)
 	atomic.StoreInt32(&globalProfiler.enabled, 0)
-	pr := globalProfiler.p.stop()
+	pr, _ := globalProfiler.p.stop()
 
 	if len(pr.Sample) == 0 {
 		t.Fatal("No samples were recorded")
 	}
 }
+
+func TestRuntimeProfiler(t *testing.T) {
+	const SCRIPT = `
+	function inner() {
+		const t = Date.now();
+		while (Date.now() - t < 100) {}
+	}
+	function outer() {
+		inner();
+	}
+	outer();
+	`
+	p := NewProfiler()
+	p.SetInterval(time.Millisecond)
+	p.SetMaxStackDepth(2)
+	var buf bytes.Buffer
+	if err := p.Start(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if err := p.Start(&buf); err == nil {
+		t.Fatal("Expected an error")
+	}
+
+	other := New()
+	otherDone := make(chan error, 1)
+	go func() {
+		_, err := other.RunScript("other.js", `
+		function other() {
+			const t = Date.now();
+			while (Date.now() - t < 100) {}
+		}
+		other();
+		`)
+		otherDone <- err
+	}()
+
+	for _, label := range []string{"a", "b"} {
+		vm := New()
+		vm.SetProfiler(p)
+		vm.SetProfileLabel(label)
+		if _, err := vm.RunScript(label+".js", SCRIPT); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := <-otherDone; err != nil {
+		t.Fatal(err)
+	}
+	p.Stop()
+
+	pr, err := profile.Parse(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pr.Period != int64(time.Millisecond) {
+		t.Fatalf("Unexpected period: %d", pr.Period)
+	}
+	labels := make(map[string]bool)
+	for _, s := range pr.Sample {
+		if len(s.Location) > 2 {
+			t.Fatalf("Too many frames: %d", len(s.Location))
+		}
+		for _, loc := range s.Location {
+			if f := loc.Line[0].Function; f.Filename != "a.js" && f.Filename != "b.js" {
+				t.Fatalf("Unexpected function in the profile: %s (%s)", f.Name, f.Filename)
+			}
+		}
+		if l := s.Label["runtime"]; len(l) == 1 {
+			labels[l[0]] = true
+		}
+	}
+	if !labels["a"] || !labels["b"] {
+		t.Fatalf("Unexpected labels: %v", labels)
+	}
+}
+
+func TestProfilerConcurrentStartStop(t *testing.T) {
+	p := NewProfiler()
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				_ = p.Start(io.Discard)
+				p.Stop()
+			}
+		}()
+	}
+	wg.Wait()
+}
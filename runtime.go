@@ -2,6 +2,7 @@ package goja
 
 import (
 	"bytes"
+	gocontext "context"
 	"errors"
 	"fmt"
 	"go/ast"
@@ -14,7 +15,7 @@ import (
 	"strconv"
 	"time"
 
-	"golang.org/x/text/collate"
+	"golang.org/x/text/language"
 
 	js_ast "github.com/dop251/goja/ast"
 	"github.com/dop251/goja/file"
@@ -25,6 +26,13 @@ import (
 const (
 	sqrt1_2 float64 = math.Sqrt2 / 2
 
+	// approximate sizes used for memory accounting, see Runtime.SetMemoryLimit()
+	memSizeObject     = 128
+	memSizeProperty   = 48
+	memSizeValue      = 16
+	memSizeSparseItem = 24
+	memSizeMapEntry   = 64
+
 	deoptimiseRegexp = false
 )
 
@@ -34,6 +42,7 @@ var (
 	typeObject   = reflect.TypeOf((*Object)(nil))
 	typeTime     = reflect.TypeOf(time.Time{})
 	typeBytes    = reflect.TypeOf(([]byte)(nil))
+	typeContext  = reflect.TypeOf((*gocontext.Context)(nil)).Elem()
 )
 
 type iterationKind int
@@ -47,6 +56,8 @@ const (
 type global struct {
 	stash    stash
 	varNames map[unistring.String]struct{}
+	// the number of global object properties created by var and function declarations
+	declProps int
 
 	Object   *Object
 	Array    *Object
@@ -62,6 +73,7 @@ type global struct {
 	Promise  *Object
 	Math     *Object
 	JSON     *Object
+	Intl     *Object
 
 	AsyncFunction *Object
 
@@ -94,6 +106,16 @@ type global struct {
 
 	GoError *Object
 
+	Iterator *Object
+
+	Collator           *Object
+	DateTimeFormat     *Object
+	ListFormat         *Object
+	NumberFormat       *Object
+	PluralRules        *Object
+	RelativeTimeFormat *Object
+	Segmenter          *Object
+
 	ObjectPrototype   *Object
 	ArrayPrototype    *Object
 	NumberPrototype   *Object
@@ -113,6 +135,15 @@ type global struct {
 	SetPrototype         *Object
 	PromisePrototype     *Object
 
+	CollatorPrototype           *Object
+	DateTimeFormatPrototype     *Object
+	ListFormatPrototype         *Object
+	NumberFormatPrototype       *Object
+	PluralRulesPrototype        *Object
+	RelativeTimeFormatPrototype *Object
+	SegmenterPrototype          *Object
+	SegmentsPrototype           *Object
+
 	GeneratorFunctionPrototype *Object
 	GeneratorFunction          *Object
 	GeneratorPrototype         *Object
@@ -120,11 +151,14 @@ type global struct {
 	AsyncFunctionPrototype *Object
 
 	IteratorPrototype             *Object
+	IteratorHelperPrototype       *Object
+	WrapForValidIteratorPrototype *Object
 	ArrayIteratorPrototype        *Object
 	MapIteratorPrototype          *Object
 	SetIteratorPrototype          *Object
 	StringIteratorPrototype       *Object
 	RegExpStringIteratorPrototype *Object
+	SegmentIteratorPrototype      *Object
 
 	ErrorPrototype *Object
 
@@ -138,6 +172,7 @@ type global struct {
 	weakMapAdder  *Object
 	mapAdder      *Object
 	setAdder      *Object
+	setHas        *Object
 	arrayValues   *Object
 	arrayToString *Object
 
@@ -178,7 +213,8 @@ type Runtime struct {
 	stringSingleton *stringObject
 	rand            RandSource
 	now             Now
-	_collator       *collate.Collator
+	_collator       *intlCollator
+	locale          *language.Tag
 	parserOptions   []parser.Option
 
 	symbolRegistry map[unistring.String]*Symbol
@@ -192,10 +228,20 @@ type Runtime struct {
 	hash  *maphash.Hash
 	idSeq uint64
 
-	jobQueue []func()
+	jobQueue []promiseJob
 
 	promiseRejectionTracker PromiseRejectionTracker
 	asyncContextTracker     AsyncContextTracker
+
+	allocated, allocLimit uint64
+	injectContext         bool
+
+	maxStringLen, maxArrayLen, maxObjectProps int
+	builtinInit                               bool
+
+	debugger *Debugger
+	profiler *Profiler
+	coverage *Coverage
 }
 
 type StackFrame struct {
@@ -330,6 +376,58 @@ type StackOverflowError struct {
 	baseUncatchableException
 }
 
+// MemoryLimitExceededError is thrown (and returned by RunProgram or by a Callable call) when the approximate
+// amount of memory allocated by a Runtime exceeds the limit set by Runtime.SetMemoryLimit().
+type MemoryLimitExceededError struct {
+	baseUncatchableException
+	limit uint64
+}
+
+// Limit returns the memory limit that has been exceeded.
+func (e *MemoryLimitExceededError) Limit() uint64 {
+	return e.limit
+}
+
+func (e *MemoryLimitExceededError) Error() string {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "memory limit of %d bytes exceeded", e.limit)
+	e.writeShortStack(&b)
+	return b.String()
+}
+
+func (e *MemoryLimitExceededError) String() string {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "memory limit of %d bytes exceeded\n", e.limit)
+	e.writeFullStack(&b)
+	return b.String()
+}
+
+// GasLimitExceededError is thrown (and returned by RunProgram or by a Callable call) when the gas limit
+// set by Runtime.SetGasLimit() is exhausted.
+type GasLimitExceededError struct {
+	baseUncatchableException
+	limit uint64
+}
+
+// Limit returns the gas limit that has been exhausted.
+func (e *GasLimitExceededError) Limit() uint64 {
+	return e.limit
+}
+
+func (e *GasLimitExceededError) Error() string {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "gas limit of %d exceeded", e.limit)
+	e.writeShortStack(&b)
+	return b.String()
+}
+
+func (e *GasLimitExceededError) String() string {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "gas limit of %d exceeded\n", e.limit)
+	e.writeFullStack(&b)
+	return b.String()
+}
+
 func (e *InterruptedError) Value() interface{} {
 	return e.iface
 }
@@ -399,23 +497,6 @@ func (e *Exception) Value() Value {
 	return e.val
 }
 
-func (r *Runtime) createIterProto(val *Object) objectImpl {
-	o := newBaseObjectObj(val, r.global.ObjectPrototype, classObject)
-
-	o._putSym(SymIterator, valueProp(r.newNativeFunc(r.returnThis, "[Symbol.iterator]", 0), true, false, true))
-	return o
-}
-
-func (r *Runtime) getIteratorPrototype() *Object {
-	var o *Object
-	if o = r.global.IteratorPrototype; o == nil {
-		o = &Object{runtime: r}
-		r.global.IteratorPrototype = o
-		o.self = r.createIterProto(o)
-	}
-	return o
-}
-
 func (r *Runtime) init() {
 	r.rand = rand.Float64
 	r.now = time.Now
@@ -1256,15 +1337,18 @@ func New() *Runtime {
 // Compile creates an internal representation of the JavaScript code that can be later run using the Runtime.RunProgram()
 // method. This representation is not linked to a runtime in any way and can be run in multiple runtimes (possibly
 // at the same time).
+// The Program does not get the debug instrumentation, so if it is run with a Debugger attached, the local variables
+// that are not captured by closures cannot be inspected. Use RunString() or RunScript() to debug such code
+// (see Runtime.AttachDebugger()).
 func Compile(name, src string, strict bool) (*Program, error) {
-	return compile(name, src, strict, true, nil)
+	return compile(name, src, strict, true, nil, false, false)
 }
 
 // CompileAST creates an internal representation of the JavaScript code that can be later run using the Runtime.RunProgram()
 // method. This representation is not linked to a runtime in any way and can be run in multiple runtimes (possibly
 // at the same time).
 func CompileAST(prg *js_ast.Program, strict bool) (*Program, error) {
-	return compileAST(prg, strict, true, nil)
+	return compileAST(prg, strict, true, nil, false, false)
 }
 
 // MustCompile is like Compile but panics if the code cannot be compiled.
@@ -1300,17 +1384,21 @@ func Parse(name, src string, options ...parser.Option) (prg *js_ast.Program, err
 	return
 }
 
-func compile(name, src string, strict, inGlobal bool, evalVm *vm, parserOptions ...parser.Option) (p *Program, err error) {
+func compile(name, src string, strict, inGlobal bool, evalVm *vm, debug, coverage bool, parserOptions ...parser.Option) (p *Program, err error) {
 	prg, err := Parse(name, src, parserOptions...)
 	if err != nil {
 		return
 	}
 
-	return compileAST(prg, strict, inGlobal, evalVm)
+	return compileAST(prg, strict, inGlobal, evalVm, debug, coverage)
 }
 
-func compileAST(prg *js_ast.Program, strict, inGlobal bool, evalVm *vm) (p *Program, err error) {
+func compileAST(prg *js_ast.Program, strict, inGlobal bool, evalVm *vm, debug, coverage bool) (p *Program, err error) {
 	c := newCompiler()
+	c.debug = debug
+	if coverage {
+		c.cov = &coverageMap{}
+	}
 
 	defer func() {
 		if x := recover(); x != nil {
@@ -1326,11 +1414,14 @@ func compileAST(prg *js_ast.Program, strict, inGlobal bool, evalVm *vm) (p *Prog
 
 	c.compile(prg, strict, inGlobal, evalVm)
 	p = c.p
+	if c.cov != nil {
+		p.cov = c.cov.finish(p.src)
+	}
 	return
 }
 
 func (r *Runtime) compile(name, src string, strict, inGlobal bool, evalVm *vm) (p *Program, err error) {
-	p, err = compile(name, src, strict, inGlobal, evalVm, r.parserOptions...)
+	p, err = compile(name, src, strict, inGlobal, evalVm, r.debugger != nil, r.coverage != nil && evalVm == nil, r.parserOptions...)
 	if err != nil {
 		switch x1 := err.(type) {
 		case *CompilerSyntaxError:
@@ -1362,6 +1453,24 @@ func (r *Runtime) RunScript(name, src string) (Value, error) {
 	return r.RunProgram(p)
 }
 
+// RunStringContext is like RunString but runs the script with the given context.Context.
+// See RunProgramContext() for details.
+func (r *Runtime) RunStringContext(ctx gocontext.Context, str string) (Value, error) {
+	return r.RunScriptContext(ctx, "", str)
+}
+
+// RunScriptContext is like RunScript but runs the script with the given context.Context.
+// See RunProgramContext() for details.
+func (r *Runtime) RunScriptContext(ctx gocontext.Context, name, src string) (Value, error) {
+	p, err := r.compile(name, src, false, true, nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return r.RunProgramContext(ctx, p)
+}
+
 func isUncatchableException(e error) bool {
 	for ; e != nil; e = errors.Unwrap(e) {
 		if _, ok := e.(uncatchableException); ok {
@@ -1385,7 +1494,17 @@ func asUncatchableException(v interface{}) error {
 
 // RunProgram executes a pre-compiled (see Compile()) code in the global context.
 func (r *Runtime) RunProgram(p *Program) (result Value, err error) {
+	if err = r.checkAllocLimit(); err != nil {
+		return
+	}
 	vm := r.vm
+	if d := r.debugger; d != nil {
+		d.onRunProgram(p)
+	}
+	if c := r.coverage; c != nil && p.cov != nil {
+		c.addProgram(p)
+		vm.covPrg = nil
+	}
 	recursive := len(vm.callStack) > 0
 	defer func() {
 		if recursive {
@@ -1439,6 +1558,70 @@ func (r *Runtime) RunProgram(p *Program) (result Value, err error) {
 	return
 }
 
+// RunProgramContext is like RunProgram but runs the program with the given context.Context.
+// If the context is cancelled or its deadline expires while the program is running, the execution is interrupted
+// (as if Interrupt() was called) and an *InterruptedError wrapping ctx.Err() is returned, so that
+// errors.Is(err, context.DeadlineExceeded) works as expected. If the context is already done, the program is not run.
+//
+// The context is available to the Go functions called by the program via FunctionCall.Context() and
+// ConstructorCall.Context(). If enabled by SetContextInjection(), Go functions whose first parameter is
+// a context.Context receive it as well.
+//
+// Note, just like Interrupt(), it only interrupts JavaScript code, it's up to the Go functions to honor the context.
+// The interrupt flag is cleared when the call returns, so there is no need to call ClearInterrupt().
+func (r *Runtime) RunProgramContext(ctx gocontext.Context, p *Program) (result Value, err error) {
+	err = r.runWithContext(ctx, func() (err error) {
+		result, err = r.RunProgram(p)
+		return
+	})
+	return
+}
+
+// CallContext calls fn with the given context.Context. fn must belong to this Runtime. See RunProgramContext()
+// for details.
+func (r *Runtime) CallContext(ctx gocontext.Context, fn Callable, this Value, args ...Value) (result Value, err error) {
+	err = r.runWithContext(ctx, func() (err error) {
+		result, err = fn(this, args...)
+		return
+	})
+	return
+}
+
+func (r *Runtime) runWithContext(ctx gocontext.Context, f func() error) error {
+	if err := ctx.Err(); err != nil {
+		return &InterruptedError{
+			iface: err,
+		}
+	}
+	vm := r.vm
+	prevCtx := vm.ctx
+	vm.ctx = ctx
+	defer func() {
+		vm.ctx = prevCtx
+	}()
+	// a nested call with the same cancellation signal is already covered by the outer call's watcher
+	if done := ctx.Done(); done != nil && (prevCtx == nil || prevCtx.Done() != done) {
+		stop := make(chan struct{})
+		fired := make(chan uint64, 1)
+		go func() {
+			select {
+			case <-done:
+				fired <- vm.interrupt(ctx.Err())
+			case <-stop:
+				fired <- 0
+			}
+		}()
+		defer func() {
+			close(stop)
+			// only clear the interrupt set above, not the one made by a concurrent Interrupt() call since then
+			if seq := <-fired; seq != 0 {
+				vm.clearInterrupt(seq)
+			}
+		}()
+	}
+	return f()
+}
+
 // CaptureCallStack appends the current call stack frames to the stack slice (which may be nil) up to the specified depth.
 // The most recent frame will be the first one.
 // If depth <= 0 or more than the number of available frames, returns the entire stack.
@@ -1767,18 +1950,26 @@ func (r *Runtime) toValue(i interface{}, origValue reflect.Value) Value {
 		}
 	case func(FunctionCall) Value:
 		name := unistring.NewFromString(runtime.FuncForPC(reflect.ValueOf(i).Pointer()).Name())
-		return r.newNativeFunc(i, name, 0)
+		return r.newNativeFunc(func(call FunctionCall) Value {
+			call.ctx = r.vm.ctx
+			return i(call)
+		}, name, 0)
 	case func(FunctionCall, *Runtime) Value:
 		name := unistring.NewFromString(runtime.FuncForPC(reflect.ValueOf(i).Pointer()).Name())
 		return r.newNativeFunc(func(call FunctionCall) Value {
+			call.ctx = r.vm.ctx
 			return i(call, r)
 		}, name, 0)
 	case func(ConstructorCall) *Object:
 		name := unistring.NewFromString(runtime.FuncForPC(reflect.ValueOf(i).Pointer()).Name())
-		return r.newNativeConstructor(i, name, 0)
+		return r.newNativeConstructor(func(call ConstructorCall) *Object {
+			call.ctx = r.vm.ctx
+			return i(call)
+		}, name, 0)
 	case func(ConstructorCall, *Runtime) *Object:
 		name := unistring.NewFromString(runtime.FuncForPC(reflect.ValueOf(i).Pointer()).Name())
 		return r.newNativeConstructor(func(call ConstructorCall) *Object {
+			call.ctx = r.vm.ctx
 			return i(call, r)
 		}, name, 0)
 	case int:
@@ -1924,6 +2115,14 @@ func (r *Runtime) wrapReflectFunc(value reflect.Value) func(FunctionCall) Value
 	return func(call FunctionCall) Value {
 		typ := value.Type()
 		nargs := typ.NumIn()
+
+		// if the first parameter is a context.Context it receives the current context rather than an argument
+		// (see SetContextInjection())
+		first := 0
+		if r.injectContext && nargs > 0 && typ.In(0) == typeContext {
+			first = 1
+			nargs--
+		}
 		var in []reflect.Value
 
 		if l := len(call.Arguments); l < nargs {
@@ -1932,15 +2131,23 @@ func (r *Runtime) wrapReflectFunc(value reflect.Value) func(FunctionCall) Value
 			if typ.IsVariadic() {
 				n--
 			}
-			in = make([]reflect.Value, n)
+			in = make([]reflect.Value, first+n)
 			for i := l; i < n; i++ {
-				in[i] = reflect.Zero(typ.In(i))
+				in[first+i] = reflect.Zero(typ.In(first + i))
 			}
 		} else {
 			if l > nargs && !typ.IsVariadic() {
 				l = nargs
 			}
-			in = make([]reflect.Value, l)
+			in = make([]reflect.Value, first+l)
+		}
+
+		if first > 0 {
+			ctx := r.vm.ctx
+			if ctx == nil {
+				ctx = gocontext.Background()
+			}
+			in[0] = reflect.ValueOf(ctx)
 		}
 
 		for i, a := range call.Arguments {
@@ -1952,11 +2159,11 @@ func (r *Runtime) wrapReflectFunc(value reflect.Value) func(FunctionCall) Value
 					n = nargs - 1
 				}
 
-				t = typ.In(n).Elem()
+				t = typ.In(first + n).Elem()
 			} else if n > nargs-1 { // ignore extra arguments
 				break
 			} else {
-				t = typ.In(n)
+				t = typ.In(first + n)
 			}
 
 			v := reflect.New(t).Elem()
@@ -1964,7 +2171,7 @@ func (r *Runtime) wrapReflectFunc(value reflect.Value) func(FunctionCall) Value
 			if err != nil {
 				panic(r.NewTypeError("could not convert function call parameter %d: %v", i, err))
 			}
-			in[i] = v
+			in[first+i] = v
 		}
 
 		out := value.Call(in)
@@ -2354,6 +2561,19 @@ func (r *Runtime) SetTimeSource(now Now) {
 	r.now = now
 }
 
+// SetDefaultLocale sets the locale used by the Intl built-ins and by the locale-sensitive methods such as
+// String.prototype.localeCompare() or Date.prototype.toLocaleString() when no locale is specified.
+// The locale must be a valid BCP 47 language tag. If not called, "en-US" is used.
+func (r *Runtime) SetDefaultLocale(locale string) error {
+	tag, err := language.Parse(locale)
+	if err != nil {
+		return err
+	}
+	r.locale = &tag
+	r._collator = nil
+	return nil
+}
+
 // SetParserOptions sets parser options to be used by RunString, RunScript and eval() within the code.
 func (r *Runtime) SetParserOptions(opts ...parser.Option) {
 	r.parserOptions = opts
@@ -2368,6 +2588,167 @@ func (r *Runtime) SetMaxCallStackSize(size int) {
 	r.vm.maxCallStackSize = size
 }
 
+// SetContextInjection enables passing the current context.Context (see RunProgramContext()) to the Go functions
+// converted by ToValue() whose first parameter is a context.Context, the JavaScript arguments are then
+// mapped to the remaining parameters. Outside of RunProgramContext() and similar methods such functions receive
+// context.Background(). When disabled (the default), such a parameter is treated like any other.
+func (r *Runtime) SetContextInjection(enabled bool) {
+	r.injectContext = enabled
+}
+
+// SetMemoryLimit sets the maximum approximate amount of memory (in bytes) the Runtime may allocate. When exceeded,
+// a *MemoryLimitExceededError is thrown which cannot be caught by the script and is returned by RunProgram or by
+// a Callable call. A value of 0 (the default) disables the limit.
+//
+// Note, this is a cumulative allocation budget rather than a limit on the live heap size: the memory released by
+// the garbage collector is not subtracted, so a long-running script that keeps allocating short-lived objects
+// eventually exceeds it. Use ResetMemoryUsage() to start a new budget, e.g. before each run.
+//
+// The accounting is approximate: it covers objects, properties, arrays, Map and Set entries, strings and
+// ArrayBuffers created by the scripts and the built-in functions. See MemoryUsage().
+// Once the limit has been exceeded all subsequent allocations fail until the limit is raised or the counter is
+// reset. The error is only thrown while JavaScript code is running: the host API (such as NewObject() or
+// ToValue()) never fails, but if the limit gets exceeded by such a call, the next RunProgram() or Callable call
+// returns the error.
+func (r *Runtime) SetMemoryLimit(limit uint64) {
+	r.allocLimit = limit
+}
+
+// MemoryUsage returns the approximate amount of memory (in bytes) allocated by the Runtime since it was
+// created or since the last call to ResetMemoryUsage().
+func (r *Runtime) MemoryUsage() uint64 {
+	return r.allocated
+}
+
+// ResetMemoryUsage resets the memory usage counter to 0. This is useful for re-using a Runtime to run
+// another script with the same budget.
+func (r *Runtime) ResetMemoryUsage() {
+	r.allocated = 0
+}
+
+// SetGasLimit sets the gas (instruction budget) limit. When the amount of gas used exceeds the limit, a
+// *GasLimitExceededError is thrown which cannot be caught by the script and is returned by RunProgram or by
+// a Callable call. A value of 0 (the default) disables the limit.
+//
+// Gas is consumed on every function entry (1 unit) and on every backward jump, i.e. on every loop iteration
+// (the length of the loop body in VM instructions), so the amount consumed approximates the number of
+// instructions executed. Unlike Interrupt() it is deterministic: running the same code with the same input
+// always consumes the same amount of gas. Note, the gas is not consumed by the Go code, including the built-in
+// functions.
+//
+// Once the limit has been exceeded, any further JavaScript code execution fails until the limit is raised or
+// the gas counter is reset with ResetGasUsed().
+func (r *Runtime) SetGasLimit(limit uint64) {
+	r.vm.gasLimit = limit
+}
+
+// GasUsed returns the amount of gas consumed since the Runtime was created or since the last call to
+// ResetGasUsed(). See SetGasLimit() for details.
+func (r *Runtime) GasUsed() uint64 {
+	return r.vm.gasUsed
+}
+
+// ResetGasUsed resets the gas counter to 0.
+func (r *Runtime) ResetGasUsed() {
+	r.vm.gasUsed = 0
+}
+
+func (r *Runtime) trackAlloc(size uint64) {
+	usage := r.allocated + size
+	if usage < size { // overflow
+		usage = math.MaxUint64
+	}
+	r.allocated = usage
+	// Outside of a run (i.e. when called from the host API) the error is not raised, instead it is returned
+	// by the next RunProgram() or Callable call, see checkAllocLimit().
+	if r.allocLimit > 0 && usage > r.allocLimit && len(r.vm.callStack) > 0 {
+		ex := &MemoryLimitExceededError{
+			limit: r.allocLimit,
+		}
+		ex.stack = r.vm.captureStack(nil, 0)
+		panic(ex)
+	}
+}
+
+// checkAllocLimit returns an *MemoryLimitExceededError if the limit has been exceeded, possibly by
+// the host API calls made outside of a run.
+func (r *Runtime) checkAllocLimit() error {
+	if r.allocLimit > 0 && r.allocated > r.allocLimit {
+		return &MemoryLimitExceededError{
+			limit: r.allocLimit,
+		}
+	}
+	return nil
+}
+
+// memSizeN returns the approximate size of n items of the given size, saturating on overflow.
+func memSizeN(n int, size uint64) uint64 {
+	if n <= 0 {
+		return 0
+	}
+	if size != 0 && uint64(n) > math.MaxUint64/size {
+		return math.MaxUint64
+	}
+	return uint64(n) * size
+}
+
+// memSizeString returns the approximate amount of memory used by a string.
+func memSizeString(s String) uint64 {
+	if _, ok := s.(asciiString); ok {
+		return uint64(s.Length())
+	}
+	return memSizeN(s.Length(), 2)
+}
+
+// SetMaxStringLength sets the maximum length of a string (in UTF-16 code units) that can be produced by string
+// concatenation, template literals, String.prototype.concat(), repeat(), padStart(), padEnd() and
+// Array.prototype.join(). When exceeded, a RangeError is thrown. A value of 0 (the default) means no limit.
+func (r *Runtime) SetMaxStringLength(n int) {
+	r.maxStringLen = n
+}
+
+// SetMaxArrayLength sets the maximum length of an array. An attempt to grow an array beyond the limit
+// (either by setting its length or by adding an element) results in a RangeError. A value of 0 (the default)
+// means no limit (other than the one imposed by the ECMAScript specification).
+// The limit only applies while JavaScript code is running, arrays created or modified by the host API outside
+// of a run (such as NewArray()) are not subject to it.
+func (r *Runtime) SetMaxArrayLength(n int) {
+	r.maxArrayLen = n
+}
+
+// SetMaxObjectProperties sets the maximum number of own properties (excluding array elements) a script may
+// add to an object. When exceeded, a RangeError is thrown. The built-in properties of the global object and
+// the ones created by global var and function declarations are not counted (and the declarations never fail).
+// A value of 0 (the default) means no limit.
+func (r *Runtime) SetMaxObjectProperties(n int) {
+	r.maxObjectProps = n
+}
+
+func (r *Runtime) checkStringLength(l int) {
+	if r.maxStringLen > 0 && l > r.maxStringLen {
+		panic(r.newError(r.getRangeError(), "Invalid string length"))
+	}
+}
+
+// beginBuiltinInit lifts the limit set by SetMaxObjectProperties() while a built-in object is being
+// initialised or a global declaration binding is being created. The returned previous state must be passed to endBuiltinInit(), i.e.
+// defer r.endBuiltinInit(r.beginBuiltinInit()).
+func (r *Runtime) beginBuiltinInit() bool {
+	prev := r.builtinInit
+	r.builtinInit = true
+	return prev
+}
+
+func (r *Runtime) endBuiltinInit(prev bool) {
+	r.builtinInit = prev
+}
+
+func (r *Runtime) checkArrayLength(l uint32) {
+	if r.maxArrayLen > 0 && int64(l) > int64(r.maxArrayLen) && len(r.vm.callStack) > 0 {
+		panic(r.newError(r.getRangeError(), "Invalid array length"))
+	}
+}
+
 // New is an equivalent of the 'new' operator allowing to call it directly from Go.
 func (r *Runtime) New(construct Value, args ...Value) (o *Object, err error) {
 	err = r.try(func() {
@@ -2431,6 +2812,11 @@ func (r *Runtime) runWrapped(f func()) (err error) {
 			}
 		}
 	}()
+	if len(r.vm.callStack) == 0 {
+		if err = r.checkAllocLimit(); err != nil {
+			return
+		}
+	}
 	ex := r.vm.try(f)
 	if ex != nil {
 		err = ex
@@ -2732,15 +3118,19 @@ func (r *Runtime) getHash() *maphash.Hash {
 
 // called when the top level function returns normally (i.e. control is passed outside the Runtime).
 func (r *Runtime) leave() {
-	var jobs []func()
+	var jobs []promiseJob
 	for len(r.jobQueue) > 0 {
 		jobs, r.jobQueue = r.jobQueue, jobs[:0]
-		for _, job := range jobs {
-			job()
+		for i := range jobs {
+			r.runPromiseJob(&jobs[i])
 		}
 	}
 	r.jobQueue = nil
 	r.vm.stack = nil
+	if d := r.debugger; d != nil {
+		// an unfinished step does not carry over to the next top-level call
+		d.step = DebugContinue
+	}
 }
 
 // called when the top level function returns (i.e. control is passed outside the Runtime) but it was due to an interrupt
@@ -1,6 +1,7 @@
 package goja
 
 import (
+	gocontext "context"
 	"errors"
 	"fmt"
 	"math"
@@ -8,6 +9,7 @@ import (
 	"runtime"
 	"strconv"
 	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -457,6 +459,110 @@ func TestInterrupt(t *testing.T) {
 	}
 }
 
+func TestRunProgramContext(t *testing.T) {
+	vm := New()
+	ctx, cancel := gocontext.WithTimeout(gocontext.Background(), 100*time.Millisecond)
+	defer cancel()
+	_, err := vm.RunStringContext(ctx, `for (;;) {}`)
+	var ie *InterruptedError
+	if !errors.As(err, &ie) {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !errors.Is(err, gocontext.DeadlineExceeded) {
+		t.Fatalf("Error does not wrap the context error: %v", err)
+	}
+
+	// the runtime must be usable after the context has expired
+	v, err := vm.RunString(`1 + 1`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v.ToInteger() != 2 {
+		t.Fatalf("Unexpected result: %v", v)
+	}
+
+	_, err = vm.RunStringContext(ctx, `1 + 1`)
+	if !errors.Is(err, gocontext.DeadlineExceeded) {
+		t.Fatalf("Expired context did not prevent the run: %v", err)
+	}
+}
+
+type testCtxKey struct{}
+
+func TestRunProgramContextNativeCalls(t *testing.T) {
+	vm := New()
+	vm.Set("getValue", func(call FunctionCall) Value {
+		return vm.ToValue(call.Context().Value(testCtxKey{}))
+	})
+	vm.Set("getValueReflect", func(ctx gocontext.Context, suffix string) string {
+		v, _ := ctx.Value(testCtxKey{}).(string)
+		return v + suffix
+	})
+	vm.Set("wait", func(call FunctionCall) Value {
+		<-call.Context().Done()
+		return nil
+	})
+
+	// the context is only injected if enabled
+	_, err := vm.RunString(`getValueReflect("!")`)
+	if ex, ok := err.(*Exception); !ok || !strings.HasPrefix(ex.Error(), "TypeError") {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	vm.SetContextInjection(true)
+
+	ctx := gocontext.WithValue(gocontext.Background(), testCtxKey{}, "test")
+	v, err := vm.RunStringContext(ctx, `getValue() + "," + getValueReflect("!") + "," + [1].map(getValue)[0]`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := v.String(); s != "test,test!,test" {
+		t.Fatalf("Unexpected result: %q", s)
+	}
+
+	v, err = vm.RunString(`getValue() === null && getValueReflect("!") === "!"`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !v.ToBoolean() {
+		t.Fatal("Context is set outside of RunProgramContext()")
+	}
+
+	fn, err := vm.RunString(`(function() { wait(); for (;;) {} })`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f, _ := AssertFunction(fn)
+	ctx, cancel := gocontext.WithCancel(gocontext.Background())
+	time.AfterFunc(10*time.Millisecond, cancel)
+	_, err = vm.CallContext(ctx, f, _undefined)
+	if !errors.Is(err, gocontext.Canceled) {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+}
+
+func TestRunProgramContextConcurrentInterrupt(t *testing.T) {
+	vm := New()
+	ctx, cancel := gocontext.WithCancel(gocontext.Background())
+	// no JavaScript code runs after the context is cancelled, so its interrupt does not trigger
+	f, _ := AssertFunction(vm.ToValue(func() {
+		cancel()
+		for atomic.LoadUint32(&vm.vm.interrupted) == 0 {
+			time.Sleep(time.Millisecond)
+		}
+		vm.Interrupt("user")
+	}))
+	if _, err := vm.CallContext(ctx, f, _undefined); err != nil {
+		t.Fatal(err)
+	}
+
+	// the interrupt made after the context's one must not be cleared
+	_, err := vm.RunString(`1 + 1`)
+	var ie *InterruptedError
+	if !errors.As(err, &ie) || ie.Value() != "user" {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+}
+
 func TestRuntime_ExportToNumbers(t *testing.T) {
 	vm := New()
 	t.Run("int8/no overflow", func(t *testing.T) {
@@ -2296,6 +2402,348 @@ func TestStackOverflowError(t *testing.T) {
 	}
 }
 
+func TestMemoryLimit(t *testing.T) {
+	vm := New()
+	vm.SetMemoryLimit(1 << 20)
+	vm.ResetMemoryUsage()
+
+	for _, script := range []string{
+		`new Array(1e9).fill(0)`,
+		`var a = []; for (;;) { a.push({}); }`,
+		`var s = "x"; for (;;) { s += s; }`,
+		`var s = "\u00e9"; for (;;) { s = s + s; }`,
+		`"x".repeat(1e9)`,
+		`"x".padStart(1e9)`,
+		`new ArrayBuffer(1e9)`,
+		`new Float64Array(1e9)`,
+		`var o = {}; for (var i = 0; ; i++) { o["p" + i] = i; }`,
+		`try { for (var s = "x";; s += s); } catch (e) { "caught"; }`,
+		`var m = new Map(); for (var i = 0; ; i++) { m.set(i, i); }`,
+		`var s = new Set(); for (var i = 0; ; i++) { s.add(i); }`,
+		`var s = new Set(); for (var i = 0; ; i++) { s = s.union(new Set([i])); }`,
+	} {
+		_, err := vm.RunString(script)
+		var me *MemoryLimitExceededError
+		if !errors.As(err, &me) {
+			t.Fatalf("%s: unexpected error: %v", script, err)
+		}
+		if me.Limit() != 1<<20 {
+			t.Fatalf("Unexpected limit: %d", me.Limit())
+		}
+		if vm.MemoryUsage() <= 1<<20 {
+			t.Fatalf("Unexpected usage: %d", vm.MemoryUsage())
+		}
+		vm.ResetMemoryUsage()
+	}
+
+	v, err := vm.RunString(`var arr = []; for (var i = 0; i < 1000; i++) { arr.push({i: i, s: "item" + i}); } arr.length`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v.ToInteger() != 1000 {
+		t.Fatalf("Unexpected result: %v", v)
+	}
+	if usage := vm.MemoryUsage(); usage < 1000*memSizeObject {
+		t.Fatalf("Usage is too low: %d", usage)
+	}
+
+	vm.SetMemoryLimit(0)
+	_, err = vm.RunString(`"x".repeat(1 << 21)`)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestMemoryLimitHostAPI(t *testing.T) {
+	vm := New()
+	vm.SetMemoryLimit(1 << 10)
+	vm.ResetMemoryUsage()
+
+	// the host API does not fail outside of a run
+	m := make(map[string]interface{})
+	for i := 0; i < 100; i++ {
+		m[strconv.Itoa(i)] = i
+	}
+	for i := 0; i < 10; i++ {
+		o := vm.NewObject()
+		if err := o.Set("m", vm.ToValue(m)); err != nil {
+			t.Fatal(err)
+		}
+		vm.NewArray(1, 2, 3)
+		if err := vm.Set("o", o); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if vm.MemoryUsage() <= 1<<10 {
+		t.Fatalf("Unexpected usage: %d", vm.MemoryUsage())
+	}
+
+	// but the next run does
+	_, err := vm.RunString(`1`)
+	var me *MemoryLimitExceededError
+	if !errors.As(err, &me) {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	fn, _ := AssertFunction(vm.Get("Object"))
+	_, err = fn(nil)
+	if !errors.As(err, &me) {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	vm.ResetMemoryUsage()
+	if _, err := vm.RunString(`1`); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestGasLimit(t *testing.T) {
+	const SCRIPT = `
+	function fib(n) {
+		return n < 2 ? n : fib(n - 1) + fib(n - 2);
+	}
+	var sum = 0;
+	for (var i = 0; i < 100; i++) {
+		sum += i;
+	}
+	fib(10) + sum;
+	`
+	prg := MustCompile("test.js", SCRIPT, false)
+
+	var used uint64
+	for i := 0; i < 2; i++ {
+		vm := New()
+		v, err := vm.RunProgram(prg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if v.ToInteger() != 55+4950 {
+			t.Fatalf("Unexpected result: %v", v)
+		}
+		if i == 0 {
+			used = vm.GasUsed()
+			if used <= 177+100 { // 177 calls of fib() and 100 iterations
+				t.Fatalf("Too little gas used: %d", used)
+			}
+		} else if vm.GasUsed() != used {
+			t.Fatalf("Gas usage is not deterministic: %d != %d", vm.GasUsed(), used)
+		}
+	}
+
+	vm := New()
+	vm.SetGasLimit(used - 1)
+	_, err := vm.RunProgram(prg)
+	var ge *GasLimitExceededError
+	if !errors.As(err, &ge) {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if ge.Limit() != used-1 {
+		t.Fatalf("Unexpected limit: %d", ge.Limit())
+	}
+
+	vm.ResetGasUsed()
+	vm.SetGasLimit(used)
+	_, err = vm.RunProgram(prg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if vm.GasUsed() != used {
+		t.Fatalf("Unexpected gas usage: %d", vm.GasUsed())
+	}
+
+	vm.ResetGasUsed()
+	vm.SetGasLimit(1000)
+	_, err = vm.RunString(`
+	try {
+		for (;;) {}
+	} catch (e) {
+	}
+	`)
+	if !errors.As(err, &ge) {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+}
+
+func TestGasLimitLoops(t *testing.T) {
+	for _, src := range []string{
+		`(function() { while (true) {} })()`,
+		`(function() { for (;;) {} })()`,
+		`(function() { do {} while (true) })()`,
+		`[1, 2, 3].forEach(function() { while (true) {} })`,
+		`(function(a = 1) { while (true) {} })()`,
+		`(function(a = 1) { let x = a; for (;;) { x++ } })()`,
+		`(() => { while (true) {} })()`,
+	} {
+		vm := New()
+		vm.SetGasLimit(1)
+		_, err := vm.RunString(src)
+		var ge *GasLimitExceededError
+		if !errors.As(err, &ge) {
+			t.Fatalf("%s: unexpected error: %v", src, err)
+		}
+	}
+}
+
+func TestResourceLimits(t *testing.T) {
+	const SCRIPT = `
+	var s = "x".repeat(10);
+	assert.throws(RangeError, function() { s + "y"; });
+	assert.throws(RangeError, function() { ` + "`${s}y`" + `; });
+	assert.throws(RangeError, function() { s.concat("y"); });
+	assert.throws(RangeError, function() { "x".repeat(11); });
+	assert.throws(RangeError, function() { "x".padStart(11); });
+	assert.throws(RangeError, function() { "x".padEnd(11, "\u00e9"); });
+	assert.throws(RangeError, function() { new Array(11).join("-"); });
+	assert.sameValue("x".repeat(5) + "y".repeat(5), s.replace(/x{5}$/, "yyyyy"));
+
+	var a = [];
+	a[9] = 1;
+	assert.throws(RangeError, function() { a.push(2); });
+	assert.throws(RangeError, function() { a[10] = 2; });
+	assert.throws(RangeError, function() { a.length = 11; });
+	assert.throws(RangeError, function() { new Array(11); });
+	assert.throws(RangeError, function() { var sparse = []; sparse[1000] = 1; });
+	assert.sameValue(a.length, 10);
+	a.length = 0;
+	a.push(1);
+
+	var o = {};
+	for (var i = 0; i < 10; i++) {
+		o["p" + i] = i;
+	}
+	assert.throws(RangeError, function() { o.extra = 1; });
+	assert.throws(RangeError, function() { Object.defineProperty(o, "extra", {value: 1}); });
+	assert.throws(RangeError, function() { o[Symbol()] = 1; });
+	o.p0 = "changed";
+	delete o.p1;
+	o.extra = 1;
+	assert.sameValue(Object.keys(o).length, 10);
+
+	assert.throws(RangeError, function() { return {a: 0, b: 1, c: 2, d: 3, e: 4, f: 5, g: 6, h: 7, i: 8, j: 9, k: 10}; });
+	assert.sameValue(Object.keys({a: 0, b: 1, c: 2, d: 3, e: 4, f: 5, g: 6, h: 7, i: 8, j: 9, a: 10}).length, 10);
+
+	// only the built-in globals and the declarations are exempt on the global object
+	assert.throws(RangeError, function() {
+		for (var j = 0; j < 100; j++) {
+			globalThis["g" + j] = j;
+		}
+	});
+	assert.sameValue(globalThis.g9, 9);
+	assert.sameValue(globalThis.g10, undefined);
+	assert.throws(RangeError, function() { Object.defineProperty(globalThis, "g10", {value: 10}); });
+	assert.throws(RangeError, function() { implicitGlobal = 1; });
+	delete globalThis.g9;
+	globalThis.g10 = 10;
+	for (var j = 0; j <= 10; j++) {
+		delete globalThis["g" + j];
+	}
+
+	assert.throws(RangeError, function() {
+		JSON.parse('{"a":0,"b":1,"c":2,"d":3,"e":4,"f":5,"g":6,"h":7,"i":8,"j":9,"k":10}');
+	});
+	assert.sameValue(Object.keys(JSON.parse('{"a":0,"b":1,"c":2,"d":3,"e":4,"f":5,"g":6,"h":7,"i":8,"a":9}')).length, 9);
+	`
+	r := New()
+	r.SetMaxStringLength(10)
+	r.SetMaxArrayLength(10)
+	r.SetMaxObjectProperties(10)
+	_, err := r.RunProgram(testLib())
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = r.RunString(SCRIPT)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// global declarations never fail
+	_, err = r.RunString(`
+	var v0, v1, v2, v3, v4, v5, v6, v7, v8, v9, v10;
+	function f0() {}
+	function f1() {}
+	`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the host API is not subject to the array length limit outside of a run
+	arr := r.NewArray(1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11)
+	if err := arr.Set("length", 20); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Set("arr", arr); err != nil {
+		t.Fatal(err)
+	}
+	_, err = r.RunString(`
+	assert.sameValue(arr.length, 20);
+	assert.throws(RangeError, function() { arr.push(1); });
+	`)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestMaxObjectPropertiesBuiltins(t *testing.T) {
+	const SCRIPT = `
+	var seen = new Set();
+	function visit(o) {
+		if (o === null || (typeof o !== "object" && typeof o !== "function") || seen.has(o)) {
+			return;
+		}
+		seen.add(o);
+		visit(Object.getPrototypeOf(o));
+		Reflect.ownKeys(o).forEach(function(k) {
+			var d = Object.getOwnPropertyDescriptor(o, k);
+			visit(d.value);
+			visit(d.get);
+			visit(d.set);
+		});
+	}
+	visit(globalThis);
+	assert.sameValue(Array.prototype[Symbol.unscopables].flat, true, "unscopables");
+	assert.sameValue(typeof /x/.flags, "string", "RegExp");
+	assert.sameValue(new Map([[1, 2]]).size, 1, "Map");
+	assert.sameValue(new Set([1]).size, 1, "Set");
+	assert.sameValue(Symbol("x").description, "x", "Symbol");
+	assert.sameValue(typeof Iterator.prototype.constructor, "function", "Iterator");
+	seen.size > 100;
+	`
+	r := New()
+	_, err := r.RunProgram(testLib())
+	if err != nil {
+		t.Fatal(err)
+	}
+	r.SetMaxObjectProperties(5)
+	v, err := r.RunString(SCRIPT)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !v.ToBoolean() {
+		t.Fatal("Too few objects visited")
+	}
+
+	// built-ins that are created directly rather than through the global object
+	for _, src := range []string{
+		`/x/.flags`,
+		`Symbol.iterator.description`,
+		`[].values().constructor`,
+		`[][Symbol.unscopables].flat`,
+	} {
+		r := New()
+		r.SetMaxObjectProperties(5)
+		if _, err := r.RunString(src); err != nil {
+			t.Fatalf("%s: %v", src, err)
+		}
+	}
+
+	_, err = r.RunString(`
+	var o = {a: 1, b: 2, c: 3, d: 4, e: 5};
+	assert.throws(RangeError, function() { o.f = 6; });
+	`)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
 func TestStacktraceLocationThrowFromCatch(t *testing.T) {
 	vm := New()
 	_, err := vm.RunString(`
@@ -2650,6 +3098,133 @@ func TestPromiseExport(t *testing.T) {
 	}
 }
 
+func TestPromiseWithResolvers(t *testing.T) {
+	const SCRIPT = `
+	var r = Promise.withResolvers();
+	assert(r.promise instanceof Promise, "promise");
+	assert.sameValue(Object.getPrototypeOf(r), Object.prototype);
+	assert.sameValue(typeof r.resolve, "function");
+	assert.sameValue(typeof r.reject, "function");
+	r.resolve(42);
+	var result = await r.promise;
+	assert.sameValue(result, 42);
+
+	class MyPromise extends Promise {}
+	assert(MyPromise.withResolvers().promise instanceof MyPromise, "subclass");
+	assert.throws(TypeError, function() { Promise.withResolvers.call({}); });
+	`
+	testAsyncFuncWithTestLib(SCRIPT, _undefined, t)
+}
+
+func TestPromiseThen(t *testing.T) {
+	vm := New()
+	p, resolve, _ := vm.NewPromise()
+	var log []string
+	p.Then(func(v Value) {
+		log = append(log, "fulfilled "+v.String())
+	}, func(v Value) {
+		log = append(log, "rejected "+v.String())
+	})
+	if len(log) != 0 {
+		t.Fatal(log)
+	}
+	resolve("ok")
+	if len(log) != 1 || log[0] != "fulfilled ok" {
+		t.Fatal(log)
+	}
+
+	// already settled
+	p.Then(func(v Value) {
+		log = append(log, "late "+v.String())
+	}, nil)
+	if len(log) != 2 || log[1] != "late ok" {
+		t.Fatal(log)
+	}
+
+	v, err := vm.RunString(`Promise.reject(new Error("boom"))`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var reason Value
+	v.Export().(*Promise).Catch(func(v Value) {
+		reason = v
+	})
+	if reason == nil || reason.(*Object).Get("message").String() != "boom" {
+		t.Fatal(reason)
+	}
+
+	// ordering relative to script reactions
+	log = nil
+	vm.Set("log", func(s string) {
+		log = append(log, s)
+	})
+	vm.Set("goThen", func(p *Promise) {
+		p.Then(func(Value) {
+			log = append(log, "go")
+		}, nil)
+	})
+	_, err = vm.RunString(`
+	var p1 = Promise.resolve();
+	p1.then(function() { log("js1"); });
+	goThen(p1);
+	p1.then(function() { log("js2"); });
+	log("sync");
+	`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(log, ",") != "sync,js1,go,js2" {
+		t.Fatal(log)
+	}
+}
+
+func TestPromiseThenRejected(t *testing.T) {
+	vm := New()
+	type event struct {
+		p  *Promise
+		op PromiseRejectionOperation
+	}
+	var events []event
+	vm.SetPromiseRejectionTracker(func(p *Promise, op PromiseRejectionOperation) {
+		events = append(events, event{p, op})
+	})
+	p, _, reject := vm.NewPromise()
+	reject("boom")
+	if len(events) != 1 || events[0].p != p || events[0].op != PromiseRejectionReject {
+		t.Fatal(events)
+	}
+
+	called := false
+	derived, err := p.Then(func(Value) {
+		called = true
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if called {
+		t.Fatal("onFulfilled called")
+	}
+	// p is now handled, but the rejection is passed on to the derived promise
+	if len(events) != 3 || events[1].p != p || events[1].op != PromiseRejectionHandle ||
+		events[2].p != derived || events[2].op != PromiseRejectionReject {
+		t.Fatal(events)
+	}
+	if derived.State() != PromiseStateRejected || derived.Result().String() != "boom" {
+		t.Fatal(derived.State(), derived.Result())
+	}
+
+	handled, err := derived.Catch(func(Value) {})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if handled.State() != PromiseStateFulfilled || handled.Result() != _undefined {
+		t.Fatal(handled.State(), handled.Result())
+	}
+	if len(events) != 4 || events[3].p != derived || events[3].op != PromiseRejectionHandle {
+		t.Fatal(events)
+	}
+}
+
 func TestErrorStack(t *testing.T) {
 	const SCRIPT = `
 	const err = new Error("test");
@@ -2671,6 +3246,43 @@ func TestErrorStack(t *testing.T) {
 	testScript(SCRIPT, _undefined, t)
 }
 
+func TestHashbang(t *testing.T) {
+	vm := New()
+	v, err := vm.RunScript("test.js", "#!/usr/bin/env goja\nvar x = 1;\nx + 1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v.ToInteger() != 2 {
+		t.Fatalf("Unexpected result: %v", v)
+	}
+
+	_, err = vm.RunScript("test.js", "#!/usr/bin/env goja\n\nthrow new Error('test')")
+	if ex, ok := err.(*Exception); ok {
+		if pos := ex.stack[0].Position(); pos.Line != 3 || pos.Column != 7 {
+			t.Fatalf("Unexpected position: %v", pos)
+		}
+	} else {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	_, err = vm.RunString(`
+	try {
+		new Function("#!/usr/bin/env goja");
+		throw new Error("Function body with a hashbang did not throw");
+	} catch (e) {
+		if (!(e instanceof SyntaxError)) {
+			throw e;
+		}
+	}
+	if (eval("#!/usr/bin/env goja\n42") !== 42) {
+		throw new Error("eval");
+	}
+	`)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
 func TestErrorFormatSymbols(t *testing.T) {
 	vm := New()
 	vm.Set("a", func() (Value, error) { return nil, errors.New("something %s %f") })
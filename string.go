@@ -53,6 +53,9 @@ type utf16Reader interface {
 // string, but in any case it is capable of holding any UTF-16 string, either valid or invalid.
 // Instances of this type, as any other primitive values, are goroutine-safe and can be passed between runtimes.
 // Strings can be created using Runtime.ToValue(goString) or StringFromUTF16.
+//
+// A Go string cannot hold lone (unpaired) surrogates, so String() and Export() replace them with U+FFFD.
+// Use IsWellFormed() to detect such strings before exporting them and ToWellFormed() to repair them explicitly.
 type String interface {
 	Value
 	CharAt(int) uint16
@@ -61,6 +64,11 @@ type String interface {
 	Substring(start, end int) String
 	CompareTo(String) int
 	Reader() io.RuneReader
+	// IsWellFormed reports whether the string is a valid UTF-16 sequence, i.e. it contains no lone surrogates.
+	IsWellFormed() bool
+	// ToWellFormed returns the string with all lone surrogates replaced by U+FFFD. If the string is
+	// well-formed it is returned as is.
+	ToWellFormed() String
 	utf16Reader() utf16Reader
 	utf16RuneReader() io.RuneReader
 	utf16Runes() []rune
@@ -61,6 +61,14 @@ func (s asciiString) Reader() io.RuneReader {
 	}
 }
 
+func (s asciiString) IsWellFormed() bool {
+	return true
+}
+
+func (s asciiString) ToWellFormed() String {
+	return s
+}
+
 func (s asciiString) utf16Reader() utf16Reader {
 	return &asciiUtf16Reader{
 		s: s,
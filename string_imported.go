@@ -237,6 +237,15 @@ func (s *stringUtf16Reader) ReadRune() (r rune, size int, err error) {
 	return
 }
 
+// IsWellFormed always returns true, a Go string cannot hold lone surrogates.
+func (i *importedString) IsWellFormed() bool {
+	return true
+}
+
+func (i *importedString) ToWellFormed() String {
+	return i
+}
+
 func (i *importedString) utf16Reader() utf16Reader {
 	if i.scanned {
 		if i.u != nil {
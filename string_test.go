@@ -166,6 +166,33 @@ func TestStringFromUTF16(t *testing.T) {
 	}
 }
 
+func TestStringWellFormed(t *testing.T) {
+	if s := asciiString("abc"); !s.IsWellFormed() || s.ToWellFormed() != s {
+		t.Fatal(s)
+	}
+	if s := newStringValue("юникод"); !s.IsWellFormed() || !s.ToWellFormed().SameAs(s) {
+		t.Fatal(s)
+	}
+	if s := StringFromUTF16([]uint16{'a', 0xD83D, 0xDE00}); !s.IsWellFormed() {
+		t.Fatal(s)
+	}
+
+	s := StringFromUTF16([]uint16{'a', 0xD800, 'b', 0xDC00, 0xD83D, 0xDE00, 0xD800})
+	if s.IsWellFormed() {
+		t.Fatal("expected a lone surrogate")
+	}
+	fixed := s.ToWellFormed()
+	if !fixed.IsWellFormed() {
+		t.Fatal(fixed)
+	}
+	if !fixed.SameAs(StringFromUTF16([]uint16{'a', 0xFFFD, 'b', 0xFFFD, 0xD83D, 0xDE00, 0xFFFD})) {
+		t.Fatal(fixed)
+	}
+	if s.CharAt(1) != 0xD800 {
+		t.Fatal("the original string was modified")
+	}
+}
+
 func TestStringBuilder(t *testing.T) {
 	t.Run("writeUTF8String-switch", func(t *testing.T) {
 		var sb StringBuilder
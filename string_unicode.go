@@ -350,6 +350,42 @@ func (s unicodeString) Reader() io.RuneReader {
 	}
 }
 
+// loneSurrogate returns the index of the first lone surrogate at or after start (the index includes the BOM)
+// or -1 if there is none.
+func (s unicodeString) loneSurrogate(start int) int {
+	for i := start; i < len(s); i++ {
+		c := s[i]
+		if isUTF16FirstSurrogate(c) {
+			if i+1 < len(s) && isUTF16SecondSurrogate(s[i+1]) {
+				i++
+				continue
+			}
+			return i
+		}
+		if isUTF16SecondSurrogate(c) {
+			return i
+		}
+	}
+	return -1
+}
+
+func (s unicodeString) IsWellFormed() bool {
+	return s.loneSurrogate(1) == -1
+}
+
+func (s unicodeString) ToWellFormed() String {
+	i := s.loneSurrogate(1)
+	if i == -1 {
+		return s
+	}
+	res := make(unicodeString, len(s))
+	copy(res, s)
+	for ; i != -1; i = res.loneSurrogate(i + 1) {
+		res[i] = utf8.RuneError
+	}
+	return res
+}
+
 func (s unicodeString) utf16Reader() utf16Reader {
 	return &utf16RuneReader{
 		s: s[1:],
@@ -1,6 +1,7 @@
 package goja
 
 import (
+	gocontext "context"
 	"fmt"
 	"math"
 	"strconv"
@@ -26,6 +27,10 @@ type stash struct {
 	names     map[unistring.String]uint32
 	obj       *Object
 
+	// If names is nil, may contain the names of the bindings in slot order. Only used for
+	// introspection (e.g. heap snapshots), never for lookups.
+	slotNames []unistring.String
+
 	outer *stash
 
 	// If this is a top-level function stash, sets the type of the function. If set, dynamic var declarations
@@ -328,11 +333,29 @@ type vm struct {
 
 	interrupted   uint32
 	interruptVal  interface{}
+	interruptSeq  uint64
 	interruptLock sync.Mutex
 
+	// ctx is the context.Context set by Runtime.RunProgramContext() and similar methods, nil if there is none
+	ctx gocontext.Context
+
+	// gas metering, see Runtime.SetGasLimit()
+	gasUsed, gasLimit uint64
+
 	curAsyncRunner *asyncRunner
 
 	profTracker *profTracker
+	// prof is the Profiler set by Runtime.SetProfiler(), it takes precedence over the process-wide one while active
+	prof      *Profiler
+	profLabel string
+
+	// dbg is the Debugger attached by Runtime.AttachDebugger(), nil if there is none
+	dbg *Debugger
+
+	// cov is the Coverage set by Runtime.SetCoverage(), covCounts are its counts for covPrg
+	cov       *Coverage
+	covPrg    *Program
+	covCounts []uint32
 }
 
 type instruction interface {
@@ -558,14 +581,14 @@ func (vm *vm) halted() bool {
 }
 
 func (vm *vm) run() {
-	if vm.profTracker != nil && !vm.runWithProfiler() {
+	if vm.profTracker != nil && !vm.runWithProfiler(nil) {
 		return
 	}
 	count := 0
 	interrupted := false
 	for {
 		if count == 0 {
-			if atomic.LoadInt32(&globalProfiler.enabled) == 1 && !vm.runWithProfiler() {
+			if p := vm.activeProfiler(); p != nil && !vm.runWithProfiler(p) {
 				return
 			}
 			count = 100
@@ -579,6 +602,12 @@ func (vm *vm) run() {
 		if pc < 0 || pc >= len(vm.prg.code) {
 			break
 		}
+		if vm.dbg != nil {
+			vm.dbg.onInstruction(vm)
+		}
+		if vm.cov != nil {
+			vm.coverageHit(pc)
+		}
 		vm.prg.code[pc].exec(vm)
 	}
 
@@ -593,10 +622,20 @@ func (vm *vm) run() {
 	}
 }
 
-func (vm *vm) runWithProfiler() bool {
+func (vm *vm) activeProfiler() *profiler {
+	if p := vm.prof; p != nil && p.active() {
+		return &p.p
+	}
+	if globalProfiler.active() {
+		return &globalProfiler.p
+	}
+	return nil
+}
+
+func (vm *vm) runWithProfiler(p *profiler) bool {
 	pt := vm.profTracker
 	if pt == nil {
-		pt = globalProfiler.p.registerVm()
+		pt = p.registerVm(vm.profLabel)
 		vm.profTracker = pt
 		defer func() {
 			atomic.StoreInt32(&vm.profTracker.finished, 1)
@@ -612,6 +651,12 @@ func (vm *vm) runWithProfiler() bool {
 		if pc < 0 || pc >= len(vm.prg.code) {
 			break
 		}
+		if vm.dbg != nil {
+			vm.dbg.onInstruction(vm)
+		}
+		if vm.cov != nil {
+			vm.coverageHit(pc)
+		}
 		vm.prg.code[pc].exec(vm)
 		req := atomic.LoadInt32(&pt.req)
 		if req == profReqStop {
@@ -630,16 +675,57 @@ func (vm *vm) runWithProfiler() bool {
 }
 
 func (vm *vm) Interrupt(v interface{}) {
+	vm.interrupt(v)
+}
+
+// interrupt is like Interrupt but also returns the sequence number of the interrupt for clearInterrupt().
+func (vm *vm) interrupt(v interface{}) uint64 {
 	vm.interruptLock.Lock()
 	vm.interruptVal = v
+	vm.interruptSeq++
+	seq := vm.interruptSeq
 	atomic.StoreUint32(&vm.interrupted, 1)
 	vm.interruptLock.Unlock()
+	return seq
 }
 
 func (vm *vm) ClearInterrupt() {
 	atomic.StoreUint32(&vm.interrupted, 0)
 }
 
+// clearInterrupt resets the interrupt flag unless Interrupt() has been called again since the interrupt
+// with the sequence number seq.
+func (vm *vm) clearInterrupt(seq uint64) {
+	vm.interruptLock.Lock()
+	if vm.interruptSeq == seq {
+		atomic.StoreUint32(&vm.interrupted, 0)
+	}
+	vm.interruptLock.Unlock()
+}
+
+// useGas is called on function entry and on backward jumps (with the length of the loop body),
+// see Runtime.SetGasLimit().
+func (vm *vm) useGas(n uint64) {
+	vm.gasUsed += n
+	if vm.gasLimit > 0 && vm.gasUsed > vm.gasLimit {
+		ex := &GasLimitExceededError{
+			limit: vm.gasLimit,
+		}
+		ex.stack = vm.captureStack(nil, 0)
+		panic(ex)
+	}
+}
+
+// useJumpGas charges a backward (or zero-length) jump by the length of the loop body,
+// but at least 1 so that empty loops are metered too.
+func (vm *vm) useJumpGas(j int) {
+	n := uint64(-j)
+	if n == 0 {
+		n = 1
+	}
+	vm.useGas(n)
+}
+
 func getFuncName(stack []Value, sb int) unistring.String {
 	if sb > 0 {
 		if f, ok := stack[sb-1].(*Object); ok {
@@ -746,6 +832,9 @@ func (vm *vm) restoreStacks(iterLen, refLen uint32) (ex *Exception) {
 
 func (vm *vm) handleThrow(arg interface{}) *Exception {
 	ex := vm.exceptionFromValue(arg)
+	if vm.dbg != nil && ex != nil {
+		vm.dbg.onException(vm, ex)
+	}
 	for len(vm.tryStack) > 0 {
 		tf := &vm.tryStack[len(vm.tryStack)-1]
 		if tf.catchPos == -1 && tf.finallyPos == -1 || ex == nil && tf.catchPos != tryPanicMarker {
@@ -904,6 +993,14 @@ func (l loadVal) exec(vm *vm) {
 	vm.pc++
 }
 
+type _nop struct{}
+
+var nop _nop
+
+func (_nop) exec(vm *vm) {
+	vm.pc++
+}
+
 type _loadUndef struct{}
 
 var loadUndef _loadUndef
@@ -1215,6 +1312,8 @@ func (_add) exec(vm *vm) {
 		if !isRightString {
 			rightString = right.toString()
 		}
+		vm.r.checkStringLength(leftString.Length() + rightString.Length())
+		vm.r.trackAlloc(memSizeString(leftString) + memSizeString(rightString))
 		ret = leftString.Concat(rightString)
 	} else {
 		if leftInt, ok := left.(valueInt); ok {
@@ -1552,6 +1651,9 @@ func (_shr) exec(vm *vm) {
 type jump int32
 
 func (j jump) exec(vm *vm) {
+	if j <= 0 {
+		vm.useJumpGas(int(j))
+	}
 	vm.pc += int(j)
 }
 
@@ -2073,7 +2175,13 @@ func (p setPropStrictP) exec(vm *vm) {
 type putProp unistring.String
 
 func (p putProp) exec(vm *vm) {
-	vm.r.toObject(vm.stack[vm.sp-2]).self._putProp(unistring.String(p), vm.stack[vm.sp-1], true, true, true)
+	obj := vm.r.toObject(vm.stack[vm.sp-2]).self
+	if o, ok := obj.(*baseObject); ok {
+		if _, exists := o.values[unistring.String(p)]; !exists {
+			o.checkPropLimit()
+		}
+	}
+	obj._putProp(unistring.String(p), vm.stack[vm.sp-1], true, true, true)
 
 	vm.sp--
 	vm.pc++
@@ -3240,6 +3348,17 @@ func (n loadDynamicCallee) exec(vm *vm) {
 	vm.pc++
 }
 
+type _debuggerStmt struct{}
+
+var debuggerStmt _debuggerStmt
+
+func (_debuggerStmt) exec(vm *vm) {
+	if vm.dbg != nil {
+		vm.dbg.onDebuggerStatement(vm)
+	}
+	vm.pc++
+}
+
 type _pop struct{}
 
 var pop _pop
@@ -3377,6 +3496,7 @@ func (vm *vm) clearStack() {
 
 type enterBlock struct {
 	names     map[unistring.String]uint32
+	slotNames []unistring.String
 	stashSize uint32
 	stackSize uint32
 }
@@ -3387,6 +3507,8 @@ func (e *enterBlock) exec(vm *vm) {
 		vm.stash.values = make([]Value, e.stashSize)
 		if len(e.names) > 0 {
 			vm.stash.names = e.names
+		} else {
+			vm.stash.slotNames = e.slotNames
 		}
 	}
 	ss := int(e.stackSize)
@@ -3401,6 +3523,7 @@ func (e *enterBlock) exec(vm *vm) {
 
 type enterCatchBlock struct {
 	names     map[unistring.String]uint32
+	slotNames []unistring.String
 	stashSize uint32
 	stackSize uint32
 }
@@ -3410,6 +3533,8 @@ func (e *enterCatchBlock) exec(vm *vm) {
 	vm.stash.values = make([]Value, e.stashSize)
 	if len(e.names) > 0 {
 		vm.stash.names = e.names
+	} else {
+		vm.stash.slotNames = e.slotNames
 	}
 	vm.sp--
 	vm.stash.values[0] = vm.stack[vm.sp]
@@ -3440,6 +3565,7 @@ func (l *leaveBlock) exec(vm *vm) {
 
 type enterFunc struct {
 	names       map[unistring.String]uint32
+	slotNames   []unistring.String
 	stashSize   uint32
 	stackSize   uint32
 	numArgs     uint32
@@ -3449,6 +3575,7 @@ type enterFunc struct {
 }
 
 func (e *enterFunc) exec(vm *vm) {
+	vm.useGas(1)
 	// Input stack:
 	//
 	// callee
@@ -3479,6 +3606,8 @@ func (e *enterFunc) exec(vm *vm) {
 		} else {
 			stash.names = e.names
 		}
+	} else {
+		stash.slotNames = e.slotNames
 	}
 
 	ss := int(e.stackSize)
@@ -3524,6 +3653,7 @@ func (e *enterFunc) exec(vm *vm) {
 // In this case the arguments remain on stack, first argsToCopy of them are copied to the stash.
 type enterFunc1 struct {
 	names      map[unistring.String]uint32
+	slotNames  []unistring.String
 	stashSize  uint32
 	numArgs    uint32
 	argsToCopy uint32
@@ -3532,6 +3662,7 @@ type enterFunc1 struct {
 }
 
 func (e *enterFunc1) exec(vm *vm) {
+	vm.useGas(1)
 	sp := vm.sp
 	vm.sb = sp - vm.args - 1
 	vm.newStash()
@@ -3548,6 +3679,8 @@ func (e *enterFunc1) exec(vm *vm) {
 		} else {
 			stash.names = e.names
 		}
+	} else {
+		stash.slotNames = e.slotNames
 	}
 	offset := vm.args - int(e.argsToCopy)
 	if offset > 0 {
@@ -3595,6 +3728,8 @@ func (e *enterFuncBody) exec(vm *vm) {
 			} else {
 				stash.names = e.names
 			}
+		} else {
+			stash.slotNames = e.slotNames
 		}
 	}
 	sp := vm.sp
@@ -3641,6 +3776,7 @@ type enterFuncStashless struct {
 }
 
 func (e *enterFuncStashless) exec(vm *vm) {
+	vm.useGas(1)
 	sp := vm.sp
 	vm.sb = sp - vm.args - 1
 	d := int(e.args) - vm.args
@@ -3870,11 +4006,14 @@ func (vm *vm) createGlobalVarBindings(names []unistring.String, d bool) {
 		globalVarNames = make(map[unistring.String]struct{})
 		vm.r.global.varNames = globalVarNames
 	}
+	// the declarations are exempt from the limit set by SetMaxObjectProperties()
+	defer vm.r.endBuiltinInit(vm.r.beginBuiltinInit())
 	o := vm.r.globalObject.self
 	if bo, ok := o.(*baseObject); ok {
 		for _, name := range names {
 			if !bo.hasOwnPropertyStr(name) && bo.extensible {
 				bo._putProp(name, _undefined, true, true, d)
+				vm.r.global.declProps++
 			}
 			globalVarNames[name] = struct{}{}
 		}
@@ -3894,6 +4033,7 @@ func (vm *vm) createGlobalVarBindings(names []unistring.String, d bool) {
 					Configurable: cf,
 				}, true)
 				o.setOwnStr(name, _undefined, false)
+				vm.r.global.declProps++
 			}
 			globalVarNames[name] = struct{}{}
 		}
@@ -3906,6 +4046,7 @@ func (vm *vm) createGlobalFuncBindings(names []unistring.String, d bool) {
 		globalVarNames = make(map[unistring.String]struct{})
 		vm.r.global.varNames = globalVarNames
 	}
+	defer vm.r.endBuiltinInit(vm.r.beginBuiltinInit())
 	o := vm.r.globalObject.self
 	b := vm.sp - len(names)
 	var shortcutObj *baseObject
@@ -3937,6 +4078,9 @@ func (vm *vm) createGlobalFuncBindings(names []unistring.String, d bool) {
 				o.setOwnStr(name, desc.Value, false) // not a bug, see https://262.ecma-international.org/#sec-createglobalfunctionbinding
 			}
 		}
+		if prop == nil {
+			vm.r.global.declProps++
+		}
 		globalVarNames[name] = struct{}{}
 	}
 	vm.sp = b
@@ -4051,6 +4195,9 @@ type jeq int32
 func (j jeq) exec(vm *vm) {
 	vm.sp--
 	if vm.stack[vm.sp].ToBoolean() {
+		if j <= 0 {
+			vm.useJumpGas(int(j))
+		}
 		vm.pc += int(j)
 	} else {
 		vm.pc++
@@ -4571,7 +4718,7 @@ func (formalArgs createArgsMapped) exec(vm *vm) {
 	args := &argumentsObject{}
 	args.extensible = true
 	args.prototype = vm.r.global.ObjectPrototype
-	args.class = "Arguments"
+	args.class = classArguments
 	v.self = args
 	args.val = v
 	args.length = vm.args
@@ -4606,7 +4753,7 @@ func (formalArgs createArgsMapped) exec(vm *vm) {
 type createArgsUnmapped uint32
 
 func (formalArgs createArgsUnmapped) exec(vm *vm) {
-	args := vm.r.newBaseObject(vm.r.global.ObjectPrototype, "Arguments")
+	args := vm.r.newBaseObject(vm.r.global.ObjectPrototype, classArguments)
 	i := 0
 	c := int(formalArgs)
 	if vm.args < c {
@@ -4792,6 +4939,7 @@ func (copyStash) exec(vm *vm) {
 	vm.stashAllocs++
 	newStash.values = append([]Value(nil), oldStash.values...)
 	newStash.names = oldStash.names
+	newStash.slotNames = oldStash.slotNames
 	vm.stash = newStash
 	vm.pc++
 }
@@ -4906,6 +5054,12 @@ func (n concatStrings) exec(vm *vm) {
 		}
 	}
 
+	vm.r.checkStringLength(length)
+	if allAscii {
+		vm.r.trackAlloc(uint64(length))
+	} else {
+		vm.r.trackAlloc(memSizeN(length, 2))
+	}
 	vm.sp -= int(n) - 1
 	if allAscii {
 		var buf strings.Builder